@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/certimate-go/certimate/internal/certacme"
+	"github.com/certimate-go/certimate/internal/dnsresponder"
 	"github.com/certimate-go/certimate/internal/tools/mproc"
 	"github.com/certimate-go/certimate/pkg/logging"
 )
@@ -71,6 +72,8 @@ func internalCertApplyCommand(_ core.App) *cobra.Command {
 
 				LegoAccount         *certacme.ACMEAccount   `json:"legoAccount,omitempty"`
 				LegoCertifierConfig *lego.CertificateConfig `json:"legoCertifierConfig,omitempty"`
+
+				DnsResponderZone string `json:"dnsResponderZone,omitempty"`
 			}
 
 			type OutData struct {
@@ -90,6 +93,9 @@ func internalCertApplyCommand(_ core.App) *cobra.Command {
 				// see: /internal/tools/mproc/sender.go
 				log.SetDefault(hookStdLog("go-acme/lego"))
 
+				// 内置权威 DNS 服务器运行在服务进程中，质询记录经由数据目录下的文件存储发布
+				dnsresponder.UseZone(params.DnsResponderZone)
+
 				client, err := certacme.NewACMEClientWithAccount(params.LegoAccount, func(legoCfg *lego.Config) error {
 					if params.LegoCertifierConfig != nil {
 						legoCfg.Certificate = *params.LegoCertifierConfig
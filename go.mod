@@ -45,6 +45,7 @@ require (
 	github.com/byteplus-sdk/byteplus-sdk-golang v1.0.71
 	github.com/go-acme/lego/v5 v5.3.1
 	github.com/go-cmd/cmd v1.4.3
+	github.com/go-jose/go-jose/v4 v4.1.4
 	github.com/go-resty/resty/v2 v2.17.2
 	github.com/go-viper/mapstructure/v2 v2.5.0
 	github.com/google/go-querystring v1.2.0
@@ -52,6 +53,7 @@ require (
 	github.com/jdcloud-api/jdcloud-sdk-go v1.67.0
 	github.com/jlaffaye/ftp v0.2.0
 	github.com/microcosm-cc/bluemonday v1.0.27
+	github.com/miekg/dns v1.1.72
 	github.com/minio/minio-go/v7 v7.2.1
 	github.com/nrdcg/oci-go-sdk/certificatesmanagement/v1065 v1065.122.0
 	github.com/nrdcg/oci-go-sdk/common/v1065 v1065.122.0
@@ -210,7 +212,6 @@ require (
 	github.com/go-acme/esa-20240910/v3 v3.4.0 // indirect
 	github.com/go-acme/jdcloud-sdk-go v1.64.0 // indirect
 	github.com/go-acme/tencentedgdeone v1.3.38 // indirect
-	github.com/go-jose/go-jose/v4 v4.1.4 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/go-ozzo/ozzo-validation/v4 v4.3.0 // indirect
 	github.com/go-test/deep v1.1.1 // indirect
@@ -242,7 +243,6 @@ require (
 	github.com/mattn/go-colorable v0.1.15 // indirect
 	github.com/mattn/go-isatty v0.0.23 // indirect
 	github.com/maxatome/go-testdeep v1.14.0 // indirect
-	github.com/miekg/dns v1.1.72 // indirect
 	github.com/minio/crc64nvme v1.1.1 // indirect
 	github.com/minio/md5-simd v1.1.2 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
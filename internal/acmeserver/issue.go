@@ -0,0 +1,194 @@
+package acmeserver
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/acme"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/certacme"
+	"github.com/certimate-go/certimate/internal/certlocalca"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/settings"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+// 签发证书的超时时间。代理至上游 CA 时需完成其质询，因此预留较长的时间。
+const certificateIssuanceTimeout = 10 * time.Minute
+
+func (s *ACMEServerService) issueCertificate(o *order, csrPEM string) {
+	ctx, cancel := context.WithTimeout(context.Background(), certificateIssuanceTimeout)
+	defer cancel()
+
+	domainOrIPs := make([]string, 0, len(o.Identifiers))
+	for _, identifier := range o.Identifiers {
+		domainOrIPs = append(domainOrIPs, identifier.Value)
+	}
+
+	certificate, err := s.obtainCertificate(ctx, domainOrIPs, csrPEM, o.NotBefore, o.NotAfter)
+	if err != nil {
+		app.GetLogger().Error(fmt.Sprintf("acme server: failed to issue certificate for %s", strings.Join(domainOrIPs, ", ")), slog.Any("error", err))
+	} else {
+		app.GetLogger().Info(fmt.Sprintf("acme server: certificate issued for %s", strings.Join(domainOrIPs, ", ")), slog.String("recordId", certificate.Id))
+	}
+
+	s.orders.Update(func() error {
+		// 重新获取订单，以便其变更被持久化
+		o := s.orders.getOrder(o.Id)
+		if o == nil {
+			return nil
+		}
+
+		if err != nil {
+			o.Status = acme.StatusInvalid
+			o.Error = newProblem(acme.ServerInternalErrorType, http.StatusInternalServerError, "failed to issue certificate: %s", err.Error())
+		} else {
+			o.Status = acme.StatusValid
+			o.CertificateId = certificate.Id
+		}
+
+		return nil
+	})
+}
+
+func (s *ACMEServerService) obtainCertificate(ctx context.Context, domainOrIPs []string, csrPEM string, notBefore, notAfter time.Time) (*domain.Certificate, error) {
+	serverSettings := settings.GetGlobalSettingsForACMEServer()
+	if notAfter.IsZero() && serverSettings.CertificateValidityDays > 0 {
+		notAfter = time.Now().Add(time.Duration(serverSettings.CertificateValidityDays) * 24 * time.Hour)
+	}
+
+	// 读取证书颁发机构授权
+	caAccessConfig := make(map[string]any)
+	if serverSettings.CAProviderAccessId != "" {
+		if access, err := s.accessRepo.GetById(ctx, serverSettings.CAProviderAccessId); err != nil {
+			return nil, fmt.Errorf("failed to get access #%s record: %w", serverSettings.CAProviderAccessId, err)
+		} else {
+			caAccessConfig = access.Config
+		}
+	}
+
+	var obtainResp *certacme.ObtainCertificateResponse
+	if serverSettings.CAProvider == domain.CAProviderTypeLocalCA {
+		issueReq := &certlocalca.IssueCertificateRequest{
+			DomainOrIPs:       domainOrIPs,
+			CSRPEM:            csrPEM,
+			ValidityNotBefore: notBefore,
+			ValidityNotAfter:  notAfter,
+			ExtKeyUsages:      xmaps.GetStringsBySplit(serverSettings.CAProviderConfig, "extKeyUsages", ";"),
+			CAAccessConfig:    caAccessConfig,
+		}
+		issueResp, err := certlocalca.IssueCertificate(ctx, issueReq)
+		if err != nil {
+			return nil, err
+		}
+
+		obtainResp = &certacme.ObtainCertificateResponse{
+			CAProvider:           domain.CAProviderTypeLocalCA,
+			CSR:                  csrPEM,
+			FullChainCertificate: issueResp.FullChainCertificate,
+			IssuerCertificate:    issueResp.IssuerCertificate,
+		}
+	} else {
+		// 读取质询提供商授权
+		providerAccessConfig := make(map[string]any)
+		if serverSettings.ProviderAccessId != "" {
+			if access, err := s.accessRepo.GetById(ctx, serverSettings.ProviderAccessId); err != nil {
+				return nil, fmt.Errorf("failed to get access #%s record: %w", serverSettings.ProviderAccessId, err)
+			} else {
+				providerAccessConfig = access.Config
+			}
+		}
+
+		acmeCfg, err := certacme.CreateACMEConfig(ctx, &certacme.ACMEConfigOptions{
+			CAProvider:               serverSettings.CAProvider,
+			CAProviderAccessConfig:   caAccessConfig,
+			CAProviderExtendedConfig: serverSettings.CAProviderConfig,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize acme config: %w", err)
+		}
+
+		acmeAcct, err := certacme.CreateACMEAccountWithSingleFlight(ctx, acmeCfg, serverSettings.ContactEmail)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize acme account: %w", err)
+		}
+
+		acmeClient, err := certacme.NewACMEClientWithAccount(acmeAcct)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize acme client: %w", err)
+		}
+
+		obtainReq := &certacme.ObtainCertificateRequest{
+			DomainOrIPs:            domainOrIPs,
+			CSRPEM:                 csrPEM,
+			ValidityNotBefore:      notBefore,
+			ValidityNotAfter:       notAfter,
+			ChallengeType:          serverSettings.ChallengeType,
+			Provider:               domain.ACMEChallengeProviderType(serverSettings.Provider),
+			ProviderAccessConfig:   providerAccessConfig,
+			ProviderExtendedConfig: serverSettings.ProviderConfig,
+		}
+		obtainResp, err = acmeClient.ObtainCertificate(ctx, obtainReq)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	certificate := &domain.Certificate{
+		Source:             domain.CertificateSourceTypeACMEServer,
+		Certificate:        obtainResp.FullChainCertificate,
+		IssuerCertificate:  obtainResp.IssuerCertificate,
+		CA:                 obtainResp.CAProvider.String(),
+		ACMEAccountUrl:     obtainResp.ACMEAccountUrl,
+		ACMECertificateUrl: obtainResp.ACMECertificateUrl,
+	}
+	certificate.PopulateFromPEM(obtainResp.FullChainCertificate, "")
+	return s.certificateRepo.Save(ctx, certificate)
+}
+
+// 判断 CSR 中的标识符是否与订单中的标识符完全一致。
+func isCSRMatchIdentifiers(csr *x509.CertificateRequest, identifiers []acme.Identifier) bool {
+	expected := make(map[string]bool, len(identifiers))
+	for _, identifier := range identifiers {
+		expected[identifier.Type+":"+identifier.Value] = true
+	}
+
+	actual := make(map[string]bool)
+	for _, name := range csr.DNSNames {
+		actual["dns:"+strings.ToLower(name)] = true
+	}
+	for _, ip := range csr.IPAddresses {
+		actual["ip:"+ip.String()] = true
+	}
+
+	// 通用名称（如有）须为订单中的标识符之一
+	if cn := strings.ToLower(csr.Subject.CommonName); cn != "" {
+		key := "dns:" + cn
+		if ip := net.ParseIP(cn); ip != nil {
+			key = "ip:" + ip.String()
+		}
+
+		if !expected[key] {
+			return false
+		}
+		actual[key] = true
+	}
+
+	if len(actual) != len(expected) {
+		return false
+	}
+	for key := range actual {
+		if !expected[key] {
+			return false
+		}
+	}
+
+	return true
+}
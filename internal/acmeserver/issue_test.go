@@ -0,0 +1,118 @@
+package acmeserver
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"github.com/go-acme/lego/v5/acme"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCSRMatchIdentifiers(t *testing.T) {
+	dns := func(value string) acme.Identifier { return acme.Identifier{Type: "dns", Value: value} }
+	ip := func(value string) acme.Identifier { return acme.Identifier{Type: "ip", Value: value} }
+
+	type testInput struct {
+		commonName  string
+		dnsNames    []string
+		ipAddresses []string
+		identifiers []acme.Identifier
+	}
+	testCases := []struct {
+		name     string
+		input    testInput
+		expected bool
+	}{
+		{
+			name: "domains matched",
+			input: testInput{
+				dnsNames:    []string{"example.com", "*.example.com"},
+				identifiers: []acme.Identifier{dns("*.example.com"), dns("example.com")},
+			},
+			expected: true,
+		},
+		{
+			name: "domains matched case insensitively",
+			input: testInput{
+				commonName:  "Example.COM",
+				dnsNames:    []string{"EXAMPLE.com"},
+				identifiers: []acme.Identifier{dns("example.com")},
+			},
+			expected: true,
+		},
+		{
+			name: "ip addresses matched",
+			input: testInput{
+				ipAddresses: []string{"127.0.0.1", "::1"},
+				identifiers: []acme.Identifier{ip("127.0.0.1"), ip("::1")},
+			},
+			expected: true,
+		},
+		{
+			name: "ip address in common name",
+			input: testInput{
+				commonName:  "127.0.0.1",
+				ipAddresses: []string{"127.0.0.1"},
+				identifiers: []acme.Identifier{ip("127.0.0.1")},
+			},
+			expected: true,
+		},
+		{
+			name: "common name only",
+			input: testInput{
+				commonName:  "example.com",
+				identifiers: []acme.Identifier{dns("example.com")},
+			},
+			expected: true,
+		},
+		{
+			name: "common name not in order",
+			input: testInput{
+				commonName:  "other.com",
+				dnsNames:    []string{"example.com"},
+				identifiers: []acme.Identifier{dns("example.com")},
+			},
+			expected: false,
+		},
+		{
+			name: "extra domain in csr",
+			input: testInput{
+				dnsNames:    []string{"example.com", "other.com"},
+				identifiers: []acme.Identifier{dns("example.com")},
+			},
+			expected: false,
+		},
+		{
+			name: "domain as ip address",
+			input: testInput{
+				dnsNames:    []string{"127.0.0.1"},
+				identifiers: []acme.Identifier{ip("127.0.0.1")},
+			},
+			expected: false,
+		},
+		{
+			name: "identifier missing in csr",
+			input: testInput{
+				dnsNames:    []string{"example.com"},
+				identifiers: []acme.Identifier{dns("example.com"), ip("127.0.0.1")},
+			},
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			csr := &x509.CertificateRequest{
+				Subject:  pkix.Name{CommonName: tc.input.commonName},
+				DNSNames: tc.input.dnsNames,
+			}
+			for _, ipaddr := range tc.input.ipAddresses {
+				csr.IPAddresses = append(csr.IPAddresses, net.ParseIP(ipaddr))
+			}
+
+			actual := isCSRMatchIdentifiers(csr, tc.input.identifiers)
+			assert.Equal(t, tc.expected, actual, "Case: %-20s", tc.name)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package acmeserver
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-acme/lego/v5/acme"
+	"github.com/go-jose/go-jose/v4"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+var supportedSignatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+var supportedMACAlgorithms = []jose.SignatureAlgorithm{
+	jose.HS256, jose.HS384, jose.HS512,
+}
+
+type signedRequest struct {
+	Payload []byte
+	Key     *jose.JSONWebKey
+	// 签名所属的账户。使用 "jwk" 头部签名时为空。
+	Account *domain.ACMEServerAccount
+}
+
+// 是否为 POST-as-GET 请求，即载荷为空。
+func (r *signedRequest) IsPostAsGet() bool {
+	return len(r.Payload) == 0
+}
+
+// 解析并校验 JWS 请求。
+// 当 useJWK 为 true 时，要求请求以 "jwk" 头部携带公钥（仅用于创建账户）；
+// 否则要求以 "kid" 头部指向一个有效的账户。
+func (s *ACMEServerService) verifyRequest(ctx context.Context, req *dtos.ACMEServerReq, useJWK bool) (*signedRequest, error) {
+	jws, err := jose.ParseSigned(string(req.Body), supportedSignatureAlgorithms)
+	if err != nil {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "failed to parse jws: %s", err.Error())
+	}
+	if len(jws.Signatures) != 1 {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the jws must contain exactly one signature")
+	}
+
+	header := jws.Signatures[0].Protected
+	if !s.nonces.Consume(header.Nonce) {
+		return nil, newProblem(acme.BadNonceErrorType, http.StatusBadRequest, "the nonce is invalid or has been used")
+	}
+
+	if url, _ := header.ExtraHeaders[jose.HeaderKey("url")].(string); url != req.Url {
+		return nil, newProblem(acme.UnauthorizedErrorType, http.StatusUnauthorized, "the url in the jws header does not match the request url")
+	}
+
+	if header.JSONWebKey != nil && header.KeyID != "" {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the jws header must not contain both 'jwk' and 'kid'")
+	}
+
+	var key *jose.JSONWebKey
+	var account *domain.ACMEServerAccount
+	if useJWK {
+		if header.JSONWebKey == nil {
+			return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the jws header must contain 'jwk'")
+		}
+		if !header.JSONWebKey.Valid() || !header.JSONWebKey.IsPublic() {
+			return nil, newProblem(acme.BadPublicKeyErrorType, http.StatusBadRequest, "the jwk is not a valid public key")
+		}
+
+		key = header.JSONWebKey
+	} else {
+		if header.KeyID == "" {
+			return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the jws header must contain 'kid'")
+		}
+
+		accountId, ok := strings.CutPrefix(header.KeyID, req.BaseUrl+"/account/")
+		if !ok || accountId == "" {
+			return nil, newProblem(acme.AccountDoesNotExistErrorType, http.StatusBadRequest, "the account does not exist")
+		}
+
+		account, err = s.accountRepo.GetById(ctx, accountId)
+		if err != nil {
+			if domain.IsRecordNotFoundError(err) {
+				return nil, newProblem(acme.AccountDoesNotExistErrorType, http.StatusBadRequest, "the account does not exist")
+			}
+			return nil, err
+		}
+		if account.Status != acme.StatusValid {
+			return nil, newProblem(acme.UnauthorizedErrorType, http.StatusUnauthorized, "the account is %s", account.Status)
+		}
+
+		key = &jose.JSONWebKey{}
+		if err := json.Unmarshal([]byte(account.Key), key); err != nil {
+			return nil, err
+		}
+	}
+
+	payload, err := jws.Verify(key)
+	if err != nil {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "failed to verify jws signature")
+	}
+
+	return &signedRequest{
+		Payload: payload,
+		Key:     key,
+		Account: account,
+	}, nil
+}
+
+// 校验外部账户绑定，返回其所属的客户端。
+func (s *ACMEServerService) verifyExternalAccountBinding(ctx context.Context, req *dtos.ACMEServerReq, eab json.RawMessage, accountKey *jose.JSONWebKey) (*domain.ACMEServerClient, error) {
+	jws, err := jose.ParseSigned(string(eab), supportedMACAlgorithms)
+	if err != nil {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "failed to parse external account binding: %s", err.Error())
+	}
+	if len(jws.Signatures) != 1 {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the external account binding must contain exactly one signature")
+	}
+
+	header := jws.Signatures[0].Protected
+	if url, _ := header.ExtraHeaders[jose.HeaderKey("url")].(string); url != req.Url {
+		return nil, newProblem(acme.UnauthorizedErrorType, http.StatusUnauthorized, "the url in the external account binding does not match the request url")
+	}
+	if header.Nonce != "" {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the external account binding must not contain 'nonce'")
+	}
+
+	client, err := s.clientRepo.GetByEabKid(ctx, header.KeyID)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, newProblem(acme.UnauthorizedErrorType, http.StatusUnauthorized, "the external account binding key id is unknown")
+		}
+		return nil, err
+	}
+	if client.Disabled {
+		return nil, newProblem(acme.UnauthorizedErrorType, http.StatusUnauthorized, "the external account binding key id is disabled")
+	}
+
+	hmacKey, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(client.EabHmacKey, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := jws.Verify(hmacKey)
+	if err != nil {
+		return nil, newProblem(acme.UnauthorizedErrorType, http.StatusUnauthorized, "failed to verify external account binding signature")
+	}
+
+	// 载荷须为账户公钥本身
+	boundKey := &jose.JSONWebKey{}
+	if err := json.Unmarshal(payload, boundKey); err != nil {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the external account binding payload is not a jwk")
+	}
+
+	boundThumbprint, err := keyThumbprint(boundKey)
+	if err != nil {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the external account binding payload is not a jwk")
+	}
+
+	accountThumbprint, err := keyThumbprint(accountKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if boundThumbprint != accountThumbprint {
+		return nil, newProblem(acme.UnauthorizedErrorType, http.StatusUnauthorized, "the external account binding does not match the account key")
+	}
+
+	return client, nil
+}
+
+func keyThumbprint(key *jose.JSONWebKey) (string, error) {
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
+
+func keyAuthorization(token string, key *jose.JSONWebKey) (string, error) {
+	thumbprint, err := keyThumbprint(key)
+	if err != nil {
+		return "", err
+	}
+
+	return token + "." + thumbprint, nil
+}
@@ -0,0 +1,361 @@
+package acmeserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-acme/lego/v5/acme"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+const testBaseUrl = "https://certimate.example.com/acme"
+
+type testAccountRepository struct {
+	acmeServerAccountRepository
+	accounts map[string]*domain.ACMEServerAccount
+}
+
+func (r *testAccountRepository) GetById(ctx context.Context, id string) (*domain.ACMEServerAccount, error) {
+	if account, ok := r.accounts[id]; ok {
+		return account, nil
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+type testClientRepository struct {
+	acmeServerClientRepository
+	clients map[string]*domain.ACMEServerClient
+}
+
+func (r *testClientRepository) GetByEabKid(ctx context.Context, eabKid string) (*domain.ACMEServerClient, error) {
+	if client, ok := r.clients[eabKid]; ok {
+		return client, nil
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func generateTestKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func signTestJWS(t *testing.T, algorithm jose.SignatureAlgorithm, key any, embedJWK bool, headers map[string]any, payload []byte) []byte {
+	t.Helper()
+
+	extraHeaders := make(map[jose.HeaderKey]any, len(headers))
+	for k, v := range headers {
+		extraHeaders[jose.HeaderKey(k)] = v
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: algorithm, Key: key}, &jose.SignerOptions{EmbedJWK: embedJWK, ExtraHeaders: extraHeaders})
+	require.NoError(t, err)
+
+	jws, err := signer.Sign(payload)
+	require.NoError(t, err)
+	return []byte(jws.FullSerialize())
+}
+
+func assertProblemType(t *testing.T, err error, expectedType string, msgAndArgs ...any) {
+	t.Helper()
+
+	var problem *acme.ProblemDetails
+	if assert.True(t, errors.As(err, &problem), msgAndArgs...) {
+		assert.Equal(t, expectedType, problem.Type, msgAndArgs...)
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	accountKey := generateTestKey(t)
+	accountJWK, err := json.Marshal(&jose.JSONWebKey{Key: accountKey.Public()})
+	require.NoError(t, err)
+
+	s := &ACMEServerService{
+		accountRepo: &testAccountRepository{
+			accounts: map[string]*domain.ACMEServerAccount{
+				"valid":       {Meta: domain.Meta{Id: "valid"}, Key: string(accountJWK), Status: acme.StatusValid},
+				"deactivated": {Meta: domain.Meta{Id: "deactivated"}, Key: string(accountJWK), Status: acme.StatusDeactivated},
+			},
+		},
+		nonces: newNonceStore(),
+	}
+
+	const url = testBaseUrl + "/new-order"
+	usedNonce := s.nonces.New()
+	s.nonces.Consume(usedNonce)
+
+	type testInput struct {
+		body   func(nonce string) []byte
+		url    string
+		useJWK bool
+	}
+	testCases := []struct {
+		name            string
+		input           testInput
+		expectedAccount string
+		expectedProblem string
+	}{
+		{
+			name: "jwk",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, accountKey, true, map[string]any{"nonce": nonce, "url": url}, []byte(`{}`))
+				},
+				useJWK: true,
+			},
+		},
+		{
+			name: "kid",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, accountKey, false, map[string]any{"nonce": nonce, "url": url, "kid": testBaseUrl + "/account/valid"}, []byte(`{}`))
+				},
+			},
+			expectedAccount: "valid",
+		},
+		{
+			name: "post-as-get",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, accountKey, false, map[string]any{"nonce": nonce, "url": url, "kid": testBaseUrl + "/account/valid"}, []byte{})
+				},
+			},
+			expectedAccount: "valid",
+		},
+		{
+			name: "malformed body",
+			input: testInput{
+				body: func(nonce string) []byte { return []byte("malformed") },
+			},
+			expectedProblem: acme.MalformedErrorType,
+		},
+		{
+			name: "unsupported algorithm",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.HS256, []byte("0123456789abcdef0123456789abcdef"), false, map[string]any{"nonce": nonce, "url": url, "kid": testBaseUrl + "/account/valid"}, []byte(`{}`))
+				},
+			},
+			expectedProblem: acme.MalformedErrorType,
+		},
+		{
+			name: "used nonce",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, accountKey, true, map[string]any{"nonce": usedNonce, "url": url}, []byte(`{}`))
+				},
+				useJWK: true,
+			},
+			expectedProblem: acme.BadNonceErrorType,
+		},
+		{
+			name: "url mismatch",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, accountKey, true, map[string]any{"nonce": nonce, "url": testBaseUrl + "/new-account"}, []byte(`{}`))
+				},
+				useJWK: true,
+			},
+			expectedProblem: acme.UnauthorizedErrorType,
+		},
+		{
+			name: "both jwk and kid",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, accountKey, true, map[string]any{"nonce": nonce, "url": url, "kid": testBaseUrl + "/account/valid"}, []byte(`{}`))
+				},
+			},
+			expectedProblem: acme.MalformedErrorType,
+		},
+		{
+			name: "missing jwk",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, accountKey, false, map[string]any{"nonce": nonce, "url": url, "kid": testBaseUrl + "/account/valid"}, []byte(`{}`))
+				},
+				useJWK: true,
+			},
+			expectedProblem: acme.MalformedErrorType,
+		},
+		{
+			name: "missing kid",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, accountKey, true, map[string]any{"nonce": nonce, "url": url}, []byte(`{}`))
+				},
+			},
+			expectedProblem: acme.MalformedErrorType,
+		},
+		{
+			name: "kid of other server",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, accountKey, false, map[string]any{"nonce": nonce, "url": url, "kid": "https://other.example.com/acme/account/valid"}, []byte(`{}`))
+				},
+			},
+			expectedProblem: acme.AccountDoesNotExistErrorType,
+		},
+		{
+			name: "unknown account",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, accountKey, false, map[string]any{"nonce": nonce, "url": url, "kid": testBaseUrl + "/account/unknown"}, []byte(`{}`))
+				},
+			},
+			expectedProblem: acme.AccountDoesNotExistErrorType,
+		},
+		{
+			name: "deactivated account",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, accountKey, false, map[string]any{"nonce": nonce, "url": url, "kid": testBaseUrl + "/account/deactivated"}, []byte(`{}`))
+				},
+			},
+			expectedProblem: acme.UnauthorizedErrorType,
+		},
+		{
+			name: "signed by other key",
+			input: testInput{
+				body: func(nonce string) []byte {
+					return signTestJWS(t, jose.ES256, generateTestKey(t), false, map[string]any{"nonce": nonce, "url": url, "kid": testBaseUrl + "/account/valid"}, []byte(`{}`))
+				},
+			},
+			expectedProblem: acme.MalformedErrorType,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &dtos.ACMEServerReq{
+				BaseUrl: testBaseUrl,
+				Url:     url,
+				Body:    tc.input.body(s.nonces.New()),
+			}
+
+			signed, err := s.verifyRequest(context.Background(), req, tc.input.useJWK)
+			if tc.expectedProblem != "" {
+				assertProblemType(t, err, tc.expectedProblem, "Case: %-20s", tc.name)
+				return
+			}
+
+			require.NoError(t, err, "Case: %-20s", tc.name)
+			assert.NotNil(t, signed.Key, "Case: %-20s", tc.name)
+			if tc.expectedAccount == "" {
+				assert.Nil(t, signed.Account, "Case: %-20s", tc.name)
+			} else if assert.NotNil(t, signed.Account, "Case: %-20s", tc.name) {
+				assert.Equal(t, tc.expectedAccount, signed.Account.Id, "Case: %-20s", tc.name)
+			}
+		})
+	}
+}
+
+func TestVerifyExternalAccountBinding(t *testing.T) {
+	hmacKey := []byte("0123456789abcdef0123456789abcdef")
+	accountKey := generateTestKey(t)
+	accountJWK := &jose.JSONWebKey{Key: accountKey.Public()}
+	accountJWKJSON, err := json.Marshal(accountJWK)
+	require.NoError(t, err)
+	otherJWKJSON, err := json.Marshal(&jose.JSONWebKey{Key: generateTestKey(t).Public()})
+	require.NoError(t, err)
+
+	s := &ACMEServerService{
+		clientRepo: &testClientRepository{
+			clients: map[string]*domain.ACMEServerClient{
+				"kid-1":    {Meta: domain.Meta{Id: "client-1"}, EabKid: "kid-1", EabHmacKey: base64.RawURLEncoding.EncodeToString(hmacKey)},
+				"kid-2":    {Meta: domain.Meta{Id: "client-2"}, EabKid: "kid-2", EabHmacKey: base64.URLEncoding.EncodeToString(hmacKey)},
+				"disabled": {Meta: domain.Meta{Id: "client-3"}, EabKid: "disabled", EabHmacKey: base64.RawURLEncoding.EncodeToString(hmacKey), Disabled: true},
+			},
+		},
+	}
+
+	const url = testBaseUrl + "/new-account"
+
+	testCases := []struct {
+		name            string
+		eab             []byte
+		expectedClient  string
+		expectedProblem string
+	}{
+		{
+			name:           "valid",
+			eab:            signTestJWS(t, jose.HS256, hmacKey, false, map[string]any{"kid": "kid-1", "url": url}, accountJWKJSON),
+			expectedClient: "client-1",
+		},
+		{
+			name:           "padded hmac key",
+			eab:            signTestJWS(t, jose.HS256, hmacKey, false, map[string]any{"kid": "kid-2", "url": url}, accountJWKJSON),
+			expectedClient: "client-2",
+		},
+		{
+			name:            "malformed",
+			eab:             []byte("malformed"),
+			expectedProblem: acme.MalformedErrorType,
+		},
+		{
+			name:            "not a mac",
+			eab:             signTestJWS(t, jose.ES256, accountKey, false, map[string]any{"kid": "kid-1", "url": url}, accountJWKJSON),
+			expectedProblem: acme.MalformedErrorType,
+		},
+		{
+			name:            "url mismatch",
+			eab:             signTestJWS(t, jose.HS256, hmacKey, false, map[string]any{"kid": "kid-1", "url": testBaseUrl + "/new-order"}, accountJWKJSON),
+			expectedProblem: acme.UnauthorizedErrorType,
+		},
+		{
+			name:            "with nonce",
+			eab:             signTestJWS(t, jose.HS256, hmacKey, false, map[string]any{"kid": "kid-1", "url": url, "nonce": "nonce"}, accountJWKJSON),
+			expectedProblem: acme.MalformedErrorType,
+		},
+		{
+			name:            "unknown kid",
+			eab:             signTestJWS(t, jose.HS256, hmacKey, false, map[string]any{"kid": "unknown", "url": url}, accountJWKJSON),
+			expectedProblem: acme.UnauthorizedErrorType,
+		},
+		{
+			name:            "disabled client",
+			eab:             signTestJWS(t, jose.HS256, hmacKey, false, map[string]any{"kid": "disabled", "url": url}, accountJWKJSON),
+			expectedProblem: acme.UnauthorizedErrorType,
+		},
+		{
+			name:            "wrong hmac key",
+			eab:             signTestJWS(t, jose.HS256, []byte("fedcba9876543210fedcba9876543210"), false, map[string]any{"kid": "kid-1", "url": url}, accountJWKJSON),
+			expectedProblem: acme.UnauthorizedErrorType,
+		},
+		{
+			name:            "payload not a jwk",
+			eab:             signTestJWS(t, jose.HS256, hmacKey, false, map[string]any{"kid": "kid-1", "url": url}, []byte(`{}`)),
+			expectedProblem: acme.MalformedErrorType,
+		},
+		{
+			name:            "bound to other key",
+			eab:             signTestJWS(t, jose.HS256, hmacKey, false, map[string]any{"kid": "kid-1", "url": url}, otherJWKJSON),
+			expectedProblem: acme.UnauthorizedErrorType,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &dtos.ACMEServerReq{BaseUrl: testBaseUrl, Url: url}
+
+			client, err := s.verifyExternalAccountBinding(context.Background(), req, tc.eab, accountJWK)
+			if tc.expectedProblem != "" {
+				assertProblemType(t, err, tc.expectedProblem, "Case: %-20s", tc.name)
+				return
+			}
+
+			require.NoError(t, err, "Case: %-20s", tc.name)
+			assert.Equal(t, tc.expectedClient, client.Id, "Case: %-20s", tc.name)
+		})
+	}
+}
@@ -0,0 +1,739 @@
+package acmeserver
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/acme"
+	"github.com/pocketbase/pocketbase/tools/security"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/settings"
+)
+
+type ACMEServerService struct {
+	accessRepo      accessRepository
+	clientRepo      acmeServerClientRepository
+	accountRepo     acmeServerAccountRepository
+	orderRepo       acmeServerOrderRepository
+	certificateRepo certificateRepository
+
+	nonces *nonceStore
+	orders *orderStore
+}
+
+func NewACMEServerService(accessRepo accessRepository, clientRepo acmeServerClientRepository, accountRepo acmeServerAccountRepository, orderRepo acmeServerOrderRepository, certificateRepo certificateRepository) *ACMEServerService {
+	return &ACMEServerService{
+		accessRepo:      accessRepo,
+		clientRepo:      clientRepo,
+		accountRepo:     accountRepo,
+		orderRepo:       orderRepo,
+		certificateRepo: certificateRepo,
+
+		nonces: newNonceStore(),
+		orders: newOrderStore(orderRepo),
+	}
+}
+
+func (s *ACMEServerService) NewNonce(ctx context.Context) (string, error) {
+	if err := s.checkEnabled(); err != nil {
+		return "", err
+	}
+
+	return s.nonces.New(), nil
+}
+
+func (s *ACMEServerService) GetDirectory(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error) {
+	if err := s.checkEnabled(); err != nil {
+		return nil, err
+	}
+
+	// 不使用 [acme.Directory]，以免输出未实现的端点
+	return &dtos.ACMEServerResp{
+		StatusCode: http.StatusOK,
+		Content: map[string]any{
+			"newNonce":   req.BaseUrl + "/new-nonce",
+			"newAccount": req.BaseUrl + "/new-account",
+			"newOrder":   req.BaseUrl + "/new-order",
+			"meta": acme.Meta{
+				ExternalAccountRequired: true,
+			},
+		},
+	}, nil
+}
+
+func (s *ACMEServerService) NewAccount(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error) {
+	if err := s.checkEnabled(); err != nil {
+		return nil, err
+	}
+
+	signed, err := s.verifyRequest(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &acme.Account{}
+	if err := json.Unmarshal(signed.Payload, payload); err != nil {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "failed to parse payload: %s", err.Error())
+	}
+
+	thumbprint, err := keyThumbprint(signed.Key)
+	if err != nil {
+		return nil, newProblem(acme.BadPublicKeyErrorType, http.StatusBadRequest, "the jwk is not a valid public key")
+	}
+
+	// 同一公钥已注册过账户，直接返回
+	if account, err := s.accountRepo.GetByKeyThumbprint(ctx, thumbprint); err == nil {
+		return &dtos.ACMEServerResp{
+			StatusCode: http.StatusOK,
+			Location:   s.accountUrl(req, account.Id),
+			Content:    s.renderAccount(req, account),
+		}, nil
+	} else if !domain.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+
+	if payload.OnlyReturnExisting {
+		return nil, newProblem(acme.AccountDoesNotExistErrorType, http.StatusBadRequest, "the account does not exist")
+	}
+
+	if len(payload.ExternalAccountBinding) == 0 {
+		return nil, newProblem(acme.ExternalAccountRequiredErrorType, http.StatusUnauthorized, "the external account binding is required")
+	}
+
+	client, err := s.verifyExternalAccountBinding(ctx, req, payload.ExternalAccountBinding, signed.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateContact(payload.Contact); err != nil {
+		return nil, err
+	}
+
+	keyJSON, err := json.Marshal(signed.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &domain.ACMEServerAccount{
+		ClientId:      client.Id,
+		Key:           string(keyJSON),
+		KeyThumbprint: thumbprint,
+		Contact:       payload.Contact,
+		Status:        acme.StatusValid,
+	}
+	account, err = s.accountRepo.Save(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.ACMEServerResp{
+		StatusCode: http.StatusCreated,
+		Location:   s.accountUrl(req, account.Id),
+		Content:    s.renderAccount(req, account),
+	}, nil
+}
+
+func (s *ACMEServerService) UpdateAccount(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error) {
+	if err := s.checkEnabled(); err != nil {
+		return nil, err
+	}
+
+	signed, err := s.verifyRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	account := signed.Account
+	if account.Id != req.ResourceId {
+		return nil, newProblem(acme.UnauthorizedErrorType, http.StatusUnauthorized, "the account does not match the request signer")
+	}
+
+	if !signed.IsPostAsGet() {
+		payload := &acme.Account{}
+		if err := json.Unmarshal(signed.Payload, payload); err != nil {
+			return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "failed to parse payload: %s", err.Error())
+		}
+
+		switch payload.Status {
+		case "":
+			if payload.Contact != nil {
+				if err := validateContact(payload.Contact); err != nil {
+					return nil, err
+				}
+
+				account.Contact = payload.Contact
+			}
+
+		case acme.StatusDeactivated:
+			account.Status = acme.StatusDeactivated
+
+		default:
+			return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the account status '%s' is not allowed", payload.Status)
+		}
+
+		account, err = s.accountRepo.Save(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &dtos.ACMEServerResp{
+		StatusCode: http.StatusOK,
+		Content:    s.renderAccount(req, account),
+	}, nil
+}
+
+func (s *ACMEServerService) ListAccountOrders(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error) {
+	if err := s.checkEnabled(); err != nil {
+		return nil, err
+	}
+
+	signed, err := s.verifyRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if signed.Account.Id != req.ResourceId {
+		return nil, newProblem(acme.UnauthorizedErrorType, http.StatusUnauthorized, "the account does not match the request signer")
+	}
+
+	orderUrls := make([]string, 0)
+	s.orders.View(func() error {
+		for _, o := range s.orders.listOrdersByAccount(signed.Account.Id) {
+			if o.Status == acme.StatusPending || o.Status == acme.StatusReady || o.Status == acme.StatusProcessing {
+				orderUrls = append(orderUrls, s.orderUrl(req, o.Id))
+			}
+		}
+		return nil
+	})
+
+	return &dtos.ACMEServerResp{
+		StatusCode: http.StatusOK,
+		Content:    map[string]any{"orders": orderUrls},
+	}, nil
+}
+
+func (s *ACMEServerService) NewOrder(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error) {
+	if err := s.checkEnabled(); err != nil {
+		return nil, err
+	}
+
+	signed, err := s.verifyRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &acme.Order{}
+	if err := json.Unmarshal(signed.Payload, payload); err != nil {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "failed to parse payload: %s", err.Error())
+	}
+	if len(payload.Identifiers) == 0 {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the order must contain at least one identifier")
+	}
+
+	client, err := s.clientRepo.GetById(ctx, signed.Account.ClientId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, newProblem(acme.UnauthorizedErrorType, http.StatusUnauthorized, "the client of the account no longer exists")
+		}
+		return nil, err
+	}
+	if client.Disabled {
+		return nil, newProblem(acme.UnauthorizedErrorType, http.StatusUnauthorized, "the client of the account is disabled")
+	}
+
+	// 规范化标识符并校验签发策略
+	identifiers := make([]acme.Identifier, 0, len(payload.Identifiers))
+	subproblems := make([]acme.SubProblem, 0)
+	for _, identifier := range payload.Identifiers {
+		identifier.Value = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(identifier.Value), "."))
+
+		switch identifier.Type {
+		case "dns":
+			if identifier.Value == "" || net.ParseIP(identifier.Value) != nil || strings.Contains(strings.TrimPrefix(identifier.Value, "*."), "*") {
+				subproblems = append(subproblems, acme.SubProblem{Type: acme.RejectedIdentifierErrorType, Detail: "invalid dns identifier", Identifier: identifier})
+				continue
+			}
+
+		case "ip":
+			ip := net.ParseIP(identifier.Value)
+			if ip == nil {
+				subproblems = append(subproblems, acme.SubProblem{Type: acme.RejectedIdentifierErrorType, Detail: "invalid ip identifier", Identifier: identifier})
+				continue
+			}
+
+			identifier.Value = ip.String()
+
+		default:
+			subproblems = append(subproblems, acme.SubProblem{Type: acme.UnsupportedIdentifierErrorType, Detail: fmt.Sprintf("the identifier type '%s' is not supported", identifier.Type), Identifier: identifier})
+			continue
+		}
+
+		if !client.IsIdentifierAllowed(identifier.Type, identifier.Value) {
+			subproblems = append(subproblems, acme.SubProblem{Type: acme.RejectedIdentifierErrorType, Detail: "the identifier is not allowed by the client policy", Identifier: identifier})
+			continue
+		}
+
+		if !slices.Contains(identifiers, identifier) {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	if len(subproblems) > 0 {
+		problem := newProblem(acme.RejectedIdentifierErrorType, http.StatusBadRequest, "some identifiers are rejected")
+		problem.SubProblems = subproblems
+		return nil, problem
+	}
+
+	var notBefore, notAfter time.Time
+	if payload.NotBefore != "" {
+		if notBefore, err = time.Parse(time.RFC3339, payload.NotBefore); err != nil {
+			return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the 'notBefore' is malformed")
+		}
+	}
+	if payload.NotAfter != "" {
+		if notAfter, err = time.Parse(time.RFC3339, payload.NotAfter); err != nil {
+			return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the 'notAfter' is malformed")
+		}
+	}
+
+	// 构造订单、授权及质询
+	expires := time.Now().Add(orderLifetime)
+	newOrder := &order{
+		Id:          security.RandomString(24),
+		AccountId:   signed.Account.Id,
+		Status:      acme.StatusPending,
+		Expires:     expires,
+		Identifiers: identifiers,
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+	}
+	newAuthzs := make([]*authorization, 0, len(identifiers))
+	newChallenges := make([]*challenge, 0)
+	for _, identifier := range identifiers {
+		authz := &authorization{
+			Id:         security.RandomString(24),
+			OrderId:    newOrder.Id,
+			Status:     acme.StatusPending,
+			Expires:    expires,
+			Identifier: identifier,
+		}
+		if strings.HasPrefix(identifier.Value, "*.") {
+			authz.Identifier.Value = strings.TrimPrefix(identifier.Value, "*.")
+			authz.Wildcard = true
+		}
+
+		// 通配符域名仅支持 DNS-01 质询，IP 地址不支持 DNS-01 质询
+		challengeTypes := []string{challengeTypeHttp01, challengeTypeDns01, challengeTypeTlsAlpn01}
+		if authz.Wildcard {
+			challengeTypes = []string{challengeTypeDns01}
+		} else if identifier.Type == "ip" {
+			challengeTypes = []string{challengeTypeHttp01, challengeTypeTlsAlpn01}
+		}
+
+		token := security.RandomString(43)
+		for _, challengeType := range challengeTypes {
+			ch := &challenge{
+				Id:              security.RandomString(24),
+				AuthorizationId: authz.Id,
+				Type:            challengeType,
+				Token:           token,
+				Status:          acme.StatusPending,
+			}
+			authz.ChallengeIds = append(authz.ChallengeIds, ch.Id)
+			newChallenges = append(newChallenges, ch)
+		}
+
+		newOrder.AuthorizationIds = append(newOrder.AuthorizationIds, authz.Id)
+		newAuthzs = append(newAuthzs, authz)
+	}
+
+	var content *acme.Order
+	if err := s.orders.Update(func() error {
+		s.orders.addOrder(newOrder, newAuthzs, newChallenges)
+		content = s.renderOrder(req, newOrder)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &dtos.ACMEServerResp{
+		StatusCode: http.StatusCreated,
+		Location:   s.orderUrl(req, newOrder.Id),
+		Content:    content,
+	}, nil
+}
+
+func (s *ACMEServerService) GetOrder(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error) {
+	if err := s.checkEnabled(); err != nil {
+		return nil, err
+	}
+
+	signed, err := s.verifyRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var content *acme.Order
+	var retryAfter int
+	if err := s.orders.Update(func() error {
+		o := s.orders.getOrder(req.ResourceId)
+		if o == nil || o.AccountId != signed.Account.Id {
+			return newProblem(acme.MalformedErrorType, http.StatusNotFound, "the order does not exist")
+		}
+
+		s.orders.refreshOrderStatus(o)
+		content = s.renderOrder(req, o)
+		if o.Status == acme.StatusProcessing {
+			retryAfter = 3
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &dtos.ACMEServerResp{
+		StatusCode: http.StatusOK,
+		RetryAfter: retryAfter,
+		Content:    content,
+	}, nil
+}
+
+func (s *ACMEServerService) FinalizeOrder(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error) {
+	if err := s.checkEnabled(); err != nil {
+		return nil, err
+	}
+
+	signed, err := s.verifyRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &acme.CSRMessage{}
+	if err := json.Unmarshal(signed.Payload, payload); err != nil {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "failed to parse payload: %s", err.Error())
+	}
+
+	csrDER, err := base64.RawURLEncoding.DecodeString(payload.Csr)
+	if err != nil {
+		return nil, newProblem(acme.BadCSRErrorType, http.StatusBadRequest, "the csr is not base64url-encoded")
+	}
+
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, newProblem(acme.BadCSRErrorType, http.StatusBadRequest, "failed to parse csr: %s", err.Error())
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, newProblem(acme.BadCSRErrorType, http.StatusBadRequest, "failed to verify csr signature")
+	}
+
+	csrPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}))
+
+	var o *order
+	var content *acme.Order
+	if err := s.orders.Update(func() error {
+		o = s.orders.getOrder(req.ResourceId)
+		if o == nil || o.AccountId != signed.Account.Id {
+			return newProblem(acme.MalformedErrorType, http.StatusNotFound, "the order does not exist")
+		}
+
+		s.orders.refreshOrderStatus(o)
+		if o.Status != acme.StatusReady {
+			return newProblem(acme.OrderNotReadyErrorType, http.StatusForbidden, "the order is %s", o.Status)
+		}
+
+		// CSR 中的标识符须与订单完全一致
+		if !isCSRMatchIdentifiers(csr, o.Identifiers) {
+			return newProblem(acme.BadCSRErrorType, http.StatusBadRequest, "the identifiers in csr do not match the order")
+		}
+
+		o.Status = acme.StatusProcessing
+		content = s.renderOrder(req, o)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// 签发证书可能耗时较长（特别是代理至上游 CA 时），因此异步执行，由客户端轮询订单状态
+	go s.issueCertificate(o, csrPEM)
+
+	return &dtos.ACMEServerResp{
+		StatusCode: http.StatusOK,
+		Location:   s.orderUrl(req, o.Id),
+		RetryAfter: 3,
+		Content:    content,
+	}, nil
+}
+
+func (s *ACMEServerService) GetAuthorization(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error) {
+	if err := s.checkEnabled(); err != nil {
+		return nil, err
+	}
+
+	signed, err := s.verifyRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var content *acme.Authorization
+	if err := s.orders.Update(func() error {
+		authz := s.orders.getAuthorization(req.ResourceId)
+		if authz == nil || !s.isAuthorizationOwnedBy(authz, signed.Account) {
+			return newProblem(acme.MalformedErrorType, http.StatusNotFound, "the authorization does not exist")
+		}
+
+		// 客户端可主动停用授权
+		if !signed.IsPostAsGet() {
+			payload := &struct {
+				Status string `json:"status"`
+			}{}
+			if err := json.Unmarshal(signed.Payload, payload); err != nil {
+				return newProblem(acme.MalformedErrorType, http.StatusBadRequest, "failed to parse payload: %s", err.Error())
+			}
+			if payload.Status != acme.StatusDeactivated {
+				return newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the authorization status '%s' is not allowed", payload.Status)
+			}
+
+			authz.Status = acme.StatusDeactivated
+		}
+
+		content = s.renderAuthorization(req, authz)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &dtos.ACMEServerResp{
+		StatusCode: http.StatusOK,
+		Content:    content,
+	}, nil
+}
+
+func (s *ACMEServerService) RespondChallenge(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error) {
+	if err := s.checkEnabled(); err != nil {
+		return nil, err
+	}
+
+	signed, err := s.verifyRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// 客户端须以空对象 "{}" 作为载荷请求开始验证，参考 RFC 8555 §7.5.1
+	if !signed.IsPostAsGet() {
+		payload := make(map[string]any)
+		if err := json.Unmarshal(signed.Payload, &payload); err != nil || len(payload) != 0 {
+			return nil, newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the payload must be an empty json object")
+		}
+	}
+
+	var ch *challenge
+	var authz *authorization
+	var content acme.Challenge
+	var authzId string
+	if err := s.orders.Update(func() error {
+		ch = s.orders.getChallenge(req.ResourceId)
+		if ch == nil {
+			return newProblem(acme.MalformedErrorType, http.StatusNotFound, "the challenge does not exist")
+		}
+
+		authz = s.orders.getAuthorization(ch.AuthorizationId)
+		if authz == nil || !s.isAuthorizationOwnedBy(authz, signed.Account) {
+			return newProblem(acme.MalformedErrorType, http.StatusNotFound, "the challenge does not exist")
+		}
+
+		// POST-as-GET 仅查询状态；载荷为空对象时才开始验证
+		startValidation := !signed.IsPostAsGet() && ch.Status == acme.StatusPending && authz.Status == acme.StatusPending
+		if startValidation {
+			ch.Status = acme.StatusProcessing
+		}
+
+		content = s.renderChallenge(req, ch)
+		authzId = authz.Id
+		if !startValidation {
+			ch = nil
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if ch != nil {
+		keyAuth, err := keyAuthorization(ch.Token, signed.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		go s.validateChallenge(ch, authz, keyAuth)
+	}
+
+	return &dtos.ACMEServerResp{
+		StatusCode: http.StatusOK,
+		Links:      []string{fmt.Sprintf("<%s>;rel=\"up\"", s.authorizationUrl(req, authzId))},
+		Content:    content,
+	}, nil
+}
+
+func (s *ACMEServerService) GetCertificate(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error) {
+	if err := s.checkEnabled(); err != nil {
+		return nil, err
+	}
+
+	signed, err := s.verifyRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// 仅允许证书所属订单的账户下载证书
+	certOrder, err := s.orderRepo.GetByCertificateId(ctx, req.ResourceId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, newProblem(acme.MalformedErrorType, http.StatusNotFound, "the certificate does not exist")
+		}
+		return nil, err
+	}
+	if certOrder.AccountId != signed.Account.Id {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusNotFound, "the certificate does not exist")
+	}
+
+	certificate, err := s.certificateRepo.GetById(ctx, req.ResourceId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, newProblem(acme.MalformedErrorType, http.StatusNotFound, "the certificate does not exist")
+		}
+		return nil, err
+	}
+	if certificate.Source != domain.CertificateSourceTypeACMEServer {
+		return nil, newProblem(acme.MalformedErrorType, http.StatusNotFound, "the certificate does not exist")
+	}
+
+	return &dtos.ACMEServerResp{
+		StatusCode:  http.StatusOK,
+		ContentType: "application/pem-certificate-chain",
+		Content:     []byte(strings.TrimSpace(certificate.Certificate) + "\n"),
+	}, nil
+}
+
+func (s *ACMEServerService) checkEnabled() error {
+	if !settings.GetGlobalSettingsForACMEServer().Enabled {
+		return newProblem(acme.ServerInternalErrorType, http.StatusNotFound, "the acme server is disabled")
+	}
+
+	return nil
+}
+
+func (s *ACMEServerService) isAuthorizationOwnedBy(authz *authorization, account *domain.ACMEServerAccount) bool {
+	o := s.orders.getOrder(authz.OrderId)
+	return o != nil && o.AccountId == account.Id
+}
+
+func (s *ACMEServerService) accountUrl(req *dtos.ACMEServerReq, accountId string) string {
+	return req.BaseUrl + "/account/" + accountId
+}
+
+func (s *ACMEServerService) orderUrl(req *dtos.ACMEServerReq, orderId string) string {
+	return req.BaseUrl + "/order/" + orderId
+}
+
+func (s *ACMEServerService) authorizationUrl(req *dtos.ACMEServerReq, authzId string) string {
+	return req.BaseUrl + "/authz/" + authzId
+}
+
+func (s *ACMEServerService) challengeUrl(req *dtos.ACMEServerReq, challengeId string) string {
+	return req.BaseUrl + "/chall/" + challengeId
+}
+
+func (s *ACMEServerService) certificateUrl(req *dtos.ACMEServerReq, certificateId string) string {
+	return req.BaseUrl + "/cert/" + certificateId
+}
+
+func (s *ACMEServerService) renderAccount(req *dtos.ACMEServerReq, account *domain.ACMEServerAccount) *acme.Account {
+	return &acme.Account{
+		Status:  account.Status,
+		Contact: account.Contact,
+		Orders:  s.accountUrl(req, account.Id) + "/orders",
+	}
+}
+
+// 须在 [orderStore] 的锁内调用。
+func (s *ACMEServerService) renderOrder(req *dtos.ACMEServerReq, o *order) *acme.Order {
+	content := &acme.Order{
+		Status:      o.Status,
+		Expires:     o.Expires.UTC().Format(time.RFC3339),
+		Identifiers: o.Identifiers,
+		Error:       o.Error,
+		Finalize:    s.orderUrl(req, o.Id) + "/finalize",
+	}
+	if !o.NotBefore.IsZero() {
+		content.NotBefore = o.NotBefore.UTC().Format(time.RFC3339)
+	}
+	if !o.NotAfter.IsZero() {
+		content.NotAfter = o.NotAfter.UTC().Format(time.RFC3339)
+	}
+	for _, authzId := range o.AuthorizationIds {
+		content.Authorizations = append(content.Authorizations, s.authorizationUrl(req, authzId))
+	}
+	if o.CertificateId != "" {
+		content.Certificate = s.certificateUrl(req, o.CertificateId)
+	}
+
+	return content
+}
+
+// 须在 [orderStore] 的锁内调用。
+func (s *ACMEServerService) renderAuthorization(req *dtos.ACMEServerReq, authz *authorization) *acme.Authorization {
+	content := &acme.Authorization{
+		Status:     authz.Status,
+		Expires:    authz.Expires.UTC(),
+		Identifier: authz.Identifier,
+		Wildcard:   authz.Wildcard,
+		Challenges: make([]acme.Challenge, 0, len(authz.ChallengeIds)),
+	}
+	for _, chId := range authz.ChallengeIds {
+		if ch := s.orders.getChallenge(chId); ch != nil {
+			content.Challenges = append(content.Challenges, s.renderChallenge(req, ch))
+		}
+	}
+
+	return content
+}
+
+// 须在 [orderStore] 的锁内调用。
+func (s *ACMEServerService) renderChallenge(req *dtos.ACMEServerReq, ch *challenge) acme.Challenge {
+	return acme.Challenge{
+		Type:      ch.Type,
+		URL:       s.challengeUrl(req, ch.Id),
+		Status:    ch.Status,
+		Validated: ch.Validated,
+		Error:     ch.Error,
+		Token:     ch.Token,
+	}
+}
+
+func newProblem(problemType string, httpStatus int, format string, args ...any) *acme.ProblemDetails {
+	return &acme.ProblemDetails{
+		Type:       problemType,
+		Detail:     fmt.Sprintf(format, args...),
+		HTTPStatus: httpStatus,
+	}
+}
+
+func validateContact(contact []string) error {
+	for _, c := range contact {
+		if !strings.HasPrefix(c, "mailto:") {
+			return newProblem(acme.UnsupportedContactErrorType, http.StatusBadRequest, "the contact '%s' is not supported", c)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package acmeserver
+
+import (
+	"context"
+
+	"github.com/pocketbase/dbx"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type accessRepository interface {
+	GetById(ctx context.Context, id string) (*domain.Access, error)
+}
+
+type acmeServerClientRepository interface {
+	GetById(ctx context.Context, id string) (*domain.ACMEServerClient, error)
+	GetByEabKid(ctx context.Context, eabKid string) (*domain.ACMEServerClient, error)
+}
+
+type acmeServerAccountRepository interface {
+	GetById(ctx context.Context, id string) (*domain.ACMEServerAccount, error)
+	GetByKeyThumbprint(ctx context.Context, keyThumbprint string) (*domain.ACMEServerAccount, error)
+	Save(ctx context.Context, account *domain.ACMEServerAccount) (*domain.ACMEServerAccount, error)
+}
+
+type acmeServerOrderRepository interface {
+	ListWithExprs(ctx context.Context, exprs ...dbx.Expression) ([]*domain.ACMEServerOrder, error)
+	GetByCertificateId(ctx context.Context, certificateId string) (*domain.ACMEServerOrder, error)
+	Save(ctx context.Context, order *domain.ACMEServerOrder) (*domain.ACMEServerOrder, error)
+	DeleteWithExprs(ctx context.Context, exprs ...dbx.Expression) (int, error)
+}
+
+type certificateRepository interface {
+	GetById(ctx context.Context, id string) (*domain.Certificate, error)
+	Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error)
+}
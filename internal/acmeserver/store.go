@@ -0,0 +1,369 @@
+package acmeserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v5/acme"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/security"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+const (
+	// 防重放随机数的有效期。
+	nonceLifetime = time.Hour
+	// 订单及授权的有效期。
+	orderLifetime = 24 * time.Hour
+)
+
+type nonceStore struct {
+	mtx    sync.Mutex
+	nonces map[string]time.Time
+}
+
+func newNonceStore() *nonceStore {
+	return &nonceStore{
+		nonces: make(map[string]time.Time),
+	}
+}
+
+func (s *nonceStore) New() string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := time.Now()
+	for nonce, expires := range s.nonces {
+		if now.After(expires) {
+			delete(s.nonces, nonce)
+		}
+	}
+
+	nonce := security.RandomString(32)
+	s.nonces[nonce] = now.Add(nonceLifetime)
+	return nonce
+}
+
+// 消费一个随机数，每个随机数仅能被使用一次。
+func (s *nonceStore) Consume(nonce string) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	expires, ok := s.nonces[nonce]
+	if !ok {
+		return false
+	}
+
+	delete(s.nonces, nonce)
+	return time.Now().Before(expires)
+}
+
+type order struct {
+	Id               string
+	AccountId        string
+	Status           string
+	Expires          time.Time
+	Identifiers      []acme.Identifier
+	NotBefore        time.Time
+	NotAfter         time.Time
+	AuthorizationIds []string
+	CertificateId    string
+	Error            *acme.ProblemDetails
+}
+
+type authorization struct {
+	Id           string
+	OrderId      string
+	Status       string
+	Expires      time.Time
+	Identifier   acme.Identifier
+	Wildcard     bool
+	ChallengeIds []string
+}
+
+type challenge struct {
+	Id              string
+	AuthorizationId string
+	Type            string
+	Token           string
+	Status          string
+	Validated       time.Time
+	Error           *acme.ProblemDetails
+}
+
+// 订单、授权及质询缓存在内存中，并在每次修改后同步持久化至数据库，服务重启后从数据库中恢复。
+type orderStore struct {
+	mtx            sync.RWMutex
+	repo           acmeServerOrderRepository
+	loadOnce       sync.Once
+	orders         map[string]*order
+	authorizations map[string]*authorization
+	challenges     map[string]*challenge
+
+	// 当前 [orderStore.Update] 中被访问过的订单，将在其结束时持久化。
+	dirty map[string]struct{}
+}
+
+// 持久化时订单及其关联的授权、质询作为一个整体保存。
+type orderSnapshot struct {
+	Order          *order           `json:"order"`
+	Authorizations []*authorization `json:"authorizations"`
+	Challenges     []*challenge     `json:"challenges"`
+}
+
+func newOrderStore(repo acmeServerOrderRepository) *orderStore {
+	return &orderStore{
+		repo:           repo,
+		orders:         make(map[string]*order),
+		authorizations: make(map[string]*authorization),
+		challenges:     make(map[string]*challenge),
+	}
+}
+
+// 在锁内读取或修改订单及其关联对象，修改将在返回前持久化。
+func (s *orderStore) Update(fn func() error) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.loadOnce.Do(s.load)
+
+	s.dirty = make(map[string]struct{})
+	defer func() { s.dirty = nil }()
+
+	err := fn()
+	if perr := s.persistDirty(); perr != nil {
+		app.GetLogger().Error("acme server: failed to persist orders", slog.Any("error", perr))
+		if err == nil {
+			err = perr
+		}
+	}
+
+	return err
+}
+
+func (s *orderStore) View(fn func() error) error {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	s.loadOnce.Do(s.load)
+
+	return fn()
+}
+
+func (s *orderStore) load() {
+	ctx := context.Background()
+	records, err := s.repo.ListWithExprs(ctx, dbx.NewExp(fmt.Sprintf("expires>DATETIME('now', '-%d seconds')", int(orderLifetime.Seconds()))))
+	if err != nil {
+		app.GetLogger().Error("acme server: failed to load orders", slog.Any("error", err))
+		return
+	}
+
+	for _, record := range records {
+		snapshot := &orderSnapshot{}
+		if data, err := json.Marshal(record.Content); err != nil {
+			continue
+		} else if err := json.Unmarshal(data, snapshot); err != nil || snapshot.Order == nil {
+			app.GetLogger().Warn(fmt.Sprintf("acme server: order #%s is malformed", record.OrderId))
+			continue
+		}
+
+		s.orders[snapshot.Order.Id] = snapshot.Order
+		for _, authz := range snapshot.Authorizations {
+			s.authorizations[authz.Id] = authz
+		}
+		for _, ch := range snapshot.Challenges {
+			s.challenges[ch.Id] = ch
+		}
+	}
+}
+
+func (s *orderStore) persistDirty() error {
+	ctx := context.Background()
+
+	var errs []error
+	for orderId := range s.dirty {
+		o, ok := s.orders[orderId]
+		if !ok {
+			continue
+		}
+
+		snapshot := &orderSnapshot{Order: o}
+		for _, authzId := range o.AuthorizationIds {
+			if authz, ok := s.authorizations[authzId]; ok {
+				snapshot.Authorizations = append(snapshot.Authorizations, authz)
+				for _, chId := range authz.ChallengeIds {
+					if ch, ok := s.challenges[chId]; ok {
+						snapshot.Challenges = append(snapshot.Challenges, ch)
+					}
+				}
+			}
+		}
+
+		content := make(map[string]any)
+		if data, err := json.Marshal(snapshot); err != nil {
+			errs = append(errs, err)
+			continue
+		} else if err := json.Unmarshal(data, &content); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		record := &domain.ACMEServerOrder{
+			OrderId:       o.Id,
+			AccountId:     o.AccountId,
+			Status:        o.Status,
+			Expires:       o.Expires,
+			CertificateId: o.CertificateId,
+			Content:       content,
+		}
+		if _, err := s.repo.Save(ctx, record); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save order #%s: %w", o.Id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *orderStore) markDirty(orderId string) {
+	if s.dirty != nil {
+		s.dirty[orderId] = struct{}{}
+	}
+}
+
+// 以下方法须在 [orderStore.Update] 或 [orderStore.View] 中调用。
+
+func (s *orderStore) addOrder(o *order, authzs []*authorization, chs []*challenge) {
+	s.purgeExpired()
+
+	s.orders[o.Id] = o
+	s.markDirty(o.Id)
+	for _, authz := range authzs {
+		s.authorizations[authz.Id] = authz
+	}
+	for _, ch := range chs {
+		s.challenges[ch.Id] = ch
+	}
+}
+
+func (s *orderStore) getOrder(id string) *order {
+	o, ok := s.orders[id]
+	if !ok {
+		return nil
+	}
+
+	s.markDirty(o.Id)
+
+	if o.Status != acme.StatusValid && o.Status != acme.StatusInvalid && time.Now().After(o.Expires) {
+		o.Status = acme.StatusInvalid
+	}
+
+	return o
+}
+
+func (s *orderStore) getAuthorization(id string) *authorization {
+	authz, ok := s.authorizations[id]
+	if !ok {
+		return nil
+	}
+
+	s.markDirty(authz.OrderId)
+
+	if authz.Status == acme.StatusPending && time.Now().After(authz.Expires) {
+		authz.Status = acme.StatusExpired
+	}
+
+	return authz
+}
+
+func (s *orderStore) getChallenge(id string) *challenge {
+	ch, ok := s.challenges[id]
+	if !ok {
+		return nil
+	}
+
+	if authz, ok := s.authorizations[ch.AuthorizationId]; ok {
+		s.markDirty(authz.OrderId)
+	}
+
+	return ch
+}
+
+func (s *orderStore) listOrdersByAccount(accountId string) []*order {
+	orders := make([]*order, 0)
+	for _, o := range s.orders {
+		if o.AccountId == accountId {
+			orders = append(orders, o)
+		}
+	}
+
+	return orders
+}
+
+// 根据授权状态刷新订单状态。
+func (s *orderStore) refreshOrderStatus(o *order) {
+	if o.Status != acme.StatusPending {
+		return
+	}
+
+	ready := true
+	for _, authzId := range o.AuthorizationIds {
+		authz := s.getAuthorization(authzId)
+		if authz == nil {
+			o.Status = acme.StatusInvalid
+			return
+		}
+
+		switch authz.Status {
+		case acme.StatusValid:
+			continue
+		case acme.StatusPending:
+			ready = false
+		default:
+			o.Status = acme.StatusInvalid
+			return
+		}
+	}
+
+	if ready {
+		o.Status = acme.StatusReady
+	}
+}
+
+func (s *orderStore) purgeExpired() {
+	// 过期后保留一段时间，以便客户端仍可查询到订单的最终状态
+	deadline := time.Now().Add(-orderLifetime)
+
+	for id, o := range s.orders {
+		if o.Expires.After(deadline) {
+			continue
+		}
+
+		for _, authzId := range o.AuthorizationIds {
+			if authz, ok := s.authorizations[authzId]; ok {
+				for _, chId := range authz.ChallengeIds {
+					delete(s.challenges, chId)
+				}
+			}
+
+			delete(s.authorizations, authzId)
+		}
+
+		delete(s.orders, id)
+	}
+
+	// 已签发证书的订单需保留至证书过期，以便其所属账户仍可下载证书
+	_, err := s.repo.DeleteWithExprs(context.Background(),
+		dbx.NewExp(fmt.Sprintf("expires<DATETIME('now', '-%d seconds')", int(orderLifetime.Seconds()))),
+		dbx.NewExp("(certificateRef='' OR certificateRef NOT IN (SELECT id FROM certificate WHERE validityNotAfter>DATETIME('now')))"),
+	)
+	if err != nil {
+		app.GetLogger().Warn("acme server: failed to delete expired orders", slog.Any("error", err))
+	}
+}
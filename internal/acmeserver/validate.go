@@ -0,0 +1,210 @@
+package acmeserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/acme"
+	"github.com/go-acme/lego/v5/challenge/tlsalpn01"
+
+	"github.com/certimate-go/certimate/internal/app"
+)
+
+const (
+	challengeTypeHttp01    = "http-01"
+	challengeTypeDns01     = "dns-01"
+	challengeTypeTlsAlpn01 = "tls-alpn-01"
+)
+
+// 质询验证的超时时间。
+const challengeValidationTimeout = 30 * time.Second
+
+// id-pe-acmeIdentifier，参考 RFC 8737。
+var oidPeAcmeIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 31}
+
+func (s *ACMEServerService) validateChallenge(ch *challenge, authz *authorization, keyAuth string) {
+	ctx, cancel := context.WithTimeout(context.Background(), challengeValidationTimeout)
+	defer cancel()
+
+	var err error
+	switch ch.Type {
+	case challengeTypeHttp01:
+		err = validateHttp01(ctx, authz.Identifier, ch.Token, keyAuth)
+	case challengeTypeDns01:
+		err = validateDns01(ctx, authz.Identifier, keyAuth)
+	case challengeTypeTlsAlpn01:
+		err = validateTlsAlpn01(ctx, authz.Identifier, keyAuth)
+	default:
+		err = newProblem(acme.MalformedErrorType, http.StatusBadRequest, "the challenge type '%s' is not supported", ch.Type)
+	}
+
+	s.orders.Update(func() error {
+		if err != nil {
+			problem := &acme.ProblemDetails{}
+			if !errors.As(err, &problem) {
+				problem = newProblem(acme.IncorrectResponseErrorType, http.StatusForbidden, "%s", err.Error())
+			}
+
+			ch.Status = acme.StatusInvalid
+			ch.Error = problem
+			authz.Status = acme.StatusInvalid
+
+			app.GetLogger().Warn(fmt.Sprintf("acme server: %s challenge for '%s' failed", ch.Type, authz.Identifier.Value), slog.Any("error", err))
+		} else {
+			ch.Status = acme.StatusValid
+			ch.Validated = time.Now().UTC()
+			authz.Status = acme.StatusValid
+		}
+
+		if o := s.orders.getOrder(authz.OrderId); o != nil {
+			s.orders.refreshOrderStatus(o)
+		}
+
+		return nil
+	})
+}
+
+func validateHttp01(ctx context.Context, identifier acme.Identifier, token, keyAuth string) error {
+	url := fmt.Sprintf("http://%s/.well-known/acme-challenge/%s", net.JoinHostPort(identifier.Value, "80"), token)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return newProblem(acme.ConnectionErrorType, http.StatusBadRequest, "failed to fetch '%s': %s", url, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newProblem(acme.UnauthorizedErrorType, http.StatusForbidden, "unexpected status code %d from '%s'", resp.StatusCode, url)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	if err != nil {
+		return newProblem(acme.ConnectionErrorType, http.StatusBadRequest, "failed to read response from '%s': %s", url, err.Error())
+	}
+
+	if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(string(body))), []byte(keyAuth)) != 1 {
+		return newProblem(acme.IncorrectResponseErrorType, http.StatusForbidden, "the key authorization from '%s' is incorrect", url)
+	}
+
+	return nil
+}
+
+func validateDns01(ctx context.Context, identifier acme.Identifier, keyAuth string) error {
+	fqdn := "_acme-challenge." + identifier.Value
+
+	txts, err := net.DefaultResolver.LookupTXT(ctx, fqdn)
+	if err != nil {
+		return newProblem(acme.DNSErrorType, http.StatusBadRequest, "failed to lookup TXT records of '%s': %s", fqdn, err.Error())
+	}
+
+	digest := sha256.Sum256([]byte(keyAuth))
+	expected := base64.RawURLEncoding.EncodeToString(digest[:])
+	if !slices.Contains(txts, expected) {
+		return newProblem(acme.IncorrectResponseErrorType, http.StatusForbidden, "no matching TXT record found at '%s'", fqdn)
+	}
+
+	return nil
+}
+
+func validateTlsAlpn01(ctx context.Context, identifier acme.Identifier, keyAuth string) error {
+	serverName := identifier.Value
+	if identifier.Type == "ip" {
+		// 对于 IP 地址标识符，SNI 须为其反向解析名称，参考 RFC 8738
+		serverName = reverseAddr(net.ParseIP(identifier.Value))
+	}
+
+	dialer := &tls.Dialer{
+		Config: &tls.Config{
+			ServerName:         serverName,
+			NextProtos:         []string{tlsalpn01.ACMETLS1Protocol},
+			InsecureSkipVerify: true,
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(identifier.Value, "443"))
+	if err != nil {
+		return newProblem(acme.TLSErrorType, http.StatusBadRequest, "failed to connect to '%s': %s", identifier.Value, err.Error())
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	if state.NegotiatedProtocol != tlsalpn01.ACMETLS1Protocol {
+		return newProblem(acme.TLSErrorType, http.StatusForbidden, "the server did not negotiate '%s' protocol", tlsalpn01.ACMETLS1Protocol)
+	}
+	if len(state.PeerCertificates) == 0 {
+		return newProblem(acme.TLSErrorType, http.StatusForbidden, "the server did not present a certificate")
+	}
+
+	cert := state.PeerCertificates[0]
+	if !isCertificateForIdentifier(cert, identifier) {
+		return newProblem(acme.IncorrectResponseErrorType, http.StatusForbidden, "the challenge certificate is not issued for '%s'", identifier.Value)
+	}
+
+	digest := sha256.Sum256([]byte(keyAuth))
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidPeAcmeIdentifier) {
+			continue
+		}
+
+		if !ext.Critical {
+			return newProblem(acme.IncorrectResponseErrorType, http.StatusForbidden, "the acmeIdentifier extension is not critical")
+		}
+
+		var value []byte
+		if _, err := asn1.Unmarshal(ext.Value, &value); err != nil {
+			return newProblem(acme.IncorrectResponseErrorType, http.StatusForbidden, "the acmeIdentifier extension is malformed")
+		}
+
+		if subtle.ConstantTimeCompare(value, digest[:]) != 1 {
+			return newProblem(acme.IncorrectResponseErrorType, http.StatusForbidden, "the key authorization in acmeIdentifier extension is incorrect")
+		}
+
+		return nil
+	}
+
+	return newProblem(acme.IncorrectResponseErrorType, http.StatusForbidden, "the challenge certificate has no acmeIdentifier extension")
+}
+
+func isCertificateForIdentifier(cert *x509.Certificate, identifier acme.Identifier) bool {
+	switch identifier.Type {
+	case "dns":
+		return len(cert.DNSNames) == 1 && strings.EqualFold(cert.DNSNames[0], identifier.Value)
+	case "ip":
+		ip := net.ParseIP(identifier.Value)
+		return len(cert.IPAddresses) == 1 && cert.IPAddresses[0].Equal(ip)
+	}
+
+	return false
+}
+
+func reverseAddr(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", ip4[3], ip4[2], ip4[1], ip4[0])
+	}
+
+	const hexDigits = "0123456789abcdef"
+	buf := make([]string, 0, len(ip)*2)
+	for i := len(ip) - 1; i >= 0; i-- {
+		buf = append(buf, string(hexDigits[ip[i]&0xf]), string(hexDigits[ip[i]>>4]))
+	}
+
+	return strings.Join(buf, ".") + ".ip6.arpa"
+}
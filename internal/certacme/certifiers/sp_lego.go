@@ -0,0 +1,26 @@
+package certifiers
+
+import (
+	"fmt"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/pkg/core"
+	chlgimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/dns01/lego"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+func init() {
+	ACMEDns01Registries.MustRegister(domain.ACMEDns01ProviderTypeLego, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
+		credentials := domain.AccessConfigForLego{}
+		if err := xmaps.Populate(options.ProviderAccessConfig, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to populate provider access config: %w", err)
+		}
+
+		provider, err := chlgimpl.NewChallenger(&chlgimpl.ChallengerConfig{
+			ProviderName:          credentials.ProviderName,
+			Credentials:           credentials.Credentials,
+			DnsPropagationTimeout: options.DnsPropagationTimeout,
+		})
+		return provider, err
+	})
+}
@@ -1,17 +1,68 @@
 package certifiers
 
 import (
+	"fmt"
+
+	"github.com/certimate-go/certimate/internal/dnsresponder"
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/pkg/core"
+	chlgdnsexecimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/dns01/exec"
+	chlgdnsimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/dns01/local"
+	chlgexecimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/http01/exec"
 	chlgimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/http01/local"
+	chlgtlsimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/tlsalpn01/local"
 	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
 )
 
 func init() {
+	ACMEDns01Registries.MustRegister(domain.ACMEDns01ProviderTypeLocal, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
+		if !dnsresponder.IsEnabled() {
+			return nil, fmt.Errorf("the built-in dns responder is not enabled")
+		}
+
+		store, err := dnsresponder.GetRecordStore()
+		if err != nil {
+			return nil, err
+		}
+
+		provider, err := chlgdnsimpl.NewChallenger(&chlgdnsimpl.ChallengerConfig{
+			Zone:                  dnsresponder.GetZone(),
+			Store:                 store,
+			DnsPropagationTimeout: options.DnsPropagationTimeout,
+		})
+		return provider, err
+	})
+
+	ACMEDns01Registries.MustRegister(domain.ACMEDns01ProviderTypeLocalExec, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
+		provider, err := chlgdnsexecimpl.NewChallenger(&chlgdnsexecimpl.ChallengerConfig{
+			ShellEnv:              xmaps.GetString(options.ProviderExtendedConfig, "shellEnv"),
+			Command:               xmaps.GetString(options.ProviderExtendedConfig, "command"),
+			CommandTimeout:        xmaps.GetInt(options.ProviderExtendedConfig, "commandTimeout"),
+			DnsPropagationTimeout: options.DnsPropagationTimeout,
+		})
+		return provider, err
+	})
+
 	ACMEHttp01Registries.MustRegister(domain.ACMEHttp01ProviderTypeLocal, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
 		provider, err := chlgimpl.NewChallenger(&chlgimpl.ChallengerConfig{
 			WebRootPath: xmaps.GetString(options.ProviderExtendedConfig, "webRootPath"),
 		})
 		return provider, err
 	})
+
+	ACMEHttp01Registries.MustRegister(domain.ACMEHttp01ProviderTypeLocalExec, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
+		provider, err := chlgexecimpl.NewChallenger(&chlgexecimpl.ChallengerConfig{
+			ShellEnv:       xmaps.GetString(options.ProviderExtendedConfig, "shellEnv"),
+			Command:        xmaps.GetString(options.ProviderExtendedConfig, "command"),
+			CommandTimeout: xmaps.GetInt(options.ProviderExtendedConfig, "commandTimeout"),
+		})
+		return provider, err
+	})
+
+	ACMETlsAlpn01Registries.MustRegister(domain.ACMETlsAlpn01ProviderTypeLocal, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
+		provider, err := chlgtlsimpl.NewChallenger(&chlgtlsimpl.ChallengerConfig{
+			ListenAddress: xmaps.GetString(options.ProviderExtendedConfig, "listenAddress"),
+		})
+		return provider, err
+	})
 }
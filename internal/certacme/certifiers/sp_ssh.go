@@ -5,11 +5,51 @@ import (
 
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/pkg/core"
+	chlgdnsexecimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/dns01/exec"
+	chlgexecimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/http01/exec"
 	chlgimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/http01/ssh"
+	chlgtlsimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/tlsalpn01/ssh"
 	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
 )
 
 func init() {
+	ACMEDns01Registries.MustRegister(domain.ACMEDns01ProviderTypeSSHExec, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
+		credentials := domain.AccessConfigForSSH{}
+		if err := xmaps.Populate(options.ProviderAccessConfig, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to populate provider access config: %w", err)
+		}
+
+		jumpServers := make([]chlgdnsexecimpl.ServerConfig, len(credentials.JumpServers))
+		for i, jumpServer := range credentials.JumpServers {
+			jumpServers[i] = chlgdnsexecimpl.ServerConfig{
+				SshHost:          jumpServer.Host,
+				SshPort:          jumpServer.Port,
+				SshAuthMethod:    jumpServer.AuthMethod,
+				SshUsername:      jumpServer.Username,
+				SshPassword:      jumpServer.Password,
+				SshKey:           jumpServer.Key,
+				SshKeyPassphrase: jumpServer.KeyPassphrase,
+			}
+		}
+
+		provider, err := chlgdnsexecimpl.NewChallenger(&chlgdnsexecimpl.ChallengerConfig{
+			ServerConfig: chlgdnsexecimpl.ServerConfig{
+				SshHost:          credentials.Host,
+				SshPort:          credentials.Port,
+				SshAuthMethod:    credentials.AuthMethod,
+				SshUsername:      credentials.Username,
+				SshPassword:      credentials.Password,
+				SshKey:           credentials.Key,
+				SshKeyPassphrase: credentials.KeyPassphrase,
+			},
+			JumpServers:           jumpServers,
+			Command:               xmaps.GetString(options.ProviderExtendedConfig, "command"),
+			CommandTimeout:        xmaps.GetInt(options.ProviderExtendedConfig, "commandTimeout"),
+			DnsPropagationTimeout: options.DnsPropagationTimeout,
+		})
+		return provider, err
+	})
+
 	ACMEHttp01Registries.MustRegister(domain.ACMEHttp01ProviderTypeSSH, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
 		credentials := domain.AccessConfigForSSH{}
 		if err := xmaps.Populate(options.ProviderAccessConfig, &credentials); err != nil {
@@ -45,4 +85,75 @@ func init() {
 		})
 		return provider, err
 	})
+
+	ACMEHttp01Registries.MustRegister(domain.ACMEHttp01ProviderTypeSSHExec, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
+		credentials := domain.AccessConfigForSSH{}
+		if err := xmaps.Populate(options.ProviderAccessConfig, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to populate provider access config: %w", err)
+		}
+
+		jumpServers := make([]chlgexecimpl.ServerConfig, len(credentials.JumpServers))
+		for i, jumpServer := range credentials.JumpServers {
+			jumpServers[i] = chlgexecimpl.ServerConfig{
+				SshHost:          jumpServer.Host,
+				SshPort:          jumpServer.Port,
+				SshAuthMethod:    jumpServer.AuthMethod,
+				SshUsername:      jumpServer.Username,
+				SshPassword:      jumpServer.Password,
+				SshKey:           jumpServer.Key,
+				SshKeyPassphrase: jumpServer.KeyPassphrase,
+			}
+		}
+
+		provider, err := chlgexecimpl.NewChallenger(&chlgexecimpl.ChallengerConfig{
+			ServerConfig: chlgexecimpl.ServerConfig{
+				SshHost:          credentials.Host,
+				SshPort:          credentials.Port,
+				SshAuthMethod:    credentials.AuthMethod,
+				SshUsername:      credentials.Username,
+				SshPassword:      credentials.Password,
+				SshKey:           credentials.Key,
+				SshKeyPassphrase: credentials.KeyPassphrase,
+			},
+			JumpServers:    jumpServers,
+			Command:        xmaps.GetString(options.ProviderExtendedConfig, "command"),
+			CommandTimeout: xmaps.GetInt(options.ProviderExtendedConfig, "commandTimeout"),
+		})
+		return provider, err
+	})
+
+	ACMETlsAlpn01Registries.MustRegister(domain.ACMETlsAlpn01ProviderTypeSSH, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
+		credentials := domain.AccessConfigForSSH{}
+		if err := xmaps.Populate(options.ProviderAccessConfig, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to populate provider access config: %w", err)
+		}
+
+		jumpServers := make([]chlgtlsimpl.ServerConfig, len(credentials.JumpServers))
+		for i, jumpServer := range credentials.JumpServers {
+			jumpServers[i] = chlgtlsimpl.ServerConfig{
+				SshHost:          jumpServer.Host,
+				SshPort:          jumpServer.Port,
+				SshAuthMethod:    jumpServer.AuthMethod,
+				SshUsername:      jumpServer.Username,
+				SshPassword:      jumpServer.Password,
+				SshKey:           jumpServer.Key,
+				SshKeyPassphrase: jumpServer.KeyPassphrase,
+			}
+		}
+
+		provider, err := chlgtlsimpl.NewChallenger(&chlgtlsimpl.ChallengerConfig{
+			ServerConfig: chlgtlsimpl.ServerConfig{
+				SshHost:          credentials.Host,
+				SshPort:          credentials.Port,
+				SshAuthMethod:    credentials.AuthMethod,
+				SshUsername:      credentials.Username,
+				SshPassword:      credentials.Password,
+				SshKey:           credentials.Key,
+				SshKeyPassphrase: credentials.KeyPassphrase,
+			},
+			JumpServers:         jumpServers,
+			RemoteListenAddress: xmaps.GetString(options.ProviderExtendedConfig, "remoteListenAddress"),
+		})
+		return provider, err
+	})
 }
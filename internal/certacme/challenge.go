@@ -0,0 +1,301 @@
+package certacme
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/acme"
+	"github.com/go-acme/lego/v5/acme/api"
+	"github.com/go-acme/lego/v5/certificate"
+	"github.com/go-acme/lego/v5/challenge"
+	"github.com/go-acme/lego/v5/challenge/dns01"
+	"github.com/go-acme/lego/v5/challenge/http01"
+	"github.com/go-acme/lego/v5/challenge/resolver"
+	"github.com/go-acme/lego/v5/challenge/tlsalpn01"
+
+	"github.com/certimate-go/certimate/internal/certacme/certifiers"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+const (
+	challengeTypeDns01     = "dns-01"
+	challengeTypeHttp01    = "http-01"
+	challengeTypeTlsAlpn01 = "tls-alpn-01"
+)
+
+// 初始化质询所需的解析器，返回用于申请证书的 Certifier。
+// 若请求中未指定按域名分派的质询配置，则直接使用客户端默认的 Certifier；
+// 否则将按域名组合各质询提供商，并限定每个域名所使用的质询方式。
+func (c *ACMEClient) setupChallenges(request *ObtainCertificateRequest) (*certificate.Certifier, error) {
+	if len(request.DomainChallenges) == 0 {
+		provider, err := newChallengeProvider(request, request.ChallengeType, request.Provider, request.ProviderAccessConfig, request.ProviderExtendedConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.setChallengeSolver(request, request.ChallengeType, provider); err != nil {
+			return nil, err
+		}
+
+		return c.client.Certificate, nil
+	}
+
+	composites := make(map[string]*compositeChallengeProvider)
+	challengeTypes := make(map[string]string)
+	providers := make(map[int]challenge.Provider)
+	for _, domainOrIP := range request.DomainOrIPs {
+		identifier := strings.ToLower(strings.TrimPrefix(domainOrIP, "*."))
+
+		// 未匹配到的域名使用默认的质询配置，以索引 -1 表示
+		index := matchDomainChallenge(request.DomainChallenges, identifier)
+		challengeType := strings.ToLower(request.ChallengeType)
+		if index >= 0 {
+			challengeType = strings.ToLower(request.DomainChallenges[index].ChallengeType)
+		}
+
+		challengeTypes[identifier] = challengeType
+
+		provider, ok := providers[index]
+		if !ok {
+			var err error
+			if index >= 0 {
+				dc := request.DomainChallenges[index]
+				provider, err = newChallengeProvider(request, dc.ChallengeType, dc.Provider, dc.ProviderAccessConfig, dc.ProviderExtendedConfig)
+			} else {
+				provider, err = newChallengeProvider(request, request.ChallengeType, request.Provider, request.ProviderAccessConfig, request.ProviderExtendedConfig)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			providers[index] = provider
+		}
+
+		if _, ok := composites[challengeType]; !ok {
+			composites[challengeType] = &compositeChallengeProvider{providers: make(map[string]challenge.Provider)}
+		}
+		composites[challengeType].providers[identifier] = provider
+	}
+
+	for challengeType, composite := range composites {
+		if err := c.setChallengeSolver(request, challengeType, composite); err != nil {
+			return nil, err
+		}
+	}
+
+	// lego 会为每个授权自行挑选质询方式，这里需替换其解析器以限定各域名的质询方式
+	var kid string
+	if reg := c.config.User.GetRegistration(); reg != nil {
+		kid = reg.Location
+	}
+
+	core, err := api.New(c.config.HTTPClient, c.config.UserAgent, c.config.CADirURL, kid, c.config.User.GetPrivateKey())
+	if err != nil {
+		return nil, err
+	}
+
+	certifier := certificate.NewCertifier(core, &challengeTypeResolver{
+		prober:         resolver.NewProber(c.client.Challenge),
+		challengeTypes: challengeTypes,
+	}, certificate.CertifierOptions{
+		Timeout:             c.config.Certificate.Timeout,
+		OverallRequestLimit: c.config.Certificate.OverallRequestLimit,
+	})
+	return certifier, nil
+}
+
+func (c *ACMEClient) setChallengeSolver(request *ObtainCertificateRequest, challengeType string, provider challenge.Provider) error {
+	switch strings.ToLower(challengeType) {
+	case challengeTypeDns01:
+		opts := &dns01.Options{}
+		opts.RecursiveNameservers = request.Nameservers
+		dns01.SetDefaultClient(dns01.NewClient(opts))
+		return c.client.Challenge.SetDNS01Provider(provider,
+			dns01.CondOptions(
+				request.DnsPropagationWait > 0,
+				dns01.PropagationWait(time.Duration(request.DnsPropagationWait)*time.Second, true),
+			),
+			dns01.CondOptions(
+				len(request.Nameservers) > 0 || request.DnsPropagationWait > 0,
+				dns01.DisableAuthoritativeNssPropagationRequirement(),
+			),
+		)
+
+	case challengeTypeHttp01:
+		return c.client.Challenge.SetHTTP01Provider(provider,
+			http01.SetDelay(time.Duration(request.HttpDelayWait)*time.Second),
+		)
+
+	case challengeTypeTlsAlpn01:
+		return c.client.Challenge.SetTLSALPN01Provider(provider,
+			tlsalpn01.SetDelay(time.Duration(request.TlsAlpnDelayWait)*time.Second),
+		)
+	}
+
+	return fmt.Errorf("unsupported challenge type: '%s'", challengeType)
+}
+
+func newChallengeProvider(request *ObtainCertificateRequest, challengeType string, providerType domain.ACMEChallengeProviderType, accessConfig, extendedConfig map[string]any) (challenge.Provider, error) {
+	switch strings.ToLower(challengeType) {
+	case challengeTypeDns01:
+		providerFactory, err := certifiers.ACMEDns01Registries.Get(domain.ACMEDns01ProviderType(providerType))
+		if err != nil {
+			return nil, err
+		}
+
+		provider, err := providerFactory(&certifiers.ProviderFactoryOptions{
+			ProviderAccessConfig:   accessConfig,
+			ProviderExtendedConfig: extendedConfig,
+			DnsPropagationTimeout:  request.DnsPropagationTimeout,
+			DnsTTL:                 request.DnsTTL,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize dns-01 provider '%s': %w", providerType, err)
+		}
+
+		return provider, nil
+
+	case challengeTypeHttp01:
+		providerFactory, err := certifiers.ACMEHttp01Registries.Get(domain.ACMEHttp01ProviderType(providerType))
+		if err != nil {
+			return nil, err
+		}
+
+		provider, err := providerFactory(&certifiers.ProviderFactoryOptions{
+			ProviderAccessConfig:   accessConfig,
+			ProviderExtendedConfig: extendedConfig,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize http-01 provider '%s': %w", providerType, err)
+		}
+
+		return provider, nil
+
+	case challengeTypeTlsAlpn01:
+		providerFactory, err := certifiers.ACMETlsAlpn01Registries.Get(domain.ACMETlsAlpn01ProviderType(providerType))
+		if err != nil {
+			return nil, err
+		}
+
+		provider, err := providerFactory(&certifiers.ProviderFactoryOptions{
+			ProviderAccessConfig:   accessConfig,
+			ProviderExtendedConfig: extendedConfig,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize tls-alpn-01 provider '%s': %w", providerType, err)
+		}
+
+		return provider, nil
+	}
+
+	return nil, fmt.Errorf("unsupported challenge type: '%s'", challengeType)
+}
+
+// 查找与标识符匹配的质询配置，返回其索引；未找到时返回 -1。
+// 当存在多个匹配项时，优先选择最长（即最具体）的域名后缀。
+func matchDomainChallenge(domainChallenges []ObtainCertificateDomainChallenge, identifier string) int {
+	matched := -1
+	matchedLen := 0
+	for i, dc := range domainChallenges {
+		suffix := strings.ToLower(strings.TrimPrefix(dc.Domain, "*."))
+		if suffix == "" {
+			continue
+		}
+
+		if identifier == suffix || strings.HasSuffix(identifier, "."+suffix) {
+			if len(suffix) > matchedLen {
+				matched = i
+				matchedLen = len(suffix)
+			}
+		}
+	}
+
+	return matched
+}
+
+// 查找与域名匹配的质询配置；未找到时返回 nil。
+func MatchDomainChallenge(domainChallenges []ObtainCertificateDomainChallenge, domainOrIP string) *ObtainCertificateDomainChallenge {
+	index := matchDomainChallenge(domainChallenges, strings.ToLower(strings.TrimPrefix(domainOrIP, "*.")))
+	if index < 0 {
+		return nil
+	}
+
+	return &domainChallenges[index]
+}
+
+// 组合质询提供商，按域名将质询分派至对应的提供商。
+type compositeChallengeProvider struct {
+	providers map[string]challenge.Provider
+}
+
+var _ challenge.ProviderTimeout = (*compositeChallengeProvider)(nil)
+
+func (p *compositeChallengeProvider) Present(ctx context.Context, domain, token, keyAuth string) error {
+	provider, err := p.lookup(domain)
+	if err != nil {
+		return err
+	}
+
+	return provider.Present(ctx, domain, token, keyAuth)
+}
+
+func (p *compositeChallengeProvider) CleanUp(ctx context.Context, domain, token, keyAuth string) error {
+	provider, err := p.lookup(domain)
+	if err != nil {
+		return err
+	}
+
+	return provider.CleanUp(ctx, domain, token, keyAuth)
+}
+
+// 取各提供商中最长的超时时间。
+func (p *compositeChallengeProvider) Timeout() (timeout, interval time.Duration) {
+	timeout, interval = dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+	for _, provider := range p.providers {
+		if t, ok := provider.(challenge.ProviderTimeout); ok {
+			pt, pi := t.Timeout()
+			timeout = max(timeout, pt)
+			interval = max(interval, pi)
+		}
+	}
+
+	return timeout, interval
+}
+
+func (p *compositeChallengeProvider) lookup(domain string) (challenge.Provider, error) {
+	identifier := strings.ToLower(strings.TrimPrefix(domain, "*."))
+	if provider, ok := p.providers[identifier]; ok {
+		return provider, nil
+	}
+
+	return nil, fmt.Errorf("no challenge provider for '%s'", domain)
+}
+
+// 限定各域名质询方式的解析器。
+// 在交由 lego 挑选质询方式前，移除授权中不属于该域名所指定方式的质询。
+type challengeTypeResolver struct {
+	prober         *resolver.Prober
+	challengeTypes map[string]string
+}
+
+func (r *challengeTypeResolver) Solve(ctx context.Context, authorizations []acme.Authorization) error {
+	filtered := make([]acme.Authorization, 0, len(authorizations))
+	for _, authz := range authorizations {
+		challengeType, ok := r.challengeTypes[strings.ToLower(authz.Identifier.Value)]
+		if ok {
+			challenges := make([]acme.Challenge, 0, len(authz.Challenges))
+			for _, chlg := range authz.Challenges {
+				if chlg.Type == challengeType {
+					challenges = append(challenges, chlg)
+				}
+			}
+			authz.Challenges = challenges
+		}
+
+		filtered = append(filtered, authz)
+	}
+
+	return r.prober.Solve(ctx, filtered)
+}
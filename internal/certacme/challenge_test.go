@@ -0,0 +1,46 @@
+package certacme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchDomainChallenge(t *testing.T) {
+	domainChallenges := []ObtainCertificateDomainChallenge{
+		{Domain: "example.com"},
+		{Domain: "*.sub.example.com"},
+		{Domain: "Example.ORG"},
+		{Domain: ""},
+		{Domain: "deep.sub.example.com"},
+	}
+
+	testCases := []struct {
+		domainOrIP string
+		expected   int
+	}{
+		{"example.com", 0},
+		{"www.example.com", 0},
+		{"*.example.com", 0},
+		{"sub.example.com", 1},
+		{"a.sub.example.com", 1},
+		{"*.sub.example.com", 1},
+		{"deep.sub.example.com", 4},
+		{"a.deep.sub.example.com", 4},
+		{"example.org", 2},
+		{"WWW.EXAMPLE.ORG", 2},
+		{"badexample.com", -1},
+		{"example.net", -1},
+		{"127.0.0.1", -1},
+		{"", -1},
+	}
+
+	for _, tc := range testCases {
+		matched := MatchDomainChallenge(domainChallenges, tc.domainOrIP)
+		if tc.expected < 0 {
+			assert.Nil(t, matched, "DomainOrIP: %-20s", tc.domainOrIP)
+		} else {
+			assert.Same(t, &domainChallenges[tc.expected], matched, "DomainOrIP: %-20s", tc.domainOrIP)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 type ACMEClient struct {
 	client  *lego.Client
+	config  *lego.Config
 	account *ACMEAccount
 }
 
@@ -63,6 +64,7 @@ func newACMEClientWithAccount(account *ACMEAccount, configures ...func(*lego.Con
 
 	return &ACMEClient{
 		client:  legoClient,
+		config:  legoCfg,
 		account: account,
 	}, nil
 }
@@ -13,12 +13,9 @@ import (
 	"github.com/go-acme/lego/v5/acme"
 	"github.com/go-acme/lego/v5/certcrypto"
 	"github.com/go-acme/lego/v5/certificate"
-	"github.com/go-acme/lego/v5/challenge/dns01"
-	"github.com/go-acme/lego/v5/challenge/http01"
 	"github.com/go-acme/lego/v5/log"
 	"github.com/samber/lo"
 
-	"github.com/certimate-go/certimate/internal/certacme/certifiers"
 	"github.com/certimate-go/certimate/internal/domain"
 	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
 )
@@ -31,12 +28,19 @@ type ObtainCertificateRequest struct {
 	ValidityNotAfter  time.Time
 	NoCommonName      bool
 
+	// 证书签名请求 PEM 内容。
+	// 非空时将以此完成订单，私钥相关字段将被忽略，且响应中不包含私钥。
+	CSRPEM string
+
 	// 提供商相关
 	ChallengeType          string
 	Provider               domain.ACMEChallengeProviderType
 	ProviderAccessConfig   map[string]any
 	ProviderExtendedConfig map[string]any
 
+	// 按域名分派的质询配置，未匹配的域名使用上述提供商
+	DomainChallenges []ObtainCertificateDomainChallenge
+
 	// 解析相关
 	DisableFollowCNAME bool
 	Nameservers        []string
@@ -49,6 +53,9 @@ type ObtainCertificateRequest struct {
 	// HTTP-01 质询相关
 	HttpDelayWait int
 
+	// TLS-ALPN-01 质询相关
+	TlsAlpnDelayWait int
+
 	// ACME 相关
 	PreferredChain string
 	ACMEProfile    string
@@ -58,6 +65,15 @@ type ObtainCertificateRequest struct {
 	ARIReplacesCertId     string
 }
 
+type ObtainCertificateDomainChallenge struct {
+	// 域名或区域后缀，匹配其自身及所有子域名
+	Domain                 string
+	ChallengeType          string
+	Provider               domain.ACMEChallengeProviderType
+	ProviderAccessConfig   map[string]any
+	ProviderExtendedConfig map[string]any
+}
+
 type ObtainCertificateResponse struct {
 	CAProvider           domain.CAProviderType
 	CSR                  string
@@ -76,63 +92,13 @@ func (c *ACMEClient) ObtainCertificate(ctx context.Context, request *ObtainCerti
 
 	os.Setenv("LEGO_DISABLE_CNAME_SUPPORT", strconv.FormatBool(request.DisableFollowCNAME))
 
-	const CHALLENGE_TYPE_DNS01 = "dns-01"
-	const CHALLENGE_TYPE_HTTP01 = "http-01"
-	switch strings.ToLower(request.ChallengeType) {
-	case CHALLENGE_TYPE_DNS01:
-		{
-			providerFactory, err := certifiers.ACMEDns01Registries.Get(domain.ACMEDns01ProviderType(request.Provider))
-			if err != nil {
-				return nil, err
-			}
-
-			provider, err := providerFactory(&certifiers.ProviderFactoryOptions{
-				ProviderAccessConfig:   request.ProviderAccessConfig,
-				ProviderExtendedConfig: request.ProviderExtendedConfig,
-				DnsPropagationTimeout:  request.DnsPropagationTimeout,
-				DnsTTL:                 request.DnsTTL,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("failed to initialize dns-01 provider '%s': %w", request.Provider, err)
-			}
-
-			opts := &dns01.Options{}
-			opts.RecursiveNameservers = request.Nameservers
-			dns01.SetDefaultClient(dns01.NewClient(opts))
-			c.client.Challenge.SetDNS01Provider(provider,
-				dns01.CondOptions(
-					request.DnsPropagationWait > 0,
-					dns01.PropagationWait(time.Duration(request.DnsPropagationWait)*time.Second, true),
-				),
-				dns01.CondOptions(
-					len(request.Nameservers) > 0 || request.DnsPropagationWait > 0,
-					dns01.DisableAuthoritativeNssPropagationRequirement(),
-				),
-			)
-		}
-
-	case CHALLENGE_TYPE_HTTP01:
-		{
-			providerFactory, err := certifiers.ACMEHttp01Registries.Get(domain.ACMEHttp01ProviderType(request.Provider))
-			if err != nil {
-				return nil, err
-			}
-
-			provider, err := providerFactory(&certifiers.ProviderFactoryOptions{
-				ProviderAccessConfig:   request.ProviderAccessConfig,
-				ProviderExtendedConfig: request.ProviderExtendedConfig,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("failed to initialize http-01 provider '%s': %w", request.Provider, err)
-			}
-
-			c.client.Challenge.SetHTTP01Provider(provider,
-				http01.SetDelay(time.Duration(request.HttpDelayWait)*time.Second),
-			)
-		}
+	certifier, err := c.setupChallenges(request)
+	if err != nil {
+		return nil, err
+	}
 
-	default:
-		return nil, fmt.Errorf("unsupported challenge type: '%s'", request.ChallengeType)
+	if request.CSRPEM != "" {
+		return c.obtainCertificateForCSR(ctx, certifier, request)
 	}
 
 	var privkey crypto.Signer
@@ -157,7 +123,7 @@ func (c *ACMEClient) ObtainCertificate(ctx context.Context, request *ObtainCerti
 		NotAfter:         request.ValidityNotAfter,
 		ReplacesCertID:   lo.If(request.ARIReplacesAccountUrl == c.account.ACMEAccountUrl, request.ARIReplacesCertId).Else(""),
 	}
-	resp, err := c.client.Certificate.Obtain(ctx, req)
+	resp, err := certifier.Obtain(ctx, req)
 	if err != nil {
 		ariErr := &acme.AlreadyReplacedError{}
 		if !errors.As(err, &ariErr) {
@@ -168,7 +134,7 @@ func (c *ACMEClient) ObtainCertificate(ctx context.Context, request *ObtainCerti
 
 		// reset ARI and retry if failure
 		req.ReplacesCertID = ""
-		resp, err = c.client.Certificate.Obtain(ctx, req)
+		resp, err = certifier.Obtain(ctx, req)
 		if err != nil {
 			return nil, err
 		}
@@ -194,3 +160,80 @@ func (c *ACMEClient) ObtainCertificate(ctx context.Context, request *ObtainCerti
 		ARIReplaced:          req.ReplacesCertID != "",
 	}, nil
 }
+
+func (c *ACMEClient) obtainCertificateForCSR(ctx context.Context, certifier *certificate.Certifier, request *ObtainCertificateRequest) (*ObtainCertificateResponse, error) {
+	csr, err := certcrypto.PemDecodeTox509CSR([]byte(request.CSRPEM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csr: %w", err)
+	}
+
+	req := certificate.ObtainForCSRRequest{
+		CSR:              csr,
+		Bundle:           true,
+		EnableCommonName: !request.NoCommonName,
+		PreferredChain:   request.PreferredChain,
+		Profile:          request.ACMEProfile,
+		NotBefore:        request.ValidityNotBefore,
+		NotAfter:         request.ValidityNotAfter,
+		ReplacesCertID:   lo.If(request.ARIReplacesAccountUrl == c.account.ACMEAccountUrl, request.ARIReplacesCertId).Else(""),
+	}
+	resp, err := certifier.ObtainForCSR(ctx, req)
+	if err != nil {
+		ariErr := &acme.AlreadyReplacedError{}
+		if !errors.As(err, &ariErr) {
+			return nil, err
+		}
+
+		log.Warn("the certificate has already been replaced, try to obtain again without ARI ...")
+
+		// reset ARI and retry if failure
+		req.ReplacesCertID = ""
+		resp, err = certifier.ObtainForCSR(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ObtainCertificateResponse{
+		CAProvider:           domain.CAProviderType(c.account.CA),
+		CSR:                  strings.TrimSpace(request.CSRPEM),
+		FullChainCertificate: strings.TrimSpace(string(resp.Certificate)),
+		IssuerCertificate:    strings.TrimSpace(string(resp.IssuerCertificate)),
+		ACMEAccountUrl:       c.account.ACMEAccountUrl,
+		ACMECertificateUrl:   resp.CertURL,
+		ARIReplaced:          req.ReplacesCertID != "",
+	}, nil
+}
+
+// 校验证书申请请求，仅初始化质询提供商而不实际向 CA 申请证书。
+func ValidateObtainCertificateRequest(request *ObtainCertificateRequest) error {
+	if request == nil {
+		return fmt.Errorf("the request is nil")
+	}
+
+	if _, err := newChallengeProvider(request, request.ChallengeType, request.Provider, request.ProviderAccessConfig, request.ProviderExtendedConfig); err != nil {
+		return err
+	}
+
+	for _, dc := range request.DomainChallenges {
+		if _, err := newChallengeProvider(request, dc.ChallengeType, dc.Provider, dc.ProviderAccessConfig, dc.ProviderExtendedConfig); err != nil {
+			return fmt.Errorf("failed to initialize challenge provider for '%s': %w", dc.Domain, err)
+		}
+	}
+
+	if request.CSRPEM != "" {
+		csr, err := certcrypto.PemDecodeTox509CSR([]byte(request.CSRPEM))
+		if err != nil {
+			return fmt.Errorf("failed to parse csr: %w", err)
+		}
+		if err := csr.CheckSignature(); err != nil {
+			return fmt.Errorf("failed to verify csr signature: %w", err)
+		}
+	} else if request.PrivateKeyPEM != "" {
+		if _, err := certcrypto.ParsePEMPrivateKey([]byte(request.PrivateKeyPEM)); err != nil {
+			return fmt.Errorf("failed to parse private key: %w", err)
+		}
+	}
+
+	return nil
+}
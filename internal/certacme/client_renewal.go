@@ -0,0 +1,76 @@
+package certacme
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+type GetRenewalInfoRequest struct {
+	Certificate string
+}
+
+type GetRenewalInfoResponse struct {
+	SuggestedWindowStart time.Time
+	SuggestedWindowEnd   time.Time
+	ExplanationUrl       string
+	RetryAfter           time.Duration
+}
+
+func (c *ACMEClient) GetRenewalInfo(ctx context.Context, request *GetRenewalInfoRequest) (*GetRenewalInfoResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+
+	certX509, err := xcert.ParseCertificateFromPEM(request.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	// 若 CA 不支持 ARI，将返回 api.ErrNoARI
+	renewalInfo, err := c.client.Certificate.GetRenewalInfo(ctx, certX509)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetRenewalInfoResponse{
+		SuggestedWindowStart: renewalInfo.SuggestedWindow.Start,
+		SuggestedWindowEnd:   renewalInfo.SuggestedWindow.End,
+		ExplanationUrl:       renewalInfo.ExplanationURL,
+		RetryAfter:           renewalInfo.RetryAfter,
+	}, nil
+}
+
+// 查询证书的 ARI 续期信息。
+// ARI 端点无需鉴权，因此使用临时密钥初始化客户端，不会注册或读取 ACME 账户。
+func GetRenewalInfo(ctx context.Context, config *ACMEConfig, request *GetRenewalInfoRequest) (*GetRenewalInfoResponse, error) {
+	if config == nil {
+		return nil, fmt.Errorf("the acme config is nil")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := xcert.ConvertECPrivateKeyToPEM(key, false)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newACMEClientWithAccount(&ACMEAccount{
+		CA:               config.CAProvider.String(),
+		PrivateKey:       keyPEM,
+		ACMEDirectoryUrl: config.CADirUrl,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetRenewalInfo(ctx, request)
+}
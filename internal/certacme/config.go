@@ -17,10 +17,11 @@ type ACMEConfigOptions struct {
 }
 
 type ACMEConfig struct {
-	CAProvider domain.CAProviderType
-	CADirUrl   string
-	EABKid     string
-	EABHmacKey string
+	CAProvider             domain.CAProviderType
+	CAProviderAccessConfig map[string]any
+	CADirUrl               string
+	EABKid                 string
+	EABHmacKey             string
 }
 
 func CreateACMEConfig(ctx context.Context, options *ACMEConfigOptions) (*ACMEConfig, error) {
@@ -42,6 +43,14 @@ func CreateACMEConfig(ctx context.Context, options *ACMEConfigOptions) (*ACMECon
 		provider = domain.CAProviderTypeLetsEncrypt
 	}
 
+	// 本地 CA 不经由 ACME 协议签发证书，只需返回解析后的提供商及其授权配置
+	if provider == domain.CAProviderTypeLocalCA {
+		return &ACMEConfig{
+			CAProvider:             provider,
+			CAProviderAccessConfig: providerAccessCfg,
+		}, nil
+	}
+
 	acmeDirUrl, err := getCADirUrl(provider, providerAccessCfg, options.CertifierKeyAlgorithm)
 	if err != nil {
 		return nil, err
@@ -53,9 +62,10 @@ func CreateACMEConfig(ctx context.Context, options *ACMEConfigOptions) (*ACMECon
 	}
 
 	return &ACMEConfig{
-		CAProvider: provider,
-		CADirUrl:   acmeDirUrl,
-		EABKid:     acmeEab.EabKid,
-		EABHmacKey: acmeEab.EabHmacKey,
+		CAProvider:             provider,
+		CAProviderAccessConfig: providerAccessCfg,
+		CADirUrl:               acmeDirUrl,
+		EABKid:                 acmeEab.EabKid,
+		EABHmacKey:             acmeEab.EabHmacKey,
 	}, nil
 }
@@ -0,0 +1,395 @@
+package certacme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/acme"
+	"github.com/go-resty/resty/v2"
+	"github.com/miekg/dns"
+
+	"github.com/certimate-go/certimate/internal/app"
+)
+
+const (
+	PreflightCheckCAA     = "caa"
+	PreflightCheckCNAME   = "cname"
+	PreflightCheckResolve = "resolve"
+)
+
+const (
+	PreflightLevelOK    = "ok"
+	PreflightLevelWarn  = "warn"
+	PreflightLevelError = "error"
+)
+
+// 单次 DNS 查询的超时时间。
+const preflightQueryTimeout = 5 * time.Second
+
+// 跟随 CNAME 的最大次数。
+const preflightMaxCNAMEHops = 16
+
+type PreflightRequest struct {
+	// ACME 服务目录地址，用于获取 CA 在 CAA 记录中的标识符。
+	CADirUrl string
+	// ACME 账户地址，用于校验 CAA 记录中的 accounturi 参数。
+	// 零值时跳过此项校验。
+	ACMEAccountUrl string
+	// 待预检的证书申请请求。
+	ObtainRequest *ObtainCertificateRequest
+}
+
+type PreflightResponse struct {
+	Diagnostics []PreflightDiagnostic `json:"diagnostics"`
+}
+
+type PreflightDiagnostic struct {
+	Identifier string `json:"identifier"`
+	Check      string `json:"check"`
+	Level      string `json:"level"`
+	Message    string `json:"message"`
+}
+
+// 返回所有错误级别的诊断项合并后的错误；无错误时返回 nil。
+func (r *PreflightResponse) Err() error {
+	errs := make([]error, 0)
+	for _, diag := range r.Diagnostics {
+		if diag.Level == PreflightLevelError {
+			errs = append(errs, fmt.Errorf("[%s] %s: %s", diag.Check, diag.Identifier, diag.Message))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *PreflightResponse) add(identifier, check, level, format string, args ...any) {
+	r.Diagnostics = append(r.Diagnostics, PreflightDiagnostic{
+		Identifier: identifier,
+		Check:      check,
+		Level:      level,
+		Message:    fmt.Sprintf(format, args...),
+	})
+}
+
+// 在申请证书前进行预检，以尽早发现会导致订单失败的配置问题。
+// 包括：
+//   - 按 RFC 8659 的规则逐级向上查找 CAA 记录，校验其是否允许所选 CA 签发证书；
+//   - 对于 DNS-01 质询，跟随 `_acme-challenge` 的 CNAME 链并检查是否存在循环；
+//   - 对于 HTTP-01、TLS-ALPN-01 质询，检查域名是否能解析到 IP 地址。
+//
+// 诊断结果通过返回值给出，仅在无法进行预检时才返回错误。
+func Preflight(ctx context.Context, request *PreflightRequest) (*PreflightResponse, error) {
+	if request == nil || request.ObtainRequest == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+
+	obtainReq := request.ObtainRequest
+	resolver := newPreflightResolver(obtainReq.Nameservers)
+	response := &PreflightResponse{Diagnostics: make([]PreflightDiagnostic, 0)}
+
+	caaIdentities, err := fetchCAAIdentities(ctx, request.CADirUrl)
+	if err != nil {
+		response.add(request.CADirUrl, PreflightCheckCAA, PreflightLevelWarn, "failed to fetch acme directory, caa check skipped: %s", err.Error())
+	} else if len(caaIdentities) == 0 {
+		response.add(request.CADirUrl, PreflightCheckCAA, PreflightLevelWarn, "the acme directory does not declare caa identities, caa check skipped")
+	}
+
+	for _, domainOrIP := range obtainReq.DomainOrIPs {
+		if net.ParseIP(domainOrIP) != nil {
+			continue
+		}
+
+		challengeType := obtainReq.ChallengeType
+		if dc := MatchDomainChallenge(obtainReq.DomainChallenges, domainOrIP); dc != nil {
+			challengeType = dc.ChallengeType
+		}
+		challengeType = strings.ToLower(challengeType)
+
+		if len(caaIdentities) > 0 {
+			preflightCheckCAA(ctx, resolver, response, domainOrIP, caaIdentities, challengeType, request.ACMEAccountUrl)
+		}
+
+		switch challengeType {
+		case challengeTypeDns01:
+			if !obtainReq.DisableFollowCNAME {
+				preflightCheckCNAME(ctx, resolver, response, domainOrIP)
+			}
+
+		case challengeTypeHttp01, challengeTypeTlsAlpn01:
+			preflightCheckResolve(ctx, resolver, response, domainOrIP, challengeType)
+		}
+	}
+
+	return response, nil
+}
+
+func fetchCAAIdentities(ctx context.Context, caDirUrl string) ([]string, error) {
+	if caDirUrl == "" {
+		return nil, fmt.Errorf("the acme directory url is empty")
+	}
+
+	directory := acme.Directory{}
+	resp, err := resty.New().
+		SetTimeout(30*time.Second).
+		SetHeader("User-Agent", app.AppUserAgent).
+		R().
+		SetContext(ctx).
+		SetResult(&directory).
+		Get(caDirUrl)
+	if err != nil {
+		return nil, err
+	} else if resp.IsError() {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode())
+	}
+
+	return directory.Meta.CaaIdentities, nil
+}
+
+func preflightCheckCAA(ctx context.Context, resolver *preflightResolver, response *PreflightResponse, domainOrIP string, caaIdentities []string, challengeType, accountUrl string) {
+	wildcard := strings.HasPrefix(domainOrIP, "*.")
+	name := dns.Fqdn(strings.TrimPrefix(domainOrIP, "*."))
+
+	// 逐级向上查找，以最先找到的非空 CAA 记录集为准
+	var records []*dns.CAA
+	var recordsAt string
+	for _, index := range dns.Split(name) {
+		current := name[index:]
+		msg, err := resolver.query(ctx, current, dns.TypeCAA)
+		if err != nil {
+			response.add(domainOrIP, PreflightCheckCAA, PreflightLevelError, "failed to lookup CAA records at '%s': %s", current, err.Error())
+			return
+		}
+		if msg.Rcode != dns.RcodeSuccess && msg.Rcode != dns.RcodeNameError {
+			// CA 在 CAA 查询失败时会拒绝签发
+			response.add(domainOrIP, PreflightCheckCAA, PreflightLevelError, "failed to lookup CAA records at '%s': %s", current, dns.RcodeToString[msg.Rcode])
+			return
+		}
+
+		for _, rr := range msg.Answer {
+			if caa, ok := rr.(*dns.CAA); ok {
+				records = append(records, caa)
+			}
+		}
+		if len(records) > 0 {
+			recordsAt = current
+			break
+		}
+	}
+
+	if len(records) == 0 {
+		response.add(domainOrIP, PreflightCheckCAA, PreflightLevelOK, "no CAA records found, any CA is permitted")
+		return
+	}
+
+	if err := evaluateCAA(records, caaIdentities, wildcard, challengeType, accountUrl); err != nil {
+		tag := "issue"
+		if wildcard {
+			tag = "issuewild"
+		}
+		response.add(domainOrIP, PreflightCheckCAA, PreflightLevelError, "the CAA records at '%s' do not permit this CA: %s; consider adding the record: %s CAA 0 %s \"%s\"", recordsAt, err.Error(), recordsAt, tag, caaIdentities[0])
+		return
+	}
+
+	response.add(domainOrIP, PreflightCheckCAA, PreflightLevelOK, "the CAA records at '%s' permit this CA", recordsAt)
+}
+
+func evaluateCAA(records []*dns.CAA, caaIdentities []string, wildcard bool, challengeType, accountUrl string) error {
+	issues := make([]*dns.CAA, 0)
+	issuewilds := make([]*dns.CAA, 0)
+	for _, record := range records {
+		switch strings.ToLower(record.Tag) {
+		case "issue":
+			issues = append(issues, record)
+		case "issuewild":
+			issuewilds = append(issuewilds, record)
+		case "iodef", "issuemail", "issuevmc":
+		default:
+			if record.Flag&128 != 0 {
+				return fmt.Errorf("unknown critical property '%s'", record.Tag)
+			}
+		}
+	}
+
+	relevant := issues
+	if wildcard && len(issuewilds) > 0 {
+		relevant = issuewilds
+	}
+	if len(relevant) == 0 {
+		return nil
+	}
+
+	var reason string
+	for _, record := range relevant {
+		parts := strings.Split(record.Value, ";")
+		issuer := strings.TrimSpace(parts[0])
+		if !slices.ContainsFunc(caaIdentities, func(identity string) bool { return strings.EqualFold(identity, issuer) }) {
+			continue
+		}
+
+		// 校验 RFC 8657 中定义的参数
+		permitted := true
+		for _, param := range parts[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "validationmethods":
+				methods := strings.Split(strings.ToLower(value), ",")
+				if !slices.Contains(methods, challengeType) {
+					permitted = false
+					reason = fmt.Sprintf("validation method '%s' is not in '%s'", challengeType, value)
+				}
+
+			case "accounturi":
+				if accountUrl != "" && strings.TrimSpace(value) != accountUrl {
+					permitted = false
+					reason = fmt.Sprintf("account '%s' does not match '%s'", accountUrl, value)
+				}
+			}
+		}
+		if permitted {
+			return nil
+		}
+	}
+
+	if reason != "" {
+		return errors.New(reason)
+	}
+
+	issuers := make([]string, 0, len(relevant))
+	for _, record := range relevant {
+		issuers = append(issuers, fmt.Sprintf("%q", record.Value))
+	}
+	return fmt.Errorf("only %s are permitted, but this CA is identified as %s", strings.Join(issuers, ", "), strings.Join(caaIdentities, ", "))
+}
+
+func preflightCheckCNAME(ctx context.Context, resolver *preflightResolver, response *PreflightResponse, domainOrIP string) {
+	name := dns.Fqdn("_acme-challenge." + strings.TrimPrefix(domainOrIP, "*."))
+
+	chain := []string{name}
+	for range preflightMaxCNAMEHops {
+		msg, err := resolver.query(ctx, name, dns.TypeCNAME)
+		if err != nil {
+			response.add(domainOrIP, PreflightCheckCNAME, PreflightLevelWarn, "failed to lookup CNAME records at '%s': %s", name, err.Error())
+			return
+		}
+
+		var target string
+		for _, rr := range msg.Answer {
+			if cname, ok := rr.(*dns.CNAME); ok && strings.EqualFold(cname.Hdr.Name, name) {
+				target = cname.Target
+				break
+			}
+		}
+		if target == "" {
+			if len(chain) > 1 {
+				response.add(domainOrIP, PreflightCheckCNAME, PreflightLevelOK, "the challenge record will be created at '%s' via CNAME chain: %s", name, strings.Join(chain, " -> "))
+			} else {
+				response.add(domainOrIP, PreflightCheckCNAME, PreflightLevelOK, "the challenge record will be created at '%s'", name)
+			}
+			return
+		}
+
+		if slices.ContainsFunc(chain, func(s string) bool { return strings.EqualFold(s, target) }) {
+			response.add(domainOrIP, PreflightCheckCNAME, PreflightLevelError, "CNAME loop detected: %s -> %s; please fix the CNAME records", strings.Join(chain, " -> "), target)
+			return
+		}
+
+		chain = append(chain, target)
+		name = target
+	}
+
+	response.add(domainOrIP, PreflightCheckCNAME, PreflightLevelError, "CNAME chain is too long (more than %d hops): %s", preflightMaxCNAMEHops, strings.Join(chain, " -> "))
+}
+
+func preflightCheckResolve(ctx context.Context, resolver *preflightResolver, response *PreflightResponse, domainOrIP string, challengeType string) {
+	if strings.HasPrefix(domainOrIP, "*.") {
+		response.add(domainOrIP, PreflightCheckResolve, PreflightLevelError, "wildcard domains can only be validated by dns-01 challenge, but '%s' is configured", challengeType)
+		return
+	}
+
+	name := dns.Fqdn(domainOrIP)
+	addrs := make([]string, 0)
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		msg, err := resolver.query(ctx, name, qtype)
+		if err != nil {
+			response.add(domainOrIP, PreflightCheckResolve, PreflightLevelWarn, "failed to lookup %s records: %s", dns.TypeToString[qtype], err.Error())
+			continue
+		}
+
+		for _, rr := range msg.Answer {
+			switch rr := rr.(type) {
+			case *dns.A:
+				addrs = append(addrs, rr.A.String())
+			case *dns.AAAA:
+				addrs = append(addrs, rr.AAAA.String())
+			}
+		}
+	}
+
+	if len(addrs) == 0 {
+		response.add(domainOrIP, PreflightCheckResolve, PreflightLevelError, "the domain does not resolve to any IP address, the CA cannot reach it for %s challenge; please add an A or AAAA record", challengeType)
+		return
+	}
+
+	response.add(domainOrIP, PreflightCheckResolve, PreflightLevelOK, "the domain resolves to %s", strings.Join(addrs, ", "))
+}
+
+type preflightResolver struct {
+	nameservers []string
+}
+
+func newPreflightResolver(nameservers []string) *preflightResolver {
+	servers := make([]string, 0, len(nameservers))
+	for _, ns := range nameservers {
+		if ns = strings.TrimSpace(ns); ns == "" {
+			continue
+		}
+
+		if _, _, err := net.SplitHostPort(ns); err != nil {
+			ns = net.JoinHostPort(ns, "53")
+		}
+		servers = append(servers, ns)
+	}
+
+	if len(servers) == 0 {
+		if config, err := dns.ClientConfigFromFile("/etc/resolv.conf"); err == nil {
+			for _, server := range config.Servers {
+				servers = append(servers, net.JoinHostPort(server, config.Port))
+			}
+		}
+	}
+
+	if len(servers) == 0 {
+		servers = []string{"8.8.8.8:53", "1.1.1.1:53"}
+	}
+
+	return &preflightResolver{nameservers: servers}
+}
+
+func (r *preflightResolver) query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, qtype)
+	msg.SetEdns0(4096, false)
+	msg.RecursionDesired = true
+
+	var errs []error
+	for _, ns := range r.nameservers {
+		qctx, cancel := context.WithTimeout(ctx, preflightQueryTimeout)
+		resp, _, err := (&dns.Client{Net: "udp"}).ExchangeContext(qctx, msg, ns)
+		if err == nil && resp.Truncated {
+			resp, _, err = (&dns.Client{Net: "tcp"}).ExchangeContext(qctx, msg, ns)
+		}
+		cancel()
+
+		if err == nil {
+			return resp, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", ns, err))
+	}
+
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,114 @@
+package certacme
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateCAA(t *testing.T) {
+	caa := func(flag uint8, tag, value string) *dns.CAA {
+		return &dns.CAA{Flag: flag, Tag: tag, Value: value}
+	}
+
+	const accountUrl = "https://acme.example.com/acct/1"
+
+	type testInput struct {
+		records       []*dns.CAA
+		wildcard      bool
+		challengeType string
+		accountUrl    string
+	}
+	testCases := []struct {
+		name        string
+		input       testInput
+		expectedErr bool
+	}{
+		{
+			name:  "no issue records",
+			input: testInput{records: []*dns.CAA{caa(0, "iodef", "mailto:admin@example.com")}},
+		},
+		{
+			name:  "issuer permitted",
+			input: testInput{records: []*dns.CAA{caa(0, "issue", "letsencrypt.org")}},
+		},
+		{
+			name:  "issuer permitted case insensitively",
+			input: testInput{records: []*dns.CAA{caa(0, "ISSUE", "LetsEncrypt.org")}},
+		},
+		{
+			name:        "issuer not permitted",
+			input:       testInput{records: []*dns.CAA{caa(0, "issue", "pki.goog")}},
+			expectedErr: true,
+		},
+		{
+			name:        "issuance forbidden",
+			input:       testInput{records: []*dns.CAA{caa(0, "issue", ";")}},
+			expectedErr: true,
+		},
+		{
+			name:  "one of issuers permitted",
+			input: testInput{records: []*dns.CAA{caa(0, "issue", "pki.goog"), caa(0, "issue", "letsencrypt.org")}},
+		},
+		{
+			name:        "unknown critical property",
+			input:       testInput{records: []*dns.CAA{caa(128, "future", "value"), caa(0, "issue", "letsencrypt.org")}},
+			expectedErr: true,
+		},
+		{
+			name:  "unknown non-critical property",
+			input: testInput{records: []*dns.CAA{caa(0, "future", "value"), caa(0, "issue", "letsencrypt.org")}},
+		},
+		{
+			name:  "wildcard falls back to issue",
+			input: testInput{records: []*dns.CAA{caa(0, "issue", "letsencrypt.org")}, wildcard: true},
+		},
+		{
+			name:        "wildcard forbidden by issuewild",
+			input:       testInput{records: []*dns.CAA{caa(0, "issue", "letsencrypt.org"), caa(0, "issuewild", ";")}, wildcard: true},
+			expectedErr: true,
+		},
+		{
+			name:  "non-wildcard ignores issuewild",
+			input: testInput{records: []*dns.CAA{caa(0, "issue", "letsencrypt.org"), caa(0, "issuewild", ";")}},
+		},
+		{
+			name:  "validation method permitted",
+			input: testInput{records: []*dns.CAA{caa(0, "issue", "letsencrypt.org; validationmethods=dns-01,http-01")}, challengeType: "dns-01"},
+		},
+		{
+			name:        "validation method not permitted",
+			input:       testInput{records: []*dns.CAA{caa(0, "issue", "letsencrypt.org; validationmethods=http-01")}, challengeType: "dns-01"},
+			expectedErr: true,
+		},
+		{
+			name:  "account permitted",
+			input: testInput{records: []*dns.CAA{caa(0, "issue", "letsencrypt.org; accounturi="+accountUrl)}, accountUrl: accountUrl},
+		},
+		{
+			name:        "account not permitted",
+			input:       testInput{records: []*dns.CAA{caa(0, "issue", "letsencrypt.org; accounturi=https://acme.example.com/acct/2")}, accountUrl: accountUrl},
+			expectedErr: true,
+		},
+		{
+			name:  "account unknown yet",
+			input: testInput{records: []*dns.CAA{caa(0, "issue", "letsencrypt.org; accounturi=https://acme.example.com/acct/2")}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			challengeType := tc.input.challengeType
+			if challengeType == "" {
+				challengeType = "dns-01"
+			}
+
+			err := evaluateCAA(tc.input.records, []string{"letsencrypt.org"}, tc.input.wildcard, challengeType, tc.input.accountUrl)
+			if tc.expectedErr {
+				assert.Error(t, err, "Case: %-20s", tc.name)
+			} else {
+				assert.NoError(t, err, "Case: %-20s", tc.name)
+			}
+		})
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pocketbase/dbx"
 
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/certacme"
+	"github.com/certimate-go/certimate/internal/certlocalca"
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/internal/domain/dtos"
 	"github.com/certimate-go/certimate/internal/settings"
@@ -20,12 +23,16 @@ import (
 )
 
 type CertificateService struct {
+	accessRepo      accessRepository
 	acmeAccountRepo acmeAccountRepository
 	certificateRepo certificateRepository
+
+	crlMtx sync.Mutex
 }
 
-func NewCertificateService(acmeAccountRepo acmeAccountRepository, certificateRepo certificateRepository) *CertificateService {
+func NewCertificateService(accessRepo accessRepository, acmeAccountRepo acmeAccountRepository, certificateRepo certificateRepository) *CertificateService {
 	return &CertificateService{
+		accessRepo:      accessRepo,
 		acmeAccountRepo: acmeAccountRepo,
 		certificateRepo: certificateRepo,
 	}
@@ -61,13 +68,16 @@ func (s *CertificateService) DownloadCertificate(ctx context.Context, req *dtos.
 				return nil, fmt.Errorf("failed to extract certs: %w", err)
 			}
 
-			keyWriter, err := zipWriter.Create(fmt.Sprintf("%s.key", canonicalName))
-			if err != nil {
-				return nil, err
-			} else {
-				_, err = keyWriter.Write([]byte(certificate.PrivateKey))
+			// 由外部 CSR 签发的证书不含私钥
+			if certificate.PrivateKey != "" {
+				keyWriter, err := zipWriter.Create(fmt.Sprintf("%s.key", canonicalName))
 				if err != nil {
 					return nil, err
+				} else {
+					_, err = keyWriter.Write([]byte(certificate.PrivateKey))
+					if err != nil {
+						return nil, err
+					}
 				}
 			}
 
@@ -111,6 +121,10 @@ func (s *CertificateService) DownloadCertificate(ctx context.Context, req *dtos.
 
 	case domain.CertificateFormatTypePFX:
 		{
+			if certificate.PrivateKey == "" {
+				return nil, fmt.Errorf("the certificate has no private key, cannot be converted to pfx format")
+			}
+
 			pfxPassword := "certimate"
 			if req.PfxPassword != "" {
 				pfxPassword = req.PfxPassword
@@ -157,6 +171,10 @@ func (s *CertificateService) DownloadCertificate(ctx context.Context, req *dtos.
 
 	case domain.CertificateFormatTypeJKS:
 		{
+			if certificate.PrivateKey == "" {
+				return nil, fmt.Errorf("the certificate has no private key, cannot be converted to jks format")
+			}
+
 			jksAlias := "certimate"
 			if req.JksAlias != "" {
 				jksAlias = req.JksAlias
@@ -222,13 +240,25 @@ func (s *CertificateService) RevokeCertificate(ctx context.Context, req *dtos.Ce
 		return nil, err
 	}
 
-	if certificate.ACMEAccountUrl == "" || certificate.ACMECertificateUrl == "" {
-		return nil, fmt.Errorf("could not revoke a certificate which is not issued in Certimate")
-	}
 	if certificate.IsRevoked {
 		return nil, fmt.Errorf("could not revoke a certificate which is already revoked")
 	}
 
+	// 由本地 CA 签发的证书只需标记为已吊销，并在下次生成 CRL 时列入
+	if certificate.CA == domain.CAProviderTypeLocalCA.String() {
+		certificate.IsRevoked = true
+		certificate.RevokedAt = time.Now()
+		if _, err := s.certificateRepo.Save(ctx, certificate); err != nil {
+			return nil, err
+		}
+
+		return &dtos.CertificateRevokeResp{}, nil
+	}
+
+	if certificate.ACMEAccountUrl == "" || certificate.ACMECertificateUrl == "" {
+		return nil, fmt.Errorf("could not revoke a certificate which is not issued in Certimate")
+	}
+
 	acmeAccount, err := s.acmeAccountRepo.GetByCAAndAcctUrl(ctx, certificate.CA, certificate.ACMEAccountUrl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to revoke certificate: could not find acme account: %w", err)
@@ -248,6 +278,7 @@ func (s *CertificateService) RevokeCertificate(ctx context.Context, req *dtos.Ce
 	}
 
 	certificate.IsRevoked = true
+	certificate.RevokedAt = time.Now()
 	certificate, err = s.certificateRepo.Save(ctx, certificate)
 	if err != nil {
 		return nil, err
@@ -256,6 +287,100 @@ func (s *CertificateService) RevokeCertificate(ctx context.Context, req *dtos.Ce
 	return &dtos.CertificateRevokeResp{}, nil
 }
 
+func (s *CertificateService) GenerateLocalCA(ctx context.Context, req *dtos.CertificateGenerateLocalCAReq) (*dtos.CertificateGenerateLocalCAResp, error) {
+	generateReq := &certlocalca.GenerateCARequest{
+		CommonName:           req.CommonName,
+		Organization:         req.Organization,
+		KeyType:              domain.CertificateKeyAlgorithmType(req.KeyAlgorithm).LegoKeyType(),
+		RootValidity:         time.Duration(req.RootValidityDays) * 24 * time.Hour,
+		IntermediateValidity: time.Duration(req.IntermediateValidityDays) * 24 * time.Hour,
+		PermittedDNSDomains:  req.PermittedDNSDomains,
+		ExcludedDNSDomains:   req.ExcludedDNSDomains,
+		PermittedIPRanges:    req.PermittedIPRanges,
+		ExcludedIPRanges:     req.ExcludedIPRanges,
+	}
+	generateResp, err := certlocalca.GenerateCA(ctx, generateReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate local ca: %w", err)
+	}
+
+	return &dtos.CertificateGenerateLocalCAResp{
+		RootCertificate:         generateResp.RootCertificate,
+		RootPrivateKey:          generateResp.RootPrivateKey,
+		IntermediateCertificate: generateResp.IntermediateCertificate,
+		IntermediatePrivateKey:  generateResp.IntermediatePrivateKey,
+	}, nil
+}
+
+func (s *CertificateService) GenerateLocalCACRL(ctx context.Context, req *dtos.CertificateGenerateLocalCACRLReq) (*dtos.CertificateGenerateLocalCACRLResp, error) {
+	access, err := s.accessRepo.GetById(ctx, req.AccessId)
+	if err != nil {
+		return nil, err
+	}
+
+	if access.Provider != domain.AccessProviderTypeLocalCA.String() {
+		return nil, domain.NewError(domain.ErrRecordNotFound.Code, fmt.Sprintf("the access #%s is not a local ca", req.AccessId))
+	}
+
+	certificates, err := s.certificateRepo.ListRevokedByCA(ctx, domain.CAProviderTypeLocalCA.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// 同一类型下可能存在多个本地 CA，非由当前 CA 签发的证书将在生成时被忽略
+	revokedCerts := make([]*certlocalca.RevokedCertificate, 0, len(certificates))
+	for _, certificate := range certificates {
+		// 早期版本未记录吊销时间，此时以最后更新时间代替
+		revokedAt := certificate.RevokedAt
+		if revokedAt.IsZero() {
+			revokedAt = certificate.UpdatedAt
+		}
+
+		revokedCerts = append(revokedCerts, &certlocalca.RevokedCertificate{
+			Certificate: certificate.Certificate,
+			RevokedAt:   revokedAt,
+		})
+	}
+
+	s.crlMtx.Lock()
+	defer s.crlMtx.Unlock()
+
+	crlReq := &certlocalca.GenerateCRLRequest{
+		CAAccessConfig:      access.Config,
+		RevokedCertificates: revokedCerts,
+	}
+
+	// 吊销列表未变化且尚未临近下次更新时间时，直接复用此前生成的 CRL，以免每次获取都消耗新的序号
+	lastCRL, err := s.accessRepo.GetCRL(ctx, access.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	crlResp, ok := certlocalca.ReuseCRL(ctx, crlReq, lastCRL)
+	if !ok {
+		crlNumber, err := s.accessRepo.IncreaseCRLNumber(ctx, access.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to allocate crl number: %w", err)
+		}
+
+		crlReq.Number = crlNumber
+		crlResp, err = certlocalca.GenerateCRL(ctx, crlReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate crl: %w", err)
+		}
+
+		if err := s.accessRepo.SaveCRL(ctx, access.Id, crlResp.CRL); err != nil {
+			return nil, fmt.Errorf("failed to save crl: %w", err)
+		}
+	}
+
+	return &dtos.CertificateGenerateLocalCACRLResp{
+		CRL:        crlResp.CRL,
+		ThisUpdate: crlResp.ThisUpdate,
+		NextUpdate: crlResp.NextUpdate,
+	}, nil
+}
+
 func (s *CertificateService) cleanupExpiredCertificates(ctx context.Context) error {
 	globalSettingsForPersistence := settings.GetGlobalSettingsForPersistence()
 	if globalSettingsForPersistence.CertificatesRetentionMaxDays != 0 {
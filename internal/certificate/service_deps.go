@@ -8,12 +8,20 @@ import (
 	"github.com/certimate-go/certimate/internal/domain"
 )
 
+type accessRepository interface {
+	GetById(ctx context.Context, id string) (*domain.Access, error)
+	IncreaseCRLNumber(ctx context.Context, id string) (int64, error)
+	GetCRL(ctx context.Context, id string) (string, error)
+	SaveCRL(ctx context.Context, id string, crl string) error
+}
+
 type acmeAccountRepository interface {
 	GetByCAAndAcctUrl(ctx context.Context, ca string, acctUrl string) (*domain.ACMEAccount, error)
 }
 
 type certificateRepository interface {
 	GetById(ctx context.Context, id string) (*domain.Certificate, error)
+	ListRevokedByCA(ctx context.Context, ca string) ([]*domain.Certificate, error)
 	Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error)
 	DeleteWithExprs(ctx context.Context, exprs ...dbx.Expression) (int, error)
 }
@@ -0,0 +1,342 @@
+package certlocalca_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v5/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/certlocalca"
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+func generateCAAccessConfig(t *testing.T, request *certlocalca.GenerateCARequest) (map[string]any, *x509.CertPool) {
+	t.Helper()
+
+	resp, err := certlocalca.GenerateCA(context.Background(), request)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM([]byte(resp.RootCertificate)))
+
+	return map[string]any{
+		"certificate": resp.IntermediateCertificate,
+		"privateKey":  resp.IntermediatePrivateKey,
+	}, roots
+}
+
+func TestIssueCertificate(t *testing.T) {
+	accessConfig, roots := generateCAAccessConfig(t, &certlocalca.GenerateCARequest{CommonName: "Test"})
+
+	csrKey, err := certcrypto.GeneratePrivateKey(certcrypto.EC256)
+	require.NoError(t, err)
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: []string{"csr.example.com"}}, csrKey)
+	require.NoError(t, err)
+	csrPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}))
+
+	type testInput struct {
+		domainOrIPs  []string
+		csrPEM       string
+		extKeyUsages []string
+	}
+	testCases := []struct {
+		name        string
+		input       testInput
+		expectedKey bool
+		expectedErr bool
+	}{
+		{
+			name:        "domains",
+			input:       testInput{domainOrIPs: []string{"example.com", "*.example.com"}},
+			expectedKey: true,
+		},
+		{
+			name:        "ip addresses",
+			input:       testInput{domainOrIPs: []string{"127.0.0.1", "::1"}},
+			expectedKey: true,
+		},
+		{
+			name:        "csr",
+			input:       testInput{domainOrIPs: []string{"csr.example.com"}, csrPEM: csrPEM},
+			expectedKey: false,
+		},
+		{
+			name:        "client auth",
+			input:       testInput{domainOrIPs: []string{"example.com"}, extKeyUsages: []string{"serverAuth", "clientAuth"}},
+			expectedKey: true,
+		},
+		{
+			name:        "unsupported ext key usage",
+			input:       testInput{domainOrIPs: []string{"example.com"}, extKeyUsages: []string{"unknown"}},
+			expectedErr: true,
+		},
+		{
+			name:        "no domains",
+			input:       testInput{},
+			expectedErr: true,
+		},
+		{
+			name:        "malformed csr",
+			input:       testInput{domainOrIPs: []string{"example.com"}, csrPEM: "malformed"},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := certlocalca.IssueCertificate(context.Background(), &certlocalca.IssueCertificateRequest{
+				DomainOrIPs:    tc.input.domainOrIPs,
+				PrivateKeyType: certcrypto.EC256,
+				CSRPEM:         tc.input.csrPEM,
+				ExtKeyUsages:   tc.input.extKeyUsages,
+				CAAccessConfig: accessConfig,
+			})
+			if tc.expectedErr {
+				assert.Error(t, err, "Case: %-20s", tc.name)
+				return
+			}
+			require.NoError(t, err, "Case: %-20s", tc.name)
+
+			assert.Equal(t, tc.expectedKey, resp.PrivateKey != "", "Case: %-20s", tc.name)
+
+			certs := make([]*x509.Certificate, 0)
+			for rest := []byte(resp.FullChainCertificate); ; {
+				var block *pem.Block
+				if block, rest = pem.Decode(rest); block == nil {
+					break
+				}
+
+				cert, err := x509.ParseCertificate(block.Bytes)
+				require.NoError(t, err)
+				certs = append(certs, cert)
+			}
+			require.Greater(t, len(certs), 1)
+
+			intermediates := x509.NewCertPool()
+			for _, cert := range certs[1:] {
+				intermediates.AddCert(cert)
+			}
+			_, err = certs[0].Verify(x509.VerifyOptions{
+				Roots:         roots,
+				Intermediates: intermediates,
+				KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+			})
+			assert.NoError(t, err, "Case: %-20s", tc.name)
+		})
+	}
+}
+
+func TestIssueCertificateWithNameConstraints(t *testing.T) {
+	accessConfig, _ := generateCAAccessConfig(t, &certlocalca.GenerateCARequest{
+		CommonName:          "Test",
+		PermittedDNSDomains: []string{"example.com"},
+		ExcludedDNSDomains:  []string{"internal.example.com"},
+		PermittedIPRanges:   []string{"10.0.0.0/8"},
+		ExcludedIPRanges:    []string{"10.0.0.0/24"},
+	})
+
+	testCases := []struct {
+		domainOrIP string
+		expected   bool
+	}{
+		{"example.com", true},
+		{"sub.example.com", true},
+		{"*.example.com", true},
+		{"EXAMPLE.COM", true},
+		{"example.org", false},
+		{"badexample.com", false},
+		{"internal.example.com", false},
+		{"a.internal.example.com", false},
+		{"10.1.2.3", true},
+		{"10.0.0.1", false},
+		{"192.168.1.1", false},
+	}
+
+	for _, tc := range testCases {
+		err := certlocalca.ValidateIssueCertificateRequest(&certlocalca.IssueCertificateRequest{
+			DomainOrIPs:    []string{tc.domainOrIP},
+			CAAccessConfig: accessConfig,
+		})
+		if tc.expected {
+			assert.NoError(t, err, "DomainOrIP: %-20s", tc.domainOrIP)
+		} else {
+			assert.Error(t, err, "DomainOrIP: %-20s", tc.domainOrIP)
+		}
+	}
+}
+
+func TestGenerateCRL(t *testing.T) {
+	accessConfig, _ := generateCAAccessConfig(t, &certlocalca.GenerateCARequest{CommonName: "Test"})
+	otherAccessConfig, _ := generateCAAccessConfig(t, &certlocalca.GenerateCARequest{CommonName: "Other"})
+
+	issue := func(config map[string]any) (string, *x509.Certificate) {
+		resp, err := certlocalca.IssueCertificate(context.Background(), &certlocalca.IssueCertificateRequest{
+			DomainOrIPs:    []string{"example.com"},
+			PrivateKeyType: certcrypto.EC256,
+			CAAccessConfig: config,
+		})
+		require.NoError(t, err)
+
+		cert, err := xcert.ParseCertificateFromPEM(resp.FullChainCertificate)
+		require.NoError(t, err)
+
+		return resp.FullChainCertificate, cert
+	}
+
+	revokedPEM, revokedCert := issue(accessConfig)
+	otherPEM, _ := issue(otherAccessConfig)
+	revokedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	type testInput struct {
+		number  int64
+		revoked []*certlocalca.RevokedCertificate
+	}
+	testCases := []struct {
+		name            string
+		input           testInput
+		expectedNumber  int64
+		expectedEntries int
+	}{
+		{
+			name:            "empty",
+			input:           testInput{number: 1},
+			expectedNumber:  1,
+			expectedEntries: 0,
+		},
+		{
+			name: "revoked",
+			input: testInput{
+				number: 42,
+				revoked: []*certlocalca.RevokedCertificate{
+					{Certificate: revokedPEM, RevokedAt: revokedAt},
+				},
+			},
+			expectedNumber:  42,
+			expectedEntries: 1,
+		},
+		{
+			name: "issued by other ca",
+			input: testInput{
+				number: 43,
+				revoked: []*certlocalca.RevokedCertificate{
+					{Certificate: revokedPEM, RevokedAt: revokedAt},
+					{Certificate: otherPEM, RevokedAt: revokedAt},
+					{Certificate: "malformed", RevokedAt: revokedAt},
+				},
+			},
+			expectedNumber:  43,
+			expectedEntries: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := certlocalca.GenerateCRL(context.Background(), &certlocalca.GenerateCRLRequest{
+				CAAccessConfig:      accessConfig,
+				RevokedCertificates: tc.input.revoked,
+				Number:              tc.input.number,
+			})
+			require.NoError(t, err, "Case: %-20s", tc.name)
+
+			block, _ := pem.Decode([]byte(resp.CRL))
+			require.NotNil(t, block)
+			crl, err := x509.ParseRevocationList(block.Bytes)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedNumber, crl.Number.Int64(), "Case: %-20s", tc.name)
+			assert.Len(t, crl.RevokedCertificateEntries, tc.expectedEntries, "Case: %-20s", tc.name)
+			for _, entry := range crl.RevokedCertificateEntries {
+				assert.Equal(t, revokedCert.SerialNumber, entry.SerialNumber, "Case: %-20s", tc.name)
+				assert.True(t, revokedAt.Equal(entry.RevocationTime), "Case: %-20s", tc.name)
+			}
+		})
+	}
+}
+
+func TestReuseCRL(t *testing.T) {
+	accessConfig, _ := generateCAAccessConfig(t, &certlocalca.GenerateCARequest{CommonName: "Test"})
+	otherAccessConfig, _ := generateCAAccessConfig(t, &certlocalca.GenerateCARequest{CommonName: "Other"})
+
+	issueResp, err := certlocalca.IssueCertificate(context.Background(), &certlocalca.IssueCertificateRequest{
+		DomainOrIPs:    []string{"example.com"},
+		PrivateKeyType: certcrypto.EC256,
+		CAAccessConfig: accessConfig,
+	})
+	require.NoError(t, err)
+
+	revokedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	revoked := []*certlocalca.RevokedCertificate{{Certificate: issueResp.FullChainCertificate, RevokedAt: revokedAt}}
+
+	generate := func(config map[string]any, revoked []*certlocalca.RevokedCertificate, validity time.Duration) string {
+		resp, err := certlocalca.GenerateCRL(context.Background(), &certlocalca.GenerateCRLRequest{
+			CAAccessConfig:      config,
+			RevokedCertificates: revoked,
+			Validity:            validity,
+			Number:              1,
+		})
+		require.NoError(t, err)
+		return resp.CRL
+	}
+
+	type testInput struct {
+		crl     string
+		revoked []*certlocalca.RevokedCertificate
+	}
+	testCases := []struct {
+		name     string
+		input    testInput
+		expected bool
+	}{
+		{
+			name:     "unchanged",
+			input:    testInput{crl: generate(accessConfig, revoked, 0), revoked: revoked},
+			expected: true,
+		},
+		{
+			name:     "newly revoked",
+			input:    testInput{crl: generate(accessConfig, nil, 0), revoked: revoked},
+			expected: false,
+		},
+		{
+			name:     "revocation time changed",
+			input:    testInput{crl: generate(accessConfig, revoked, 0), revoked: []*certlocalca.RevokedCertificate{{Certificate: issueResp.FullChainCertificate, RevokedAt: revokedAt.Add(time.Minute)}}},
+			expected: false,
+		},
+		{
+			name:     "half of validity elapsed",
+			input:    testInput{crl: generate(accessConfig, revoked, time.Nanosecond), revoked: revoked},
+			expected: false,
+		},
+		{
+			name:     "signed by other ca",
+			input:    testInput{crl: generate(otherAccessConfig, nil, 0)},
+			expected: false,
+		},
+		{
+			name:     "empty",
+			input:    testInput{revoked: revoked},
+			expected: false,
+		},
+		{
+			name:     "malformed",
+			input:    testInput{crl: "malformed", revoked: revoked},
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, ok := certlocalca.ReuseCRL(context.Background(), &certlocalca.GenerateCRLRequest{
+				CAAccessConfig:      accessConfig,
+				RevokedCertificates: tc.input.revoked,
+			}, tc.input.crl)
+			assert.Equal(t, tc.expected, ok, "Case: %-20s", tc.name)
+			if tc.expected {
+				assert.Equal(t, tc.input.crl, resp.CRL, "Case: %-20s", tc.name)
+			}
+		})
+	}
+}
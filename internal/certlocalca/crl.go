@@ -0,0 +1,161 @@
+package certlocalca
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+// CRL 的默认有效期，到期前需重新生成并发布。
+const defaultCRLValidity = 7 * 24 * time.Hour
+
+type RevokedCertificate struct {
+	Certificate string
+	RevokedAt   time.Time
+}
+
+type GenerateCRLRequest struct {
+	CAAccessConfig map[string]any
+
+	// 已吊销的证书，非由该 CA 签发的证书将被忽略。
+	RevokedCertificates []*RevokedCertificate
+
+	// CRL 有效期。
+	// 零值时默认值 7 天。
+	Validity time.Duration
+
+	// CRL 序号，同一 CA 下须单调递增。
+	// 零值时以生成时间作为序号。
+	Number int64
+}
+
+type GenerateCRLResponse struct {
+	CRL        string // PEM 格式
+	ThisUpdate time.Time
+	NextUpdate time.Time
+}
+
+func GenerateCRL(ctx context.Context, request *GenerateCRLRequest) (*GenerateCRLResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+
+	issuer, err := LoadIssuer(request.CAAccessConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if issuer.Certificate.KeyUsage&x509.KeyUsageCRLSign == 0 {
+		return nil, fmt.Errorf("the certificate of local ca is not allowed to sign crl")
+	}
+
+	validity := request.Validity
+	if validity <= 0 {
+		validity = defaultCRLValidity
+	}
+
+	entries := collectRevocationListEntries(issuer, request.RevokedCertificates)
+
+	now := time.Now()
+	number := request.Number
+	if number <= 0 {
+		number = now.Unix()
+	}
+
+	template := &x509.RevocationList{
+		Number:                    big.NewInt(number),
+		ThisUpdate:                now,
+		NextUpdate:                now.Add(validity),
+		RevokedCertificateEntries: entries,
+	}
+
+	crlDER, err := x509.CreateRevocationList(rand.Reader, template, issuer.Certificate, issuer.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign crl: %w", err)
+	}
+
+	crlPEM := pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crlDER})
+	return &GenerateCRLResponse{
+		CRL:        strings.TrimSpace(string(crlPEM)),
+		ThisUpdate: template.ThisUpdate,
+		NextUpdate: template.NextUpdate,
+	}, nil
+}
+
+// 复用此前生成的 CRL。
+// 仅当其由该 CA 签发、所列的吊销证书未发生变化，且尚未过半有效期时才可复用；
+// 否则需重新生成，以便依赖方在其过期前获取到新的 CRL。
+func ReuseCRL(ctx context.Context, request *GenerateCRLRequest, crlPEM string) (*GenerateCRLResponse, bool) {
+	if request == nil || crlPEM == "" {
+		return nil, false
+	}
+
+	issuer, err := LoadIssuer(request.CAAccessConfig)
+	if err != nil {
+		return nil, false
+	}
+
+	block, _ := pem.Decode([]byte(crlPEM))
+	if block == nil || block.Type != "X509 CRL" {
+		return nil, false
+	}
+
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	if err != nil {
+		return nil, false
+	}
+	if crl.CheckSignatureFrom(issuer.Certificate) != nil {
+		return nil, false
+	}
+	if time.Now().After(crl.ThisUpdate.Add(crl.NextUpdate.Sub(crl.ThisUpdate) / 2)) {
+		return nil, false
+	}
+
+	entries := collectRevocationListEntries(issuer, request.RevokedCertificates)
+	if len(entries) != len(crl.RevokedCertificateEntries) {
+		return nil, false
+	}
+
+	published := make(map[string]int64, len(crl.RevokedCertificateEntries))
+	for _, entry := range crl.RevokedCertificateEntries {
+		published[entry.SerialNumber.String()] = entry.RevocationTime.Unix()
+	}
+	for _, entry := range entries {
+		if revokedAt, ok := published[entry.SerialNumber.String()]; !ok || revokedAt != entry.RevocationTime.Unix() {
+			return nil, false
+		}
+	}
+
+	return &GenerateCRLResponse{
+		CRL:        strings.TrimSpace(crlPEM),
+		ThisUpdate: crl.ThisUpdate,
+		NextUpdate: crl.NextUpdate,
+	}, true
+}
+
+func collectRevocationListEntries(issuer *Issuer, revokedCerts []*RevokedCertificate) []x509.RevocationListEntry {
+	entries := make([]x509.RevocationListEntry, 0, len(revokedCerts))
+	for _, revoked := range revokedCerts {
+		cert, err := xcert.ParseCertificateFromPEM(revoked.Certificate)
+		if err != nil {
+			continue
+		}
+		if cert.CheckSignatureFrom(issuer.Certificate) != nil {
+			continue
+		}
+
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   cert.SerialNumber,
+			RevocationTime: revoked.RevokedAt,
+		})
+	}
+
+	return entries
+}
@@ -0,0 +1,184 @@
+package certlocalca
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/certcrypto"
+
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+type GenerateCARequest struct {
+	CommonName   string
+	Organization string
+	KeyType      certcrypto.KeyType
+
+	// 根证书及中间证书的有效期。
+	// 零值时分别默认为 10 年、5 年。
+	RootValidity         time.Duration
+	IntermediateValidity time.Duration
+
+	// 写入中间证书的名称约束。
+	PermittedDNSDomains []string
+	ExcludedDNSDomains  []string
+	PermittedIPRanges   []string // CIDR 格式
+	ExcludedIPRanges    []string // CIDR 格式
+}
+
+type GenerateCAResponse struct {
+	RootCertificate         string
+	RootPrivateKey          string
+	IntermediateCertificate string // 中间证书及根证书组成的证书链
+	IntermediatePrivateKey  string
+}
+
+// 生成一套根证书及中间证书。
+// 根证书的私钥应离线保管，日常签发仅需使用中间证书及其私钥。
+func GenerateCA(ctx context.Context, request *GenerateCARequest) (*GenerateCAResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+
+	if request.CommonName == "" {
+		return nil, fmt.Errorf("the common name is empty")
+	}
+
+	if request.KeyType == "" {
+		request.KeyType = certcrypto.EC256
+	}
+	if request.RootValidity <= 0 {
+		request.RootValidity = 10 * 365 * 24 * time.Hour
+	}
+	if request.IntermediateValidity <= 0 {
+		request.IntermediateValidity = 5 * 365 * 24 * time.Hour
+	}
+
+	now := time.Now().Add(-5 * time.Minute)
+	subject := pkix.Name{CommonName: request.CommonName}
+	if request.Organization != "" {
+		subject.Organization = []string{request.Organization}
+	}
+
+	// 根证书
+	rootKey, err := certcrypto.GeneratePrivateKey(request.KeyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate root private key: %w", err)
+	}
+
+	rootTemplate := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: request.CommonName + " Root CA", Organization: subject.Organization},
+		NotBefore:             now,
+		NotAfter:              now.Add(request.RootValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            1,
+	}
+	rootCert, err := signCACertificate(rootTemplate, nil, rootKey, rootKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign root certificate: %w", err)
+	}
+
+	// 中间证书
+	intermediateKey, err := certcrypto.GeneratePrivateKey(request.KeyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate intermediate private key: %w", err)
+	}
+
+	intermediateTemplate := &x509.Certificate{
+		Subject:               subject,
+		NotBefore:             now,
+		NotAfter:              now.Add(request.IntermediateValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            0,
+		MaxPathLenZero:        true,
+		PermittedDNSDomains:   request.PermittedDNSDomains,
+		ExcludedDNSDomains:    request.ExcludedDNSDomains,
+	}
+	if intermediateTemplate.NotAfter.After(rootCert.NotAfter) {
+		intermediateTemplate.NotAfter = rootCert.NotAfter
+	}
+	if intermediateTemplate.PermittedIPRanges, err = parseCIDRs(request.PermittedIPRanges); err != nil {
+		return nil, err
+	}
+	if intermediateTemplate.ExcludedIPRanges, err = parseCIDRs(request.ExcludedIPRanges); err != nil {
+		return nil, err
+	}
+	intermediateTemplate.PermittedDNSDomainsCritical = len(intermediateTemplate.PermittedDNSDomains) > 0 || len(intermediateTemplate.PermittedIPRanges) > 0
+
+	intermediateCert, err := signCACertificate(intermediateTemplate, rootCert, intermediateKey, rootKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign intermediate certificate: %w", err)
+	}
+
+	rootCertPEM, err := xcert.ConvertCertificateToPEM(rootCert)
+	if err != nil {
+		return nil, err
+	}
+
+	rootKeyPEM, err := xcert.ConvertPrivateKeyToPEM(rootKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	intermediateCertPEM, err := xcert.ConvertCertificateToPEM(intermediateCert)
+	if err != nil {
+		return nil, err
+	}
+
+	intermediateKeyPEM, err := xcert.ConvertPrivateKeyToPEM(intermediateKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenerateCAResponse{
+		RootCertificate:         strings.TrimSpace(rootCertPEM),
+		RootPrivateKey:          strings.TrimSpace(rootKeyPEM),
+		IntermediateCertificate: strings.TrimSpace(intermediateCertPEM) + "\n" + strings.TrimSpace(rootCertPEM),
+		IntermediatePrivateKey:  strings.TrimSpace(intermediateKeyPEM),
+	}, nil
+}
+
+func signCACertificate(template, parent *x509.Certificate, key crypto.Signer, parentKey crypto.Signer) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber = serialNumber
+
+	if parent == nil {
+		parent = template
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCertificate(certDER)
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	ipnets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipnet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ip range '%s': %w", cidr, err)
+		}
+
+		ipnets = append(ipnets, ipnet)
+	}
+
+	return ipnets, nil
+}
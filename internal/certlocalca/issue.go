@@ -0,0 +1,298 @@
+package certlocalca
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/certcrypto"
+
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+// 未指定有效期时所签发证书的默认有效期。
+const defaultValidity = 90 * 24 * time.Hour
+
+var extKeyUsages = map[string]x509.ExtKeyUsage{
+	"serverAuth":      x509.ExtKeyUsageServerAuth,
+	"clientAuth":      x509.ExtKeyUsageClientAuth,
+	"codeSigning":     x509.ExtKeyUsageCodeSigning,
+	"emailProtection": x509.ExtKeyUsageEmailProtection,
+	"timeStamping":    x509.ExtKeyUsageTimeStamping,
+	"ocspSigning":     x509.ExtKeyUsageOCSPSigning,
+}
+
+// 所签发的证书均为终端实体证书，而名称约束按 RFC 5280 仅对 CA 证书有效，因此不在其中写入名称约束；
+// 如需限制可签发的域名及 IP 地址范围，应在生成本地 CA 时为中间证书设置名称约束（见 [GenerateCARequest]），签发前将据此校验。
+type IssueCertificateRequest struct {
+	DomainOrIPs       []string
+	PrivateKeyType    certcrypto.KeyType
+	PrivateKeyPEM     string
+	ValidityNotBefore time.Time
+	ValidityNotAfter  time.Time
+	NoCommonName      bool
+
+	// 证书签名请求 PEM 内容。
+	// 非空时将使用其中的公钥签发证书，此时响应中不包含私钥。
+	CSRPEM string
+
+	// 扩展密钥用途，可取值 "serverAuth"、"clientAuth"、"codeSigning"、"emailProtection"、"timeStamping"、"ocspSigning"。
+	// 零值时默认值 ["serverAuth"]。
+	ExtKeyUsages []string
+
+	// CA 相关
+	CAAccessConfig map[string]any
+}
+
+type IssueCertificateResponse struct {
+	FullChainCertificate string
+	IssuerCertificate    string
+	PrivateKey           string
+}
+
+func IssueCertificate(ctx context.Context, request *IssueCertificateRequest) (*IssueCertificateResponse, error) {
+	issuer, template, err := prepareIssueCertificate(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var privkey crypto.Signer
+	var pubkey crypto.PublicKey
+	if request.CSRPEM != "" {
+		csr, err := parseCSR(request.CSRPEM)
+		if err != nil {
+			return nil, err
+		}
+
+		pubkey = csr.PublicKey
+	} else if request.PrivateKeyPEM != "" {
+		pk, err := certcrypto.ParsePEMPrivateKey([]byte(request.PrivateKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+
+		privkey = pk
+		pubkey = pk.Public()
+	} else {
+		pk, err := certcrypto.GeneratePrivateKey(request.PrivateKeyType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate private key: %w", err)
+		}
+
+		privkey = pk
+		pubkey = pk.Public()
+	}
+
+	if _, ok := pubkey.(*rsa.PublicKey); ok {
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, issuer.Certificate, pubkey, issuer.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, err
+	}
+
+	certPEM, err := xcert.ConvertCertificateToPEM(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	privkeyPEM := ""
+	if privkey != nil {
+		privkeyPEM, err = xcert.ConvertPrivateKeyToPEM(privkey, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	issuerPEM := issuer.ChainPEM()
+	return &IssueCertificateResponse{
+		FullChainCertificate: strings.TrimSpace(certPEM) + "\n" + issuerPEM,
+		IssuerCertificate:    issuerPEM,
+		PrivateKey:           strings.TrimSpace(privkeyPEM),
+	}, nil
+}
+
+// 校验证书签发请求，仅加载签发者并构造证书模板而不实际签发证书。
+func ValidateIssueCertificateRequest(request *IssueCertificateRequest) error {
+	if _, _, err := prepareIssueCertificate(request); err != nil {
+		return err
+	}
+
+	if request.CSRPEM != "" {
+		if _, err := parseCSR(request.CSRPEM); err != nil {
+			return err
+		}
+	} else if request.PrivateKeyPEM != "" {
+		if _, err := certcrypto.ParsePEMPrivateKey([]byte(request.PrivateKeyPEM)); err != nil {
+			return fmt.Errorf("failed to parse private key: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func parseCSR(csrPEM string) (*x509.CertificateRequest, error) {
+	csr, err := certcrypto.PemDecodeTox509CSR([]byte(csrPEM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csr: %w", err)
+	}
+
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("failed to verify csr signature: %w", err)
+	}
+
+	return csr, nil
+}
+
+func prepareIssueCertificate(request *IssueCertificateRequest) (*Issuer, *x509.Certificate, error) {
+	if request == nil {
+		return nil, nil, fmt.Errorf("the request is nil")
+	}
+
+	if len(request.DomainOrIPs) == 0 {
+		return nil, nil, fmt.Errorf("no domains or ip addresses")
+	}
+
+	issuer, err := LoadIssuer(request.CAAccessConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  false,
+	}
+
+	for _, domainOrIP := range request.DomainOrIPs {
+		if ip := net.ParseIP(domainOrIP); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, domainOrIP)
+		}
+	}
+
+	if !request.NoCommonName {
+		template.Subject = pkix.Name{CommonName: request.DomainOrIPs[0]}
+	}
+
+	if len(request.ExtKeyUsages) == 0 {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	} else {
+		for _, name := range request.ExtKeyUsages {
+			eku, ok := extKeyUsages[name]
+			if !ok {
+				return nil, nil, fmt.Errorf("unsupported extended key usage: '%s'", name)
+			}
+
+			template.ExtKeyUsage = append(template.ExtKeyUsage, eku)
+		}
+	}
+
+	if err := checkNameConstraints(append([]*x509.Certificate{issuer.Certificate}, issuer.Chain...), template.DNSNames, template.IPAddresses); err != nil {
+		return nil, nil, err
+	}
+
+	// 有效期不得超出 CA 证书本身的有效期
+	template.NotBefore = request.ValidityNotBefore
+	if template.NotBefore.IsZero() {
+		template.NotBefore = time.Now().Add(-5 * time.Minute)
+	}
+	template.NotAfter = request.ValidityNotAfter
+	if template.NotAfter.IsZero() {
+		template.NotAfter = template.NotBefore.Add(defaultValidity)
+	}
+	if template.NotAfter.After(issuer.Certificate.NotAfter) {
+		template.NotAfter = issuer.Certificate.NotAfter
+	}
+	if !template.NotAfter.After(template.NotBefore) {
+		return nil, nil, fmt.Errorf("the validity period is invalid, or the certificate of local ca has expired")
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	template.SerialNumber = serialNumber
+
+	if issuer.CRLDistributionPoint != "" {
+		template.CRLDistributionPoints = []string{issuer.CRLDistributionPoint}
+	}
+
+	return issuer, template, nil
+}
+
+// 检查待签发的域名及 IP 地址是否满足证书链中各 CA 证书的名称约束，以免签发出无法通过校验的证书。
+func checkNameConstraints(caCerts []*x509.Certificate, dnsNames []string, ipAddrs []net.IP) error {
+	for _, caCert := range caCerts {
+		for _, name := range dnsNames {
+			name = strings.TrimPrefix(strings.ToLower(name), "*.")
+
+			if len(caCert.PermittedDNSDomains) > 0 {
+				permitted := false
+				for _, constraint := range caCert.PermittedDNSDomains {
+					if matchDomainConstraint(name, constraint) {
+						permitted = true
+						break
+					}
+				}
+				if !permitted {
+					return fmt.Errorf("the domain '%s' is not permitted by the name constraints of '%s'", name, caCert.Subject.CommonName)
+				}
+			}
+
+			for _, constraint := range caCert.ExcludedDNSDomains {
+				if matchDomainConstraint(name, constraint) {
+					return fmt.Errorf("the domain '%s' is excluded by the name constraints of '%s'", name, caCert.Subject.CommonName)
+				}
+			}
+		}
+
+		for _, ip := range ipAddrs {
+			if len(caCert.PermittedIPRanges) > 0 {
+				permitted := false
+				for _, ipnet := range caCert.PermittedIPRanges {
+					if ipnet.Contains(ip) {
+						permitted = true
+						break
+					}
+				}
+				if !permitted {
+					return fmt.Errorf("the ip address '%s' is not permitted by the name constraints of '%s'", ip, caCert.Subject.CommonName)
+				}
+			}
+
+			for _, ipnet := range caCert.ExcludedIPRanges {
+				if ipnet.Contains(ip) {
+					return fmt.Errorf("the ip address '%s' is excluded by the name constraints of '%s'", ip, caCert.Subject.CommonName)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func matchDomainConstraint(name, constraint string) bool {
+	constraint = strings.ToLower(constraint)
+	if strings.HasPrefix(constraint, ".") {
+		// 以点号开头的约束仅匹配其子域名
+		return strings.HasSuffix(name, constraint)
+	}
+
+	return name == constraint || strings.HasSuffix(name, "."+constraint)
+}
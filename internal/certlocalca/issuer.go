@@ -0,0 +1,119 @@
+package certlocalca
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+type Issuer struct {
+	// 签发证书所用的 CA 证书。
+	Certificate *x509.Certificate
+	// 上级证书链，不含 [Issuer.Certificate] 本身。
+	Chain []*x509.Certificate
+	// CA 私钥。
+	PrivateKey crypto.Signer
+	// CRL 分发点 URL。
+	CRLDistributionPoint string
+}
+
+// 从本地 CA 的授权配置中加载签发者。
+func LoadIssuer(accessConfig map[string]any) (*Issuer, error) {
+	credentials := domain.AccessConfigForLocalCA{}
+	if err := xmaps.Populate(accessConfig, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to populate local ca access config: %w", err)
+	}
+
+	if strings.TrimSpace(credentials.Certificate) == "" {
+		return nil, fmt.Errorf("the certificate of local ca is empty")
+	}
+	if strings.TrimSpace(credentials.PrivateKey) == "" {
+		return nil, fmt.Errorf("the private key of local ca is empty")
+	}
+
+	certs, err := parseCertificateChainFromPEM(credentials.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate of local ca: %w", err)
+	}
+
+	caCert := certs[0]
+	if !caCert.IsCA || caCert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, fmt.Errorf("the certificate of local ca is not allowed to sign certificates")
+	}
+
+	privkey, err := xcert.ParsePrivateKeyFromPEM(credentials.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key of local ca: %w", err)
+	}
+
+	signer, ok := privkey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("the private key of local ca is not a signer")
+	}
+
+	if pubkey, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pubkey.Equal(caCert.PublicKey) {
+		return nil, fmt.Errorf("the private key of local ca does not match its certificate")
+	}
+
+	return &Issuer{
+		Certificate:          caCert,
+		Chain:                certs[1:],
+		PrivateKey:           signer,
+		CRLDistributionPoint: credentials.CRLDistributionPoint,
+	}, nil
+}
+
+// 返回应附带在所签发证书之后的证书链 PEM 内容。
+// 自签名的根证书不会被包含在内，除非签发者本身即为根证书。
+func (i *Issuer) ChainPEM() string {
+	var buf bytes.Buffer
+	for _, cert := range append([]*x509.Certificate{i.Certificate}, i.Chain...) {
+		if isSelfSigned(cert) && cert != i.Certificate {
+			continue
+		}
+
+		pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+
+	return strings.TrimSpace(buf.String())
+}
+
+func parseCertificateChainFromPEM(certPEM string) ([]*x509.Certificate, error) {
+	certs := make([]*x509.Certificate, 0)
+
+	rest := []byte(certPEM)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate found")
+	}
+
+	return certs, nil
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/certimate-go/certimate/internal/certmgmt/deployers"
 	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/pkg/core"
 )
 
 type DeployCertificateRequest struct {
@@ -26,6 +27,38 @@ func (c *Client) DeployCertificate(ctx context.Context, request *DeployCertifica
 		return nil, fmt.Errorf("the request is nil")
 	}
 
+	provider, err := c.newDeployer(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkPrivateKeyRequirement(request, provider); err != nil {
+		return nil, err
+	}
+
+	provider.SetLogger(c.logger)
+	if _, err := provider.Deploy(ctx, request.CertificatePEM, request.PrivateKeyPEM); err != nil {
+		return nil, err
+	}
+
+	return &DeployCertificateResponse{}, nil
+}
+
+// 校验部署请求，仅初始化部署提供商而不实际部署证书。
+func (c *Client) ValidateDeployCertificateRequest(ctx context.Context, request *DeployCertificateRequest) error {
+	if request == nil {
+		return fmt.Errorf("the request is nil")
+	}
+
+	provider, err := c.newDeployer(request)
+	if err != nil {
+		return err
+	}
+
+	return checkPrivateKeyRequirement(request, provider)
+}
+
+func (c *Client) newDeployer(request *DeployCertificateRequest) (core.Deployer, error) {
 	providerFactory, err := deployers.Registries.Get(request.Provider)
 	if err != nil {
 		return nil, err
@@ -39,10 +72,18 @@ func (c *Client) DeployCertificate(ctx context.Context, request *DeployCertifica
 		return nil, fmt.Errorf("failed to initialize deployment provider '%s': %w", request.Provider, err)
 	}
 
-	provider.SetLogger(c.logger)
-	if _, err := provider.Deploy(ctx, request.CertificatePEM, request.PrivateKeyPEM); err != nil {
-		return nil, err
+	return provider, nil
+}
+
+// 由外部 CSR 签发的证书不含私钥，无法部署到必须提供私钥的目标。
+func checkPrivateKeyRequirement(request *DeployCertificateRequest, provider core.Deployer) error {
+	if request.CertificatePEM == "" || request.PrivateKeyPEM != "" {
+		return nil
 	}
 
-	return &DeployCertificateResponse{}, nil
+	if requirer, ok := provider.(core.DeployerPrivateKeyRequirer); ok && requirer.RequiresPrivateKey() {
+		return fmt.Errorf("the deployment provider '%s' requires a private key, but the certificate has none (it may be issued from an external csr)", request.Provider)
+	}
+
+	return nil
 }
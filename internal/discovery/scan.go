@@ -0,0 +1,152 @@
+package discovery
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+	xtls "github.com/certimate-go/certimate/pkg/utils/tls"
+)
+
+// 单次发现任务最多扫描的地址数量（不含端口）。
+const maxDiscoveryTargets = 65536
+
+type discoveryTarget struct {
+	host       string
+	serverName string
+}
+
+type discoveredCertificate struct {
+	serialNumber string
+	chain        []*x509.Certificate
+	endpoints    []string
+}
+
+// 将 CIDR 网段及主机名展开为扫描目标。
+func expandTargets(cidrs []string, hostnames []string) ([]discoveryTarget, error) {
+	targets := make([]discoveryTarget, 0)
+
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			// 允许直接填写单个 IP 地址
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("the cidr '%s' is invalid", cidr)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefix = prefix.Masked()
+
+		if hostBits := prefix.Addr().BitLen() - prefix.Bits(); hostBits >= 32 || len(targets)+(1<<hostBits) > maxDiscoveryTargets {
+			return nil, fmt.Errorf("too many addresses to scan, the limit is %d", maxDiscoveryTargets)
+		}
+
+		for addr := prefix.Addr(); addr.IsValid() && prefix.Contains(addr); addr = addr.Next() {
+			targets = append(targets, discoveryTarget{host: addr.String()})
+		}
+	}
+
+	for _, hostname := range hostnames {
+		hostname = strings.ToLower(strings.TrimSpace(hostname))
+		if hostname == "" {
+			continue
+		}
+
+		if len(targets)+1 > maxDiscoveryTargets {
+			return nil, fmt.Errorf("too many addresses to scan, the limit is %d", maxDiscoveryTargets)
+		}
+		targets = append(targets, discoveryTarget{host: hostname, serverName: hostname})
+	}
+
+	return targets, nil
+}
+
+// 探测所有目标的所有端口，并将获取到的证书按序列号去重。
+func scanTargets(ctx context.Context, targets []discoveryTarget, discoverySettings domain.SettingsContentForDiscovery) ([]*discoveredCertificate, int) {
+	var mtx sync.Mutex
+	found := make([]*discoveredCertificate, 0)
+	foundBySerial := make(map[string][]*discoveredCertificate)
+	scanned := 0
+
+	timeout := time.Duration(discoverySettings.Timeout) * time.Second
+
+	eg := errgroup.Group{}
+	eg.SetLimit(discoverySettings.Concurrency)
+	for _, target := range targets {
+		for _, port := range discoverySettings.Ports {
+			endpoint := net.JoinHostPort(target.host, strconv.Itoa(port))
+
+			eg.Go(func() error {
+				chain := probeEndpoint(ctx, endpoint, target.serverName, timeout)
+
+				mtx.Lock()
+				defer mtx.Unlock()
+
+				scanned++
+				if len(chain) == 0 {
+					return nil
+				}
+
+				leaf := chain[0]
+				serialNumber := strings.ToUpper(leaf.SerialNumber.Text(16))
+
+				// 序列号仅在同一颁发者下唯一，故还需比较证书本身
+				idx := slices.IndexFunc(foundBySerial[serialNumber], func(item *discoveredCertificate) bool {
+					return xcert.EqualCertificates(item.chain[0], leaf)
+				})
+				if idx >= 0 {
+					item := foundBySerial[serialNumber][idx]
+					if !slices.Contains(item.endpoints, endpoint) {
+						item.endpoints = append(item.endpoints, endpoint)
+					}
+					return nil
+				}
+
+				item := &discoveredCertificate{serialNumber: serialNumber, chain: chain, endpoints: []string{endpoint}}
+				foundBySerial[serialNumber] = append(foundBySerial[serialNumber], item)
+				found = append(found, item)
+				return nil
+			})
+		}
+	}
+	eg.Wait()
+
+	for _, item := range found {
+		slices.Sort(item.endpoints)
+	}
+
+	return found, scanned
+}
+
+func probeEndpoint(ctx context.Context, endpoint string, serverName string, timeout time.Duration) []*x509.Certificate {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tlsConfig := xtls.NewInsecureConfig()
+	tlsConfig.ServerName = serverName
+
+	conn, err := xtls.DialWithProtocol(ctx, endpoint, xtls.ProtocolTLS, tlsConfig)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+
+	return conn.ConnectionState().PeerCertificates
+}
@@ -0,0 +1,105 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandTargets(t *testing.T) {
+	type testInput struct {
+		cidrs     []string
+		hostnames []string
+	}
+	testCases := []struct {
+		name        string
+		input       testInput
+		expected    []discoveryTarget
+		expectedErr bool
+	}{
+		{
+			name:     "empty",
+			input:    testInput{cidrs: []string{"", " "}, hostnames: []string{""}},
+			expected: []discoveryTarget{},
+		},
+		{
+			name:  "ipv4 cidr",
+			input: testInput{cidrs: []string{"192.168.1.0/30"}},
+			expected: []discoveryTarget{
+				{host: "192.168.1.0"},
+				{host: "192.168.1.1"},
+				{host: "192.168.1.2"},
+				{host: "192.168.1.3"},
+			},
+		},
+		{
+			name:  "unmasked cidr",
+			input: testInput{cidrs: []string{" 10.0.0.5/31 "}},
+			expected: []discoveryTarget{
+				{host: "10.0.0.4"},
+				{host: "10.0.0.5"},
+			},
+		},
+		{
+			name:  "single ip addresses",
+			input: testInput{cidrs: []string{"10.0.0.1", "::1"}},
+			expected: []discoveryTarget{
+				{host: "10.0.0.1"},
+				{host: "::1"},
+			},
+		},
+		{
+			name:  "ipv6 cidr",
+			input: testInput{cidrs: []string{"2001:db8::/127"}},
+			expected: []discoveryTarget{
+				{host: "2001:db8::"},
+				{host: "2001:db8::1"},
+			},
+		},
+		{
+			name:  "hostnames",
+			input: testInput{cidrs: []string{"10.0.0.1"}, hostnames: []string{" Example.COM ", "www.example.com"}},
+			expected: []discoveryTarget{
+				{host: "10.0.0.1"},
+				{host: "example.com", serverName: "example.com"},
+				{host: "www.example.com", serverName: "www.example.com"},
+			},
+		},
+		{
+			name:        "invalid cidr",
+			input:       testInput{cidrs: []string{"10.0.0.0/33"}},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid address",
+			input:       testInput{cidrs: []string{"example.com"}},
+			expectedErr: true,
+		},
+		{
+			name:        "too many addresses",
+			input:       testInput{cidrs: []string{"10.0.0.0/15"}},
+			expectedErr: true,
+		},
+		{
+			name:        "too many addresses in total",
+			input:       testInput{cidrs: []string{"10.0.0.0/16", "10.1.0.0/31"}},
+			expectedErr: true,
+		},
+		{
+			name:        "too many ipv6 addresses",
+			input:       testInput{cidrs: []string{"2001:db8::/64"}},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			targets, err := expandTargets(tc.input.cidrs, tc.input.hostnames)
+			if tc.expectedErr {
+				assert.Error(t, err, "Case: %-20s", tc.name)
+			} else {
+				assert.NoError(t, err, "Case: %-20s", tc.name)
+				assert.Equal(t, tc.expected, targets, "Case: %-20s", tc.name)
+			}
+		})
+	}
+}
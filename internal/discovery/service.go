@@ -0,0 +1,167 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/settings"
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+type DiscoveryService struct {
+	certificateRepo certificateRepository
+
+	running atomic.Bool
+}
+
+func NewDiscoveryService(certificateRepo certificateRepository) *DiscoveryService {
+	return &DiscoveryService{
+		certificateRepo: certificateRepo,
+	}
+}
+
+func (s *DiscoveryService) InitSchedule(ctx context.Context) error {
+	app.GetScheduler().MustAdd("runNetworkDiscovery", "0 2 * * *", func() {
+		discoverySettings := settings.GetGlobalSettingsForDiscovery()
+		if !discoverySettings.Enabled {
+			return
+		}
+
+		res, err := s.runDiscovery(context.Background(), discoverySettings)
+		if err != nil {
+			app.GetLogger().Error("failed to run network discovery", slog.Any("error", err))
+			return
+		}
+
+		app.GetLogger().Info(fmt.Sprintf("network discovery finished: %d scanned, %d found, %d created, %d updated", res.Scanned, res.Found, res.Created, res.Updated))
+	})
+
+	return nil
+}
+
+// 执行一次发现任务。请求中未指定扫描目标时使用全局设置中的配置。
+func (s *DiscoveryService) RunDiscovery(ctx context.Context, req *dtos.DiscoveryRunReq) (*dtos.DiscoveryRunResp, error) {
+	discoverySettings := settings.GetGlobalSettingsForDiscovery()
+	if len(req.CIDRs) > 0 || len(req.Hostnames) > 0 {
+		discoverySettings.CIDRs = req.CIDRs
+		discoverySettings.Hostnames = req.Hostnames
+	}
+	if len(req.Ports) > 0 {
+		discoverySettings.Ports = req.Ports
+	}
+
+	return s.runDiscovery(ctx, discoverySettings)
+}
+
+func (s *DiscoveryService) runDiscovery(ctx context.Context, discoverySettings domain.SettingsContentForDiscovery) (*dtos.DiscoveryRunResp, error) {
+	// 同一时刻仅允许运行一个发现任务，避免大网段扫描时任务堆积
+	if !s.running.CompareAndSwap(false, true) {
+		return nil, errors.New("network discovery is already running")
+	}
+	defer s.running.Store(false)
+
+	targets, err := expandTargets(discoverySettings.CIDRs, discoverySettings.Hostnames)
+	if err != nil {
+		return nil, err
+	} else if len(targets) == 0 {
+		return nil, domain.ErrInvalidParams
+	}
+
+	for _, port := range discoverySettings.Ports {
+		if port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("the port '%d' is invalid", port)
+		}
+	}
+
+	found, scanned := scanTargets(ctx, targets, discoverySettings)
+
+	resp := &dtos.DiscoveryRunResp{Scanned: scanned, Found: len(found)}
+	for _, item := range found {
+		result, err := s.importCertificate(ctx, item)
+		if err != nil {
+			app.GetLogger().Error(fmt.Sprintf("failed to import discovered certificate '%s'", item.serialNumber), slog.Any("error", err))
+			continue
+		}
+
+		switch result {
+		case importResultCreated:
+			resp.Created++
+		case importResultUpdated:
+			resp.Updated++
+		default:
+			resp.Unchanged++
+		}
+	}
+
+	return resp, nil
+}
+
+type importResult int
+
+const (
+	importResultUnchanged importResult = iota
+	importResultCreated
+	importResultUpdated
+)
+
+// 将发现的证书导入至证书集合。已存在的证书仅合并其被发现的端点，否则新建一条来源为 "discovered" 的证书记录。
+func (s *DiscoveryService) importCertificate(ctx context.Context, item *discoveredCertificate) (importResult, error) {
+	leaf := item.chain[0]
+
+	existings, err := s.certificateRepo.ListBySerialNumber(ctx, item.serialNumber)
+	if err != nil {
+		return importResultUnchanged, err
+	}
+
+	for _, existing := range existings {
+		existingX509, err := xcert.ParseCertificateFromPEM(existing.Certificate)
+		if err != nil || !xcert.EqualCertificates(existingX509, leaf) {
+			continue
+		}
+
+		endpoints := slices.Clone(existing.DiscoveredEndpoints)
+		for _, endpoint := range item.endpoints {
+			if !slices.Contains(endpoints, endpoint) {
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+		if len(endpoints) == len(existing.DiscoveredEndpoints) {
+			return importResultUnchanged, nil
+		}
+
+		slices.Sort(endpoints)
+		existing.DiscoveredEndpoints = endpoints
+		if _, err := s.certificateRepo.Save(ctx, existing); err != nil {
+			return importResultUnchanged, err
+		}
+
+		return importResultUpdated, nil
+	}
+
+	chainPEM := ""
+	for _, cert := range item.chain {
+		certPEM, err := xcert.ConvertCertificateToPEM(cert)
+		if err != nil {
+			return importResultUnchanged, err
+		}
+		chainPEM += certPEM
+	}
+
+	certificate := &domain.Certificate{
+		Source:              domain.CertificateSourceTypeDiscovered,
+		DiscoveredEndpoints: item.endpoints,
+	}
+	certificate.PopulateFromPEM(chainPEM, "")
+	if _, err := s.certificateRepo.Save(ctx, certificate); err != nil {
+		return importResultUnchanged, err
+	}
+
+	return importResultCreated, nil
+}
@@ -0,0 +1,12 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type certificateRepository interface {
+	ListBySerialNumber(ctx context.Context, serialNumber string) ([]*domain.Certificate, error)
+	Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error)
+}
@@ -0,0 +1,149 @@
+package dnsresponder
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/certimate-go/certimate/internal/app"
+	chlgimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/dns01/local"
+)
+
+type Config struct {
+	// 监听地址，形如 ":53"。
+	ListenAddress string
+	// 权威区域，形如 "acme.example.com"；零值时不启用。
+	Zone string
+	// 权威区域的域名服务器主机名。
+	Nameserver string
+	// 应答的 TTL。
+	TTL int
+}
+
+var (
+	zone      string
+	server    *chlgimpl.Server
+	memStore  chlgimpl.RecordStore
+	fileStore chlgimpl.RecordStore
+	mtx       sync.Mutex
+)
+
+// 是否已配置内置权威 DNS 服务器。
+func IsEnabled() bool {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	return zone != ""
+}
+
+// 返回内置权威 DNS 服务器的区域。
+func GetZone() string {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	return zone
+}
+
+// 返回将各域名委派至内置权威 DNS 服务器所需创建的 CNAME 记录。
+// 泛域名与其主域名共用同一条记录。
+func GetCNAMERecords(domains []string) []string {
+	zone := GetZone()
+
+	records := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		record := chlgimpl.CNAMERecord(domain, zone)
+		if !slices.Contains(records, record) {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+// 返回发布质询记录所用的存储。
+// 在服务进程内直接发布至内置权威 DNS 服务器的进程内存储；
+// 在证书申请子进程中则写入数据目录下的文件存储，由服务进程读取。
+func GetRecordStore() (chlgimpl.RecordStore, error) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if server != nil {
+		return memStore, nil
+	}
+
+	return getFileStore()
+}
+
+// 在证书申请子进程中指定内置权威 DNS 服务器的区域。
+// 区域由服务进程通过多进程指令传入。
+func UseZone(z string) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	zone = z
+}
+
+// 启动内置权威 DNS 服务器。
+// 仅在服务器成功监听后才启用，否则质询记录将无处应答。
+func Setup(config *Config) error {
+	if config == nil || config.Zone == "" {
+		return nil
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	mstore := chlgimpl.NewMemoryRecordStore()
+	stores := []chlgimpl.RecordStore{mstore}
+	if store, err := getFileStore(); err != nil {
+		app.GetLogger().Error("failed to init dns responder record store, records published by child processes will be ignored", slog.Any("error", err))
+	} else {
+		stores = append(stores, store)
+	}
+
+	srv, err := chlgimpl.NewServer(&chlgimpl.ServerConfig{
+		ListenAddress: config.ListenAddress,
+		Zone:          config.Zone,
+		Nameserver:    config.Nameserver,
+		TTL:           config.TTL,
+		Stores:        stores,
+	}, app.GetLogger())
+	if err != nil {
+		return fmt.Errorf("failed to init dns responder: %w", err)
+	}
+
+	if err := srv.Start(); err != nil {
+		return fmt.Errorf("failed to start dns responder on '%s': %w", config.ListenAddress, err)
+	}
+
+	zone = config.Zone
+	server = srv
+	memStore = mstore
+	return nil
+}
+
+func Teardown() {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if server != nil {
+		server.Shutdown(context.Background())
+		server = nil
+	}
+}
+
+func getFileStore() (chlgimpl.RecordStore, error) {
+	if fileStore == nil {
+		store, err := chlgimpl.NewFileRecordStore(filepath.Join(app.GetApp().DataDir(), "dnsresponder"))
+		if err != nil {
+			return nil, err
+		}
+
+		fileStore = store
+	}
+
+	return fileStore, nil
+}
@@ -404,6 +404,11 @@ type AccessConfigForLeCDN struct {
 	AllowInsecureConnections bool   `json:"allowInsecureConnections,omitempty"`
 }
 
+type AccessConfigForLego struct {
+	ProviderName string            `json:"providerName"`
+	Credentials  map[string]string `json:"credentials,omitempty"`
+}
+
 type AccessConfigForLinode struct {
 	AccessToken string `json:"accessToken"`
 }
@@ -412,6 +417,12 @@ type AccessConfigForLiteSSL struct {
 	AccessConfigForACMEExternalAccountBinding
 }
 
+type AccessConfigForLocalCA struct {
+	Certificate          string `json:"certificate"`                    // 签发证书所用的 CA 证书 PEM 内容，可附带其上级证书链
+	PrivateKey           string `json:"privateKey"`                     // CA 证书对应的私钥 PEM 内容
+	CRLDistributionPoint string `json:"crlDistributionPoint,omitempty"` // 写入所签发证书中的 CRL 分发点 URL，通常为 "<服务地址>/api/certificates/localca/<授权 ID>/crl"
+}
+
 type AccessConfigForMatrix struct {
 	ServerUrl   string `json:"serverUrl"`
 	UserId      string `json:"userId"`
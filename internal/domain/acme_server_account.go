@@ -0,0 +1,12 @@
+package domain
+
+const CollectionNameACMEServerAccount = "acme_server_account"
+
+type ACMEServerAccount struct {
+	Meta
+	ClientId      string   `db:"clientRef"     json:"clientId"`
+	Key           string   `db:"key"           json:"key"`
+	KeyThumbprint string   `db:"keyThumbprint" json:"keyThumbprint"`
+	Contact       []string `db:"contact"       json:"contact"`
+	Status        string   `db:"status"        json:"status"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"net"
+	"strings"
+)
+
+const CollectionNameACMEServerClient = "acme_server_client"
+
+type ACMEServerClient struct {
+	Meta
+	Name               string   `db:"name"               json:"name"`
+	EabKid             string   `db:"eabKid"             json:"eabKid"`
+	EabHmacKey         string   `db:"eabHmacKey"         json:"eabHmacKey"`
+	AllowedIdentifiers []string `db:"allowedIdentifiers" json:"allowedIdentifiers"`
+	Disabled           bool     `db:"disabled"           json:"disabled"`
+}
+
+// 判断客户端是否允许为指定的标识符申请证书。
+// 规则可以是：
+//   - 精确的域名，如 "foo.example.com"；
+//   - 以 "*." 开头的通配规则，匹配其下任意层级的子域名（包括同名的通配符域名），如 "*.example.com"；
+//   - "*"，匹配任意域名；
+//   - IP 地址或 CIDR，如 "10.0.0.1"、"10.0.0.0/8"。
+//
+// 未配置任何规则时，拒绝所有标识符。
+func (c *ACMEServerClient) IsIdentifierAllowed(identifierType, identifierValue string) bool {
+	identifierValue = strings.ToLower(strings.TrimSpace(identifierValue))
+
+	for _, rule := range c.AllowedIdentifiers {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		if rule == "" {
+			continue
+		}
+
+		switch identifierType {
+		case "dns":
+			if net.ParseIP(rule) != nil || strings.Contains(rule, "/") {
+				continue
+			}
+
+			if rule == "*" || rule == identifierValue {
+				return true
+			}
+
+			if strings.HasPrefix(rule, "*.") && strings.HasSuffix(identifierValue, rule[1:]) {
+				return true
+			}
+
+		case "ip":
+			ip := net.ParseIP(identifierValue)
+			if ip == nil {
+				return false
+			}
+
+			if _, ipnet, err := net.ParseCIDR(rule); err == nil {
+				if ipnet.Contains(ip) {
+					return true
+				}
+			} else if ruleIP := net.ParseIP(rule); ruleIP != nil && ruleIP.Equal(ip) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
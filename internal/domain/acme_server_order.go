@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+)
+
+const CollectionNameACMEServerOrder = "acme_server_order"
+
+type ACMEServerOrder struct {
+	Meta
+	OrderId       string         `db:"orderId"        json:"orderId"`
+	AccountId     string         `db:"accountRef"     json:"accountId"`
+	Status        string         `db:"status"         json:"status"`
+	Expires       time.Time      `db:"expires"        json:"expires"`
+	CertificateId string         `db:"certificateRef" json:"certificateId"`
+	Content       map[string]any `db:"content"        json:"content"`
+}
@@ -34,12 +34,19 @@ type Certificate struct {
 	CA                 string                          `db:"ca"                json:"ca"`
 	ACMEAccountUrl     string                          `db:"acmeAcctUrl"       json:"acmeAcctUrl"`
 	ACMECertificateUrl string                          `db:"acmeCertUrl"       json:"acmeCertUrl"`
+	ARIWindowStart     time.Time                       `db:"ariWindowStart"    json:"ariWindowStart"`
+	ARIWindowEnd       time.Time                       `db:"ariWindowEnd"      json:"ariWindowEnd"`
+	ARIRetryAfter      time.Time                       `db:"ariRetryAfter"     json:"ariRetryAfter"`
 	IsRenewed          bool                            `db:"isRenewed"         json:"isRenewed"`
 	IsRevoked          bool                            `db:"isRevoked"         json:"isRevoked"`
+	RevokedAt          time.Time                       `db:"revokedAt"         json:"revokedAt"`
 	WorkflowId         string                          `db:"workflowRef"       json:"workflowId"`
 	WorkflowRunId      string                          `db:"workflowRunRef"    json:"workflowRunId"`
 	WorkflowNodeId     string                          `db:"workflowNodeId"    json:"workflowNodeId"`
 	DeletedAt          *time.Time                      `db:"deleted" json:"deleted"`
+
+	// 由网络发现导入时，该证书被发现的端点列表。
+	DiscoveredEndpoints []string `db:"discoveredEndpoints" json:"discoveredEndpoints"`
 }
 
 func (c *Certificate) PopulateFromX509(certX509 *x509.Certificate) *Certificate {
@@ -103,8 +110,10 @@ func (t CertificateSourceType) String() string {
 }
 
 const (
-	CertificateSourceTypeRequest = CertificateSourceType("request")
-	CertificateSourceTypeUpload  = CertificateSourceType("upload")
+	CertificateSourceTypeRequest    = CertificateSourceType("request")
+	CertificateSourceTypeUpload     = CertificateSourceType("upload")
+	CertificateSourceTypeACMEServer = CertificateSourceType("acmeserver")
+	CertificateSourceTypeDiscovered = CertificateSourceType("discovered")
 )
 
 type CertificateKeyAlgorithmType certcrypto.KeyType
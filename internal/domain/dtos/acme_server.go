@@ -0,0 +1,23 @@
+package dtos
+
+type ACMEServerReq struct {
+	// ACME 服务的基础 URL，如 "https://certimate.example.com/acme"。
+	BaseUrl string `json:"-"`
+	// 请求的完整 URL，须与 JWS 受保护头部中的 "url" 一致。
+	Url string `json:"-"`
+	// 路径中的资源 ID，如账户、订单、授权、质询或证书的 ID。
+	ResourceId string `json:"-"`
+	// 请求体，即 JWS（Flattened JSON Serialization）。
+	Body []byte `json:"-"`
+}
+
+type ACMEServerResp struct {
+	StatusCode  int      `json:"-"`
+	Location    string   `json:"-"`
+	Links       []string `json:"-"`
+	RetryAfter  int      `json:"-"`
+	ContentType string   `json:"-"`
+	// 响应体。
+	// 为字节切片时原样输出，否则序列化为 JSON。
+	Content any `json:"-"`
+}
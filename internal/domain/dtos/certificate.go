@@ -1,6 +1,8 @@
 package dtos
 
 import (
+	"time"
+
 	"github.com/certimate-go/certimate/internal/domain"
 )
 
@@ -23,3 +25,32 @@ type CertificateRevokeReq struct {
 }
 
 type CertificateRevokeResp struct{}
+
+type CertificateGenerateLocalCAReq struct {
+	CommonName               string   `json:"commonName"`
+	Organization             string   `json:"organization,omitempty"`
+	KeyAlgorithm             string   `json:"keyAlgorithm,omitempty"`
+	RootValidityDays         int      `json:"rootValidityDays,omitempty"`
+	IntermediateValidityDays int      `json:"intermediateValidityDays,omitempty"`
+	PermittedDNSDomains      []string `json:"permittedDnsDomains,omitempty"`
+	ExcludedDNSDomains       []string `json:"excludedDnsDomains,omitempty"`
+	PermittedIPRanges        []string `json:"permittedIpRanges,omitempty"`
+	ExcludedIPRanges         []string `json:"excludedIpRanges,omitempty"`
+}
+
+type CertificateGenerateLocalCAResp struct {
+	RootCertificate         string `json:"rootCertificate"`
+	RootPrivateKey          string `json:"rootPrivateKey"`
+	IntermediateCertificate string `json:"intermediateCertificate"`
+	IntermediatePrivateKey  string `json:"intermediatePrivateKey"`
+}
+
+type CertificateGenerateLocalCACRLReq struct {
+	AccessId string `json:"-"`
+}
+
+type CertificateGenerateLocalCACRLResp struct {
+	CRL        string    `json:"crl"`
+	ThisUpdate time.Time `json:"thisUpdate"`
+	NextUpdate time.Time `json:"nextUpdate"`
+}
@@ -0,0 +1,19 @@
+package dtos
+
+type DiscoveryRunReq struct {
+	// 待扫描的 CIDR 网段及主机名。
+	// 均为空时使用全局设置中的配置。
+	CIDRs     []string `json:"cidrs"`
+	Hostnames []string `json:"hostnames"`
+	// 待扫描的端口。
+	// 为空时使用全局设置中的配置。
+	Ports []int `json:"ports"`
+}
+
+type DiscoveryRunResp struct {
+	Scanned   int `json:"scanned"`
+	Found     int `json:"found"`
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
@@ -0,0 +1,25 @@
+package dtos
+
+import (
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type MonitoredEndpointScanReq struct {
+	EndpointId string `json:"-"`
+}
+
+type MonitoredEndpointScanResp struct {
+	Scan *domain.MonitoredEndpointScan `json:"scan"`
+}
+
+type MonitoredEndpointImportReq struct {
+	// CSV 格式的端点列表，首行须为表头。
+	// 支持的列：name、host、port、domain、protocol、scanInterval、warningDays，其中 host 为必填列。
+	Content string `json:"content"`
+}
+
+type MonitoredEndpointImportResp struct {
+	Created int      `json:"created"`
+	Updated int      `json:"updated"`
+	Errors  []string `json:"errors"`
+}
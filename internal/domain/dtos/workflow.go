@@ -5,8 +5,9 @@ import (
 )
 
 type WorkflowStartRunReq struct {
-	WorkflowId string                     `json:"-"`
-	RunTrigger domain.WorkflowTriggerType `json:"trigger"`
+	WorkflowId     string                     `json:"-"`
+	RunTrigger     domain.WorkflowTriggerType `json:"trigger"`
+	RunTriggerData map[string]any             `json:"-"`
 }
 
 type WorkflowStartRunResp struct {
@@ -20,6 +21,55 @@ type WorkflowCancelRunReq struct {
 
 type WorkflowCancelRunResp struct{}
 
+type WorkflowResumeRunReq struct {
+	WorkflowId string `bind:"path" json:"-"`
+	RunId      string `bind:"path" json:"-"`
+}
+
+type WorkflowResumeRunResp struct {
+	RunId string `json:"runId"`
+}
+
+type WorkflowListVersionsReq struct {
+	WorkflowId string `bind:"path" json:"-"`
+}
+
+type WorkflowListVersionsResp struct {
+	Versions []*domain.WorkflowVersion `json:"versions"`
+}
+
+type WorkflowDiffVersionsReq struct {
+	WorkflowId    string `bind:"path" json:"-"`
+	FromVersionId string `json:"-"`
+	ToVersionId   string `json:"-"`
+}
+
+type WorkflowDiffVersionsResp struct {
+	FromVersion int                       `json:"fromVersion"`
+	ToVersion   int                       `json:"toVersion"` // 零值表示当前已发布的内容
+	Diff        *domain.WorkflowGraphDiff `json:"diff"`
+}
+
+type WorkflowRollbackVersionReq struct {
+	WorkflowId string `bind:"path" json:"-"`
+	VersionId  string `bind:"path" json:"-"`
+}
+
+type WorkflowRollbackVersionResp struct {
+	VersionId string `json:"versionId"`
+	Version   int    `json:"version"`
+}
+
+type WorkflowTriggerWebhookReq struct {
+	WorkflowId string         `bind:"path" json:"-"`
+	Secret     string         `json:"-"`
+	Payload    map[string]any `json:"-"`
+}
+
+type WorkflowTriggerWebhookResp struct {
+	RunId string `json:"runId"`
+}
+
 type WorkflowStatisticsResp struct {
 	Concurrency      int      `json:"concurrency"`
 	PendingRunIds    []string `json:"pendingRunIds"`
@@ -0,0 +1,245 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+func (e *EvalResult) Add(other *EvalResult) (*EvalResult, error) {
+	if e.Type == DateTime && other.Type == Duration {
+		return e.shiftTime(other, 1)
+	} else if e.Type == Duration && other.Type == DateTime {
+		return other.shiftTime(e, 1)
+	}
+
+	if err := e.coerce(other); err != nil {
+		return nil, err
+	}
+
+	switch e.Type {
+	case String:
+		left, err := e.GetString()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetString()
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{
+			Type:  String,
+			Value: left + right,
+		}, nil
+
+	case Number:
+		return e.calcNumber(other, func(l, r float64) (float64, error) { return l + r, nil })
+
+	case Duration:
+		return e.calcDuration(other, func(l, r time.Duration) time.Duration { return l + r })
+
+	default:
+		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+	}
+}
+
+func (e *EvalResult) Subtract(other *EvalResult) (*EvalResult, error) {
+	if e.Type == DateTime && other.Type == Duration {
+		return e.shiftTime(other, -1)
+	}
+
+	if err := e.coerce(other); err != nil {
+		return nil, err
+	}
+
+	switch e.Type {
+	case Number:
+		return e.calcNumber(other, func(l, r float64) (float64, error) { return l - r, nil })
+
+	case Duration:
+		return e.calcDuration(other, func(l, r time.Duration) time.Duration { return l - r })
+
+	case DateTime:
+		left, err := e.GetTime()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetTime()
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{
+			Type:  Duration,
+			Value: left.Sub(right),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+	}
+}
+
+func (e *EvalResult) Multiply(other *EvalResult) (*EvalResult, error) {
+	if e.Type == Duration && other.Type == Number {
+		return e.scaleDuration(other, false)
+	} else if e.Type == Number && other.Type == Duration {
+		return other.scaleDuration(e, false)
+	}
+
+	if err := e.coerce(other); err != nil {
+		return nil, err
+	}
+
+	switch e.Type {
+	case Number:
+		return e.calcNumber(other, func(l, r float64) (float64, error) { return l * r, nil })
+
+	default:
+		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+	}
+}
+
+func (e *EvalResult) Divide(other *EvalResult) (*EvalResult, error) {
+	if e.Type == Duration && other.Type == Number {
+		return e.scaleDuration(other, true)
+	}
+
+	if err := e.coerce(other); err != nil {
+		return nil, err
+	}
+
+	switch e.Type {
+	case Number:
+		return e.calcNumber(other, func(l, r float64) (float64, error) {
+			if r == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			return l / r, nil
+		})
+
+	case Duration:
+		left, err := e.GetDuration()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetDuration()
+		if err != nil {
+			return nil, err
+		}
+
+		if right == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+
+		return &EvalResult{
+			Type:  Number,
+			Value: float64(left) / float64(right),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+	}
+}
+
+func (e *EvalResult) Modulo(other *EvalResult) (*EvalResult, error) {
+	if err := e.coerce(other); err != nil {
+		return nil, err
+	}
+
+	switch e.Type {
+	case Number:
+		return e.calcNumber(other, func(l, r float64) (float64, error) {
+			if r == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			return math.Mod(l, r), nil
+		})
+
+	default:
+		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+	}
+}
+
+func (e *EvalResult) calcNumber(other *EvalResult, fn func(l, r float64) (float64, error)) (*EvalResult, error) {
+	left, err := e.GetFloat64()
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := other.GetFloat64()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := fn(left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvalResult{
+		Type:  Number,
+		Value: value,
+	}, nil
+}
+
+func (e *EvalResult) calcDuration(other *EvalResult, fn func(l, r time.Duration) time.Duration) (*EvalResult, error) {
+	left, err := e.GetDuration()
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := other.GetDuration()
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvalResult{
+		Type:  Duration,
+		Value: fn(left, right),
+	}, nil
+}
+
+func (e *EvalResult) shiftTime(duration *EvalResult, sign int) (*EvalResult, error) {
+	t, err := e.GetTime()
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := duration.GetDuration()
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvalResult{
+		Type:  DateTime,
+		Value: t.Add(time.Duration(sign) * d),
+	}, nil
+}
+
+func (e *EvalResult) scaleDuration(factor *EvalResult, divide bool) (*EvalResult, error) {
+	d, err := e.GetDuration()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := factor.GetFloat64()
+	if err != nil {
+		return nil, err
+	}
+
+	if divide {
+		if f == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		f = 1 / f
+	}
+
+	return &EvalResult{
+		Type:  Duration,
+		Value: time.Duration(float64(d) * f),
+	}, nil
+}
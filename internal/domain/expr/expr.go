@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 )
 
 type (
 	ExprType               string
 	ExprComparisonOperator string
 	ExprLogicalOperator    string
+	ExprArithmeticOperator string
 	ExprValueType          string
 )
 
@@ -25,20 +27,32 @@ const (
 	Or  ExprLogicalOperator = "or"
 	Not ExprLogicalOperator = "not"
 
-	Number  ExprValueType = "number"
-	String  ExprValueType = "string"
-	Boolean ExprValueType = "boolean"
+	Add      ExprArithmeticOperator = "add"
+	Subtract ExprArithmeticOperator = "sub"
+	Multiply ExprArithmeticOperator = "mul"
+	Divide   ExprArithmeticOperator = "div"
+	Modulo   ExprArithmeticOperator = "mod"
+
+	Number   ExprValueType = "number"
+	String   ExprValueType = "string"
+	Boolean  ExprValueType = "boolean"
+	DateTime ExprValueType = "datetime"
+	Duration ExprValueType = "duration"
 
 	ConstantExprType   ExprType = "const"
 	VariantExprType    ExprType = "var"
 	ComparisonExprType ExprType = "comparison"
 	LogicalExprType    ExprType = "logical"
 	NotExprType        ExprType = "not"
+	ArithmeticExprType ExprType = "arithmetic"
+	FunctionExprType   ExprType = "func"
 )
 
 type EvalResult struct {
 	Type  ExprValueType
 	Value any
+
+	inferred bool // 值类型是否由变量值推断而来，推断的类型可在运算时按另一操作数的类型转换
 }
 
 func (e *EvalResult) GetFloat64() (float64, error) {
@@ -46,6 +60,19 @@ func (e *EvalResult) GetFloat64() (float64, error) {
 		return 0, fmt.Errorf("type mismatch: %s", e.Type)
 	}
 
+	switch v := e.Value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	}
+
 	stringValue, ok := e.Value.(string)
 	if !ok {
 		return 0, fmt.Errorf("value is not a string: %v", e.Value)
@@ -58,6 +85,144 @@ func (e *EvalResult) GetFloat64() (float64, error) {
 	return floatValue, nil
 }
 
+func (e *EvalResult) GetString() (string, error) {
+	if e.Type != String {
+		return "", fmt.Errorf("type mismatch: %s", e.Type)
+	}
+
+	switch v := e.Value.(type) {
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+
+	return "", fmt.Errorf("value is not a string: %v", e.Value)
+}
+
+func (e *EvalResult) GetTime() (time.Time, error) {
+	if e.Type != DateTime {
+		return time.Time{}, fmt.Errorf("type mismatch: %s", e.Type)
+	}
+
+	switch v := e.Value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		return parseDateTime(v)
+	}
+
+	return time.Time{}, fmt.Errorf("value is not a datetime: %v", e.Value)
+}
+
+func (e *EvalResult) GetDuration() (time.Duration, error) {
+	if e.Type != Duration {
+		return 0, fmt.Errorf("type mismatch: %s", e.Type)
+	}
+
+	switch v := e.Value.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		return parseDuration(v)
+	}
+
+	return 0, fmt.Errorf("value is not a duration: %v", e.Value)
+}
+
+// 当两个操作数类型不一致时，尝试将类型由推断而来的一方转换为另一方的类型。
+func (e *EvalResult) coerce(other *EvalResult) error {
+	if e.Type == other.Type {
+		return nil
+	}
+
+	if e.inferred {
+		if err := e.convert(other.Type); err == nil {
+			return nil
+		}
+	}
+
+	if other.inferred {
+		if err := other.convert(e.Type); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+}
+
+func (e *EvalResult) convert(valueType ExprValueType) error {
+	converted := &EvalResult{Type: valueType, Value: fmt.Sprintf("%v", e.Value)}
+	if t, ok := e.Value.(time.Time); ok {
+		converted.Value = t.Format(time.RFC3339)
+	}
+
+	var err error
+	switch valueType {
+	case Number:
+		_, err = converted.GetFloat64()
+	case String:
+		_, err = converted.GetString()
+	case Boolean:
+		_, err = converted.GetBool()
+	case DateTime:
+		_, err = converted.GetTime()
+	case Duration:
+		_, err = converted.GetDuration()
+	default:
+		err = fmt.Errorf("unsupported value type: %s", valueType)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.Type = converted.Type
+	e.Value = converted.Value
+	e.inferred = false
+	return nil
+}
+
+// 比较两个日期时间或时长类型的值，返回 -1、0 或 1。
+func (e *EvalResult) compareTemporal(other *EvalResult) (int, error) {
+	switch e.Type {
+	case DateTime:
+		left, err := e.GetTime()
+		if err != nil {
+			return 0, err
+		}
+
+		right, err := other.GetTime()
+		if err != nil {
+			return 0, err
+		}
+
+		return left.Compare(right), nil
+
+	case Duration:
+		left, err := e.GetDuration()
+		if err != nil {
+			return 0, err
+		}
+
+		right, err := other.GetDuration()
+		if err != nil {
+			return 0, err
+		}
+
+		switch {
+		case left < right:
+			return -1, nil
+		case left > right:
+			return 1, nil
+		default:
+			return 0, nil
+		}
+
+	default:
+		return 0, fmt.Errorf("unsupported value type: %s", e.Type)
+	}
+}
+
 func (e *EvalResult) GetBool() (bool, error) {
 	if e.Type != Boolean {
 		return false, fmt.Errorf("type mismatch: %s", e.Type)
@@ -82,15 +247,25 @@ func (e *EvalResult) GetBool() (bool, error) {
 }
 
 func (e *EvalResult) GreaterThan(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+	if err := e.coerce(other); err != nil {
+		return nil, err
 	}
 
 	switch e.Type {
 	case String:
+		left, err := e.GetString()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetString()
+		if err != nil {
+			return nil, err
+		}
+
 		return &EvalResult{
 			Type:  Boolean,
-			Value: e.Value.(string) > other.Value.(string),
+			Value: left > right,
 		}, nil
 
 	case Number:
@@ -109,21 +284,42 @@ func (e *EvalResult) GreaterThan(other *EvalResult) (*EvalResult, error) {
 			Value: left > right,
 		}, nil
 
+	case DateTime, Duration:
+		c, err := e.compareTemporal(other)
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{
+			Type:  Boolean,
+			Value: c > 0,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
 	}
 }
 
 func (e *EvalResult) GreaterOrEqual(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+	if err := e.coerce(other); err != nil {
+		return nil, err
 	}
 
 	switch e.Type {
 	case String:
+		left, err := e.GetString()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetString()
+		if err != nil {
+			return nil, err
+		}
+
 		return &EvalResult{
 			Type:  Boolean,
-			Value: e.Value.(string) >= other.Value.(string),
+			Value: left >= right,
 		}, nil
 
 	case Number:
@@ -142,21 +338,42 @@ func (e *EvalResult) GreaterOrEqual(other *EvalResult) (*EvalResult, error) {
 			Value: left >= right,
 		}, nil
 
+	case DateTime, Duration:
+		c, err := e.compareTemporal(other)
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{
+			Type:  Boolean,
+			Value: c >= 0,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
 	}
 }
 
 func (e *EvalResult) LessThan(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+	if err := e.coerce(other); err != nil {
+		return nil, err
 	}
 
 	switch e.Type {
 	case String:
+		left, err := e.GetString()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetString()
+		if err != nil {
+			return nil, err
+		}
+
 		return &EvalResult{
 			Type:  Boolean,
-			Value: e.Value.(string) < other.Value.(string),
+			Value: left < right,
 		}, nil
 
 	case Number:
@@ -175,21 +392,42 @@ func (e *EvalResult) LessThan(other *EvalResult) (*EvalResult, error) {
 			Value: left < right,
 		}, nil
 
+	case DateTime, Duration:
+		c, err := e.compareTemporal(other)
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{
+			Type:  Boolean,
+			Value: c < 0,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
 	}
 }
 
 func (e *EvalResult) LessOrEqual(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+	if err := e.coerce(other); err != nil {
+		return nil, err
 	}
 
 	switch e.Type {
 	case String:
+		left, err := e.GetString()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetString()
+		if err != nil {
+			return nil, err
+		}
+
 		return &EvalResult{
 			Type:  Boolean,
-			Value: e.Value.(string) <= other.Value.(string),
+			Value: left <= right,
 		}, nil
 
 	case Number:
@@ -208,21 +446,42 @@ func (e *EvalResult) LessOrEqual(other *EvalResult) (*EvalResult, error) {
 			Value: left <= right,
 		}, nil
 
+	case DateTime, Duration:
+		c, err := e.compareTemporal(other)
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{
+			Type:  Boolean,
+			Value: c <= 0,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
 	}
 }
 
 func (e *EvalResult) Equal(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+	if err := e.coerce(other); err != nil {
+		return nil, err
 	}
 
 	switch e.Type {
 	case String:
+		left, err := e.GetString()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetString()
+		if err != nil {
+			return nil, err
+		}
+
 		return &EvalResult{
 			Type:  Boolean,
-			Value: e.Value.(string) == other.Value.(string),
+			Value: left == right,
 		}, nil
 
 	case Number:
@@ -257,21 +516,42 @@ func (e *EvalResult) Equal(other *EvalResult) (*EvalResult, error) {
 			Value: left == right,
 		}, nil
 
+	case DateTime, Duration:
+		c, err := e.compareTemporal(other)
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{
+			Type:  Boolean,
+			Value: c == 0,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
 	}
 }
 
 func (e *EvalResult) NotEqual(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+	if err := e.coerce(other); err != nil {
+		return nil, err
 	}
 
 	switch e.Type {
 	case String:
+		left, err := e.GetString()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetString()
+		if err != nil {
+			return nil, err
+		}
+
 		return &EvalResult{
 			Type:  Boolean,
-			Value: e.Value.(string) != other.Value.(string),
+			Value: left != right,
 		}, nil
 
 	case Number:
@@ -306,14 +586,25 @@ func (e *EvalResult) NotEqual(other *EvalResult) (*EvalResult, error) {
 			Value: left != right,
 		}, nil
 
+	case DateTime, Duration:
+		c, err := e.compareTemporal(other)
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{
+			Type:  Boolean,
+			Value: c != 0,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
 	}
 }
 
 func (e *EvalResult) And(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+	if err := e.coerce(other); err != nil {
+		return nil, err
 	}
 
 	switch e.Type {
@@ -339,8 +630,8 @@ func (e *EvalResult) And(other *EvalResult) (*EvalResult, error) {
 }
 
 func (e *EvalResult) Or(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+	if err := e.coerce(other); err != nil {
+		return nil, err
 	}
 
 	switch e.Type {
@@ -414,26 +705,54 @@ type VariantExpr struct {
 func (v VariantExpr) GetType() ExprType { return v.Type }
 
 func (v VariantExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
-	if v.Selector.Id == "" {
-		return nil, fmt.Errorf("node id is empty")
-	}
 	if v.Selector.Name == "" {
 		return nil, fmt.Errorf("name is empty")
 	}
 
-	if _, ok := variables[v.Selector.Id]; !ok {
-		return nil, fmt.Errorf("node %s not found", v.Selector.Id)
+	// 节点 ID 为空时表示引用全局变量
+	scope := v.Selector.Id
+	if _, ok := variables[scope]; !ok {
+		if scope == "" {
+			return nil, fmt.Errorf("variable %s not found", v.Selector.Name)
+		}
+		return nil, fmt.Errorf("node %s not found", scope)
 	}
 
-	if _, ok := variables[v.Selector.Id][v.Selector.Name]; !ok {
-		return nil, fmt.Errorf("variable %s not found in node %s", v.Selector.Name, v.Selector.Id)
+	value, ok := variables[scope][v.Selector.Name]
+	if !ok {
+		if scope == "" {
+			return nil, fmt.Errorf("variable %s not found", v.Selector.Name)
+		}
+		return nil, fmt.Errorf("variable %s not found in node %s", v.Selector.Name, scope)
+	}
+
+	if v.Selector.Type == "" {
+		return inferEvalResult(value), nil
 	}
+
 	return &EvalResult{
 		Type:  v.Selector.Type,
-		Value: variables[v.Selector.Id][v.Selector.Name],
+		Value: value,
 	}, nil
 }
 
+// 根据变量值推断其类型。
+// 字符串类型的值可能是其他类型的字符串形式，因此标记为推断所得，以便在运算时转换。
+func inferEvalResult(value any) *EvalResult {
+	switch v := value.(type) {
+	case bool:
+		return &EvalResult{Type: Boolean, Value: v}
+	case int, int32, int64, float32, float64:
+		return &EvalResult{Type: Number, Value: v}
+	case time.Time:
+		return &EvalResult{Type: DateTime, Value: v}
+	case time.Duration:
+		return &EvalResult{Type: Duration, Value: v}
+	default:
+		return &EvalResult{Type: String, Value: fmt.Sprintf("%v", v), inferred: true}
+	}
+}
+
 type ComparisonExpr struct {
 	Type     ExprType               `json:"type"` // compare
 	Operator ExprComparisonOperator `json:"operator"`
@@ -515,6 +834,41 @@ func (n NotExpr) Eval(variables map[string]map[string]any) (*EvalResult, error)
 	return inner.Not()
 }
 
+type ArithmeticExpr struct {
+	Type     ExprType               `json:"type"` // arithmetic
+	Operator ExprArithmeticOperator `json:"operator"`
+	Left     Expr                   `json:"left"`
+	Right    Expr                   `json:"right"`
+}
+
+func (a ArithmeticExpr) GetType() ExprType { return a.Type }
+
+func (a ArithmeticExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
+	left, err := a.Left.Eval(variables)
+	if err != nil {
+		return nil, err
+	}
+	right, err := a.Right.Eval(variables)
+	if err != nil {
+		return nil, err
+	}
+
+	switch a.Operator {
+	case Add:
+		return left.Add(right)
+	case Subtract:
+		return left.Subtract(right)
+	case Multiply:
+		return left.Multiply(right)
+	case Divide:
+		return left.Divide(right)
+	case Modulo:
+		return left.Modulo(right)
+	default:
+		return nil, fmt.Errorf("unknown expression operator: %s", a.Operator)
+	}
+}
+
 type rawExpr struct {
 	Type ExprType `json:"type"`
 }
@@ -560,6 +914,18 @@ func UnmarshalExpr(data []byte) (Expr, error) {
 			return nil, err
 		}
 		return e.ToNotExpr()
+	case ArithmeticExprType:
+		var e ArithmeticExprRaw
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return e.ToArithmeticExpr()
+	case FunctionExprType:
+		var e FunctionExprRaw
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return e.ToFunctionExpr()
 	default:
 		return nil, fmt.Errorf("unknown expression type: %s", typ.Type)
 	}
@@ -628,3 +994,49 @@ func (r NotExprRaw) ToNotExpr() (NotExpr, error) {
 		Expr: inner,
 	}, nil
 }
+
+type ArithmeticExprRaw struct {
+	Type     ExprType               `json:"type"`
+	Operator ExprArithmeticOperator `json:"operator"`
+	Left     json.RawMessage        `json:"left"`
+	Right    json.RawMessage        `json:"right"`
+}
+
+func (r ArithmeticExprRaw) ToArithmeticExpr() (ArithmeticExpr, error) {
+	left, err := UnmarshalExpr(r.Left)
+	if err != nil {
+		return ArithmeticExpr{}, err
+	}
+	right, err := UnmarshalExpr(r.Right)
+	if err != nil {
+		return ArithmeticExpr{}, err
+	}
+	return ArithmeticExpr{
+		Type:     r.Type,
+		Operator: r.Operator,
+		Left:     left,
+		Right:    right,
+	}, nil
+}
+
+type FunctionExprRaw struct {
+	Type ExprType          `json:"type"`
+	Name string            `json:"name"`
+	Args []json.RawMessage `json:"args"`
+}
+
+func (r FunctionExprRaw) ToFunctionExpr() (FunctionExpr, error) {
+	args := make([]Expr, 0, len(r.Args))
+	for _, rawArg := range r.Args {
+		arg, err := UnmarshalExpr(rawArg)
+		if err != nil {
+			return FunctionExpr{}, err
+		}
+		args = append(args, arg)
+	}
+	return FunctionExpr{
+		Type: r.Type,
+		Name: r.Name,
+		Args: args,
+	}, nil
+}
@@ -2,6 +2,7 @@ package expr_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -118,3 +119,105 @@ func TestEvalLogicalExpr(t *testing.T) {
 		assert.True(t, result.Value.(bool))
 	})
 }
+
+func TestParseExpr(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expectedErr bool
+	}{
+		{name: "comparison", input: `certificate.daysLeft < 15`},
+		{name: "logical", input: `certificate.daysLeft < 15 && run.trigger == "scheduled"`},
+		{name: "keywords", input: `not certificate.validity or run.trigger == 'manual'`},
+		{name: "arithmetic", input: `(certificate.daysLeft + 1) * 2 % 7 >= -3`},
+		{name: "duration", input: `certificate.notAfter - now() < 1d12h`},
+		{name: "functions", input: `in(run.trigger, "manual", "scheduled") && matches(certificate.commonName, "^.+\\.example\\.com$")`},
+		{name: "node variable", input: `node("ODnYSOXB6HQP2_vz6JcZE", "certificate.validity") == false`},
+		{name: "unknown function", input: `foo(1)`, expectedErr: true},
+		{name: "invalid duration", input: `1x < 2h`, expectedErr: true},
+		{name: "unterminated string", input: `run.trigger == "manual`, expectedErr: true},
+		{name: "missing operand", input: `certificate.daysLeft <`, expectedErr: true},
+		{name: "unbalanced parentheses", input: `(certificate.daysLeft < 15`, expectedErr: true},
+		{name: "invalid node arguments", input: `node(run.id, "certificate.validity")`, expectedErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := expr.ParseExpr(tc.input)
+			if tc.expectedErr {
+				assert.Error(t, err, "Case: %-20s", tc.name)
+				return
+			}
+
+			assert.NoError(t, err, "Case: %-20s", tc.name)
+
+			// 解析结果应能序列化为 JSON 并还原
+			data, err := expr.MarshalExpr(parsed)
+			assert.NoError(t, err, "Case: %-20s", tc.name)
+
+			unmarshaled, err := expr.UnmarshalExpr(data)
+			assert.NoError(t, err, "Case: %-20s", tc.name)
+			assert.Equal(t, parsed, unmarshaled, "Case: %-20s", tc.name)
+		})
+	}
+}
+
+func TestEvalParsedExpr(t *testing.T) {
+	notAfter := time.Now().Add(10 * 24 * time.Hour).Truncate(time.Second)
+	variables := map[string]map[string]any{
+		"": {
+			"run.trigger":            "scheduled",
+			"certificate.commonName": "www.example.com",
+			"certificate.daysLeft":   "10",
+			"certificate.validity":   true,
+			"certificate.notAfter":   notAfter,
+			"certificate.notBefore":  notAfter.Add(-90 * 24 * time.Hour).Format(time.RFC3339),
+		},
+		"ODnYSOXB6HQP2_vz6JcZE": {
+			"certificate.daysLeft": 10,
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		input       string
+		expected    any
+		expectedErr bool
+	}{
+		{name: "number", input: `certificate.daysLeft < 15 && run.trigger == "scheduled"`, expected: true},
+		{name: "arithmetic", input: `certificate.daysLeft * 2 + 1 == 21`, expected: true},
+		{name: "modulo", input: `certificate.daysLeft % 3 == 1`, expected: true},
+		{name: "negative", input: `-certificate.daysLeft < -5`, expected: true},
+		{name: "datetime minus datetime", input: `certificate.notAfter - now() < 15d`, expected: true},
+		{name: "datetime plus duration", input: `certificate.notAfter > now() + 1w`, expected: true},
+		{name: "datetime string", input: `certificate.notAfter - certificate.notBefore == 2160h`, expected: true},
+		{name: "duration division", input: `(certificate.notAfter - certificate.notBefore) / 1d == 90`, expected: true},
+		{name: "datetime function", input: `certificate.notAfter > datetime("2000-01-01")`, expected: true},
+		{name: "contains", input: `contains(certificate.commonName, "example")`, expected: true},
+		{name: "hasPrefix", input: `hasPrefix(certificate.commonName, "api.")`, expected: false},
+		{name: "hasSuffix", input: `hasSuffix(upper(certificate.commonName), ".COM")`, expected: true},
+		{name: "matches", input: `matches(certificate.commonName, "^www\\.")`, expected: true},
+		{name: "in", input: `in(run.trigger, "manual", "scheduled")`, expected: true},
+		{name: "in number", input: `in(certificate.daysLeft, 7, 14, 30)`, expected: false},
+		{name: "len", input: `len(certificate.commonName) == 15`, expected: true},
+		{name: "boolean", input: `certificate.validity && !(run.trigger == "manual")`, expected: true},
+		{name: "node variable", input: `node("ODnYSOXB6HQP2_vz6JcZE", "certificate.daysLeft") >= 10`, expected: true},
+		{name: "unknown variable", input: `certificate.hoursLeft > 0`, expectedErr: true},
+		{name: "type mismatch", input: `certificate.validity > 1d`, expectedErr: true},
+		{name: "division by zero", input: `certificate.daysLeft / 0 > 1`, expectedErr: true},
+		{name: "invalid regex", input: `matches(certificate.commonName, "(")`, expectedErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := expr.ParseExpr(tc.input)
+			assert.NoError(t, err, "failed to parse expression")
+
+			result, err := parsed.Eval(variables)
+			if tc.expectedErr {
+				assert.Error(t, err, "Case: %-20s", tc.name)
+			} else {
+				assert.NoError(t, err, "Case: %-20s", tc.name)
+				assert.Equal(t, tc.expected, result.Value, "Case: %-20s", tc.name)
+			}
+		})
+	}
+}
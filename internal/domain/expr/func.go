@@ -0,0 +1,157 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type FunctionExpr struct {
+	Type ExprType `json:"type"` // func
+	Name string   `json:"name"`
+	Args []Expr   `json:"args"`
+}
+
+func (f FunctionExpr) GetType() ExprType { return f.Type }
+
+func (f FunctionExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
+	fn, ok := functions[f.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function: %s", f.Name)
+	}
+
+	if len(f.Args) < fn.minArgs || (fn.maxArgs >= 0 && len(f.Args) > fn.maxArgs) {
+		return nil, fmt.Errorf("function %s: wrong number of arguments: %d", f.Name, len(f.Args))
+	}
+
+	args := make([]*EvalResult, 0, len(f.Args))
+	for _, arg := range f.Args {
+		res, err := arg.Eval(variables)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, res)
+	}
+
+	res, err := fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("function %s: %w", f.Name, err)
+	}
+
+	return res, nil
+}
+
+type function struct {
+	minArgs int
+	maxArgs int // 负数表示不限制
+	call    func(args []*EvalResult) (*EvalResult, error)
+}
+
+var functions = map[string]function{
+	"contains":  {2, 2, stringPredicate(strings.Contains)},
+	"hasPrefix": {2, 2, stringPredicate(strings.HasPrefix)},
+	"hasSuffix": {2, 2, stringPredicate(strings.HasSuffix)},
+	"matches": {2, 2, func(args []*EvalResult) (*EvalResult, error) {
+		s, err := asString(args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		pattern, err := asString(args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		matched, err := regexp.MatchString(pattern, s)
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{Type: Boolean, Value: matched}, nil
+	}},
+	"lower": {1, 1, stringTransform(strings.ToLower)},
+	"upper": {1, 1, stringTransform(strings.ToUpper)},
+	"trim":  {1, 1, stringTransform(strings.TrimSpace)},
+	"len": {1, 1, func(args []*EvalResult) (*EvalResult, error) {
+		s, err := asString(args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{Type: Number, Value: float64(len([]rune(s)))}, nil
+	}},
+	"in": {1, -1, func(args []*EvalResult) (*EvalResult, error) {
+		for _, item := range args[1:] {
+			rs, err := args[0].Equal(item)
+			if err != nil {
+				return nil, err
+			}
+
+			if rs.Value == true {
+				return &EvalResult{Type: Boolean, Value: true}, nil
+			}
+		}
+
+		return &EvalResult{Type: Boolean, Value: false}, nil
+	}},
+	"now": {0, 0, func(args []*EvalResult) (*EvalResult, error) {
+		return &EvalResult{Type: DateTime, Value: time.Now()}, nil
+	}},
+	"datetime": {1, 1, func(args []*EvalResult) (*EvalResult, error) {
+		res := &EvalResult{Type: args[0].Type, Value: args[0].Value, inferred: true}
+		if err := res.convert(DateTime); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	}},
+	"duration": {1, 1, func(args []*EvalResult) (*EvalResult, error) {
+		res := &EvalResult{Type: args[0].Type, Value: args[0].Value, inferred: true}
+		if err := res.convert(Duration); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+	}},
+}
+
+func stringPredicate(fn func(s, substr string) bool) func(args []*EvalResult) (*EvalResult, error) {
+	return func(args []*EvalResult) (*EvalResult, error) {
+		left, err := asString(args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := asString(args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{Type: Boolean, Value: fn(left, right)}, nil
+	}
+}
+
+func stringTransform(fn func(s string) string) func(args []*EvalResult) (*EvalResult, error) {
+	return func(args []*EvalResult) (*EvalResult, error) {
+		s, err := asString(args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return &EvalResult{Type: String, Value: fn(s)}, nil
+	}
+}
+
+// 字符串函数的参数允许是任意类型的值，非字符串类型将按其字符串形式处理。
+func asString(res *EvalResult) (string, error) {
+	if res.Type == String {
+		return res.GetString()
+	}
+
+	if t, ok := res.Value.(time.Time); ok {
+		return t.Format(time.RFC3339), nil
+	}
+
+	return fmt.Sprintf("%v", res.Value), nil
+}
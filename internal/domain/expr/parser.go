@@ -0,0 +1,485 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// 将文本形式的表达式解析为表达式树，解析结果可通过 [MarshalExpr] 序列化为 JSON。
+//
+// 语法示例：
+//
+//	certificate.daysLeft < 15 && run.trigger == "scheduled"
+//	certificate.notAfter - now() < 15d
+//	in(run.trigger, "manual", "scheduled") || matches(certificate.commonName, "^.+\\.example\\.com$")
+//	node("ODnYSOXB6HQP2_vz6JcZE", "certificate.validity") == false
+//
+// 其中，形如 "a.b" 的标识符表示全局变量，"node(id, name)" 表示指定节点的变量；
+// 形如 "15d"、"1h30m" 的数字字面量表示时长。
+func ParseExpr(text string) (Expr, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected token '%s' at position %d", tok.text, tok.pos)
+	}
+
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenDuration
+	tokenString
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string // 对于字符串字面量，为去除引号并处理转义后的内容
+	pos  int
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%"}
+
+func tokenize(text string) ([]token, error) {
+	tokens := make([]token, 0)
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+
+		case r == '"' || r == '\'':
+			start := i
+			i++
+
+			var sb strings.Builder
+			closed := false
+			for i < len(runes) {
+				c := runes[i]
+				if c == r {
+					closed = true
+					i++
+					break
+				}
+
+				if c == '\\' && i+1 < len(runes) {
+					i++
+					switch runes[i] {
+					case 'n':
+						sb.WriteRune('\n')
+					case 't':
+						sb.WriteRune('\t')
+					default:
+						sb.WriteRune(runes[i])
+					}
+					i++
+					continue
+				}
+
+				sb.WriteRune(c)
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: sb.String(), pos: start})
+
+		case unicode.IsDigit(r):
+			start := i
+			kind := tokenNumber
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || unicode.IsLetter(runes[i])) {
+				if unicode.IsLetter(runes[i]) {
+					kind = tokenDuration
+				}
+				i++
+			}
+
+			literal := string(runes[start:i])
+			if kind == tokenDuration {
+				if _, err := parseDuration(literal); err != nil {
+					return nil, fmt.Errorf("invalid duration '%s' at position %d", literal, start)
+				}
+			}
+
+			tokens = append(tokens, token{kind: kind, text: literal, pos: start})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+
+			ident := string(runes[start:i])
+			if strings.HasSuffix(ident, ".") || strings.Contains(ident, "..") {
+				return nil, fmt.Errorf("invalid identifier '%s' at position %d", ident, start)
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, text: ident, pos: start})
+
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character '%c' at position %d", r, i)
+			}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// 如果下一个记号是给定的运算符或关键字之一，则消费并返回它。
+func (p *parser) accept(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokenOperator && tok.kind != tokenIdent {
+		return "", false
+	}
+
+	for _, op := range ops {
+		if tok.text == op {
+			p.next()
+			return op, true
+		}
+	}
+
+	return "", false
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	tok := p.next()
+	if tok.kind != kind {
+		if tok.kind == tokenEOF {
+			return fmt.Errorf("expected '%s', but reached the end of expression", text)
+		}
+		return fmt.Errorf("expected '%s', but got '%s' at position %d", text, tok.text, tok.pos)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = LogicalExpr{Type: LogicalExprType, Operator: Or, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+
+		left = LogicalExpr{Type: LogicalExprType, Operator: And, Left: left, Right: right}
+	}
+}
+
+var comparisonOperators = map[string]ExprComparisonOperator{
+	"==": Equal,
+	"!=": NotEqual,
+	"<":  LessThan,
+	"<=": LessOrEqual,
+	">":  GreaterThan,
+	">=": GreaterOrEqual,
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	op, ok := p.accept("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return left, nil
+	}
+
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	return ComparisonExpr{Type: ComparisonExprType, Operator: comparisonOperators[op], Left: left, Right: right}, nil
+}
+
+func (p *parser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+
+		operator := Add
+		if op == "-" {
+			operator = Subtract
+		}
+		left = ArithmeticExpr{Type: ArithmeticExprType, Operator: operator, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.accept("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		var operator ExprArithmeticOperator
+		switch op {
+		case "*":
+			operator = Multiply
+		case "/":
+			operator = Divide
+		case "%":
+			operator = Modulo
+		}
+		left = ArithmeticExpr{Type: ArithmeticExprType, Operator: operator, Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return NotExpr{Type: NotExprType, Expr: inner}, nil
+	}
+
+	if _, ok := p.accept("-"); ok {
+		// 负数字面量直接折叠为常量，其余情况转换为乘以 -1
+		if tok := p.peek(); tok.kind == tokenNumber {
+			p.next()
+			return ConstantExpr{Type: ConstantExprType, Value: "-" + tok.text, ValueType: Number}, nil
+		} else if tok.kind == tokenDuration {
+			p.next()
+			return ConstantExpr{Type: ConstantExprType, Value: "-" + tok.text, ValueType: Duration}, nil
+		}
+
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return ArithmeticExpr{
+			Type:     ArithmeticExprType,
+			Operator: Multiply,
+			Left:     ConstantExpr{Type: ConstantExprType, Value: "-1", ValueType: Number},
+			Right:    inner,
+		}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenNumber:
+		return ConstantExpr{Type: ConstantExprType, Value: tok.text, ValueType: Number}, nil
+
+	case tokenDuration:
+		return ConstantExpr{Type: ConstantExprType, Value: tok.text, ValueType: Duration}, nil
+
+	case tokenString:
+		return ConstantExpr{Type: ConstantExprType, Value: tok.text, ValueType: String}, nil
+
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(tokenRParen, ")"); err != nil {
+			return nil, err
+		}
+
+		return inner, nil
+
+	case tokenIdent:
+		switch tok.text {
+		case "true", "false":
+			return ConstantExpr{Type: ConstantExprType, Value: tok.text, ValueType: Boolean}, nil
+		}
+
+		if p.peek().kind != tokenLParen {
+			return VariantExpr{Type: VariantExprType, Selector: ExprValueSelector{Name: tok.text}}, nil
+		}
+
+		p.next()
+		args, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+
+		if tok.text == "node" {
+			return p.buildNodeVariant(tok, args)
+		}
+
+		if _, ok := functions[tok.text]; !ok {
+			return nil, fmt.Errorf("unknown function '%s' at position %d", tok.text, tok.pos)
+		}
+
+		return FunctionExpr{Type: FunctionExprType, Name: tok.text, Args: args}, nil
+
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+
+	default:
+		return nil, fmt.Errorf("unexpected token '%s' at position %d", tok.text, tok.pos)
+	}
+}
+
+func (p *parser) parseArgs() ([]Expr, error) {
+	args := make([]Expr, 0)
+	if p.peek().kind == tokenRParen {
+		p.next()
+		return args, nil
+	}
+
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		tok := p.next()
+		switch tok.kind {
+		case tokenComma:
+			continue
+		case tokenRParen:
+			return args, nil
+		case tokenEOF:
+			return nil, fmt.Errorf("expected ')', but reached the end of expression")
+		default:
+			return nil, fmt.Errorf("expected ',' or ')', but got '%s' at position %d", tok.text, tok.pos)
+		}
+	}
+}
+
+// 将 node(id, name) 形式的节点变量引用转换为变量表达式。
+func (p *parser) buildNodeVariant(tok token, args []Expr) (Expr, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("node() at position %d requires exactly 2 arguments", tok.pos)
+	}
+
+	selector := ExprValueSelector{}
+	for i, arg := range args {
+		c, ok := arg.(ConstantExpr)
+		if !ok || c.ValueType != String {
+			return nil, fmt.Errorf("node() at position %d requires string literal arguments", tok.pos)
+		}
+
+		if i == 0 {
+			selector.Id = c.Value
+		} else {
+			selector.Name = c.Value
+		}
+	}
+
+	return VariantExpr{Type: VariantExprType, Selector: selector}, nil
+}
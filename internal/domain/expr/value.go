@@ -0,0 +1,86 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	time.DateTime,
+	time.DateOnly,
+}
+
+func parseDateTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("failed to parse datetime: %s", s)
+}
+
+// 解析时长字符串。
+// 在 [time.ParseDuration] 的基础上额外支持以 "d" 表示天、"w" 表示周，如 "15d"、"1d12h"。
+func parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("failed to parse duration: empty string")
+	}
+
+	sign := time.Duration(1)
+	rest := s
+	if strings.HasPrefix(rest, "-") {
+		sign = -1
+		rest = rest[1:]
+	} else if strings.HasPrefix(rest, "+") {
+		rest = rest[1:]
+	}
+
+	var total time.Duration
+	for rest != "" {
+		i := 0
+		for i < len(rest) && (rest[i] == '.' || (rest[i] >= '0' && rest[i] <= '9')) {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("failed to parse duration: %s", s)
+		}
+
+		j := i
+		for j < len(rest) && !(rest[j] == '.' || (rest[j] >= '0' && rest[j] <= '9')) {
+			j++
+		}
+
+		number, unit := rest[:i], rest[i:j]
+		rest = rest[j:]
+
+		var scale time.Duration
+		switch unit {
+		case "w":
+			scale = 7 * 24 * time.Hour
+		case "d":
+			scale = 24 * time.Hour
+		default:
+			d, err := time.ParseDuration(number + unit)
+			if err != nil {
+				return 0, fmt.Errorf("failed to parse duration: %s", s)
+			}
+			total += d
+			continue
+		}
+
+		f, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse duration: %s", s)
+		}
+		total += time.Duration(f * float64(scale))
+	}
+
+	return sign * total, nil
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	xtls "github.com/certimate-go/certimate/pkg/utils/tls"
+)
+
+const CollectionNameMonitoredEndpoint = "monitored_endpoint"
+
+type MonitoredEndpoint struct {
+	Meta
+	Name                 string                  `db:"name"                 json:"name"`
+	Host                 string                  `db:"host"                 json:"host"`
+	Port                 int32                   `db:"port"                 json:"port"`
+	Domain               string                  `db:"domain"               json:"domain"`
+	Protocol             string                  `db:"protocol"             json:"protocol"`
+	ScanInterval         int                     `db:"scanInterval"         json:"scanInterval"`
+	WarningDays          int                     `db:"warningDays"          json:"warningDays"`
+	Disabled             bool                    `db:"disabled"             json:"disabled"`
+	LastScannedAt        time.Time               `db:"lastScannedAt"        json:"lastScannedAt"`
+	LastStatus           MonitoredEndpointStatus `db:"lastStatus"           json:"lastStatus"`
+	LastSerialNumber     string                  `db:"lastSerialNumber"     json:"lastSerialNumber"`
+	LastValidityNotAfter time.Time               `db:"lastValidityNotAfter" json:"lastValidityNotAfter"`
+}
+
+// 返回端点的地址，形如 "host:port"。未指定端口时根据协议决定。
+func (e *MonitoredEndpoint) Address() string {
+	port := int(e.Port)
+	if port <= 0 {
+		port = xtls.GetDefaultPort(e.Protocol)
+	}
+
+	return net.JoinHostPort(e.Host, strconv.Itoa(port))
+}
+
+// 返回用于 SNI 及主机名校验的域名。未指定域名时使用主机地址。
+func (e *MonitoredEndpoint) ServerName() string {
+	if e.Domain != "" {
+		return e.Domain
+	}
+
+	return e.Host
+}
+
+// 判断端点是否已到达下一次扫描的时间。
+func (e *MonitoredEndpoint) IsScanDue(now time.Time) bool {
+	if e.Disabled {
+		return false
+	}
+
+	if e.LastScannedAt.IsZero() {
+		return true
+	}
+
+	interval := e.ScanInterval
+	if interval <= 0 {
+		interval = 60
+	}
+
+	return !now.Before(e.LastScannedAt.Add(time.Duration(interval) * time.Minute))
+}
+
+type MonitoredEndpointStatus string
+
+const (
+	MonitoredEndpointStatusOk       = MonitoredEndpointStatus("ok")
+	MonitoredEndpointStatusExpiring = MonitoredEndpointStatus("expiring")
+	MonitoredEndpointStatusInvalid  = MonitoredEndpointStatus("invalid")
+	MonitoredEndpointStatusError    = MonitoredEndpointStatus("error")
+)
+
+const CollectionNameMonitoredEndpointScan = "monitored_endpoint_scan"
+
+type MonitoredEndpointScan struct {
+	Meta
+	EndpointId        string                  `db:"endpointRef"       json:"endpointId"`
+	Status            MonitoredEndpointStatus `db:"status"            json:"status"`
+	SerialNumber      string                  `db:"serialNumber"      json:"serialNumber"`
+	SubjectAltNames   string                  `db:"subjectAltNames"   json:"subjectAltNames"`
+	IssuerName        string                  `db:"issuerName"        json:"issuerName"`
+	ValidityNotBefore time.Time               `db:"validityNotBefore" json:"validityNotBefore"`
+	ValidityNotAfter  time.Time               `db:"validityNotAfter"  json:"validityNotAfter"`
+	Error             string                  `db:"error"             json:"error"`
+}
@@ -86,11 +86,13 @@ const (
 	AccessProviderTypeKubernetes          = AccessProviderType("k8s")
 	AccessProviderTypeLarkBot             = AccessProviderType("larkbot")
 	AccessProviderTypeLeCDN               = AccessProviderType("lecdn")
+	AccessProviderTypeLego                = AccessProviderType("lego")
 	AccessProviderTypeLetsEncrypt         = AccessProviderType("letsencrypt")
 	AccessProviderTypeLetsEncryptStaging  = AccessProviderType("letsencryptstaging")
 	AccessProviderTypeLinode              = AccessProviderType("linode")
 	AccessProviderTypeLiteSSL             = AccessProviderType("litessl")
 	AccessProviderTypeLocal               = AccessProviderType("local")
+	AccessProviderTypeLocalCA             = AccessProviderType("localca")
 	AccessProviderTypeMatrix              = AccessProviderType("matrix")
 	AccessProviderTypeMattermost          = AccessProviderType("mattermost")
 	AccessProviderTypeMohua               = AccessProviderType("mohua")
@@ -166,6 +168,7 @@ const (
 	CAProviderTypeLetsEncrypt         = CAProviderType(AccessProviderTypeLetsEncrypt)
 	CAProviderTypeLetsEncryptStaging  = CAProviderType(AccessProviderTypeLetsEncryptStaging)
 	CAProviderTypeLiteSSL             = CAProviderType(AccessProviderTypeLiteSSL)
+	CAProviderTypeLocalCA             = CAProviderType(AccessProviderTypeLocalCA)
 	CAProviderTypeSectigo             = CAProviderType(AccessProviderTypeSectigo)
 	CAProviderTypeSSLCom              = CAProviderType(AccessProviderTypeSSLCOM)
 	CAProviderTypeZeroSSL             = CAProviderType(AccessProviderTypeZeroSSL)
@@ -246,7 +249,10 @@ const (
 	ACMEDns01ProviderTypeIONOS             = ACMEDns01ProviderType(AccessProviderTypeIONOS)
 	ACMEDns01ProviderTypeJDCloud           = ACMEDns01ProviderType(AccessProviderTypeJDCloud) // 兼容旧值，等同于 [ACMEDns01ProviderTypeJDCloudDNS]
 	ACMEDns01ProviderTypeJDCloudDNS        = ACMEDns01ProviderType(AccessProviderTypeJDCloud + "-dns")
+	ACMEDns01ProviderTypeLego              = ACMEDns01ProviderType(AccessProviderTypeLego)
 	ACMEDns01ProviderTypeLinode            = ACMEDns01ProviderType(AccessProviderTypeLinode)
+	ACMEDns01ProviderTypeLocal             = ACMEDns01ProviderType(AccessProviderTypeLocal)
+	ACMEDns01ProviderTypeLocalExec         = ACMEDns01ProviderType(AccessProviderTypeLocal + "-exec")
 	ACMEDns01ProviderTypeNamecheap         = ACMEDns01ProviderType(AccessProviderTypeNamecheap)
 	ACMEDns01ProviderTypeNameDotCom        = ACMEDns01ProviderType(AccessProviderTypeNameDotCom)
 	ACMEDns01ProviderTypeNameSilo          = ACMEDns01ProviderType(AccessProviderTypeNameSilo)
@@ -266,6 +272,7 @@ const (
 	ACMEDns01ProviderTypeRuCenter          = ACMEDns01ProviderType(AccessProviderTypeRuCenter)
 	ACMEDns01ProviderTypeSimplyCom         = ACMEDns01ProviderType(AccessProviderTypeSimplyCom)
 	ACMEDns01ProviderTypeSpaceship         = ACMEDns01ProviderType(AccessProviderTypeSpaceship)
+	ACMEDns01ProviderTypeSSHExec           = ACMEDns01ProviderType(AccessProviderTypeSSH + "-exec")
 	ACMEDns01ProviderTypeTechnitiumDNS     = ACMEDns01ProviderType(AccessProviderTypeTechnitiumDNS)
 	ACMEDns01ProviderTypeTencentCloud      = ACMEDns01ProviderType(AccessProviderTypeTencentCloud) // 兼容旧值，等同于 [ACMEDns01ProviderTypeTencentCloudDNS]
 	ACMEDns01ProviderTypeTencentCloudDNS   = ACMEDns01ProviderType(AccessProviderTypeTencentCloud + "-dns")
@@ -297,10 +304,30 @@ ACME HTTP-01 提供商常量值。
 NOTICE: If you add new constant, please keep ASCII order.
 */
 const (
-	ACMEHttp01ProviderTypeLocal = ACMEHttp01ProviderType(AccessProviderTypeLocal)
-	ACMEHttp01ProviderTypeFTP   = ACMEHttp01ProviderType(AccessProviderTypeFTP)
-	ACMEHttp01ProviderTypeS3    = ACMEHttp01ProviderType(AccessProviderTypeS3)
-	ACMEHttp01ProviderTypeSSH   = ACMEHttp01ProviderType(AccessProviderTypeSSH)
+	ACMEHttp01ProviderTypeLocal     = ACMEHttp01ProviderType(AccessProviderTypeLocal)
+	ACMEHttp01ProviderTypeLocalExec = ACMEHttp01ProviderType(AccessProviderTypeLocal + "-exec")
+	ACMEHttp01ProviderTypeFTP       = ACMEHttp01ProviderType(AccessProviderTypeFTP)
+	ACMEHttp01ProviderTypeS3        = ACMEHttp01ProviderType(AccessProviderTypeS3)
+	ACMEHttp01ProviderTypeSSH       = ACMEHttp01ProviderType(AccessProviderTypeSSH)
+	ACMEHttp01ProviderTypeSSHExec   = ACMEHttp01ProviderType(AccessProviderTypeSSH + "-exec")
+)
+
+type ACMETlsAlpn01ProviderType ACMEChallengeProviderType
+
+func (t ACMETlsAlpn01ProviderType) String() string {
+	return string(t)
+}
+
+/*
+ACME TLS-ALPN-01 提供商常量值。
+短横线前的部分始终等于授权提供商类型。
+
+注意：如果追加新的常量值，请保持以 ASCII 排序。
+NOTICE: If you add new constant, please keep ASCII order.
+*/
+const (
+	ACMETlsAlpn01ProviderTypeLocal = ACMETlsAlpn01ProviderType(AccessProviderTypeLocal)
+	ACMETlsAlpn01ProviderTypeSSH   = ACMETlsAlpn01ProviderType(AccessProviderTypeSSH)
 )
 
 type DeploymentProviderType string
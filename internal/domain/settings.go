@@ -18,6 +18,9 @@ const (
 	SettingsNameScriptTemplate       = "scriptTemplate"
 	SettingsNameSSLProvider          = "sslProvider"
 	SettingsNamePersistence          = "persistence"
+	SettingsNameACMEServer           = "acmeServer"
+	SettingsNameMonitoring           = "monitoring"
+	SettingsNameDiscovery            = "discovery"
 )
 
 type SettingsContent map[string]any
@@ -34,6 +37,67 @@ type SettingsContentForPersistence struct {
 	WorkflowRunsRetentionMaxDays        int `json:"workflowRunsRetentionMaxDays"`
 }
 
+type SettingsContentForACMEServer struct {
+	Enabled bool `json:"enabled"`
+
+	// 签发证书所用的 CA 提供商。
+	// 为本地 CA 时直接签发；否则将订单代理至上游 CA，并使用以下质询配置完成验证。
+	CAProvider         CAProviderType `json:"caProvider"`
+	CAProviderAccessId string         `json:"caProviderAccessId"`
+	CAProviderConfig   map[string]any `json:"caProviderConfig,omitempty"`
+
+	// 代理至上游 CA 时的账户及质询配置。
+	ContactEmail     string         `json:"contactEmail,omitempty"`
+	ChallengeType    string         `json:"challengeType,omitempty"`
+	Provider         string         `json:"provider,omitempty"`
+	ProviderAccessId string         `json:"providerAccessId,omitempty"`
+	ProviderConfig   map[string]any `json:"providerConfig,omitempty"`
+
+	// 证书有效期（单位：天）。
+	// 零值时由 CA 决定。
+	CertificateValidityDays int `json:"certificateValidityDays,omitempty"`
+}
+
+type SettingsContentForMonitoring struct {
+	// 同时扫描的端点数量上限。
+	// 零值时默认值 10。
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// 证书到期前多少天视为即将过期，可被端点自身的配置覆盖。
+	// 零值时默认值 21。
+	WarningDaysBeforeExpire int `json:"warningDaysBeforeExpire,omitempty"`
+
+	// 扫描历史的最大保留天数。
+	// 零值时表示永久保留。
+	ScansRetentionMaxDays int `json:"scansRetentionMaxDays,omitempty"`
+
+	// 端点状态发生变化时的通知配置。
+	// 未指定通知提供商时不发送通知。
+	NotifyProvider         string         `json:"notifyProvider,omitempty"`
+	NotifyProviderAccessId string         `json:"notifyProviderAccessId,omitempty"`
+	NotifyProviderConfig   map[string]any `json:"notifyProviderConfig,omitempty"`
+}
+
+type SettingsContentForDiscovery struct {
+	// 是否启用定时发现任务。
+	Enabled bool `json:"enabled"`
+
+	// 待扫描的 CIDR 网段，如 "10.0.0.0/24"。
+	CIDRs []string `json:"cidrs,omitempty"`
+	// 待扫描的主机名，扫描时将作为 SNI。
+	Hostnames []string `json:"hostnames,omitempty"`
+	// 待扫描的端口。
+	// 零值时默认值 [443]。
+	Ports []int `json:"ports,omitempty"`
+
+	// 同时探测的地址数量上限。
+	// 零值时默认值 50。
+	Concurrency int `json:"concurrency,omitempty"`
+	// 单个地址的探测超时时间（单位：秒）。
+	// 零值时默认值 5。
+	Timeout int `json:"timeout,omitempty"`
+}
+
 func (c SettingsContent) AsSSLProvider() *SettingsContentForSSLProvider {
 	content := &SettingsContentForSSLProvider{}
 	xmaps.Populate(c, content)
@@ -67,3 +131,56 @@ func (c SettingsContent) AsPersistence() *SettingsContentForPersistence {
 
 	return content
 }
+
+func (c SettingsContent) AsACMEServer() *SettingsContentForACMEServer {
+	content := &SettingsContentForACMEServer{}
+	xmaps.Populate(c, content)
+
+	if content.CAProvider == "" {
+		content.CAProvider = CAProviderTypeLocalCA
+	}
+
+	if content.CertificateValidityDays < 0 {
+		content.CertificateValidityDays = 0
+	}
+
+	return content
+}
+
+func (c SettingsContent) AsMonitoring() *SettingsContentForMonitoring {
+	content := &SettingsContentForMonitoring{}
+	xmaps.Populate(c, content)
+
+	if content.Concurrency <= 0 {
+		content.Concurrency = 10
+	}
+
+	if content.WarningDaysBeforeExpire <= 0 {
+		content.WarningDaysBeforeExpire = 21
+	}
+
+	if content.ScansRetentionMaxDays < 0 {
+		content.ScansRetentionMaxDays = 0
+	}
+
+	return content
+}
+
+func (c SettingsContent) AsDiscovery() *SettingsContentForDiscovery {
+	content := &SettingsContentForDiscovery{}
+	xmaps.Populate(c, content)
+
+	if len(content.Ports) == 0 {
+		content.Ports = []int{443}
+	}
+
+	if content.Concurrency <= 0 {
+		content.Concurrency = 50
+	}
+
+	if content.Timeout <= 0 {
+		content.Timeout = 5
+	}
+
+	return content
+}
@@ -3,10 +3,13 @@ package domain
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/certimate-go/certimate/internal/domain/expr"
 	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+	xtls "github.com/certimate-go/certimate/pkg/utils/tls"
 )
 
 const CollectionNameWorkflow = "workflow"
@@ -17,6 +20,8 @@ type Workflow struct {
 	Description   string                `db:"description"   json:"description"`
 	Trigger       WorkflowTriggerType   `db:"trigger"       json:"trigger"`
 	TriggerCron   string                `db:"triggerCron"   json:"triggerCron"`
+	TriggerConfig WorkflowTriggerConfig `db:"triggerConfig" json:"triggerConfig"`
+	RunTimeout    int                   `db:"runTimeout"    json:"runTimeout"`
 	Enabled       bool                  `db:"enabled"       json:"enabled"`
 	GraphDraft    *WorkflowGraph        `db:"graphDraft"    json:"graphDraft"`
 	GraphContent  *WorkflowGraph        `db:"graphContent"  json:"graphContent"`
@@ -60,7 +65,62 @@ func (g *WorkflowGraph) Verify() error {
 		return fmt.Errorf("the last node is not an end node")
 	}
 
-	return nil
+	var err error
+	walkWorkflowNodes(g.Nodes, func(node *WorkflowNode) bool {
+		if node.Type == WorkflowNodeTypeSubWorkflow && node.Data.Config.AsSubWorkflow().WorkflowId == "" {
+			err = fmt.Errorf("the sub-workflow of node #%s is not specified", node.Id)
+			return false
+		}
+		return true
+	})
+
+	return err
+}
+
+// 用于在校验时获取指定工作流已发布的图结构。
+type WorkflowGraphResolver func(workflowId string) (*WorkflowGraph, error)
+
+// 校验工作流图，并借助 resolver 递归检查子工作流之间是否存在循环引用。
+func (g *WorkflowGraph) VerifyWithResolver(workflowId string, resolver WorkflowGraphResolver) error {
+	if err := g.Verify(); err != nil {
+		return err
+	}
+
+	if resolver == nil {
+		return nil
+	}
+
+	return g.verifySubWorkflows([]string{workflowId}, resolver)
+}
+
+func (g *WorkflowGraph) verifySubWorkflows(path []string, resolver WorkflowGraphResolver) error {
+	var err error
+	walkWorkflowNodes(g.Nodes, func(node *WorkflowNode) bool {
+		if node.Type != WorkflowNodeTypeSubWorkflow || node.Data.Disabled {
+			return true
+		}
+
+		subWorkflowId := node.Data.Config.AsSubWorkflow().WorkflowId
+		subPath := append(slices.Clone(path), subWorkflowId)
+		if slices.Contains(path, subWorkflowId) {
+			err = fmt.Errorf("circular sub-workflow reference detected: %s", strings.Join(subPath, " -> "))
+			return false
+		}
+
+		subGraph, resolveErr := resolver(subWorkflowId)
+		if resolveErr != nil {
+			err = fmt.Errorf("could not resolve sub-workflow #%s of node #%s: %w", subWorkflowId, node.Id, resolveErr)
+			return false
+		} else if subGraph == nil || len(subGraph.Nodes) == 0 {
+			err = fmt.Errorf("the sub-workflow #%s of node #%s has no published content", subWorkflowId, node.Id)
+			return false
+		}
+
+		err = subGraph.verifySubWorkflows(subPath, resolver)
+		return err == nil
+	})
+
+	return err
 }
 
 func (g *WorkflowGraph) Clone() *WorkflowGraph {
@@ -69,6 +129,22 @@ func (g *WorkflowGraph) Clone() *WorkflowGraph {
 	}
 }
 
+func walkWorkflowNodes(nodes []*WorkflowNode, fn func(node *WorkflowNode) bool) bool {
+	for _, node := range nodes {
+		if !fn(node) {
+			return false
+		}
+
+		if len(node.Blocks) > 0 {
+			if !walkWorkflowNodes(node.Blocks, fn) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 type WorkflowTriggerType string
 
 func (t WorkflowTriggerType) String() string {
@@ -78,8 +154,54 @@ func (t WorkflowTriggerType) String() string {
 const (
 	WorkflowTriggerTypeScheduled = WorkflowTriggerType("scheduled")
 	WorkflowTriggerTypeManual    = WorkflowTriggerType("manual")
+	WorkflowTriggerTypeDryRun    = WorkflowTriggerType("dryrun")
+
+	WorkflowTriggerTypeWebhook             = WorkflowTriggerType("webhook")
+	WorkflowTriggerTypeCertificateExpiring = WorkflowTriggerType("certificateExpiring")
+	WorkflowTriggerTypeWorkflowCompleted   = WorkflowTriggerType("workflowCompleted")
 )
 
+type WorkflowTriggerConfig map[string]any
+
+func (c WorkflowTriggerConfig) AsWebhook() WorkflowTriggerConfigForWebhook {
+	return WorkflowTriggerConfigForWebhook{
+		Secret: xmaps.GetString(c, "secret"),
+	}
+}
+
+func (c WorkflowTriggerConfig) AsCertificateExpiring() WorkflowTriggerConfigForCertificateExpiring {
+	return WorkflowTriggerConfigForCertificateExpiring{
+		DaysBeforeExpire: xmaps.GetInt(c, "daysBeforeExpire"),
+		Domains:          xmaps.GetStringsBySplit(c, "domains", ";"),
+	}
+}
+
+func (c WorkflowTriggerConfig) AsWorkflowCompleted() WorkflowTriggerConfigForWorkflowCompleted {
+	statuses := make([]WorkflowRunStatusType, 0)
+	for _, s := range xmaps.GetOrDefaultStringsBySplit(c, "statuses", ";", []string{WorkflowRunStatusTypeSucceeded.String()}) {
+		statuses = append(statuses, WorkflowRunStatusType(s))
+	}
+
+	return WorkflowTriggerConfigForWorkflowCompleted{
+		WorkflowId: xmaps.GetString(c, "workflowId"),
+		Statuses:   statuses,
+	}
+}
+
+type WorkflowTriggerConfigForWebhook struct {
+	Secret string `json:"secret"` // 调用 Webhook 时需携带的密钥
+}
+
+type WorkflowTriggerConfigForCertificateExpiring struct {
+	DaysBeforeExpire int      `json:"daysBeforeExpire,omitempty"` // 距离到期前多少天触发（零值时使用全局设置）
+	Domains          []string `json:"domains,omitempty"`          // 仅匹配这些域名的证书（支持通配符，零值时匹配全部证书）
+}
+
+type WorkflowTriggerConfigForWorkflowCompleted struct {
+	WorkflowId string                  `json:"workflowId"`         // 上游工作流 ID
+	Statuses   []WorkflowRunStatusType `json:"statuses,omitempty"` // 上游工作流运行结束时的状态（零值时仅在成功时触发）
+}
+
 type WorkflowNode struct {
 	Id     string           `json:"id"` // 节点 ID 只在该工作流中唯一，在全局中不保证唯一性
 	Type   WorkflowNodeType `json:"type"`
@@ -94,27 +216,46 @@ func (t WorkflowNodeType) String() string {
 }
 
 const (
-	WorkflowNodeTypeStart       = WorkflowNodeType("start")
-	WorkflowNodeTypeEnd         = WorkflowNodeType("end")
-	WorkflowNodeTypeCondition   = WorkflowNodeType("condition")
-	WorkflowNodeTypeBranchBlock = WorkflowNodeType("branchBlock")
-	WorkflowNodeTypeTryCatch    = WorkflowNodeType("tryCatch")
-	WorkflowNodeTypeTryBlock    = WorkflowNodeType("tryBlock")
-	WorkflowNodeTypeCatchBlock  = WorkflowNodeType("catchBlock")
-	WorkflowNodeTypeDelay       = WorkflowNodeType("delay")
-	WorkflowNodeTypeBizApply    = WorkflowNodeType("bizApply")
-	WorkflowNodeTypeBizUpload   = WorkflowNodeType("bizUpload")
-	WorkflowNodeTypeBizMonitor  = WorkflowNodeType("bizMonitor")
-	WorkflowNodeTypeBizDeploy   = WorkflowNodeType("bizDeploy")
-	WorkflowNodeTypeBizNotify   = WorkflowNodeType("bizNotify")
+	WorkflowNodeTypeStart         = WorkflowNodeType("start")
+	WorkflowNodeTypeEnd           = WorkflowNodeType("end")
+	WorkflowNodeTypeCondition     = WorkflowNodeType("condition")
+	WorkflowNodeTypeBranchBlock   = WorkflowNodeType("branchBlock")
+	WorkflowNodeTypeTryCatch      = WorkflowNodeType("tryCatch")
+	WorkflowNodeTypeTryBlock      = WorkflowNodeType("tryBlock")
+	WorkflowNodeTypeCatchBlock    = WorkflowNodeType("catchBlock")
+	WorkflowNodeTypeParallel      = WorkflowNodeType("parallel")
+	WorkflowNodeTypeParallelBlock = WorkflowNodeType("parallelBlock")
+	WorkflowNodeTypeDelay         = WorkflowNodeType("delay")
+	WorkflowNodeTypeBizApply      = WorkflowNodeType("bizApply")
+	WorkflowNodeTypeBizUpload     = WorkflowNodeType("bizUpload")
+	WorkflowNodeTypeBizMonitor    = WorkflowNodeType("bizMonitor")
+	WorkflowNodeTypeBizDeploy     = WorkflowNodeType("bizDeploy")
+	WorkflowNodeTypeBizNotify     = WorkflowNodeType("bizNotify")
+	WorkflowNodeTypeSubWorkflow   = WorkflowNodeType("subWorkflow")
 )
 
 type WorkflowNodeData struct {
-	Name     string             `json:"name"`
-	Disabled bool               `json:"disabled,omitempty,omitzero"`
-	Config   WorkflowNodeConfig `json:"config,omitempty,omitzero"`
+	Name     string                   `json:"name"`
+	Disabled bool                     `json:"disabled,omitempty,omitzero"`
+	Config   WorkflowNodeConfig       `json:"config,omitempty,omitzero"`
+	Retry    *WorkflowNodeRetryPolicy `json:"retry,omitempty"`
+	Timeout  int                      `json:"timeout,omitempty"`
 }
 
+type WorkflowNodeRetryPolicy struct {
+	MaxAttempts   int      `json:"maxAttempts"`             // 最大尝试次数（含首次执行，小于等于 1 时表示不重试）
+	InitialDelay  int      `json:"initialDelay,omitempty"`  // 首次重试前的等待时间，单位：秒
+	MaxDelay      int      `json:"maxDelay,omitempty"`      // 重试等待时间的上限，单位：秒（零值时不限制）
+	BackoffFactor float64  `json:"backoffFactor,omitempty"` // 退避系数，每次重试的等待时间为上一次的若干倍（零值时默认值 2）
+	RetryOn       string   `json:"retryOn,omitempty"`       // 可重试的错误类别，可取值 "any"、"transient"（零值时默认值 "any"）
+	ErrorPatterns []string `json:"errorPatterns,omitempty"` // 额外视为可重试的错误信息的正则表达式列表
+}
+
+const (
+	WorkflowNodeRetryOnAny       = "any"
+	WorkflowNodeRetryOnTransient = "transient"
+)
+
 type WorkflowNodeConfig map[string]any
 
 func (c WorkflowNodeConfig) AsDelay() WorkflowNodeConfigForDelay {
@@ -123,50 +264,93 @@ func (c WorkflowNodeConfig) AsDelay() WorkflowNodeConfigForDelay {
 	}
 }
 
-func (c WorkflowNodeConfig) AsBranchBlock() WorkflowNodeConfigForBranchBlock {
+func (c WorkflowNodeConfig) AsCondition() WorkflowNodeConfigForCondition {
+	return WorkflowNodeConfigForCondition{
+		Parallel:       xmaps.GetBool(c, "parallel"),
+		MaxConcurrency: xmaps.GetInt(c, "maxConcurrency"),
+	}
+}
+
+func (c WorkflowNodeConfig) AsTryCatch() WorkflowNodeConfigForTryCatch {
+	return WorkflowNodeConfigForTryCatch{
+		Parallel:       xmaps.GetBool(c, "parallel"),
+		MaxConcurrency: xmaps.GetInt(c, "maxConcurrency"),
+	}
+}
+
+func (c WorkflowNodeConfig) AsParallel() WorkflowNodeConfigForParallel {
+	return WorkflowNodeConfigForParallel{
+		MaxConcurrency: xmaps.GetInt(c, "maxConcurrency"),
+	}
+}
+
+func (c WorkflowNodeConfig) AsBranchBlock() (WorkflowNodeConfigForBranchBlock, error) {
 	expression := c["expression"]
 	if expression == nil {
-		return WorkflowNodeConfigForBranchBlock{}
+		return WorkflowNodeConfigForBranchBlock{}, nil
+	}
+
+	// 条件表达式既可以是 JSON 形式的表达式树，也可以是文本形式
+	if exprText, ok := expression.(string); ok {
+		expr, err := expr.ParseExpr(exprText)
+		if err != nil {
+			return WorkflowNodeConfigForBranchBlock{}, err
+		}
+
+		return WorkflowNodeConfigForBranchBlock{
+			Expression: expr,
+		}, nil
 	}
 
 	exprRaw, _ := json.Marshal(expression)
 	expr, err := expr.UnmarshalExpr([]byte(exprRaw))
 	if err != nil {
-		return WorkflowNodeConfigForBranchBlock{}
+		return WorkflowNodeConfigForBranchBlock{}, err
 	}
 
 	return WorkflowNodeConfigForBranchBlock{
 		Expression: expr,
-	}
+	}, nil
 }
 
 func (c WorkflowNodeConfig) AsBizApply() WorkflowNodeConfigForBizApply {
+	domainChallenges := struct {
+		Items []WorkflowNodeConfigForBizApplyDomainChallenge `json:"domainChallenges"`
+	}{}
+	xmaps.Populate(c, &domainChallenges)
+
 	return WorkflowNodeConfigForBizApply{
-		Domains:               xmaps.GetStringsBySplit(c, "domains", ";"),
-		IPAddrs:               xmaps.GetStringsBySplit(c, "ipaddrs", ";"),
-		ContactEmail:          xmaps.GetString(c, "contactEmail"),
-		ChallengeType:         xmaps.GetString(c, "challengeType"),
-		Provider:              xmaps.GetString(c, "provider"),
-		ProviderAccessId:      xmaps.GetString(c, "providerAccessId"),
-		ProviderConfig:        xmaps.GetKVMapAny(c, "providerConfig"),
-		KeySource:             xmaps.GetOrDefaultString(c, "keySource", "auto"),
-		KeyAlgorithm:          xmaps.GetOrDefaultString(c, "keyAlgorithm", CertificateKeyAlgorithmTypeRSA2048.String()),
-		KeyContent:            xmaps.GetString(c, "keyContent"),
-		CAProvider:            xmaps.GetString(c, "caProvider"),
-		CAProviderAccessId:    xmaps.GetString(c, "caProviderAccessId"),
-		CAProviderConfig:      xmaps.GetKVMapAny(c, "caProviderConfig"),
-		ValidityLifetime:      xmaps.GetString(c, "validityLifetime"),
-		PreferredChain:        xmaps.GetString(c, "preferredChain"),
-		ACMEProfile:           xmaps.GetString(c, "acmeProfile"),
-		Nameservers:           xmaps.GetStringsBySplit(c, "nameservers", ";"),
-		DnsPropagationWait:    xmaps.GetInt(c, "dnsPropagationWait"),
-		DnsPropagationTimeout: xmaps.GetInt(c, "dnsPropagationTimeout"),
-		DnsTTL:                xmaps.GetInt(c, "dnsTTL"),
-		HttpDelayWait:         xmaps.GetInt(c, "httpDelayWait"),
-		DisableCommonName:     xmaps.GetBool(c, "disableCommonName"),
-		DisableFollowCNAME:    xmaps.GetBool(c, "disableFollowCNAME"),
-		DisableARI:            xmaps.GetBool(c, "disableARI"),
-		SkipBeforeExpiryDays:  xmaps.GetInt(c, "skipBeforeExpiryDays"),
+		Domains:                     xmaps.GetStringsBySplit(c, "domains", ";"),
+		IPAddrs:                     xmaps.GetStringsBySplit(c, "ipaddrs", ";"),
+		ContactEmail:                xmaps.GetString(c, "contactEmail"),
+		ChallengeType:               xmaps.GetString(c, "challengeType"),
+		Provider:                    xmaps.GetString(c, "provider"),
+		ProviderAccessId:            xmaps.GetString(c, "providerAccessId"),
+		ProviderConfig:              xmaps.GetKVMapAny(c, "providerConfig"),
+		DomainChallenges:            domainChallenges.Items,
+		KeySource:                   xmaps.GetOrDefaultString(c, "keySource", "auto"),
+		KeyAlgorithm:                xmaps.GetOrDefaultString(c, "keyAlgorithm", CertificateKeyAlgorithmTypeRSA2048.String()),
+		KeyContent:                  xmaps.GetString(c, "keyContent"),
+		CSRSource:                   xmaps.GetOrDefaultString(c, "csrSource", "form"),
+		CSRContent:                  xmaps.GetString(c, "csrContent"),
+		CSRAllowInsecureConnections: xmaps.GetBool(c, "csrAllowInsecureConnections"),
+		CAProvider:                  xmaps.GetString(c, "caProvider"),
+		CAProviderAccessId:          xmaps.GetString(c, "caProviderAccessId"),
+		CAProviderConfig:            xmaps.GetKVMapAny(c, "caProviderConfig"),
+		ValidityLifetime:            xmaps.GetString(c, "validityLifetime"),
+		PreferredChain:              xmaps.GetString(c, "preferredChain"),
+		ACMEProfile:                 xmaps.GetString(c, "acmeProfile"),
+		Nameservers:                 xmaps.GetStringsBySplit(c, "nameservers", ";"),
+		DnsPropagationWait:          xmaps.GetInt(c, "dnsPropagationWait"),
+		DnsPropagationTimeout:       xmaps.GetInt(c, "dnsPropagationTimeout"),
+		DnsTTL:                      xmaps.GetInt(c, "dnsTTL"),
+		HttpDelayWait:               xmaps.GetInt(c, "httpDelayWait"),
+		TlsAlpnDelayWait:            xmaps.GetInt(c, "tlsalpnDelayWait"),
+		DisableCommonName:           xmaps.GetBool(c, "disableCommonName"),
+		DisableFollowCNAME:          xmaps.GetBool(c, "disableFollowCNAME"),
+		DisableARI:                  xmaps.GetBool(c, "disableARI"),
+		EnablePreflight:             xmaps.GetBool(c, "enablePreflight"),
+		SkipBeforeExpiryDays:        xmaps.GetInt(c, "skipBeforeExpiryDays"),
 	}
 }
 
@@ -180,11 +364,18 @@ func (c WorkflowNodeConfig) AsBizUpload() WorkflowNodeConfigForBizUpload {
 
 func (c WorkflowNodeConfig) AsBizMonitor() WorkflowNodeConfigForBizMonitor {
 	host := xmaps.GetString(c, "host")
+	protocol := strings.ToLower(xmaps.GetOrDefaultString(c, "protocol", "https"))
+
 	return WorkflowNodeConfigForBizMonitor{
 		Host:        host,
-		Port:        xmaps.GetOrDefaultInt32(c, "port", 443),
+		Port:        xmaps.GetOrDefaultInt32(c, "port", int32(xtls.GetDefaultPort(protocol))),
 		Domain:      xmaps.GetOrDefaultString(c, "domain", host),
+		Protocol:    protocol,
 		RequestPath: xmaps.GetString(c, "path"),
+		EnableAudit: xmaps.GetBool(c, "enableAudit"),
+		CABundle:    xmaps.GetString(c, "caBundle"),
+		ProbeAllIPs: xmaps.GetBool(c, "probeAllIPs"),
+		Nameservers: xmaps.GetStringsBySplit(c, "nameservers", ";"),
 	}
 }
 
@@ -209,40 +400,76 @@ func (c WorkflowNodeConfig) AsBizNotify() WorkflowNodeConfigForBizNotify {
 	}
 }
 
+func (c WorkflowNodeConfig) AsSubWorkflow() WorkflowNodeConfigForSubWorkflow {
+	return WorkflowNodeConfigForSubWorkflow{
+		WorkflowId:              xmaps.GetString(c, "workflowId"),
+		CertificateOutputNodeId: xmaps.GetString(c, "certificateOutputNodeId"),
+		Parameters:              xmaps.GetKVMapAny(c, "parameters"),
+	}
+}
+
 type WorkflowNodeConfigForDelay struct {
 	Wait int `json:"wait"` // 等待时间
 }
 
+type WorkflowNodeConfigForCondition struct {
+	Parallel       bool `json:"parallel,omitempty"`       // 是否并行执行各分支
+	MaxConcurrency int  `json:"maxConcurrency,omitempty"` // 并行执行时的最大并发数（零值时不限制）
+}
+
+type WorkflowNodeConfigForTryCatch struct {
+	Parallel       bool `json:"parallel,omitempty"`       // 是否并行执行各 Try 分支
+	MaxConcurrency int  `json:"maxConcurrency,omitempty"` // 并行执行时的最大并发数（零值时不限制）
+}
+
+type WorkflowNodeConfigForParallel struct {
+	MaxConcurrency int `json:"maxConcurrency,omitempty"` // 最大并发数（零值时不限制）
+}
+
 type WorkflowNodeConfigForBranchBlock struct {
 	Expression expr.Expr `json:"expression"` // 条件表达式
 }
 
 type WorkflowNodeConfigForBizApply struct {
-	Domains               []string       `json:"domains"`                         // 域名列表，以半角分号分隔
-	IPAddrs               []string       `json:"ipaddrs"`                         // IP 地址列表，以半角分号分隔
-	ContactEmail          string         `json:"contactEmail"`                    // 联系邮箱
-	ChallengeType         string         `json:"challengeType"`                   // 质询方式
-	Provider              string         `json:"provider"`                        // 质询提供商
-	ProviderAccessId      string         `json:"providerAccessId"`                // 质询提供商授权记录 ID
-	ProviderConfig        map[string]any `json:"providerConfig,omitempty"`        // 质询提供商额外配置
-	CAProvider            string         `json:"caProvider,omitempty"`            // CA 提供商（零值时使用全局配置）
-	CAProviderAccessId    string         `json:"caProviderAccessId,omitempty"`    // CA 提供商授权记录 ID
-	CAProviderConfig      map[string]any `json:"caProviderConfig,omitempty"`      // CA 提供商额外配置
-	KeySource             string         `json:"keySource"`                       // 私钥来源，可取值 "auto"、"reuse"、"custom"（零值时默认值 "auto"）
-	KeyAlgorithm          string         `json:"keyAlgorithm,omitempty"`          // 私钥算法
-	KeyContent            string         `json:"keyContent,omitempty"`            // 私钥内容
-	ValidityLifetime      string         `json:"validityLifetime,omitempty"`      // 有效期，形如 "30d"、"6h"
-	PreferredChain        string         `json:"preferredChain,omitempty"`        // 首选证书链
-	ACMEProfile           string         `json:"acmeProfile,omitempty"`           // ACME Profiles Extension
-	Nameservers           []string       `json:"nameservers,omitempty"`           // DNS 服务器列表，以半角分号分隔。等同于 lego 的 `--dns.resolvers` 参数
-	DnsPropagationWait    int            `json:"dnsPropagationWait,omitempty"`    // DNS 传播等待时间。等同于 lego 的 `--dns.propagation.wait` 参数
-	DnsPropagationTimeout int            `json:"dnsPropagationTimeout,omitempty"` // DNS 传播检查超时时间。等同于 lego 的 `--dns.timeout` 参数
-	DnsTTL                int            `json:"dnsTTL,omitempty"`                // DNS 解析记录 TTL
-	HttpDelayWait         int            `json:"httpDelayWait,omitempty"`         // HTTP 等待时间。等同于 lego 的 `--http.delay` 参数
-	DisableCommonName     bool           `json:"disableCommonName,omitempty"`     // 是否不包含 CommonName
-	DisableFollowCNAME    bool           `json:"disableFollowCNAME,omitempty"`    // 是否关闭 CNAME 跟随
-	DisableARI            bool           `json:"disableARI,omitempty"`            // 是否关闭 ARI
-	SkipBeforeExpiryDays  int            `json:"skipBeforeExpiryDays,omitempty"`  // 证书到期前多少天前跳过续期
+	Domains                     []string                                       `json:"domains"`                               // 域名列表，以半角分号分隔
+	IPAddrs                     []string                                       `json:"ipaddrs"`                               // IP 地址列表，以半角分号分隔
+	ContactEmail                string                                         `json:"contactEmail"`                          // 联系邮箱
+	ChallengeType               string                                         `json:"challengeType"`                         // 质询方式，可取值 "dns-01"、"http-01"、"tls-alpn-01"
+	Provider                    string                                         `json:"provider"`                              // 质询提供商
+	ProviderAccessId            string                                         `json:"providerAccessId"`                      // 质询提供商授权记录 ID
+	ProviderConfig              map[string]any                                 `json:"providerConfig,omitempty"`              // 质询提供商额外配置
+	DomainChallenges            []WorkflowNodeConfigForBizApplyDomainChallenge `json:"domainChallenges,omitempty"`            // 按域名指定的质询配置，未匹配的域名使用上述质询配置
+	CAProvider                  string                                         `json:"caProvider,omitempty"`                  // CA 提供商（零值时使用全局配置）
+	CAProviderAccessId          string                                         `json:"caProviderAccessId,omitempty"`          // CA 提供商授权记录 ID
+	CAProviderConfig            map[string]any                                 `json:"caProviderConfig,omitempty"`            // CA 提供商额外配置
+	KeySource                   string                                         `json:"keySource"`                             // 私钥来源，可取值 "auto"、"reuse"、"custom"、"csr"（零值时默认值 "auto"）
+	KeyAlgorithm                string                                         `json:"keyAlgorithm,omitempty"`                // 私钥算法
+	KeyContent                  string                                         `json:"keyContent,omitempty"`                  // 私钥内容
+	CSRSource                   string                                         `json:"csrSource,omitempty"`                   // 证书签名请求来源，可取值 "form"、"local"、"url"（零值时默认值 "form"）
+	CSRContent                  string                                         `json:"csrContent,omitempty"`                  // 证书签名请求，根据来源决定是 PEM 内容 / 文件路径 / URL
+	CSRAllowInsecureConnections bool                                           `json:"csrAllowInsecureConnections,omitempty"` // 从 URL 下载证书签名请求时是否跳过 TLS 证书校验（零值时默认值 false）
+	ValidityLifetime            string                                         `json:"validityLifetime,omitempty"`            // 有效期，形如 "30d"、"6h"
+	PreferredChain              string                                         `json:"preferredChain,omitempty"`              // 首选证书链
+	ACMEProfile                 string                                         `json:"acmeProfile,omitempty"`                 // ACME Profiles Extension
+	Nameservers                 []string                                       `json:"nameservers,omitempty"`                 // DNS 服务器列表，以半角分号分隔。等同于 lego 的 `--dns.resolvers` 参数
+	DnsPropagationWait          int                                            `json:"dnsPropagationWait,omitempty"`          // DNS 传播等待时间。等同于 lego 的 `--dns.propagation.wait` 参数
+	DnsPropagationTimeout       int                                            `json:"dnsPropagationTimeout,omitempty"`       // DNS 传播检查超时时间。等同于 lego 的 `--dns.timeout` 参数
+	DnsTTL                      int                                            `json:"dnsTTL,omitempty"`                      // DNS 解析记录 TTL
+	HttpDelayWait               int                                            `json:"httpDelayWait,omitempty"`               // HTTP 等待时间。等同于 lego 的 `--http.delay` 参数
+	TlsAlpnDelayWait            int                                            `json:"tlsalpnDelayWait,omitempty"`            // TLS-ALPN 等待时间。等同于 lego 的 `--tls.delay` 参数
+	DisableCommonName           bool                                           `json:"disableCommonName,omitempty"`           // 是否不包含 CommonName
+	DisableFollowCNAME          bool                                           `json:"disableFollowCNAME,omitempty"`          // 是否关闭 CNAME 跟随
+	DisableARI                  bool                                           `json:"disableARI,omitempty"`                  // 是否关闭 ARI
+	EnablePreflight             bool                                           `json:"enablePreflight,omitempty"`             // 是否在申请证书前进行预检（CAA、CNAME、域名解析）
+	SkipBeforeExpiryDays        int                                            `json:"skipBeforeExpiryDays,omitempty"`        // 证书到期前多少天前跳过续期（ARI 可用时以其建议的续期窗口为准）
+}
+
+type WorkflowNodeConfigForBizApplyDomainChallenge struct {
+	Domain           string         `json:"domain"`                   // 域名或区域后缀，如 "example.com" 可匹配其自身及所有子域名；也可以是 IP 地址
+	ChallengeType    string         `json:"challengeType"`            // 质询方式，可取值 "dns-01"、"http-01"、"tls-alpn-01"
+	Provider         string         `json:"provider"`                 // 质询提供商
+	ProviderAccessId string         `json:"providerAccessId"`         // 质询提供商授权记录 ID
+	ProviderConfig   map[string]any `json:"providerConfig,omitempty"` // 质询提供商额外配置
 }
 
 type WorkflowNodeConfigForBizUpload struct {
@@ -252,10 +479,15 @@ type WorkflowNodeConfigForBizUpload struct {
 }
 
 type WorkflowNodeConfigForBizMonitor struct {
-	Host        string `json:"host"`                  // 主机地址
-	Port        int32  `json:"port,omitempty"`        // 端口（零值时默认值 443）
-	Domain      string `json:"domain,omitempty"`      // 域名（零值时默认值 [Host]）
-	RequestPath string `json:"requestPath,omitempty"` // 请求路径
+	Host        string   `json:"host"`                  // 主机地址
+	Port        int32    `json:"port,omitempty"`        // 端口（零值时根据协议决定，如 HTTPS 为 443）
+	Domain      string   `json:"domain,omitempty"`      // 域名（零值时默认值 [Host]）
+	Protocol    string   `json:"protocol,omitempty"`    // 协议，可取值 "https"、"tls"、"smtp"、"imap"、"pop3"、"ftp"、"ldap"、"postgres"、"mysql"（零值时默认值 "https"）
+	RequestPath string   `json:"requestPath,omitempty"` // 请求路径，仅 HTTPS 协议时有效
+	EnableAudit bool     `json:"enableAudit,omitempty"` // 是否启用深度审计（证书链、吊销状态、TLS 版本及弱密码套件）
+	CABundle    string   `json:"caBundle,omitempty"`    // 额外信任的 CA 证书（PEM 格式），仅启用深度审计时有效
+	ProbeAllIPs bool     `json:"probeAllIPs,omitempty"` // 是否逐一探测主机地址解析到的全部 IP 地址
+	Nameservers []string `json:"nameservers,omitempty"` // DNS 服务器列表，以半角分号分隔，仅探测全部 IP 地址时有效
 }
 
 type WorkflowNodeConfigForBizDeploy struct {
@@ -266,6 +498,12 @@ type WorkflowNodeConfigForBizDeploy struct {
 	SkipOnLastSucceeded     bool           `json:"skipOnLastSucceeded"`        // 上次部署成功时是否跳过
 }
 
+type WorkflowNodeConfigForSubWorkflow struct {
+	WorkflowId              string         `json:"workflowId"`                        // 子工作流 ID
+	CertificateOutputNodeId string         `json:"certificateOutputNodeId,omitempty"` // 传入子工作流的证书来源于前序节点输出的节点 ID
+	Parameters              map[string]any `json:"parameters,omitempty"`              // 传入子工作流的参数
+}
+
 type WorkflowNodeConfigForBizNotify struct {
 	Provider             string         `json:"provider"`                 // 通知提供商
 	ProviderAccessId     string         `json:"providerAccessId"`         // 通知提供商授权记录 ID
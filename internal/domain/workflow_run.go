@@ -8,13 +8,19 @@ const CollectionNameWorkflowRun = "workflow_run"
 
 type WorkflowRun struct {
 	Meta
-	WorkflowId string                `db:"workflowRef" json:"workflowId"`
-	Status     WorkflowRunStatusType `db:"status"      json:"status"`
-	Trigger    WorkflowTriggerType   `db:"trigger"     json:"trigger"`
-	StartedAt  time.Time             `db:"startedAt"   json:"startedAt"`
-	EndedAt    time.Time             `db:"endedAt"     json:"endedAt"`
-	Graph      *WorkflowGraph        `db:"graph"       json:"graph"`
-	Error      string                `db:"error"       json:"error"`
+	WorkflowId       string                `db:"workflowRef"      json:"workflowId"`
+	VersionId        string                `db:"versionRef"       json:"versionId"`
+	Status           WorkflowRunStatusType `db:"status"           json:"status"`
+	Trigger          WorkflowTriggerType   `db:"trigger"          json:"trigger"`
+	TriggerData      map[string]any        `db:"triggerData"      json:"triggerData"`
+	StartedAt        time.Time             `db:"startedAt"        json:"startedAt"`
+	EndedAt          time.Time             `db:"endedAt"          json:"endedAt"`
+	Graph            *WorkflowGraph        `db:"graph"            json:"graph"`
+	Error            string                `db:"error"            json:"error"`
+	ErrorNodeId      string                `db:"errorNodeId"      json:"errorNodeId"`
+	ResumeFromRunId  string                `db:"resumeFromRunRef" json:"resumeFromRunId"`
+	ResumeFromNodeId string                `db:"resumeFromNodeId" json:"resumeFromNodeId"`
+	Plan             WorkflowRunPlan       `db:"plan"             json:"plan"`
 }
 
 type WorkflowRunStatusType string
@@ -30,3 +36,30 @@ const (
 	WorkflowRunStatusTypeFailed     WorkflowRunStatusType = "failed"
 	WorkflowRunStatusTypeCanceled   WorkflowRunStatusType = "canceled"
 )
+
+// 试运行模式下生成的执行计划。
+type WorkflowRunPlan []*WorkflowRunPlanEntry
+
+type WorkflowRunPlanEntry struct {
+	NodeId   string                    `json:"nodeId"`
+	NodeName string                    `json:"nodeName"`
+	NodeType WorkflowNodeType          `json:"nodeType"`
+	Action   WorkflowRunPlanActionType `json:"action"`
+	Message  string                    `json:"message,omitempty"`
+}
+
+type WorkflowRunPlanActionType string
+
+func (t WorkflowRunPlanActionType) String() string {
+	return string(t)
+}
+
+const (
+	WorkflowRunPlanActionTypeSkip   WorkflowRunPlanActionType = "skip"
+	WorkflowRunPlanActionTypeWait   WorkflowRunPlanActionType = "wait"
+	WorkflowRunPlanActionTypeApply  WorkflowRunPlanActionType = "apply"
+	WorkflowRunPlanActionTypeUpload WorkflowRunPlanActionType = "upload"
+	WorkflowRunPlanActionTypeDeploy WorkflowRunPlanActionType = "deploy"
+	WorkflowRunPlanActionTypeNotify WorkflowRunPlanActionType = "notify"
+	WorkflowRunPlanActionTypeFail   WorkflowRunPlanActionType = "fail"
+)
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+const CollectionNameWorkflowTriggerDedupe = "workflow_trigger_dedupe"
+
+// 工作流触发的去重记录，用于避免同一事件重复触发工作流。
+// 与运行记录分开保存，以免运行历史被清理后重复触发。
+type WorkflowTriggerDedupe struct {
+	Meta
+	WorkflowId string    `db:"workflowRef" json:"workflowId"`
+	Key        string    `db:"key"         json:"key"`
+	ExpireAt   time.Time `db:"expireAt"    json:"expireAt"`
+}
@@ -0,0 +1,472 @@
+package domain
+
+import (
+	"crypto/x509"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/certimate-go/certimate/internal/domain/expr"
+)
+
+type WorkflowGraphIssueLevel string
+
+const (
+	WorkflowGraphIssueLevelError   = WorkflowGraphIssueLevel("error")
+	WorkflowGraphIssueLevelWarning = WorkflowGraphIssueLevel("warning")
+)
+
+type WorkflowGraphIssue struct {
+	NodeId   string                  `json:"nodeId,omitempty"`   // 问题所在的节点 ID（零值时表示针对整个工作流图）
+	NodeName string                  `json:"nodeName,omitempty"` // 问题所在的节点名称
+	Level    WorkflowGraphIssueLevel `json:"level"`
+	Message  string                  `json:"message"`
+}
+
+func (i *WorkflowGraphIssue) Error() string {
+	if i.NodeId == "" {
+		return i.Message
+	}
+
+	return fmt.Sprintf("node #%s (%s): %s", i.NodeId, i.NodeName, i.Message)
+}
+
+type WorkflowGraphIssues []*WorkflowGraphIssue
+
+func (issues WorkflowGraphIssues) Errors() WorkflowGraphIssues {
+	return issues.filter(WorkflowGraphIssueLevelError)
+}
+
+func (issues WorkflowGraphIssues) Warnings() WorkflowGraphIssues {
+	return issues.filter(WorkflowGraphIssueLevelWarning)
+}
+
+func (issues WorkflowGraphIssues) HasErrors() bool {
+	return len(issues.Errors()) > 0
+}
+
+// 将校验问题格式化为多行文本，便于记录日志。
+func (issues WorkflowGraphIssues) String() string {
+	lines := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		lines = append(lines, fmt.Sprintf("[%s] %s", issue.Level, issue.Error()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (issues WorkflowGraphIssues) filter(level WorkflowGraphIssueLevel) WorkflowGraphIssues {
+	filtered := make(WorkflowGraphIssues, 0)
+	for _, issue := range issues {
+		if issue.Level == level {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+// 对工作流图进行完整的结构校验，返回所有发现的错误与警告。
+// 与 [WorkflowGraph.Verify] 不同，此方法不会在遇到第一个问题时停止，适用于保存时向用户反馈。
+func (g *WorkflowGraph) Validate() WorkflowGraphIssues {
+	v := &workflowGraphValidator{
+		issues: make(WorkflowGraphIssues, 0),
+		nodes:  make(map[string]*workflowGraphValidatorNode),
+	}
+	v.validate(g)
+	return v.issues
+}
+
+type workflowGraphValidator struct {
+	issues WorkflowGraphIssues
+	nodes  map[string]*workflowGraphValidatorNode
+}
+
+type workflowGraphValidatorNode struct {
+	node      *WorkflowNode
+	order     int             // 前序遍历的顺序
+	ancestors []*WorkflowNode // 从根到父节点的祖先节点
+}
+
+func (v *workflowGraphValidator) addError(node *WorkflowNode, format string, args ...any) {
+	v.addIssue(node, WorkflowGraphIssueLevelError, fmt.Sprintf(format, args...))
+}
+
+func (v *workflowGraphValidator) addWarning(node *WorkflowNode, format string, args ...any) {
+	v.addIssue(node, WorkflowGraphIssueLevelWarning, fmt.Sprintf(format, args...))
+}
+
+func (v *workflowGraphValidator) addIssue(node *WorkflowNode, level WorkflowGraphIssueLevel, message string) {
+	issue := &WorkflowGraphIssue{Level: level, Message: message}
+	if node != nil {
+		issue.NodeId = node.Id
+		issue.NodeName = node.Data.Name
+	}
+	v.issues = append(v.issues, issue)
+}
+
+func (v *workflowGraphValidator) validate(g *WorkflowGraph) {
+	if g == nil || len(g.Nodes) < 2 {
+		v.addError(nil, "the workflow graph must have at least a start node and an end node")
+		return
+	}
+
+	if g.Nodes[0].Type != WorkflowNodeTypeStart {
+		v.addError(g.Nodes[0], "the first node is not a start node")
+	}
+	if g.Nodes[len(g.Nodes)-1].Type != WorkflowNodeTypeEnd {
+		v.addError(g.Nodes[len(g.Nodes)-1], "the last node is not an end node")
+	}
+
+	// 先建立索引，以便后续检查节点之间的引用关系
+	order := 0
+	var index func(nodes []*WorkflowNode, ancestors []*WorkflowNode)
+	index = func(nodes []*WorkflowNode, ancestors []*WorkflowNode) {
+		for _, node := range nodes {
+			if node.Id == "" {
+				v.addError(node, "the node id is empty")
+			} else if _, ok := v.nodes[node.Id]; ok {
+				v.addError(node, "the node id is duplicated")
+			} else {
+				v.nodes[node.Id] = &workflowGraphValidatorNode{node: node, order: order, ancestors: ancestors}
+			}
+			order++
+
+			index(node.Blocks, append(slices.Clone(ancestors), node))
+		}
+	}
+	index(g.Nodes, make([]*WorkflowNode, 0))
+
+	v.validateBlocks(nil, g.Nodes)
+}
+
+func (v *workflowGraphValidator) validateBlocks(parent *WorkflowNode, nodes []*WorkflowNode) {
+	for i, node := range nodes {
+		v.validateNode(parent, node)
+
+		if node.Type == WorkflowNodeTypeEnd && !node.Data.Disabled && i < len(nodes)-1 {
+			v.addWarning(nodes[i+1], "the node is unreachable, because it is placed after an end node")
+		}
+
+		v.validateBlocks(node, node.Blocks)
+	}
+}
+
+func (v *workflowGraphValidator) validateNode(parent *WorkflowNode, node *WorkflowNode) {
+	if node.Data.Timeout < 0 {
+		v.addError(node, "the timeout must not be negative")
+	}
+
+	if node.Data.Retry != nil {
+		for _, pattern := range node.Data.Retry.ErrorPatterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				v.addError(node, "the retry error pattern '%s' is invalid: %s", pattern, err.Error())
+			}
+		}
+	}
+
+	// 校验容器与分支节点之间的层级关系
+	var expectedParentType WorkflowNodeType
+	switch node.Type {
+	case WorkflowNodeTypeBranchBlock:
+		expectedParentType = WorkflowNodeTypeCondition
+	case WorkflowNodeTypeTryBlock, WorkflowNodeTypeCatchBlock:
+		expectedParentType = WorkflowNodeTypeTryCatch
+	case WorkflowNodeTypeParallelBlock:
+		expectedParentType = WorkflowNodeTypeParallel
+	}
+	if expectedParentType != "" && (parent == nil || parent.Type != expectedParentType) {
+		v.addError(node, "the node must be placed inside a '%s' node", expectedParentType)
+	} else if expectedParentType == "" && parent != nil && slices.Contains([]WorkflowNodeType{WorkflowNodeTypeCondition, WorkflowNodeTypeTryCatch, WorkflowNodeTypeParallel}, parent.Type) {
+		v.addError(node, "the node could not be placed directly inside a '%s' node", parent.Type)
+	}
+
+	switch node.Type {
+	case WorkflowNodeTypeStart, WorkflowNodeTypeEnd,
+		WorkflowNodeTypeTryBlock, WorkflowNodeTypeCatchBlock, WorkflowNodeTypeParallelBlock:
+		// 无需额外校验
+
+	case WorkflowNodeTypeDelay:
+		if node.Data.Config.AsDelay().Wait <= 0 {
+			v.addWarning(node, "the wait time is not set")
+		}
+
+	case WorkflowNodeTypeCondition:
+		if !v.hasBlocksOfType(node, WorkflowNodeTypeBranchBlock) {
+			v.addError(node, "the condition node has no branch")
+		}
+
+	case WorkflowNodeTypeBranchBlock:
+		v.validateBranchBlock(node)
+
+	case WorkflowNodeTypeTryCatch:
+		if !v.hasBlocksOfType(node, WorkflowNodeTypeTryBlock) {
+			v.addError(node, "the try-catch node has no try block")
+		}
+		if !v.hasBlocksOfType(node, WorkflowNodeTypeCatchBlock) {
+			v.addError(node, "the try-catch node has no catch block")
+		}
+
+	case WorkflowNodeTypeParallel:
+		if !v.hasBlocksOfType(node, WorkflowNodeTypeParallelBlock) {
+			v.addWarning(node, "the parallel node has no block")
+		}
+
+	case WorkflowNodeTypeBizApply:
+		nodeCfg := node.Data.Config.AsBizApply()
+		if len(nodeCfg.Domains) == 0 && len(nodeCfg.IPAddrs) == 0 {
+			v.addError(node, "the domains or ip addresses are not specified")
+		}
+		if nodeCfg.CAProvider == CAProviderTypeLocalCA.String() {
+			// 本地 CA 直接签发证书，无需联系邮箱及质询
+			if nodeCfg.CAProviderAccessId == "" {
+				v.addError(node, "the local ca access is not specified")
+			}
+		} else {
+			if nodeCfg.ContactEmail == "" {
+				v.addError(node, "the contact email is not specified")
+			}
+			switch strings.ToLower(nodeCfg.ChallengeType) {
+			case "dns-01", "http-01", "tls-alpn-01":
+			default:
+				v.addError(node, "the challenge type '%s' is not supported", nodeCfg.ChallengeType)
+			}
+			if nodeCfg.Provider == "" {
+				v.addError(node, "the challenge provider is not specified")
+			} else if nodeCfg.ProviderAccessId == "" {
+				v.addWarning(node, "the challenge provider access is not specified")
+			}
+			for _, domainChallenge := range nodeCfg.DomainChallenges {
+				if domainChallenge.Domain == "" {
+					v.addError(node, "the domain of challenge mapping is not specified")
+					continue
+				}
+				switch strings.ToLower(domainChallenge.ChallengeType) {
+				case "dns-01", "http-01", "tls-alpn-01":
+				default:
+					v.addError(node, "the challenge type '%s' for '%s' is not supported", domainChallenge.ChallengeType, domainChallenge.Domain)
+				}
+				if domainChallenge.Provider == "" {
+					v.addError(node, "the challenge provider for '%s' is not specified", domainChallenge.Domain)
+				} else if domainChallenge.ProviderAccessId == "" {
+					v.addWarning(node, "the challenge provider access for '%s' is not specified", domainChallenge.Domain)
+				}
+			}
+		}
+		if nodeCfg.KeySource == "custom" && nodeCfg.KeyContent == "" {
+			v.addError(node, "the private key content is not specified")
+		}
+		if nodeCfg.KeySource == "csr" && nodeCfg.CSRContent == "" {
+			v.addError(node, "the certificate signing request is not specified")
+		}
+
+	case WorkflowNodeTypeBizUpload:
+		nodeCfg := node.Data.Config.AsBizUpload()
+		if nodeCfg.Certificate == "" {
+			v.addError(node, "the certificate is not specified")
+		}
+		if nodeCfg.PrivateKey == "" {
+			v.addError(node, "the private key is not specified")
+		}
+
+	case WorkflowNodeTypeBizMonitor:
+		nodeCfg := node.Data.Config.AsBizMonitor()
+		if nodeCfg.Host == "" {
+			v.addError(node, "the host is not specified")
+		}
+		if nodeCfg.Port <= 0 || nodeCfg.Port > 65535 {
+			v.addError(node, "the port %d is out of range", nodeCfg.Port)
+		}
+		if !slices.Contains([]string{"https", "tls", "smtp", "imap", "pop3", "ftp", "ldap", "postgres", "mysql"}, nodeCfg.Protocol) {
+			v.addError(node, "the protocol '%s' is not supported", nodeCfg.Protocol)
+		}
+		if nodeCfg.CABundle != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(nodeCfg.CABundle)) {
+			v.addError(node, "the ca bundle does not contain any valid certificate")
+		}
+
+	case WorkflowNodeTypeBizDeploy:
+		nodeCfg := node.Data.Config.AsBizDeploy()
+		v.validateCertificateReference(node, nodeCfg.CertificateOutputNodeId, true)
+		if nodeCfg.Provider == "" {
+			v.addError(node, "the deployment provider is not specified")
+		} else if nodeCfg.ProviderAccessId == "" {
+			v.addWarning(node, "the deployment provider access is not specified")
+		}
+
+	case WorkflowNodeTypeBizNotify:
+		nodeCfg := node.Data.Config.AsBizNotify()
+		if nodeCfg.Provider == "" {
+			v.addError(node, "the notification provider is not specified")
+		}
+		if nodeCfg.ProviderAccessId == "" {
+			v.addError(node, "the notification provider access is not specified")
+		}
+		if nodeCfg.Subject == "" && nodeCfg.Message == "" {
+			v.addWarning(node, "the notification subject and message are both empty")
+		}
+
+	case WorkflowNodeTypeSubWorkflow:
+		nodeCfg := node.Data.Config.AsSubWorkflow()
+		if nodeCfg.WorkflowId == "" {
+			v.addError(node, "the sub-workflow is not specified")
+		}
+		v.validateCertificateReference(node, nodeCfg.CertificateOutputNodeId, false)
+
+	default:
+		v.addError(node, "unknown node type '%s'", node.Type)
+	}
+}
+
+func (v *workflowGraphValidator) validateBranchBlock(node *WorkflowNode) {
+	nodeCfg, err := node.Data.Config.AsBranchBlock()
+	if err != nil {
+		v.addError(node, "the branch expression is invalid: %s", err.Error())
+		return
+	} else if nodeCfg.Expression == nil {
+		return
+	}
+
+	for _, variant := range collectVariantExprs(nodeCfg.Expression) {
+		selector := variant.Selector
+		if selector.Name == "" {
+			v.addError(node, "the branch expression references an empty variable")
+			continue
+		} else if selector.Id == "" {
+			// 全局变量可能由任意节点或触发器产生，无法静态确定
+			continue
+		}
+
+		// 节点 ID 与名称变量在节点开始执行时即已设置，因此允许引用祖先节点
+		ref, ok := v.nodes[selector.Id]
+		if !ok {
+			v.addError(node, "the branch expression references variable '%s' of a non-existent node #%s", selector.Name, selector.Id)
+			continue
+		} else if !v.isExecutedBefore(ref, node) {
+			v.addError(node, "the branch expression references variable '%s' of node #%s, which is not executed before this branch", selector.Name, selector.Id)
+			continue
+		}
+
+		if names, ok := workflowNodeVariableNames(ref.node.Type); ok && !slices.Contains(names, selector.Name) {
+			v.addWarning(node, "the branch expression references unknown variable '%s' of node #%s", selector.Name, selector.Id)
+		}
+	}
+}
+
+func (v *workflowGraphValidator) validateCertificateReference(node *WorkflowNode, refNodeId string, required bool) {
+	if refNodeId == "" {
+		if required {
+			v.addError(node, "the certificate source node is not specified")
+		}
+		return
+	}
+
+	ref, ok := v.nodes[refNodeId]
+	if !ok {
+		v.addError(node, "the certificate source node #%s does not exist", refNodeId)
+		return
+	}
+
+	self, ok := v.nodes[node.Id]
+	if !ok || ref.order >= self.order || slices.ContainsFunc(self.ancestors, func(n *WorkflowNode) bool { return n.Id == refNodeId }) {
+		v.addError(node, "the certificate source node #%s is not executed before this node", refNodeId)
+		return
+	}
+
+	switch ref.node.Type {
+	case WorkflowNodeTypeBizApply, WorkflowNodeTypeBizUpload, WorkflowNodeTypeSubWorkflow:
+	default:
+		v.addError(node, "the certificate source node #%s does not output any certificate", refNodeId)
+		return
+	}
+
+	if ref.node.Data.Disabled {
+		v.addWarning(node, "the certificate source node #%s is disabled", refNodeId)
+		return
+	}
+
+	// 检查来源节点是否位于与当前节点并行、或可能不会执行的分支中
+	for _, ancestor := range ref.ancestors {
+		if slices.Contains(self.ancestors, ancestor) {
+			continue
+		}
+
+		switch ancestor.Type {
+		case WorkflowNodeTypeParallelBlock:
+			if parallel, ok := v.nodes[ancestor.Id]; ok && len(parallel.ancestors) > 0 && slices.Contains(self.ancestors, parallel.ancestors[len(parallel.ancestors)-1]) {
+				v.addError(node, "the certificate source node #%s runs in a concurrent parallel block", refNodeId)
+				return
+			}
+
+		case WorkflowNodeTypeBranchBlock, WorkflowNodeTypeCatchBlock:
+			v.addWarning(node, "the certificate source node #%s is inside a branch that may not be executed", refNodeId)
+			return
+		}
+	}
+}
+
+func (v *workflowGraphValidator) isExecutedBefore(ref *workflowGraphValidatorNode, node *WorkflowNode) bool {
+	self, ok := v.nodes[node.Id]
+	if !ok {
+		return false
+	}
+
+	return ref.order <= self.order
+}
+
+func (v *workflowGraphValidator) hasBlocksOfType(node *WorkflowNode, nodeType WorkflowNodeType) bool {
+	return slices.ContainsFunc(node.Blocks, func(n *WorkflowNode) bool { return n.Type == nodeType })
+}
+
+func collectVariantExprs(e expr.Expr) []expr.VariantExpr {
+	variants := make([]expr.VariantExpr, 0)
+
+	var walk func(e expr.Expr)
+	walk = func(e expr.Expr) {
+		switch t := e.(type) {
+		case expr.VariantExpr:
+			variants = append(variants, t)
+		case expr.ComparisonExpr:
+			walk(t.Left)
+			walk(t.Right)
+		case expr.LogicalExpr:
+			walk(t.Left)
+			walk(t.Right)
+		case expr.NotExpr:
+			walk(t.Expr)
+		case expr.ArithmeticExpr:
+			walk(t.Left)
+			walk(t.Right)
+		case expr.FunctionExpr:
+			for _, arg := range t.Args {
+				walk(arg)
+			}
+		}
+	}
+	walk(e)
+
+	return variants
+}
+
+var (
+	workflowNodeVariableNamesMap = make(map[WorkflowNodeType][]string)
+	workflowNodeVariableNamesMtx sync.RWMutex
+)
+
+// 注册指定类型的节点执行后可能产生的节点作用域变量名，供校验分支条件表达式时使用。
+// 变量名由工作流引擎统一注册，以免与引擎实际产生的变量不一致。
+func RegisterWorkflowNodeVariableNames(nodeType WorkflowNodeType, names ...string) {
+	workflowNodeVariableNamesMtx.Lock()
+	defer workflowNodeVariableNamesMtx.Unlock()
+
+	workflowNodeVariableNamesMap[nodeType] = slices.Clone(names)
+}
+
+// 返回指定类型的节点执行后可能产生的节点作用域变量名。
+// 第二个返回值为 false 时表示无法静态确定（如子工作流节点等未注册的节点类型）。
+func workflowNodeVariableNames(nodeType WorkflowNodeType) ([]string, bool) {
+	workflowNodeVariableNamesMtx.RLock()
+	defer workflowNodeVariableNamesMtx.RUnlock()
+
+	names, ok := workflowNodeVariableNamesMap[nodeType]
+	return names, ok
+}
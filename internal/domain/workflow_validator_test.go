@@ -0,0 +1,223 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowGraphValidate(t *testing.T) {
+	RegisterWorkflowNodeVariableNames(WorkflowNodeTypeBizApply, "node.id", "node.name", "node.skipped", "certificate.validity", "certificate.daysLeft")
+
+	newNode := func(id string, nodeType WorkflowNodeType, config map[string]any, blocks ...*WorkflowNode) *WorkflowNode {
+		return &WorkflowNode{Id: id, Type: nodeType, Data: WorkflowNodeData{Config: config}, Blocks: blocks}
+	}
+	newGraph := func(nodes ...*WorkflowNode) *WorkflowGraph {
+		return &WorkflowGraph{
+			Nodes: append(append([]*WorkflowNode{newNode("start", WorkflowNodeTypeStart, nil)}, nodes...), newNode("end", WorkflowNodeTypeEnd, nil)),
+		}
+	}
+	newApply := func(id string) *WorkflowNode {
+		return newNode(id, WorkflowNodeTypeBizApply, map[string]any{
+			"domains":          "example.com",
+			"contactEmail":     "admin@example.com",
+			"challengeType":    "dns-01",
+			"provider":         "cloudflare",
+			"providerAccessId": "access1",
+		})
+	}
+	newDeploy := func(id string, certificateOutputNodeId string) *WorkflowNode {
+		return newNode(id, WorkflowNodeTypeBizDeploy, map[string]any{
+			"certificateOutputNodeId": certificateOutputNodeId,
+			"provider":                "ssh",
+			"providerAccessId":        "access2",
+		})
+	}
+	newBranch := func(id string, expression any, blocks ...*WorkflowNode) *WorkflowNode {
+		config := map[string]any{}
+		if expression != nil {
+			config["expression"] = expression
+		}
+		return newNode(id, WorkflowNodeTypeBranchBlock, config, blocks...)
+	}
+	issue := func(level WorkflowGraphIssueLevel, nodeId string, message string) *WorkflowGraphIssue {
+		return &WorkflowGraphIssue{Level: level, NodeId: nodeId, Message: message}
+	}
+
+	testCases := []struct {
+		name     string
+		input    *WorkflowGraph
+		expected WorkflowGraphIssues
+	}{
+		{
+			name:     "valid",
+			input:    newGraph(newApply("apply"), newDeploy("deploy", "apply")),
+			expected: WorkflowGraphIssues{},
+		},
+		{
+			name:  "empty graph",
+			input: &WorkflowGraph{},
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelError, "", "the workflow graph must have at least a start node and an end node"),
+			},
+		},
+		{
+			name: "missing start and end",
+			input: &WorkflowGraph{
+				Nodes: []*WorkflowNode{newApply("apply1"), newApply("apply2")},
+			},
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelError, "apply1", "the first node is not a start node"),
+				issue(WorkflowGraphIssueLevelError, "apply2", "the last node is not an end node"),
+			},
+		},
+		{
+			name:  "duplicated node id",
+			input: newGraph(newApply("apply"), newApply("apply")),
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelError, "apply", "the node id is duplicated"),
+			},
+		},
+		{
+			name: "invalid timeout and retry pattern",
+			input: newGraph(&WorkflowNode{
+				Id:   "delay",
+				Type: WorkflowNodeTypeDelay,
+				Data: WorkflowNodeData{
+					Config:  map[string]any{"wait": 1},
+					Retry:   &WorkflowNodeRetryPolicy{MaxAttempts: 2, ErrorPatterns: []string{"("}},
+					Timeout: -1,
+				},
+			}),
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelError, "delay", "the timeout must not be negative"),
+				issue(WorkflowGraphIssueLevelError, "delay", "the retry error pattern '(' is invalid: error parsing regexp: missing closing ): `(`"),
+			},
+		},
+		{
+			name: "misplaced blocks",
+			input: newGraph(
+				newBranch("branch", nil),
+				newNode("condition", WorkflowNodeTypeCondition, nil, newBranch("branch1", nil), newApply("apply")),
+			),
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelError, "branch", "the node must be placed inside a 'condition' node"),
+				issue(WorkflowGraphIssueLevelError, "apply", "the node could not be placed directly inside a 'condition' node"),
+			},
+		},
+		{
+			name: "empty container nodes",
+			input: newGraph(
+				newNode("condition", WorkflowNodeTypeCondition, nil),
+				newNode("trycatch", WorkflowNodeTypeTryCatch, nil),
+				newNode("parallel", WorkflowNodeTypeParallel, nil),
+			),
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelError, "condition", "the condition node has no branch"),
+				issue(WorkflowGraphIssueLevelError, "trycatch", "the try-catch node has no try block"),
+				issue(WorkflowGraphIssueLevelError, "trycatch", "the try-catch node has no catch block"),
+				issue(WorkflowGraphIssueLevelWarning, "parallel", "the parallel node has no block"),
+			},
+		},
+		{
+			name: "unreachable node after end",
+			input: newGraph(
+				newNode("condition", WorkflowNodeTypeCondition, nil,
+					newBranch("branch", nil, newNode("end1", WorkflowNodeTypeEnd, nil), newApply("apply")),
+				),
+			),
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelWarning, "apply", "the node is unreachable, because it is placed after an end node"),
+			},
+		},
+		{
+			name: "unknown node type",
+			input: newGraph(
+				newNode("foo", WorkflowNodeType("foo"), nil),
+			),
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelError, "foo", "unknown node type 'foo'"),
+			},
+		},
+		{
+			name: "invalid branch expression",
+			input: newGraph(
+				newNode("condition", WorkflowNodeTypeCondition, nil, newBranch("branch", `certificate.daysLeft <`)),
+			),
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelError, "branch", "the branch expression is invalid: unexpected end of expression"),
+			},
+		},
+		{
+			name: "branch expression referencing node variables",
+			input: newGraph(
+				newApply("apply"),
+				newNode("sub", WorkflowNodeTypeSubWorkflow, map[string]any{"workflowId": "wf1"}),
+				newNode("condition", WorkflowNodeTypeCondition, nil,
+					newBranch("branch1", `node("apply", "certificate.daysLeft") < 15 && node("condition", "node.name") != ""`),
+					newBranch("branch2", `node("apply", "certificate.foo") == "bar"`),
+					newBranch("branch3", `node("missing", "certificate.daysLeft") < 15`),
+					newBranch("branch4", `node("later", "certificate.daysLeft") < 15`),
+					newBranch("branch5", `node("sub", "anything") == 1 && run.trigger == "manual"`),
+				),
+				newApply("later"),
+			),
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelWarning, "branch2", "the branch expression references unknown variable 'certificate.foo' of node #apply"),
+				issue(WorkflowGraphIssueLevelError, "branch3", "the branch expression references variable 'certificate.daysLeft' of a non-existent node #missing"),
+				issue(WorkflowGraphIssueLevelError, "branch4", "the branch expression references variable 'certificate.daysLeft' of node #later, which is not executed before this branch"),
+			},
+		},
+		{
+			name: "certificate references",
+			input: newGraph(
+				newDeploy("deploy1", ""),
+				newDeploy("deploy2", "missing"),
+				newDeploy("deploy3", "apply"),
+				newApply("apply"),
+				newDeploy("deploy4", "deploy1"),
+			),
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelError, "deploy1", "the certificate source node is not specified"),
+				issue(WorkflowGraphIssueLevelError, "deploy2", "the certificate source node #missing does not exist"),
+				issue(WorkflowGraphIssueLevelError, "deploy3", "the certificate source node #apply is not executed before this node"),
+				issue(WorkflowGraphIssueLevelError, "deploy4", "the certificate source node #deploy1 does not output any certificate"),
+			},
+		},
+		{
+			name: "certificate source inside branch or parallel block",
+			input: newGraph(
+				newNode("condition", WorkflowNodeTypeCondition, nil,
+					newBranch("branch", nil, newApply("apply1")),
+				),
+				newDeploy("deploy1", "apply1"),
+				newNode("parallel", WorkflowNodeTypeParallel, nil,
+					newNode("block1", WorkflowNodeTypeParallelBlock, nil, newApply("apply2")),
+					newNode("block2", WorkflowNodeTypeParallelBlock, nil, newDeploy("deploy2", "apply2")),
+				),
+			),
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelWarning, "deploy1", "the certificate source node #apply1 is inside a branch that may not be executed"),
+				issue(WorkflowGraphIssueLevelError, "deploy2", "the certificate source node #apply2 runs in a concurrent parallel block"),
+			},
+		},
+		{
+			name: "certificate source disabled",
+			input: newGraph(
+				&WorkflowNode{Id: "apply", Type: WorkflowNodeTypeBizApply, Data: WorkflowNodeData{Disabled: true, Config: newApply("apply").Data.Config}},
+				newDeploy("deploy", "apply"),
+			),
+			expected: WorkflowGraphIssues{
+				issue(WorkflowGraphIssueLevelWarning, "deploy", "the certificate source node #apply is disabled"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.input.Validate()
+			assert.Equal(t, tc.expected, actual, "Case: %-20s", tc.name)
+			assert.Equal(t, len(tc.expected.Errors()) > 0, actual.HasErrors(), "Case: %-20s", tc.name)
+		})
+	}
+}
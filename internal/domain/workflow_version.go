@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+const CollectionNameWorkflowVersion = "workflow_version"
+
+type WorkflowVersion struct {
+	Meta
+	WorkflowId   string         `db:"workflowRef"  json:"workflowId"`
+	Version      int            `db:"version"      json:"version"`
+	GraphContent *WorkflowGraph `db:"graphContent" json:"graphContent"`
+	Description  string         `db:"description"  json:"description"`
+}
+
+// 两个版本工作流图之间的结构化差异，节点以 ID 作为比对依据。
+type WorkflowGraphDiff struct {
+	Added    []*WorkflowGraphDiffEntry `json:"added"`
+	Removed  []*WorkflowGraphDiffEntry `json:"removed"`
+	Modified []*WorkflowGraphDiffEntry `json:"modified"`
+}
+
+func (d *WorkflowGraphDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+type WorkflowGraphDiffEntry struct {
+	NodeId   string           `json:"nodeId"`
+	NodeName string           `json:"nodeName"`
+	NodeType WorkflowNodeType `json:"nodeType"`
+	Changes  []string         `json:"changes,omitempty"` // 发生变化的属性，如 "name"、"disabled"、"config.provider"、"position"
+}
+
+// 比较两个工作流图，返回由 from 变为 to 所产生的差异。
+// 任意一方为 nil 时视为空图。
+func DiffWorkflowGraphs(from, to *WorkflowGraph) *WorkflowGraphDiff {
+	diff := &WorkflowGraphDiff{
+		Added:    make([]*WorkflowGraphDiffEntry, 0),
+		Removed:  make([]*WorkflowGraphDiffEntry, 0),
+		Modified: make([]*WorkflowGraphDiffEntry, 0),
+	}
+
+	fromIndex := indexWorkflowGraphNodes(from)
+	toIndex := indexWorkflowGraphNodes(to)
+
+	for _, id := range fromIndex.order {
+		if _, ok := toIndex.nodes[id]; !ok {
+			diff.Removed = append(diff.Removed, newWorkflowGraphDiffEntry(fromIndex.nodes[id], nil))
+		}
+	}
+
+	for _, id := range toIndex.order {
+		toNode := toIndex.nodes[id]
+		fromNode, ok := fromIndex.nodes[id]
+		if !ok {
+			diff.Added = append(diff.Added, newWorkflowGraphDiffEntry(toNode, nil))
+			continue
+		}
+
+		changes := diffWorkflowNodes(fromNode, toNode)
+		if fromIndex.parents[id] != toIndex.parents[id] ||
+			fromIndex.previousCommonSibling(id, toIndex) != toIndex.previousCommonSibling(id, fromIndex) {
+			changes = append(changes, "position")
+		}
+		if len(changes) > 0 {
+			diff.Modified = append(diff.Modified, newWorkflowGraphDiffEntry(toNode, changes))
+		}
+	}
+
+	return diff
+}
+
+func newWorkflowGraphDiffEntry(node *WorkflowNode, changes []string) *WorkflowGraphDiffEntry {
+	return &WorkflowGraphDiffEntry{
+		NodeId:   node.Id,
+		NodeName: node.Data.Name,
+		NodeType: node.Type,
+		Changes:  changes,
+	}
+}
+
+func diffWorkflowNodes(from, to *WorkflowNode) []string {
+	changes := make([]string, 0)
+
+	if from.Type != to.Type {
+		changes = append(changes, "type")
+	}
+	if from.Data.Name != to.Data.Name {
+		changes = append(changes, "name")
+	}
+	if from.Data.Disabled != to.Data.Disabled {
+		changes = append(changes, "disabled")
+	}
+	if !reflect.DeepEqual(from.Data.Retry, to.Data.Retry) {
+		changes = append(changes, "retry")
+	}
+	if from.Data.Timeout != to.Data.Timeout {
+		changes = append(changes, "timeout")
+	}
+
+	keys := make([]string, 0, len(from.Data.Config)+len(to.Data.Config))
+	for key := range from.Data.Config {
+		keys = append(keys, key)
+	}
+	for key := range to.Data.Config {
+		if _, ok := from.Data.Config[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		fromValue, fromOk := from.Data.Config[key]
+		toValue, toOk := to.Data.Config[key]
+		if fromOk != toOk || !reflect.DeepEqual(fromValue, toValue) {
+			changes = append(changes, fmt.Sprintf("config.%s", key))
+		}
+	}
+
+	return changes
+}
+
+type workflowGraphNodeIndex struct {
+	nodes    map[string]*WorkflowNode
+	parents  map[string]string
+	siblings map[string][]string // 以父节点 ID 为键，顶层节点的父节点 ID 为空字符串
+	order    []string
+}
+
+func indexWorkflowGraphNodes(graph *WorkflowGraph) *workflowGraphNodeIndex {
+	index := &workflowGraphNodeIndex{
+		nodes:    make(map[string]*WorkflowNode),
+		parents:  make(map[string]string),
+		siblings: make(map[string][]string),
+		order:    make([]string, 0),
+	}
+
+	if graph != nil {
+		index.walk(graph.Nodes, "")
+	}
+
+	return index
+}
+
+func (idx *workflowGraphNodeIndex) walk(nodes []*WorkflowNode, parentId string) {
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+
+		idx.nodes[node.Id] = node
+		idx.parents[node.Id] = parentId
+		idx.siblings[parentId] = append(idx.siblings[parentId], node.Id)
+		idx.order = append(idx.order, node.Id)
+
+		if len(node.Blocks) > 0 {
+			idx.walk(node.Blocks, node.Id)
+		}
+	}
+}
+
+// 返回同级节点中、位于指定节点之前且同时存在于另一张图中的最近节点 ID。
+// 仅以两张图共有的节点定位，以免新增或删除的节点使其后的所有节点都被视为移动。
+func (idx *workflowGraphNodeIndex) previousCommonSibling(nodeId string, other *workflowGraphNodeIndex) string {
+	siblings := idx.siblings[idx.parents[nodeId]]
+	pos := slices.Index(siblings, nodeId)
+	for i := pos - 1; i >= 0; i-- {
+		if _, ok := other.nodes[siblings[i]]; ok {
+			return siblings[i]
+		}
+	}
+
+	return ""
+}
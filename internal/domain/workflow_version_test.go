@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffWorkflowGraphs(t *testing.T) {
+	newNode := func(id string, name string, config map[string]any, blocks ...*WorkflowNode) *WorkflowNode {
+		return &WorkflowNode{Id: id, Type: WorkflowNodeTypeBizDeploy, Data: WorkflowNodeData{Name: name, Config: config}, Blocks: blocks}
+	}
+	newGraph := func(nodes ...*WorkflowNode) *WorkflowGraph {
+		return &WorkflowGraph{Nodes: nodes}
+	}
+	entry := func(id string, changes ...string) *WorkflowGraphDiffEntry {
+		return &WorkflowGraphDiffEntry{NodeId: id, NodeName: id, NodeType: WorkflowNodeTypeBizDeploy, Changes: changes}
+	}
+
+	type testInput struct {
+		from *WorkflowGraph
+		to   *WorkflowGraph
+	}
+	testCases := []struct {
+		name     string
+		input    testInput
+		expected *WorkflowGraphDiff
+	}{
+		{
+			name: "identical",
+			input: testInput{
+				from: newGraph(newNode("a", "a", map[string]any{"provider": "ssh"})),
+				to:   newGraph(newNode("a", "a", map[string]any{"provider": "ssh"})),
+			},
+			expected: &WorkflowGraphDiff{Added: []*WorkflowGraphDiffEntry{}, Removed: []*WorkflowGraphDiffEntry{}, Modified: []*WorkflowGraphDiffEntry{}},
+		},
+		{
+			name: "from nil",
+			input: testInput{
+				from: nil,
+				to:   newGraph(newNode("a", "a", nil, newNode("b", "b", nil))),
+			},
+			expected: &WorkflowGraphDiff{Added: []*WorkflowGraphDiffEntry{entry("a"), entry("b")}, Removed: []*WorkflowGraphDiffEntry{}, Modified: []*WorkflowGraphDiffEntry{}},
+		},
+		{
+			name: "added and removed",
+			input: testInput{
+				from: newGraph(newNode("a", "a", nil), newNode("b", "b", nil)),
+				to:   newGraph(newNode("a", "a", nil), newNode("c", "c", nil)),
+			},
+			expected: &WorkflowGraphDiff{Added: []*WorkflowGraphDiffEntry{entry("c")}, Removed: []*WorkflowGraphDiffEntry{entry("b")}, Modified: []*WorkflowGraphDiffEntry{}},
+		},
+		{
+			name: "modified name and config",
+			input: testInput{
+				from: newGraph(&WorkflowNode{Id: "a", Type: WorkflowNodeTypeBizDeploy, Data: WorkflowNodeData{Name: "old", Config: map[string]any{"provider": "ssh", "removed": 1}}}),
+				to:   newGraph(&WorkflowNode{Id: "a", Type: WorkflowNodeTypeBizDeploy, Data: WorkflowNodeData{Name: "a", Config: map[string]any{"provider": "local", "added": 1}, Disabled: true, Timeout: 60}}),
+			},
+			expected: &WorkflowGraphDiff{Added: []*WorkflowGraphDiffEntry{}, Removed: []*WorkflowGraphDiffEntry{}, Modified: []*WorkflowGraphDiffEntry{entry("a", "name", "disabled", "timeout", "config.added", "config.provider", "config.removed")}},
+		},
+		{
+			name: "moved into another parent",
+			input: testInput{
+				from: newGraph(newNode("a", "a", nil), newNode("b", "b", nil)),
+				to:   newGraph(newNode("a", "a", nil, newNode("b", "b", nil))),
+			},
+			expected: &WorkflowGraphDiff{Added: []*WorkflowGraphDiffEntry{}, Removed: []*WorkflowGraphDiffEntry{}, Modified: []*WorkflowGraphDiffEntry{entry("b", "position")}},
+		},
+		{
+			name: "reordered siblings",
+			input: testInput{
+				from: newGraph(newNode("a", "a", nil), newNode("b", "b", nil), newNode("c", "c", nil)),
+				to:   newGraph(newNode("a", "a", nil), newNode("c", "c", nil), newNode("b", "b", nil)),
+			},
+			expected: &WorkflowGraphDiff{Added: []*WorkflowGraphDiffEntry{}, Removed: []*WorkflowGraphDiffEntry{}, Modified: []*WorkflowGraphDiffEntry{entry("c", "position"), entry("b", "position")}},
+		},
+		{
+			name: "insertion does not move following siblings",
+			input: testInput{
+				from: newGraph(newNode("a", "a", nil), newNode("b", "b", nil), newNode("c", "c", nil)),
+				to:   newGraph(newNode("a", "a", nil), newNode("x", "x", nil), newNode("b", "b", nil), newNode("c", "c", nil)),
+			},
+			expected: &WorkflowGraphDiff{Added: []*WorkflowGraphDiffEntry{entry("x")}, Removed: []*WorkflowGraphDiffEntry{}, Modified: []*WorkflowGraphDiffEntry{}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := DiffWorkflowGraphs(tc.input.from, tc.input.to)
+			assert.Equal(t, tc.expected, actual, "Case: %-20s", tc.name)
+			assert.Equal(t, len(tc.expected.Added)+len(tc.expected.Removed)+len(tc.expected.Modified) == 0, actual.IsEmpty(), "Case: %-20s", tc.name)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	xtls "github.com/certimate-go/certimate/pkg/utils/tls"
+)
+
+var supportedProtocols = []string{"https", "tls", "smtp", "imap", "pop3", "ftp", "ldap", "postgres", "mysql"}
+
+// 从 CSV 批量导入端点。已存在的端点（以主机地址、端口、域名确定）将被更新，其余的将被新建。
+// 单行数据有误时跳过该行并记录错误，不影响其他行的导入。
+func (s *MonitoringService) ImportEndpoints(ctx context.Context, req *dtos.MonitoredEndpointImportReq) (*dtos.MonitoredEndpointImportResp, error) {
+	reader := csv.NewReader(strings.NewReader(req.Content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, domain.ErrInvalidParams
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["host"]; !ok {
+		return nil, fmt.Errorf("the csv header must contain column 'host'")
+	}
+
+	resp := &dtos.MonitoredEndpointImportResp{Errors: make([]string, 0)}
+	for {
+		row, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return resp, fmt.Errorf("failed to read csv: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		getColumn := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		endpoint, err := parseEndpointFromCSV(getColumn)
+		if err != nil {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("line %d: %s", line, err.Error()))
+			continue
+		}
+
+		if existing, err := s.endpointRepo.GetByAddress(ctx, endpoint.Host, endpoint.Port, endpoint.Domain); err == nil {
+			existing.Name = endpoint.Name
+			existing.Protocol = endpoint.Protocol
+			existing.ScanInterval = endpoint.ScanInterval
+			existing.WarningDays = endpoint.WarningDays
+			endpoint = existing
+		} else if !domain.IsRecordNotFoundError(err) {
+			return resp, err
+		}
+
+		isNew := endpoint.Id == ""
+		if _, err := s.endpointRepo.Save(ctx, endpoint); err != nil {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("line %d: %s", line, err.Error()))
+			continue
+		}
+
+		if isNew {
+			resp.Created++
+		} else {
+			resp.Updated++
+		}
+	}
+
+	return resp, nil
+}
+
+func parseEndpointFromCSV(getColumn func(name string) string) (*domain.MonitoredEndpoint, error) {
+	endpoint := &domain.MonitoredEndpoint{
+		Name:     getColumn("name"),
+		Host:     strings.ToLower(getColumn("host")),
+		Domain:   strings.ToLower(getColumn("domain")),
+		Protocol: strings.ToLower(getColumn("protocol")),
+	}
+	if endpoint.Host == "" {
+		return nil, fmt.Errorf("the host is empty")
+	}
+	if endpoint.Domain == "" {
+		endpoint.Domain = endpoint.Host
+	}
+	if endpoint.Protocol == "" {
+		endpoint.Protocol = "https"
+	} else if !slices.Contains(supportedProtocols, endpoint.Protocol) {
+		return nil, fmt.Errorf("the protocol '%s' is not supported", endpoint.Protocol)
+	}
+
+	if s := getColumn("port"); s == "" {
+		endpoint.Port = int32(xtls.GetDefaultPort(endpoint.Protocol))
+	} else if port, err := strconv.Atoi(s); err != nil || port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("the port '%s' is invalid", s)
+	} else {
+		endpoint.Port = int32(port)
+	}
+
+	if s := getColumn("scaninterval"); s != "" {
+		interval, err := strconv.Atoi(s)
+		if err != nil || interval < 0 {
+			return nil, fmt.Errorf("the scan interval '%s' is invalid", s)
+		}
+		endpoint.ScanInterval = interval
+	}
+
+	if s := getColumn("warningdays"); s != "" {
+		days, err := strconv.Atoi(s)
+		if err != nil || days < 0 {
+			return nil, fmt.Errorf("the warning days '%s' is invalid", s)
+		}
+		endpoint.WarningDays = days
+	}
+
+	if endpoint.Name == "" {
+		endpoint.Name = endpoint.Address()
+	}
+
+	return endpoint, nil
+}
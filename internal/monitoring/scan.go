@@ -0,0 +1,126 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/notify"
+	xcertx509 "github.com/certimate-go/certimate/pkg/utils/cert/x509"
+	xtls "github.com/certimate-go/certimate/pkg/utils/tls"
+)
+
+// 探测单个端点的超时时间。
+const probeTimeout = 30 * time.Second
+
+// 探测端点并根据证书状态生成扫描记录。
+func probeEndpoint(ctx context.Context, endpoint *domain.MonitoredEndpoint, warningDays int) *domain.MonitoredEndpointScan {
+	scan := &domain.MonitoredEndpointScan{EndpointId: endpoint.Id}
+
+	protocol := strings.ToLower(endpoint.Protocol)
+	if protocol == "" || protocol == "https" {
+		protocol = xtls.ProtocolTLS
+	}
+
+	serverName := endpoint.ServerName()
+	tlsConfig := xtls.NewInsecureConfig()
+	tlsConfig.ServerName = serverName
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	conn, err := xtls.DialWithProtocol(ctx, endpoint.Address(), protocol, tlsConfig)
+	if err != nil {
+		scan.Status = domain.MonitoredEndpointStatusError
+		scan.Error = err.Error()
+		return scan
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		scan.Status = domain.MonitoredEndpointStatusError
+		scan.Error = "no ssl certificates retrieved"
+		return scan
+	}
+
+	cert := state.PeerCertificates[0]
+	scan.SerialNumber = strings.ToUpper(cert.SerialNumber.Text(16))
+	scan.SubjectAltNames = strings.Join(xcertx509.GetSubjectAltNames(cert), ";")
+	scan.IssuerName = cert.Issuer.String()
+	scan.ValidityNotBefore = cert.NotBefore
+	scan.ValidityNotAfter = cert.NotAfter
+
+	now := time.Now()
+	daysLeft := int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24))
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		scan.Status = domain.MonitoredEndpointStatusInvalid
+		scan.Error = "the certificate is either expired or not yet valid"
+	} else if err := cert.VerifyHostname(serverName); err != nil {
+		scan.Status = domain.MonitoredEndpointStatusInvalid
+		scan.Error = "the certificate is not matched the host"
+	} else if daysLeft <= warningDays {
+		scan.Status = domain.MonitoredEndpointStatusExpiring
+	} else {
+		scan.Status = domain.MonitoredEndpointStatusOk
+	}
+
+	return scan
+}
+
+func (s *MonitoringService) notifyStatusChanged(ctx context.Context, endpoint *domain.MonitoredEndpoint, scan *domain.MonitoredEndpointScan, prevStatus domain.MonitoredEndpointStatus, monitoringSettings domain.SettingsContentForMonitoring) error {
+	if monitoringSettings.NotifyProvider == "" {
+		return nil
+	}
+
+	providerAccessConfig := make(map[string]any)
+	if monitoringSettings.NotifyProviderAccessId != "" {
+		if access, err := s.accessRepo.GetById(ctx, monitoringSettings.NotifyProviderAccessId); err != nil {
+			return fmt.Errorf("failed to get access #%s record: %w", monitoringSettings.NotifyProviderAccessId, err)
+		} else {
+			providerAccessConfig = access.Config
+		}
+	}
+
+	displayName := endpoint.Name
+	if displayName == "" {
+		displayName = endpoint.Address()
+	}
+
+	if prevStatus == "" {
+		prevStatus = "unknown"
+	}
+
+	lines := []string{
+		fmt.Sprintf("Endpoint: %s", endpoint.Address()),
+		fmt.Sprintf("Domain: %s", endpoint.ServerName()),
+		fmt.Sprintf("Status: %s -> %s", prevStatus, scan.Status),
+	}
+	if scan.SerialNumber != "" {
+		lines = append(lines,
+			fmt.Sprintf("Serial Number: %s", scan.SerialNumber),
+			fmt.Sprintf("Issuer: %s", scan.IssuerName),
+			fmt.Sprintf("Expires At: %s", scan.ValidityNotAfter.Format(time.RFC3339)),
+		)
+	}
+	if scan.Error != "" {
+		lines = append(lines, fmt.Sprintf("Error: %s", scan.Error))
+	}
+
+	notifier := notify.NewClient()
+	notifyReq := &notify.SendNotificationRequest{
+		Provider:               domain.NotificationProviderType(monitoringSettings.NotifyProvider),
+		ProviderAccessConfig:   providerAccessConfig,
+		ProviderExtendedConfig: monitoringSettings.NotifyProviderConfig,
+		Subject:                fmt.Sprintf("[Certimate] Endpoint %s is %s", displayName, scan.Status),
+		Message:                strings.Join(lines, "\n"),
+	}
+	if _, err := notifier.SendNotification(ctx, notifyReq); err != nil {
+		return err
+	}
+
+	return nil
+}
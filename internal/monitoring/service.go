@@ -0,0 +1,146 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/settings"
+)
+
+type MonitoringService struct {
+	accessRepo   accessRepository
+	endpointRepo monitoredEndpointRepository
+	scanRepo     monitoredEndpointScanRepository
+
+	scanning atomic.Bool
+}
+
+func NewMonitoringService(accessRepo accessRepository, endpointRepo monitoredEndpointRepository, scanRepo monitoredEndpointScanRepository) *MonitoringService {
+	return &MonitoringService{
+		accessRepo:   accessRepo,
+		endpointRepo: endpointRepo,
+		scanRepo:     scanRepo,
+	}
+}
+
+func (s *MonitoringService) InitSchedule(ctx context.Context) error {
+	// 每分钟检查一次，实际扫描频率由各端点的扫描间隔决定
+	app.GetScheduler().MustAdd("scanMonitoredEndpoints", "* * * * *", func() {
+		s.scanDueEndpoints(context.Background())
+	})
+
+	app.GetScheduler().MustAdd("cleanupMonitoredEndpointScans", "0 0 * * *", func() {
+		s.cleanupExpiredScans(context.Background())
+	})
+
+	return nil
+}
+
+func (s *MonitoringService) ScanEndpoint(ctx context.Context, req *dtos.MonitoredEndpointScanReq) (*dtos.MonitoredEndpointScanResp, error) {
+	endpoint, err := s.endpointRepo.GetById(ctx, req.EndpointId)
+	if err != nil {
+		return nil, err
+	}
+
+	scan, err := s.scanEndpoint(ctx, endpoint, settings.GetGlobalSettingsForMonitoring())
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.MonitoredEndpointScanResp{Scan: scan}, nil
+}
+
+func (s *MonitoringService) scanDueEndpoints(ctx context.Context) error {
+	// 上一轮扫描尚未结束时跳过本轮，避免端点数量较多时任务堆积
+	if !s.scanning.CompareAndSwap(false, true) {
+		return nil
+	}
+	defer s.scanning.Store(false)
+
+	endpoints, err := s.endpointRepo.ListEnabled(ctx)
+	if err != nil {
+		app.GetLogger().Error("failed to list monitored endpoints", slog.Any("error", err))
+		return err
+	}
+
+	now := time.Now()
+	monitoringSettings := settings.GetGlobalSettingsForMonitoring()
+
+	eg := errgroup.Group{}
+	eg.SetLimit(monitoringSettings.Concurrency)
+	for _, endpoint := range endpoints {
+		if !endpoint.IsScanDue(now) {
+			continue
+		}
+
+		eg.Go(func() error {
+			if _, err := s.scanEndpoint(ctx, endpoint, monitoringSettings); err != nil {
+				app.GetLogger().Error(fmt.Sprintf("failed to scan monitored endpoint #%s", endpoint.Id), slog.Any("error", err))
+			}
+			return nil
+		})
+	}
+
+	return eg.Wait()
+}
+
+func (s *MonitoringService) scanEndpoint(ctx context.Context, endpoint *domain.MonitoredEndpoint, monitoringSettings domain.SettingsContentForMonitoring) (*domain.MonitoredEndpointScan, error) {
+	warningDays := endpoint.WarningDays
+	if warningDays <= 0 {
+		warningDays = monitoringSettings.WarningDaysBeforeExpire
+	}
+
+	scan := probeEndpoint(ctx, endpoint, warningDays)
+	scan, err := s.scanRepo.Save(ctx, scan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save scan record: %w", err)
+	}
+
+	prevStatus := endpoint.LastStatus
+	endpoint.LastScannedAt = scan.CreatedAt
+	endpoint.LastStatus = scan.Status
+	if scan.Status != domain.MonitoredEndpointStatusError {
+		endpoint.LastSerialNumber = scan.SerialNumber
+		endpoint.LastValidityNotAfter = scan.ValidityNotAfter
+	}
+	if _, err := s.endpointRepo.Save(ctx, endpoint); err != nil {
+		return scan, fmt.Errorf("failed to update endpoint record: %w", err)
+	}
+
+	// 仅在状态发生变化时通知；首次扫描结果正常时无需通知
+	if scan.Status != prevStatus && (prevStatus != "" || scan.Status != domain.MonitoredEndpointStatusOk) {
+		if err := s.notifyStatusChanged(ctx, endpoint, scan, prevStatus, monitoringSettings); err != nil {
+			app.GetLogger().Warn(fmt.Sprintf("failed to send notification for monitored endpoint #%s", endpoint.Id), slog.Any("error", err))
+		}
+	}
+
+	return scan, nil
+}
+
+func (s *MonitoringService) cleanupExpiredScans(ctx context.Context) error {
+	monitoringSettings := settings.GetGlobalSettingsForMonitoring()
+	if monitoringSettings.ScansRetentionMaxDays != 0 {
+		ret, err := s.scanRepo.DeleteWithExprs(ctx,
+			dbx.NewExp(fmt.Sprintf("created<DATETIME('now', '-%d days')", monitoringSettings.ScansRetentionMaxDays)),
+		)
+		if err != nil {
+			app.GetLogger().Error("failed to delete expired monitored endpoint scans", slog.Any("error", err))
+			return err
+		}
+
+		if ret > 0 {
+			app.GetLogger().Info(fmt.Sprintf("cleanup %d expired monitored endpoint scans", ret))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package monitoring
+
+import (
+	"context"
+
+	"github.com/pocketbase/dbx"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type accessRepository interface {
+	GetById(ctx context.Context, id string) (*domain.Access, error)
+}
+
+type monitoredEndpointRepository interface {
+	ListEnabled(ctx context.Context) ([]*domain.MonitoredEndpoint, error)
+	GetById(ctx context.Context, id string) (*domain.MonitoredEndpoint, error)
+	GetByAddress(ctx context.Context, host string, port int32, domainName string) (*domain.MonitoredEndpoint, error)
+	Save(ctx context.Context, endpoint *domain.MonitoredEndpoint) (*domain.MonitoredEndpoint, error)
+}
+
+type monitoredEndpointScanRepository interface {
+	Save(ctx context.Context, scan *domain.MonitoredEndpointScan) (*domain.MonitoredEndpointScan, error)
+	DeleteWithExprs(ctx context.Context, exprs ...dbx.Expression) (int, error)
+}
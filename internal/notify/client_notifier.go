@@ -6,6 +6,7 @@ import (
 
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/internal/notify/notifiers"
+	"github.com/certimate-go/certimate/pkg/core"
 )
 
 type SendNotificationRequest struct {
@@ -26,6 +27,30 @@ func (c *Client) SendNotification(ctx context.Context, request *SendNotification
 		return nil, fmt.Errorf("the request is nil")
 	}
 
+	provider, err := c.newNotifier(request)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.SetLogger(c.logger)
+	if _, err := provider.Notify(ctx, request.Subject, request.Message); err != nil {
+		return nil, err
+	}
+
+	return &SendNotificationResponse{}, nil
+}
+
+// 校验通知请求，仅初始化通知提供商而不实际推送通知。
+func (c *Client) ValidateSendNotificationRequest(ctx context.Context, request *SendNotificationRequest) error {
+	if request == nil {
+		return fmt.Errorf("the request is nil")
+	}
+
+	_, err := c.newNotifier(request)
+	return err
+}
+
+func (c *Client) newNotifier(request *SendNotificationRequest) (core.Notifier, error) {
 	providerFactory, err := notifiers.Registries.Get(request.Provider)
 	if err != nil {
 		return nil, err
@@ -39,10 +64,5 @@ func (c *Client) SendNotification(ctx context.Context, request *SendNotification
 		return nil, fmt.Errorf("failed to initialize notification provider '%s': %w", request.Provider, err)
 	}
 
-	provider.SetLogger(c.logger)
-	if _, err := provider.Notify(ctx, request.Subject, request.Message); err != nil {
-		return nil, err
-	}
-
-	return &SendNotificationResponse{}, nil
+	return provider, nil
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/pocketbase/pocketbase/core"
 
@@ -34,6 +35,57 @@ func (r *AccessRepository) GetById(ctx context.Context, id string) (*domain.Acce
 	return r.castRecordToModel(record)
 }
 
+// 分配下一个 CRL 序号。
+// 序号按 CA 单调递增，且不小于当前时间戳，以兼容早期以生成时间作为序号的 CRL。
+func (r *AccessRepository) IncreaseCRLNumber(ctx context.Context, id string) (int64, error) {
+	var number int64
+	err := app.GetApp().RunInTransaction(func(txApp core.App) error {
+		record, err := txApp.FindRecordById(domain.CollectionNameAccess, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrRecordNotFound
+			}
+			return err
+		}
+
+		number = max(int64(record.GetInt("crlNumber"))+1, time.Now().Unix())
+		record.Set("crlNumber", number)
+		return txApp.Save(record)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return number, nil
+}
+
+// 获取此前生成的 CRL（PEM 格式）。
+func (r *AccessRepository) GetCRL(ctx context.Context, id string) (string, error) {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNameAccess, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.ErrRecordNotFound
+		}
+		return "", err
+	}
+
+	return record.GetString("crl"), nil
+}
+
+// 保存已生成的 CRL（PEM 格式），以便在吊销列表未变化时复用。
+func (r *AccessRepository) SaveCRL(ctx context.Context, id string, crl string) error {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNameAccess, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrRecordNotFound
+		}
+		return err
+	}
+
+	record.Set("crl", crl)
+	return app.GetApp().Save(record)
+}
+
 func (r *AccessRepository) castRecordToModel(record *core.Record) (*domain.Access, error) {
 	if record == nil {
 		return nil, fmt.Errorf("the record is nil")
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type ACMEServerAccountRepository struct{}
+
+func NewACMEServerAccountRepository() *ACMEServerAccountRepository {
+	return &ACMEServerAccountRepository{}
+}
+
+func (r *ACMEServerAccountRepository) GetById(ctx context.Context, id string) (*domain.ACMEServerAccount, error) {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNameACMEServerAccount, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *ACMEServerAccountRepository) GetByKeyThumbprint(ctx context.Context, keyThumbprint string) (*domain.ACMEServerAccount, error) {
+	record, err := app.GetApp().FindFirstRecordByFilter(
+		domain.CollectionNameACMEServerAccount,
+		"keyThumbprint={:keyThumbprint}",
+		dbx.Params{"keyThumbprint": keyThumbprint},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *ACMEServerAccountRepository) Save(ctx context.Context, account *domain.ACMEServerAccount) (*domain.ACMEServerAccount, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameACMEServerAccount)
+	if err != nil {
+		return account, err
+	}
+
+	var record *core.Record
+	if account.Id == "" {
+		record = core.NewRecord(collection)
+	} else {
+		record, err = app.GetApp().FindRecordById(collection, account.Id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return account, domain.ErrRecordNotFound
+			}
+			return account, err
+		}
+	}
+
+	record.Set("clientRef", account.ClientId)
+	record.Set("key", account.Key)
+	record.Set("keyThumbprint", account.KeyThumbprint)
+	record.Set("contact", account.Contact)
+	record.Set("status", account.Status)
+	if err := app.GetApp().Save(record); err != nil {
+		return account, err
+	}
+
+	account.Id = record.Id
+	account.CreatedAt = record.GetDateTime("created").Time()
+	account.UpdatedAt = record.GetDateTime("updated").Time()
+	return account, nil
+}
+
+func (r *ACMEServerAccountRepository) castRecordToModel(record *core.Record) (*domain.ACMEServerAccount, error) {
+	if record == nil {
+		return nil, fmt.Errorf("the record is nil")
+	}
+
+	contact := make([]string, 0)
+	if err := record.UnmarshalJSONField("contact", &contact); err != nil {
+		return nil, fmt.Errorf("field 'contact' is malformed")
+	}
+
+	account := &domain.ACMEServerAccount{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		ClientId:      record.GetString("clientRef"),
+		Key:           record.GetString("key"),
+		KeyThumbprint: record.GetString("keyThumbprint"),
+		Contact:       contact,
+		Status:        record.GetString("status"),
+	}
+	return account, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type ACMEServerClientRepository struct{}
+
+func NewACMEServerClientRepository() *ACMEServerClientRepository {
+	return &ACMEServerClientRepository{}
+}
+
+func (r *ACMEServerClientRepository) GetById(ctx context.Context, id string) (*domain.ACMEServerClient, error) {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNameACMEServerClient, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *ACMEServerClientRepository) GetByEabKid(ctx context.Context, eabKid string) (*domain.ACMEServerClient, error) {
+	record, err := app.GetApp().FindFirstRecordByFilter(
+		domain.CollectionNameACMEServerClient,
+		"eabKid={:eabKid}",
+		dbx.Params{"eabKid": eabKid},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *ACMEServerClientRepository) castRecordToModel(record *core.Record) (*domain.ACMEServerClient, error) {
+	if record == nil {
+		return nil, fmt.Errorf("the record is nil")
+	}
+
+	allowedIdentifiers := make([]string, 0)
+	if err := record.UnmarshalJSONField("allowedIdentifiers", &allowedIdentifiers); err != nil {
+		return nil, fmt.Errorf("field 'allowedIdentifiers' is malformed")
+	}
+
+	client := &domain.ACMEServerClient{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		Name:               record.GetString("name"),
+		EabKid:             record.GetString("eabKid"),
+		EabHmacKey:         record.GetString("eabHmacKey"),
+		AllowedIdentifiers: allowedIdentifiers,
+		Disabled:           record.GetBool("disabled"),
+	}
+	return client, nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type ACMEServerOrderRepository struct{}
+
+func NewACMEServerOrderRepository() *ACMEServerOrderRepository {
+	return &ACMEServerOrderRepository{}
+}
+
+func (r *ACMEServerOrderRepository) ListWithExprs(ctx context.Context, exprs ...dbx.Expression) ([]*domain.ACMEServerOrder, error) {
+	records, err := app.GetApp().FindAllRecords(domain.CollectionNameACMEServerOrder, exprs...)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*domain.ACMEServerOrder, 0)
+	for _, record := range records {
+		order, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+func (r *ACMEServerOrderRepository) GetByCertificateId(ctx context.Context, certificateId string) (*domain.ACMEServerOrder, error) {
+	record, err := app.GetApp().FindFirstRecordByFilter(
+		domain.CollectionNameACMEServerOrder,
+		"certificateRef={:certificateId}",
+		dbx.Params{"certificateId": certificateId},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *ACMEServerOrderRepository) Save(ctx context.Context, order *domain.ACMEServerOrder) (*domain.ACMEServerOrder, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameACMEServerOrder)
+	if err != nil {
+		return order, err
+	}
+
+	// 订单以其自身的标识（而非记录 ID）区分，因此按 orderId 查找已有记录
+	record, err := app.GetApp().FindFirstRecordByFilter(collection, "orderId={:orderId}", dbx.Params{"orderId": order.OrderId})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return order, err
+		}
+		record = core.NewRecord(collection)
+	}
+
+	record.Set("orderId", order.OrderId)
+	record.Set("accountRef", order.AccountId)
+	record.Set("status", order.Status)
+	record.Set("expires", order.Expires)
+	record.Set("certificateRef", order.CertificateId)
+	record.Set("content", order.Content)
+	if err := app.GetApp().Save(record); err != nil {
+		return order, err
+	}
+
+	order.Id = record.Id
+	order.CreatedAt = record.GetDateTime("created").Time()
+	order.UpdatedAt = record.GetDateTime("updated").Time()
+	return order, nil
+}
+
+func (r *ACMEServerOrderRepository) DeleteWithExprs(ctx context.Context, exprs ...dbx.Expression) (int, error) {
+	records, err := app.GetApp().FindAllRecords(domain.CollectionNameACMEServerOrder, exprs...)
+	if err != nil {
+		return 0, err
+	}
+
+	var ret int
+	var errs []error
+	for _, record := range records {
+		if err := app.GetApp().Delete(record); err != nil {
+			errs = append(errs, err)
+		} else {
+			ret++
+		}
+	}
+
+	if len(errs) > 0 {
+		return ret, errors.Join(errs...)
+	}
+
+	return ret, nil
+}
+
+func (r *ACMEServerOrderRepository) castRecordToModel(record *core.Record) (*domain.ACMEServerOrder, error) {
+	if record == nil {
+		return nil, fmt.Errorf("the record is nil")
+	}
+
+	content := make(map[string]any)
+	if err := record.UnmarshalJSONField("content", &content); err != nil {
+		return nil, fmt.Errorf("field 'content' is malformed")
+	}
+
+	order := &domain.ACMEServerOrder{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		OrderId:       record.GetString("orderId"),
+		AccountId:     record.GetString("accountRef"),
+		Status:        record.GetString("status"),
+		Expires:       record.GetDateTime("expires").Time(),
+		CertificateId: record.GetString("certificateRef"),
+		Content:       content,
+	}
+	return order, nil
+}
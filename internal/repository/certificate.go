@@ -74,6 +74,78 @@ func (r *CertificateRepository) GetByWorkflowRunIdAndNodeId(ctx context.Context,
 	return r.castRecordToModel(records[0])
 }
 
+func (r *CertificateRepository) ListExpiringWithinDays(ctx context.Context, days int) ([]*domain.Certificate, error) {
+	records, err := app.GetApp().FindAllRecords(
+		domain.CollectionNameCertificate,
+		dbx.NewExp("validityNotAfter<=DATETIME('now', {:offset})", dbx.Params{"offset": fmt.Sprintf("+%d days", days)}),
+		dbx.NewExp("validityNotAfter>DATETIME('now')"),
+		dbx.HashExp{"isRevoked": false},
+		dbx.HashExp{"deleted": ""},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := make([]*domain.Certificate, 0)
+	for _, record := range records {
+		certificate, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates, nil
+}
+
+func (r *CertificateRepository) ListBySerialNumber(ctx context.Context, serialNumber string) ([]*domain.Certificate, error) {
+	records, err := app.GetApp().FindAllRecords(
+		domain.CollectionNameCertificate,
+		dbx.HashExp{"serialNumber": serialNumber},
+		dbx.HashExp{"deleted": ""},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := make([]*domain.Certificate, 0)
+	for _, record := range records {
+		certificate, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates, nil
+}
+
+func (r *CertificateRepository) ListRevokedByCA(ctx context.Context, ca string) ([]*domain.Certificate, error) {
+	records, err := app.GetApp().FindAllRecords(
+		domain.CollectionNameCertificate,
+		dbx.HashExp{"ca": ca},
+		dbx.HashExp{"isRevoked": true},
+		dbx.NewExp("validityNotAfter>DATETIME('now')"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := make([]*domain.Certificate, 0)
+	for _, record := range records {
+		certificate, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates, nil
+}
+
 func (r *CertificateRepository) Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error) {
 	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameCertificate)
 	if err != nil {
@@ -110,8 +182,13 @@ func (r *CertificateRepository) Save(ctx context.Context, certificate *domain.Ce
 	record.Set("ca", certificate.CA)
 	record.Set("acmeAcctUrl", certificate.ACMEAccountUrl)
 	record.Set("acmeCertUrl", certificate.ACMECertificateUrl)
+	record.Set("ariWindowStart", certificate.ARIWindowStart)
+	record.Set("ariWindowEnd", certificate.ARIWindowEnd)
+	record.Set("ariRetryAfter", certificate.ARIRetryAfter)
+	record.Set("discoveredEndpoints", certificate.DiscoveredEndpoints)
 	record.Set("isRenewed", certificate.IsRenewed)
 	record.Set("isRevoked", certificate.IsRevoked)
+	record.Set("revokedAt", certificate.RevokedAt)
 	record.Set("workflowRef", certificate.WorkflowId)
 	record.Set("workflowRunRef", certificate.WorkflowRunId)
 	record.Set("workflowNodeId", certificate.WorkflowNodeId)
@@ -153,6 +230,11 @@ func (r *CertificateRepository) castRecordToModel(record *core.Record) (*domain.
 		return nil, fmt.Errorf("the record is nil")
 	}
 
+	discoveredEndpoints := make([]string, 0)
+	if err := record.UnmarshalJSONField("discoveredEndpoints", &discoveredEndpoints); err != nil {
+		return nil, fmt.Errorf("field 'discoveredEndpoints' is malformed")
+	}
+
 	certificate := &domain.Certificate{
 		Meta: domain.Meta{
 			Id:        record.Id,
@@ -176,11 +258,17 @@ func (r *CertificateRepository) castRecordToModel(record *core.Record) (*domain.
 		CA:                 record.GetString("ca"),
 		ACMEAccountUrl:     record.GetString("acmeAcctUrl"),
 		ACMECertificateUrl: record.GetString("acmeCertUrl"),
+		ARIWindowStart:     record.GetDateTime("ariWindowStart").Time(),
+		ARIWindowEnd:       record.GetDateTime("ariWindowEnd").Time(),
+		ARIRetryAfter:      record.GetDateTime("ariRetryAfter").Time(),
 		IsRenewed:          record.GetBool("isRenewed"),
 		IsRevoked:          record.GetBool("isRevoked"),
+		RevokedAt:          record.GetDateTime("revokedAt").Time(),
 		WorkflowId:         record.GetString("workflowRef"),
 		WorkflowRunId:      record.GetString("workflowRunRef"),
 		WorkflowNodeId:     record.GetString("workflowNodeId"),
+
+		DiscoveredEndpoints: discoveredEndpoints,
 	}
 	return certificate, nil
 }
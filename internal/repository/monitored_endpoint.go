@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type MonitoredEndpointRepository struct{}
+
+func NewMonitoredEndpointRepository() *MonitoredEndpointRepository {
+	return &MonitoredEndpointRepository{}
+}
+
+func (r *MonitoredEndpointRepository) ListEnabled(ctx context.Context) ([]*domain.MonitoredEndpoint, error) {
+	records, err := app.GetApp().FindAllRecords(
+		domain.CollectionNameMonitoredEndpoint,
+		dbx.NewExp("disabled=false"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]*domain.MonitoredEndpoint, 0)
+	for _, record := range records {
+		endpoint, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+func (r *MonitoredEndpointRepository) GetById(ctx context.Context, id string) (*domain.MonitoredEndpoint, error) {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNameMonitoredEndpoint, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *MonitoredEndpointRepository) GetByAddress(ctx context.Context, host string, port int32, domainName string) (*domain.MonitoredEndpoint, error) {
+	record, err := app.GetApp().FindFirstRecordByFilter(
+		domain.CollectionNameMonitoredEndpoint,
+		"host={:host} && port={:port} && domain={:domain}",
+		dbx.Params{"host": host, "port": port, "domain": domainName},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *MonitoredEndpointRepository) Save(ctx context.Context, endpoint *domain.MonitoredEndpoint) (*domain.MonitoredEndpoint, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameMonitoredEndpoint)
+	if err != nil {
+		return endpoint, err
+	}
+
+	var record *core.Record
+	if endpoint.Id == "" {
+		record = core.NewRecord(collection)
+	} else {
+		record, err = app.GetApp().FindRecordById(collection, endpoint.Id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return endpoint, domain.ErrRecordNotFound
+			}
+			return endpoint, err
+		}
+	}
+
+	record.Set("name", endpoint.Name)
+	record.Set("host", endpoint.Host)
+	record.Set("port", endpoint.Port)
+	record.Set("domain", endpoint.Domain)
+	record.Set("protocol", endpoint.Protocol)
+	record.Set("scanInterval", endpoint.ScanInterval)
+	record.Set("warningDays", endpoint.WarningDays)
+	record.Set("disabled", endpoint.Disabled)
+	record.Set("lastScannedAt", endpoint.LastScannedAt)
+	record.Set("lastStatus", string(endpoint.LastStatus))
+	record.Set("lastSerialNumber", endpoint.LastSerialNumber)
+	record.Set("lastValidityNotAfter", endpoint.LastValidityNotAfter)
+	if err := app.GetApp().Save(record); err != nil {
+		return endpoint, err
+	}
+
+	endpoint.Id = record.Id
+	endpoint.CreatedAt = record.GetDateTime("created").Time()
+	endpoint.UpdatedAt = record.GetDateTime("updated").Time()
+	return endpoint, nil
+}
+
+func (r *MonitoredEndpointRepository) castRecordToModel(record *core.Record) (*domain.MonitoredEndpoint, error) {
+	if record == nil {
+		return nil, fmt.Errorf("the record is nil")
+	}
+
+	endpoint := &domain.MonitoredEndpoint{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		Name:                 record.GetString("name"),
+		Host:                 record.GetString("host"),
+		Port:                 int32(record.GetInt("port")),
+		Domain:               record.GetString("domain"),
+		Protocol:             record.GetString("protocol"),
+		ScanInterval:         record.GetInt("scanInterval"),
+		WarningDays:          record.GetInt("warningDays"),
+		Disabled:             record.GetBool("disabled"),
+		LastScannedAt:        record.GetDateTime("lastScannedAt").Time(),
+		LastStatus:           domain.MonitoredEndpointStatus(record.GetString("lastStatus")),
+		LastSerialNumber:     record.GetString("lastSerialNumber"),
+		LastValidityNotAfter: record.GetDateTime("lastValidityNotAfter").Time(),
+	}
+	return endpoint, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type MonitoredEndpointScanRepository struct{}
+
+func NewMonitoredEndpointScanRepository() *MonitoredEndpointScanRepository {
+	return &MonitoredEndpointScanRepository{}
+}
+
+func (r *MonitoredEndpointScanRepository) Save(ctx context.Context, scan *domain.MonitoredEndpointScan) (*domain.MonitoredEndpointScan, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameMonitoredEndpointScan)
+	if err != nil {
+		return scan, err
+	}
+
+	var record *core.Record
+	if scan.Id == "" {
+		record = core.NewRecord(collection)
+	} else {
+		record, err = app.GetApp().FindRecordById(collection, scan.Id)
+		if err != nil {
+			return scan, err
+		}
+	}
+
+	record.Set("endpointRef", scan.EndpointId)
+	record.Set("status", string(scan.Status))
+	record.Set("serialNumber", scan.SerialNumber)
+	record.Set("subjectAltNames", scan.SubjectAltNames)
+	record.Set("issuerName", scan.IssuerName)
+	record.Set("validityNotBefore", scan.ValidityNotBefore)
+	record.Set("validityNotAfter", scan.ValidityNotAfter)
+	record.Set("error", scan.Error)
+	if err := app.GetApp().Save(record); err != nil {
+		return scan, err
+	}
+
+	scan.Id = record.Id
+	scan.CreatedAt = record.GetDateTime("created").Time()
+	scan.UpdatedAt = record.GetDateTime("updated").Time()
+	return scan, nil
+}
+
+func (r *MonitoredEndpointScanRepository) DeleteWithExprs(ctx context.Context, exprs ...dbx.Expression) (int, error) {
+	records, err := app.GetApp().FindAllRecords(domain.CollectionNameMonitoredEndpointScan, exprs...)
+	if err != nil {
+		return 0, err
+	}
+
+	var ret int
+	var errs []error
+	for _, record := range records {
+		if err := app.GetApp().Delete(record); err != nil {
+			errs = append(errs, err)
+		} else {
+			ret++
+		}
+	}
+
+	if len(errs) > 0 {
+		return ret, errors.Join(errs...)
+	}
+
+	return ret, nil
+}
@@ -19,12 +19,16 @@ func NewWorkflowRepository() *WorkflowRepository {
 }
 
 func (r *WorkflowRepository) ListEnabledScheduled(ctx context.Context) ([]*domain.Workflow, error) {
+	return r.ListEnabledByTrigger(ctx, domain.WorkflowTriggerTypeScheduled)
+}
+
+func (r *WorkflowRepository) ListEnabledByTrigger(ctx context.Context, trigger domain.WorkflowTriggerType) ([]*domain.Workflow, error) {
 	records, err := app.GetApp().FindRecordsByFilter(
 		domain.CollectionNameWorkflow,
 		"enabled={:enabled} && trigger={:trigger}",
 		"-created",
 		0, 0,
-		dbx.Params{"enabled": true, "trigger": domain.WorkflowTriggerTypeScheduled.String()},
+		dbx.Params{"enabled": true, "trigger": trigger.String()},
 	)
 	if err != nil {
 		return nil, err
@@ -78,6 +82,8 @@ func (r *WorkflowRepository) Save(ctx context.Context, workflow *domain.Workflow
 	record.Set("description", workflow.Description)
 	record.Set("trigger", workflow.Trigger.String())
 	record.Set("triggerCron", workflow.TriggerCron)
+	record.Set("triggerConfig", workflow.TriggerConfig)
+	record.Set("runTimeout", workflow.RunTimeout)
 	record.Set("enabled", workflow.Enabled)
 	record.Set("graphDraft", workflow.GraphDraft)
 	record.Set("graphContent", workflow.GraphContent)
@@ -111,6 +117,11 @@ func (r *WorkflowRepository) castRecordToModel(record *core.Record) (*domain.Wor
 		return nil, fmt.Errorf("field 'graphContent' is malformed")
 	}
 
+	triggerConfig := make(map[string]any)
+	if err := record.UnmarshalJSONField("triggerConfig", &triggerConfig); err != nil {
+		return nil, fmt.Errorf("field 'triggerConfig' is malformed")
+	}
+
 	workflow := &domain.Workflow{
 		Meta: domain.Meta{
 			Id:        record.Id,
@@ -121,6 +132,8 @@ func (r *WorkflowRepository) castRecordToModel(record *core.Record) (*domain.Wor
 		Description:   record.GetString("description"),
 		Trigger:       domain.WorkflowTriggerType(record.GetString("trigger")),
 		TriggerCron:   record.GetString("triggerCron"),
+		TriggerConfig: triggerConfig,
+		RunTimeout:    record.GetInt("runTimeout"),
 		Enabled:       record.GetBool("enabled"),
 		GraphDraft:    graphDraft,
 		GraphContent:  graphContent,
@@ -50,12 +50,18 @@ func (r *WorkflowRunRepository) Save(ctx context.Context, workflowRun *domain.Wo
 	}
 
 	record.Set("workflowRef", workflowRun.WorkflowId)
+	record.Set("versionRef", workflowRun.VersionId)
 	record.Set("trigger", workflowRun.Trigger.String())
+	record.Set("triggerData", workflowRun.TriggerData)
 	record.Set("status", workflowRun.Status.String())
 	record.Set("startedAt", workflowRun.StartedAt)
 	record.Set("endedAt", workflowRun.EndedAt)
 	record.Set("graph", workflowRun.Graph)
 	record.Set("error", workflowRun.Error)
+	record.Set("errorNodeId", workflowRun.ErrorNodeId)
+	record.Set("resumeFromRunRef", workflowRun.ResumeFromRunId)
+	record.Set("resumeFromNodeId", workflowRun.ResumeFromNodeId)
+	record.Set("plan", workflowRun.Plan)
 	err = app.GetApp().Save(record)
 	if err != nil {
 		return workflowRun, err
@@ -88,12 +94,18 @@ func (r *WorkflowRunRepository) SaveWithCascading(ctx context.Context, workflowR
 
 	err = app.GetApp().RunInTransaction(func(txApp core.App) error {
 		record.Set("workflowRef", workflowRun.WorkflowId)
+		record.Set("versionRef", workflowRun.VersionId)
 		record.Set("trigger", workflowRun.Trigger.String())
+		record.Set("triggerData", workflowRun.TriggerData)
 		record.Set("status", workflowRun.Status.String())
 		record.Set("startedAt", workflowRun.StartedAt)
 		record.Set("endedAt", workflowRun.EndedAt)
 		record.Set("graph", workflowRun.Graph)
 		record.Set("error", workflowRun.Error)
+		record.Set("errorNodeId", workflowRun.ErrorNodeId)
+		record.Set("resumeFromRunRef", workflowRun.ResumeFromRunId)
+		record.Set("resumeFromNodeId", workflowRun.ResumeFromNodeId)
+		record.Set("plan", workflowRun.Plan)
 		err = txApp.Save(record)
 		if err != nil {
 			return err
@@ -199,19 +211,35 @@ func (r *WorkflowRunRepository) castRecordToModel(record *core.Record) (*domain.
 		return nil, fmt.Errorf("field 'graph' is malformed")
 	}
 
+	triggerData := make(map[string]any)
+	if err := record.UnmarshalJSONField("triggerData", &triggerData); err != nil {
+		return nil, fmt.Errorf("field 'triggerData' is malformed")
+	}
+
+	plan := domain.WorkflowRunPlan{}
+	if err := record.UnmarshalJSONField("plan", &plan); err != nil {
+		return nil, fmt.Errorf("field 'plan' is malformed")
+	}
+
 	workflowRun := &domain.WorkflowRun{
 		Meta: domain.Meta{
 			Id:        record.Id,
 			CreatedAt: record.GetDateTime("created").Time(),
 			UpdatedAt: record.GetDateTime("updated").Time(),
 		},
-		WorkflowId: record.GetString("workflowRef"),
-		Status:     domain.WorkflowRunStatusType(record.GetString("status")),
-		Trigger:    domain.WorkflowTriggerType(record.GetString("trigger")),
-		StartedAt:  record.GetDateTime("startedAt").Time(),
-		EndedAt:    record.GetDateTime("endedAt").Time(),
-		Graph:      graph,
-		Error:      record.GetString("error"),
+		WorkflowId:       record.GetString("workflowRef"),
+		VersionId:        record.GetString("versionRef"),
+		Status:           domain.WorkflowRunStatusType(record.GetString("status")),
+		Trigger:          domain.WorkflowTriggerType(record.GetString("trigger")),
+		TriggerData:      triggerData,
+		StartedAt:        record.GetDateTime("startedAt").Time(),
+		EndedAt:          record.GetDateTime("endedAt").Time(),
+		Graph:            graph,
+		Error:            record.GetString("error"),
+		ErrorNodeId:      record.GetString("errorNodeId"),
+		ResumeFromRunId:  record.GetString("resumeFromRunRef"),
+		ResumeFromNodeId: record.GetString("resumeFromNodeId"),
+		Plan:             plan,
 	}
 	return workflowRun, nil
 }
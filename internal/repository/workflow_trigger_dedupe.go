@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type WorkflowTriggerDedupeRepository struct{}
+
+func NewWorkflowTriggerDedupeRepository() *WorkflowTriggerDedupeRepository {
+	return &WorkflowTriggerDedupeRepository{}
+}
+
+func (r *WorkflowTriggerDedupeRepository) ExistsByWorkflowIdAndKey(ctx context.Context, workflowId string, key string) (bool, error) {
+	var exists int
+	err := app.GetApp().RecordQuery(domain.CollectionNameWorkflowTriggerDedupe).
+		Select("(1)").
+		AndWhere(dbx.HashExp{"workflowRef": workflowId, "key": key}).
+		Limit(1).
+		Row(&exists)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *WorkflowTriggerDedupeRepository) Save(ctx context.Context, dedupe *domain.WorkflowTriggerDedupe) (*domain.WorkflowTriggerDedupe, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameWorkflowTriggerDedupe)
+	if err != nil {
+		return dedupe, err
+	}
+
+	var record *core.Record
+	if dedupe.Id == "" {
+		record = core.NewRecord(collection)
+	} else {
+		record, err = app.GetApp().FindRecordById(collection, dedupe.Id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return dedupe, err
+			}
+			record = core.NewRecord(collection)
+		}
+	}
+
+	record.Set("workflowRef", dedupe.WorkflowId)
+	record.Set("key", dedupe.Key)
+	record.Set("expireAt", dedupe.ExpireAt)
+	if err := app.GetApp().Save(record); err != nil {
+		return dedupe, err
+	}
+
+	dedupe.Id = record.Id
+	dedupe.CreatedAt = record.GetDateTime("created").Time()
+	dedupe.UpdatedAt = record.GetDateTime("updated").Time()
+	return dedupe, nil
+}
+
+func (r *WorkflowTriggerDedupeRepository) DeleteWithExprs(ctx context.Context, exprs ...dbx.Expression) (int, error) {
+	records, err := app.GetApp().FindAllRecords(domain.CollectionNameWorkflowTriggerDedupe, exprs...)
+	if err != nil {
+		return 0, nil
+	}
+
+	var ret int
+	var errs []error
+	for _, record := range records {
+		if err := app.GetApp().Delete(record); err != nil {
+			errs = append(errs, err)
+		} else {
+			ret++
+		}
+	}
+
+	if len(errs) > 0 {
+		return ret, errors.Join(errs...)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type WorkflowVersionRepository struct{}
+
+func NewWorkflowVersionRepository() *WorkflowVersionRepository {
+	return &WorkflowVersionRepository{}
+}
+
+func (r *WorkflowVersionRepository) ListByWorkflowId(ctx context.Context, workflowId string) ([]*domain.WorkflowVersion, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameWorkflowVersion,
+		"workflowRef={:workflowId}",
+		"-version",
+		0, 0,
+		dbx.Params{"workflowId": workflowId},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowVersions := make([]*domain.WorkflowVersion, 0)
+	for _, record := range records {
+		workflowVersion, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		workflowVersions = append(workflowVersions, workflowVersion)
+	}
+
+	return workflowVersions, nil
+}
+
+func (r *WorkflowVersionRepository) GetById(ctx context.Context, id string) (*domain.WorkflowVersion, error) {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNameWorkflowVersion, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *WorkflowVersionRepository) GetLatestByWorkflowId(ctx context.Context, workflowId string) (*domain.WorkflowVersion, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameWorkflowVersion,
+		"workflowRef={:workflowId}",
+		"-version",
+		1, 0,
+		dbx.Params{"workflowId": workflowId},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	return r.castRecordToModel(records[0])
+}
+
+func (r *WorkflowVersionRepository) Save(ctx context.Context, workflowVersion *domain.WorkflowVersion) (*domain.WorkflowVersion, error) {
+	record, err := r.saveRecord(workflowVersion)
+	if err != nil {
+		return workflowVersion, err
+	}
+
+	workflowVersion.Id = record.Id
+	workflowVersion.CreatedAt = record.GetDateTime("created").Time()
+	workflowVersion.UpdatedAt = record.GetDateTime("updated").Time()
+	return workflowVersion, nil
+}
+
+func (r *WorkflowVersionRepository) castRecordToModel(record *core.Record) (*domain.WorkflowVersion, error) {
+	if record == nil {
+		return nil, fmt.Errorf("the record is nil")
+	}
+
+	graphContent := &domain.WorkflowGraph{}
+	if err := record.UnmarshalJSONField("graphContent", graphContent); err != nil {
+		return nil, fmt.Errorf("field 'graphContent' is malformed")
+	}
+
+	workflowVersion := &domain.WorkflowVersion{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		WorkflowId:   record.GetString("workflowRef"),
+		Version:      record.GetInt("version"),
+		GraphContent: graphContent,
+		Description:  record.GetString("description"),
+	}
+	return workflowVersion, nil
+}
+
+func (r *WorkflowVersionRepository) saveRecord(workflowVersion *domain.WorkflowVersion) (*core.Record, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameWorkflowVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *core.Record
+	if workflowVersion.Id == "" {
+		record = core.NewRecord(collection)
+	} else {
+		record, err = app.GetApp().FindRecordById(collection, workflowVersion.Id)
+		if err != nil {
+			return record, err
+		}
+	}
+	record.Set("workflowRef", workflowVersion.WorkflowId)
+	record.Set("version", workflowVersion.Version)
+	record.Set("graphContent", workflowVersion.GraphContent)
+	record.Set("description", workflowVersion.Description)
+	if err := app.GetApp().Save(record); err != nil {
+		return record, err
+	}
+
+	return record, nil
+}
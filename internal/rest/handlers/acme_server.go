@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-acme/lego/v5/acme"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+type acmeServerService interface {
+	NewNonce(ctx context.Context) (string, error)
+	GetDirectory(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error)
+	NewAccount(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error)
+	UpdateAccount(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error)
+	ListAccountOrders(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error)
+	NewOrder(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error)
+	GetOrder(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error)
+	FinalizeOrder(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error)
+	GetAuthorization(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error)
+	RespondChallenge(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error)
+	GetCertificate(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error)
+}
+
+type ACMEServerHandler struct {
+	service acmeServerService
+	prefix  string
+}
+
+// 注册符合 RFC 8555 的 ACME 服务端点。
+// 这些端点按 ACME 协议输出响应，而非本项目通用的响应格式。
+func NewACMEServerHandler(router *router.RouterGroup[*core.RequestEvent], service acmeServerService) {
+	handler := &ACMEServerHandler{
+		service: service,
+		prefix:  router.Prefix,
+	}
+
+	router.GET("/directory", handler.getDirectory)
+	router.HEAD("/new-nonce", handler.newNonce)
+	router.GET("/new-nonce", handler.newNonce)
+	router.POST("/new-account", handler.serveSigned(service.NewAccount))
+	router.POST("/account/{resourceId}", handler.serveSigned(service.UpdateAccount))
+	router.POST("/account/{resourceId}/orders", handler.serveSigned(service.ListAccountOrders))
+	router.POST("/new-order", handler.serveSigned(service.NewOrder))
+	router.POST("/order/{resourceId}", handler.serveSigned(service.GetOrder))
+	router.POST("/order/{resourceId}/finalize", handler.serveSigned(service.FinalizeOrder))
+	router.POST("/authz/{resourceId}", handler.serveSigned(service.GetAuthorization))
+	router.POST("/chall/{resourceId}", handler.serveSigned(service.RespondChallenge))
+	router.POST("/cert/{resourceId}", handler.serveSigned(service.GetCertificate))
+}
+
+func (handler *ACMEServerHandler) getDirectory(e *core.RequestEvent) error {
+	req := handler.buildRequest(e)
+
+	res, err := handler.service.GetDirectory(e.Request.Context(), req)
+	if err != nil {
+		return handler.writeError(e, req, err)
+	}
+
+	return handler.writeResponse(e, req, res)
+}
+
+func (handler *ACMEServerHandler) newNonce(e *core.RequestEvent) error {
+	req := handler.buildRequest(e)
+
+	nonce, err := handler.service.NewNonce(e.Request.Context())
+	if err != nil {
+		return handler.writeError(e, req, err)
+	}
+
+	e.Response.Header().Set("Replay-Nonce", nonce)
+	e.Response.Header().Set("Cache-Control", "no-store")
+	e.Response.Header().Add("Link", `<`+req.BaseUrl+`/directory>;rel="index"`)
+	if e.Request.Method == http.MethodHead {
+		return e.NoContent(http.StatusOK)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+func (handler *ACMEServerHandler) serveSigned(fn func(ctx context.Context, req *dtos.ACMEServerReq) (*dtos.ACMEServerResp, error)) func(e *core.RequestEvent) error {
+	const MAX_PAYLOAD_SIZE = 1 << 20
+
+	return func(e *core.RequestEvent) error {
+		req := handler.buildRequest(e)
+
+		if contentType := e.Request.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/jose+json") {
+			return handler.writeError(e, req, &acme.ProblemDetails{
+				Type:       acme.MalformedErrorType,
+				Detail:     "the content type must be 'application/jose+json'",
+				HTTPStatus: http.StatusUnsupportedMediaType,
+			})
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(e.Response, e.Request.Body, MAX_PAYLOAD_SIZE))
+		if err != nil {
+			return handler.writeError(e, req, &acme.ProblemDetails{
+				Type:       acme.MalformedErrorType,
+				Detail:     "failed to read request body",
+				HTTPStatus: http.StatusBadRequest,
+			})
+		}
+		req.Body = body
+
+		res, err := fn(e.Request.Context(), req)
+		if err != nil {
+			return handler.writeError(e, req, err)
+		}
+
+		return handler.writeResponse(e, req, res)
+	}
+}
+
+func (handler *ACMEServerHandler) buildRequest(e *core.RequestEvent) *dtos.ACMEServerReq {
+	scheme := "http"
+	if e.Request.TLS != nil || strings.EqualFold(e.Request.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+
+	baseUrl := scheme + "://" + e.Request.Host + handler.prefix
+	return &dtos.ACMEServerReq{
+		BaseUrl:    baseUrl,
+		Url:        baseUrl + strings.TrimPrefix(e.Request.URL.Path, handler.prefix),
+		ResourceId: e.Request.PathValue("resourceId"),
+	}
+}
+
+func (handler *ACMEServerHandler) writeHeaders(e *core.RequestEvent, req *dtos.ACMEServerReq) {
+	// 每个响应均附带新的随机数，以便客户端发起下一次请求
+	if nonce, err := handler.service.NewNonce(e.Request.Context()); err == nil {
+		e.Response.Header().Set("Replay-Nonce", nonce)
+	}
+
+	e.Response.Header().Set("Cache-Control", "no-store")
+	e.Response.Header().Add("Link", `<`+req.BaseUrl+`/directory>;rel="index"`)
+}
+
+func (handler *ACMEServerHandler) writeResponse(e *core.RequestEvent, req *dtos.ACMEServerReq, res *dtos.ACMEServerResp) error {
+	handler.writeHeaders(e, req)
+
+	if res.Location != "" {
+		e.Response.Header().Set("Location", res.Location)
+	}
+	for _, link := range res.Links {
+		e.Response.Header().Add("Link", link)
+	}
+	if res.RetryAfter > 0 {
+		e.Response.Header().Set("Retry-After", strconv.Itoa(res.RetryAfter))
+	}
+
+	if bytes, ok := res.Content.([]byte); ok {
+		return e.Blob(res.StatusCode, res.ContentType, bytes)
+	}
+
+	return e.JSON(res.StatusCode, res.Content)
+}
+
+func (handler *ACMEServerHandler) writeError(e *core.RequestEvent, req *dtos.ACMEServerReq, err error) error {
+	handler.writeHeaders(e, req)
+
+	problem := &acme.ProblemDetails{}
+	if !errors.As(err, &problem) {
+		problem = &acme.ProblemDetails{
+			Type:       acme.ServerInternalErrorType,
+			Detail:     err.Error(),
+			HTTPStatus: http.StatusInternalServerError,
+		}
+	}
+
+	e.Response.Header().Set("Content-Type", "application/problem+json")
+	return e.JSON(problem.HTTPStatus, problem)
+}
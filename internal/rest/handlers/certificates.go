@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"context"
+	"encoding/pem"
+	"net/http"
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/router"
 
+	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/internal/domain/dtos"
 	"github.com/certimate-go/certimate/internal/rest/resp"
 )
@@ -13,6 +16,8 @@ import (
 type certificateService interface {
 	DownloadCertificate(ctx context.Context, req *dtos.CertificateDownloadReq) (*dtos.CertificateDownloadResp, error)
 	RevokeCertificate(ctx context.Context, req *dtos.CertificateRevokeReq) (*dtos.CertificateRevokeResp, error)
+	GenerateLocalCA(ctx context.Context, req *dtos.CertificateGenerateLocalCAReq) (*dtos.CertificateGenerateLocalCAResp, error)
+	GenerateLocalCACRL(ctx context.Context, req *dtos.CertificateGenerateLocalCACRLReq) (*dtos.CertificateGenerateLocalCACRLResp, error)
 }
 
 type CertificatesHandler struct {
@@ -27,10 +32,20 @@ func NewCertificatesHandler(router *router.RouterGroup[*core.RequestEvent], serv
 	group := router.Group("/certificates")
 	group.POST("/{certificateId}/download", handler.downloadCertificate)
 	group.POST("/{certificateId}/revoke", handler.revokeCertificate)
+	group.POST("/localca/generate", handler.generateLocalCA)
 
 	group.POST("/{certificateId}/archive", handler.downloadCertificate) // 兼容旧版
 }
 
+func NewCertificatesCRLHandler(router *router.RouterGroup[*core.RequestEvent], service certificateService) {
+	handler := &CertificatesHandler{
+		service: service,
+	}
+
+	group := router.Group("/certificates")
+	group.GET("/localca/{accessId}/crl", handler.getLocalCACRL)
+}
+
 func (handler *CertificatesHandler) downloadCertificate(e *core.RequestEvent) error {
 	req := &dtos.CertificateDownloadReq{}
 	req.CertificateId = e.Request.PathValue("certificateId")
@@ -60,3 +75,37 @@ func (handler *CertificatesHandler) revokeCertificate(e *core.RequestEvent) erro
 
 	return resp.Ok(e, res)
 }
+
+func (handler *CertificatesHandler) generateLocalCA(e *core.RequestEvent) error {
+	req := &dtos.CertificateGenerateLocalCAReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.GenerateLocalCA(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *CertificatesHandler) getLocalCACRL(e *core.RequestEvent) error {
+	req := &dtos.CertificateGenerateLocalCACRLReq{}
+	req.AccessId = e.Request.PathValue("accessId")
+
+	res, err := handler.service.GenerateLocalCACRL(e.Request.Context(), req)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return e.NotFoundError("", nil)
+		}
+		return e.InternalServerError("", err)
+	}
+
+	block, _ := pem.Decode([]byte(res.CRL))
+	if block == nil {
+		return e.InternalServerError("", nil)
+	}
+
+	return e.Blob(http.StatusOK, "application/pkix-crl", block.Bytes)
+}
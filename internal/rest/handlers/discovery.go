@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/rest/resp"
+)
+
+type discoveryService interface {
+	RunDiscovery(ctx context.Context, req *dtos.DiscoveryRunReq) (*dtos.DiscoveryRunResp, error)
+}
+
+type DiscoveryHandler struct {
+	service discoveryService
+}
+
+func NewDiscoveryHandler(router *router.RouterGroup[*core.RequestEvent], service discoveryService) {
+	handler := &DiscoveryHandler{
+		service: service,
+	}
+
+	group := router.Group("/discovery")
+	group.POST("/run", handler.run)
+}
+
+func (handler *DiscoveryHandler) run(e *core.RequestEvent) error {
+	req := &dtos.DiscoveryRunReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.RunDiscovery(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/rest/resp"
+)
+
+type monitoringService interface {
+	ScanEndpoint(ctx context.Context, req *dtos.MonitoredEndpointScanReq) (*dtos.MonitoredEndpointScanResp, error)
+	ImportEndpoints(ctx context.Context, req *dtos.MonitoredEndpointImportReq) (*dtos.MonitoredEndpointImportResp, error)
+}
+
+type MonitoredEndpointsHandler struct {
+	service monitoringService
+}
+
+func NewMonitoredEndpointsHandler(router *router.RouterGroup[*core.RequestEvent], service monitoringService) {
+	handler := &MonitoredEndpointsHandler{
+		service: service,
+	}
+
+	group := router.Group("/monitored-endpoints")
+	group.POST("/import", handler.importEndpoints)
+	group.POST("/{endpointId}/scan", handler.scanEndpoint)
+}
+
+func (handler *MonitoredEndpointsHandler) importEndpoints(e *core.RequestEvent) error {
+	req := &dtos.MonitoredEndpointImportReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.ImportEndpoints(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *MonitoredEndpointsHandler) scanEndpoint(e *core.RequestEvent) error {
+	req := &dtos.MonitoredEndpointScanReq{}
+	req.EndpointId = e.Request.PathValue("endpointId")
+
+	res, err := handler.service.ScanEndpoint(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/rest/resp"
+)
+
+type webhookService interface {
+	TriggerWebhook(ctx context.Context, req *dtos.WorkflowTriggerWebhookReq) (*dtos.WorkflowTriggerWebhookResp, error)
+}
+
+type WebhooksHandler struct {
+	service webhookService
+}
+
+func NewWebhooksHandler(router *router.RouterGroup[*core.RequestEvent], service webhookService) {
+	handler := &WebhooksHandler{
+		service: service,
+	}
+
+	router.POST("/workflows/{workflowId}", handler.triggerWorkflow)
+}
+
+func (handler *WebhooksHandler) triggerWorkflow(e *core.RequestEvent) error {
+	const MAX_PAYLOAD_SIZE = 1 << 20
+
+	req := &dtos.WorkflowTriggerWebhookReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.Secret = e.Request.Header.Get("X-Webhook-Secret")
+	if req.Secret == "" {
+		req.Secret = strings.TrimPrefix(e.Request.Header.Get("Authorization"), "Bearer ")
+	}
+
+	req.Payload = make(map[string]any)
+	if err := json.NewDecoder(http.MaxBytesReader(e.Response, e.Request.Body, MAX_PAYLOAD_SIZE)).Decode(&req.Payload); err != nil && !errors.Is(err, io.EOF) {
+		return resp.Err(e, fmt.Errorf("invalid parameters: the payload must be a JSON object: %w", err))
+	}
+
+	res, err := handler.service.TriggerWebhook(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
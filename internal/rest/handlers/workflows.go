@@ -16,6 +16,10 @@ type workflowService interface {
 	GetStatistics(ctx context.Context) (*dtos.WorkflowStatisticsResp, error)
 	StartRun(ctx context.Context, req *dtos.WorkflowStartRunReq) (*dtos.WorkflowStartRunResp, error)
 	CancelRun(ctx context.Context, req *dtos.WorkflowCancelRunReq) (*dtos.WorkflowCancelRunResp, error)
+	ResumeRun(ctx context.Context, req *dtos.WorkflowResumeRunReq) (*dtos.WorkflowResumeRunResp, error)
+	ListVersions(ctx context.Context, req *dtos.WorkflowListVersionsReq) (*dtos.WorkflowListVersionsResp, error)
+	DiffVersions(ctx context.Context, req *dtos.WorkflowDiffVersionsReq) (*dtos.WorkflowDiffVersionsResp, error)
+	RollbackVersion(ctx context.Context, req *dtos.WorkflowRollbackVersionReq) (*dtos.WorkflowRollbackVersionResp, error)
 	Shutdown(ctx context.Context)
 }
 
@@ -32,6 +36,10 @@ func NewWorkflowsHandler(router *router.RouterGroup[*core.RequestEvent], service
 	group.GET("/stats", handler.getStatistics)
 	group.POST("/{workflowId}/runs", handler.startRun)
 	group.POST("/{workflowId}/runs/{runId}/cancel", handler.cancelRun)
+	group.POST("/{workflowId}/runs/{runId}/resume", handler.resumeRun)
+	group.GET("/{workflowId}/versions", handler.listVersions)
+	group.GET("/{workflowId}/versions/diff", handler.diffVersions)
+	group.POST("/{workflowId}/versions/{versionId}/rollback", handler.rollbackVersion)
 }
 
 func (handler *WorkflowsHandler) getStatistics(e *core.RequestEvent) error {
@@ -49,8 +57,8 @@ func (handler *WorkflowsHandler) startRun(e *core.RequestEvent) error {
 	if err := e.BindBody(req); err != nil {
 		return resp.Err(e, err)
 	}
-	if req.RunTrigger != domain.WorkflowTriggerTypeManual {
-		return resp.Err(e, fmt.Errorf("invalid parameters: the value of 'trigger' must be 'manual'"))
+	if req.RunTrigger != domain.WorkflowTriggerTypeManual && req.RunTrigger != domain.WorkflowTriggerTypeDryRun {
+		return resp.Err(e, fmt.Errorf("invalid parameters: the value of 'trigger' must be 'manual' or 'dryrun'"))
 	}
 
 	res, err := handler.service.StartRun(e.Request.Context(), req)
@@ -73,3 +81,58 @@ func (handler *WorkflowsHandler) cancelRun(e *core.RequestEvent) error {
 
 	return resp.Ok(e, res)
 }
+
+func (handler *WorkflowsHandler) resumeRun(e *core.RequestEvent) error {
+	req := &dtos.WorkflowResumeRunReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.RunId = e.Request.PathValue("runId")
+
+	res, err := handler.service.ResumeRun(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowsHandler) listVersions(e *core.RequestEvent) error {
+	req := &dtos.WorkflowListVersionsReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+
+	res, err := handler.service.ListVersions(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowsHandler) diffVersions(e *core.RequestEvent) error {
+	req := &dtos.WorkflowDiffVersionsReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.FromVersionId = e.Request.URL.Query().Get("from")
+	req.ToVersionId = e.Request.URL.Query().Get("to")
+	if req.FromVersionId == "" {
+		return resp.Err(e, fmt.Errorf("invalid parameters: the value of 'from' is required"))
+	}
+
+	res, err := handler.service.DiffVersions(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowsHandler) rollbackVersion(e *core.RequestEvent) error {
+	req := &dtos.WorkflowRollbackVersionReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.VersionId = e.Request.PathValue("versionId")
+
+	res, err := handler.service.RollbackVersion(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
@@ -5,7 +5,10 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/router"
 
+	"github.com/certimate-go/certimate/internal/acmeserver"
 	"github.com/certimate-go/certimate/internal/certificate"
+	"github.com/certimate-go/certimate/internal/discovery"
+	"github.com/certimate-go/certimate/internal/monitoring"
 	"github.com/certimate-go/certimate/internal/notify"
 	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/internal/rest/handlers"
@@ -18,20 +21,42 @@ var (
 	workflowSvc    *workflow.WorkflowService
 	statisticsSvc  *statistics.StatisticsService
 	notifySvc      *notify.NotifyService
+	acmeServerSvc  *acmeserver.ACMEServerService
+	monitoringSvc  *monitoring.MonitoringService
+	discoverySvc   *discovery.DiscoveryService
 )
 
 func BindRouter(router *router.Router[*core.RequestEvent]) {
 	accessRepo := repository.NewAccessRepository()
 	workflowRepo := repository.NewWorkflowRepository()
 	workflowRunRepo := repository.NewWorkflowRunRepository()
+	workflowVersionRepo := repository.NewWorkflowVersionRepository()
+	workflowTriggerDedupeRepo := repository.NewWorkflowTriggerDedupeRepository()
 	acmeAccountRepo := repository.NewACMEAccountRepository()
+	acmeServerClientRepo := repository.NewACMEServerClientRepository()
+	acmeServerAccountRepo := repository.NewACMEServerAccountRepository()
+	acmeServerOrderRepo := repository.NewACMEServerOrderRepository()
 	certificateRepo := repository.NewCertificateRepository()
 	statisticsRepo := repository.NewStatisticsRepository()
+	monitoredEndpointRepo := repository.NewMonitoredEndpointRepository()
+	monitoredEndpointScanRepo := repository.NewMonitoredEndpointScanRepository()
 
-	certificateSvc = certificate.NewCertificateService(acmeAccountRepo, certificateRepo)
-	workflowSvc = workflow.NewWorkflowService(workflowRepo, workflowRunRepo)
+	certificateSvc = certificate.NewCertificateService(accessRepo, acmeAccountRepo, certificateRepo)
+	workflowSvc = workflow.NewWorkflowService(workflowRepo, workflowRunRepo, workflowVersionRepo, workflowTriggerDedupeRepo, certificateRepo)
 	statisticsSvc = statistics.NewStatisticsService(statisticsRepo)
 	notifySvc = notify.NewNotifyService(accessRepo)
+	acmeServerSvc = acmeserver.NewACMEServerService(accessRepo, acmeServerClientRepo, acmeServerAccountRepo, acmeServerOrderRepo, certificateRepo)
+	monitoringSvc = monitoring.NewMonitoringService(accessRepo, monitoredEndpointRepo, monitoredEndpointScanRepo)
+	discoverySvc = discovery.NewDiscoveryService(certificateRepo)
+
+	// Webhook 使用工作流自身配置的密钥鉴权，因此不要求超级用户身份
+	handlers.NewWebhooksHandler(router.Group("/api/webhooks"), workflowSvc)
+
+	// ACME 客户端使用 JWS 及外部账户绑定鉴权，因此不要求超级用户身份
+	handlers.NewACMEServerHandler(router.Group("/acme"), acmeServerSvc)
+
+	// 本地 CA 的 CRL 需供依赖方通过所签发证书中的 CRL 分发点获取，因此不要求超级用户身份
+	handlers.NewCertificatesCRLHandler(router.Group("/api"), certificateSvc)
 
 	group := router.Group("/api")
 	group.Bind(apis.RequireSuperuserAuth())
@@ -39,4 +64,6 @@ func BindRouter(router *router.Router[*core.RequestEvent]) {
 	handlers.NewWorkflowsHandler(group, workflowSvc)
 	handlers.NewStatisticsHandler(group, statisticsSvc)
 	handlers.NewNotificationsHandler(group, notifySvc)
+	handlers.NewMonitoredEndpointsHandler(group, monitoringSvc)
+	handlers.NewDiscoveryHandler(group, discoverySvc)
 }
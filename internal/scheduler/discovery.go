@@ -0,0 +1,13 @@
+package scheduler
+
+import (
+	"context"
+)
+
+type discoveryService interface {
+	InitSchedule(ctx context.Context) error
+}
+
+func initDiscoveryScheduler(service discoveryService) error {
+	return service.InitSchedule(context.Background())
+}
@@ -0,0 +1,13 @@
+package scheduler
+
+import (
+	"context"
+)
+
+type monitoringService interface {
+	InitSchedule(ctx context.Context) error
+}
+
+func initMonitoringScheduler(service monitoringService) error {
+	return service.InitSchedule(context.Background())
+}
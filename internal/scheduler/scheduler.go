@@ -5,18 +5,27 @@ import (
 
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/certificate"
+	"github.com/certimate-go/certimate/internal/discovery"
+	"github.com/certimate-go/certimate/internal/monitoring"
 	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/internal/workflow"
 )
 
 func Setup() {
+	accessRepo := repository.NewAccessRepository()
 	workflowRepo := repository.NewWorkflowRepository()
 	workflowRunRepo := repository.NewWorkflowRunRepository()
+	workflowVersionRepo := repository.NewWorkflowVersionRepository()
+	workflowTriggerDedupeRepo := repository.NewWorkflowTriggerDedupeRepository()
 	acmeAccountRepo := repository.NewACMEAccountRepository()
 	certificateRepo := repository.NewCertificateRepository()
+	monitoredEndpointRepo := repository.NewMonitoredEndpointRepository()
+	monitoredEndpointScanRepo := repository.NewMonitoredEndpointScanRepository()
 
-	workflowSvc := workflow.NewWorkflowService(workflowRepo, workflowRunRepo)
-	certificateSvc := certificate.NewCertificateService(acmeAccountRepo, certificateRepo)
+	workflowSvc := workflow.NewWorkflowService(workflowRepo, workflowRunRepo, workflowVersionRepo, workflowTriggerDedupeRepo, certificateRepo)
+	certificateSvc := certificate.NewCertificateService(accessRepo, acmeAccountRepo, certificateRepo)
+	monitoringSvc := monitoring.NewMonitoringService(accessRepo, monitoredEndpointRepo, monitoredEndpointScanRepo)
+	discoverySvc := discovery.NewDiscoveryService(certificateRepo)
 
 	if err := initWorkflowScheduler(workflowSvc); err != nil {
 		app.GetLogger().Error("failed to init workflow scheduler", slog.Any("error", err))
@@ -25,4 +34,12 @@ func Setup() {
 	if err := initCertificateScheduler(certificateSvc); err != nil {
 		app.GetLogger().Error("failed to init certificate scheduler", slog.Any("error", err))
 	}
+
+	if err := initMonitoringScheduler(monitoringSvc); err != nil {
+		app.GetLogger().Error("failed to init monitoring scheduler", slog.Any("error", err))
+	}
+
+	if err := initDiscoveryScheduler(discoverySvc); err != nil {
+		app.GetLogger().Error("failed to init discovery scheduler", slog.Any("error", err))
+	}
 }
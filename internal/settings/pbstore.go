@@ -30,6 +30,36 @@ func GetGlobalSettingsForPersistence() domain.SettingsContentForPersistence {
 	return *content.(domain.SettingsContent).AsPersistence()
 }
 
+func GetGlobalSettingsForACMEServer() domain.SettingsContentForACMEServer {
+	pb := app.GetApp()
+	name := domain.SettingsNameACMEServer
+	content := pb.Store().Get(buildPbStoreKey(name))
+	if content == nil {
+		content = domain.SettingsContent{}
+	}
+	return *content.(domain.SettingsContent).AsACMEServer()
+}
+
+func GetGlobalSettingsForMonitoring() domain.SettingsContentForMonitoring {
+	pb := app.GetApp()
+	name := domain.SettingsNameMonitoring
+	content := pb.Store().Get(buildPbStoreKey(name))
+	if content == nil {
+		content = domain.SettingsContent{}
+	}
+	return *content.(domain.SettingsContent).AsMonitoring()
+}
+
+func GetGlobalSettingsForDiscovery() domain.SettingsContentForDiscovery {
+	pb := app.GetApp()
+	name := domain.SettingsNameDiscovery
+	content := pb.Store().Get(buildPbStoreKey(name))
+	if content == nil {
+		content = domain.SettingsContent{}
+	}
+	return *content.(domain.SettingsContent).AsDiscovery()
+}
+
 func registerSettingsStoreByName(settingsName string) error {
 	settingsRepo := repository.NewSettingsRepository()
 	settings, err := settingsRepo.GetByName(context.Background(), settingsName)
@@ -9,5 +9,8 @@ func Setup() {
 
 	registerSettingsStoreByName(domain.SettingsNameSSLProvider)
 	registerSettingsStoreByName(domain.SettingsNamePersistence)
+	registerSettingsStoreByName(domain.SettingsNameACMEServer)
+	registerSettingsStoreByName(domain.SettingsNameMonitoring)
+	registerSettingsStoreByName(domain.SettingsNameDiscovery)
 	registerSettingsRecordEvents()
 }
@@ -19,5 +19,6 @@ type workflowRunRepository interface {
 }
 
 type workflowLogRepository interface {
+	ListByWorkflowRunId(ctx context.Context, workflowRunId string) ([]*domain.WorkflowLog, error)
 	Save(ctx context.Context, workflowLog *domain.WorkflowLog) (*domain.WorkflowLog, error)
 }
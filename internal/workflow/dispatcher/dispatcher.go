@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"runtime"
 	"runtime/debug"
+	"slices"
 	"sync"
 	"time"
 
@@ -241,9 +242,14 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 
 	// 初始化工作流引擎
 	logsBuf := make(domain.WorkflowLogs, 0)
+	logsMtx := sync.Mutex{} // 并行分支可能同时写入日志
 	we := engine.NewWorkflowEngine()
 	we.OnEnd(func(ctx context.Context) error {
-		if errmsg := logsBuf.ErrorString(); errmsg == "" {
+		logsMtx.Lock()
+		errmsg := logsBuf.ErrorString()
+		logsMtx.Unlock()
+
+		if errmsg == "" {
 			workflowRun.Status = domain.WorkflowRunStatusTypeSucceeded
 			workflowRun.EndedAt = time.Now()
 		} else {
@@ -256,7 +262,22 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		return nil
 	})
 	we.OnError(func(ctx context.Context, err error) error {
-		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// 记录导致运行失败的首个节点，以便后续从该节点处恢复运行
+		// 已被 TryCatch 等节点处理的异常不会出现在最终错误中；
+		// 子工作流中的节点不在当前工作流图中，由外层的子工作流节点记录
+		var nodeErr *engine.NodeError
+		if errors.As(err, &nodeErr) {
+			if _, ok := workflowRun.Graph.GetNodeById(nodeErr.NodeId); ok {
+				workflowRun.ErrorNodeId = nodeErr.NodeId
+			}
+		}
+
+		if errors.Is(err, engine.ErrRunTimedOut) || errors.Is(err, engine.ErrNodeTimedOut) {
+			workflowRun.Status = domain.WorkflowRunStatusTypeFailed
+			workflowRun.EndedAt = time.Now()
+			workflowRun.Error = err.Error()
+			wd.workflowRunRepo.SaveWithCascading(context.Background(), workflowRun)
+		} else if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 			workflowRun.Status = domain.WorkflowRunStatusTypeCanceled
 			wd.workflowRunRepo.SaveWithCascading(context.Background(), workflowRun)
 		} else {
@@ -268,6 +289,23 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 
 		return nil
 	})
+	we.OnNodeEnd(func(ctx context.Context, node *engine.Node, res *engine.NodeExecutionResult) error {
+		if workflowRun.Trigger != domain.WorkflowTriggerTypeDryRun || res == nil || res.Plan == nil {
+			return nil
+		}
+
+		logsMtx.Lock()
+		workflowRun.Plan = append(workflowRun.Plan, &domain.WorkflowRunPlanEntry{
+			NodeId:   node.Id,
+			NodeName: node.Data.Name,
+			NodeType: node.Type,
+			Action:   res.Plan.Action,
+			Message:  res.Plan.Message,
+		})
+		logsMtx.Unlock()
+
+		return nil
+	})
 	we.OnNodeError(func(ctx context.Context, node *engine.Node, err error) error {
 		if errors.Is(err, engine.ErrTerminated) || errors.Is(err, engine.ErrBlocksException) {
 			return nil
@@ -282,7 +320,19 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		log.Level = int32(slog.LevelError)
 		log.Message = err.Error()
 		log.CreatedAt = time.Now()
+
+		logsMtx.Lock()
 		logsBuf = append(logsBuf, log)
+		if workflowRun.Trigger == domain.WorkflowTriggerTypeDryRun {
+			workflowRun.Plan = append(workflowRun.Plan, &domain.WorkflowRunPlanEntry{
+				NodeId:   node.Id,
+				NodeName: node.Data.Name,
+				NodeType: node.Type,
+				Action:   domain.WorkflowRunPlanActionTypeFail,
+				Message:  err.Error(),
+			})
+		}
+		logsMtx.Unlock()
 
 		if _, err := wd.workflowLogRepo.Save(ctx, &log); err != nil {
 			wd.syslog.Error(err.Error())
@@ -301,7 +351,10 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		log.Message = record.Message
 		log.Data = record.Data()
 		log.CreatedAt = time.Now()
+
+		logsMtx.Lock()
 		logsBuf = append(logsBuf, log)
+		logsMtx.Unlock()
 
 		if _, err := wd.workflowLogRepo.Save(ctx, &log); err != nil {
 			wd.syslog.Error(err.Error())
@@ -311,6 +364,7 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 	})
 
 	// 执行工作流
+	resumeFromRunIds := wd.getResumeFromRunIds(task.ctx, workflowRun)
 	wd.syslog.Info(fmt.Sprintf("workflow #%s's run #%s started", task.WorkflowId, task.RunId))
 	we.Invoke(task.ctx, engine.WorkflowExecution{
 		WorkflowId:          workflow.Id,
@@ -318,12 +372,58 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		WorkflowDescription: workflow.Description,
 		RunId:               workflowRun.Id,
 		RunTrigger:          workflowRun.Trigger,
+		RunTriggerData:      workflowRun.TriggerData,
 		RunAt:               workflowRun.StartedAt,
+		RunTimeout:          time.Duration(workflow.RunTimeout) * time.Second,
 		Graph:               workflowRun.Graph,
+		ResumeFromNodeId:    workflowRun.ResumeFromNodeId,
+		ResumeFromRunIds:    resumeFromRunIds,
+		ResumeRanNodeIds:    wd.getRanNodeIdsOfRuns(task.ctx, resumeFromRunIds),
 	})
 	wd.syslog.Info(fmt.Sprintf("workflow #%s's run #%s stopped", task.WorkflowId, task.RunId))
 }
 
+// 沿恢复链获取被恢复的运行，由近及远。
+func (wd *workflowDispatcher) getResumeFromRunIds(ctx context.Context, workflowRun *domain.WorkflowRun) []string {
+	runIds := make([]string, 0)
+
+	runId := workflowRun.ResumeFromRunId
+	for runId != "" && !slices.Contains(runIds, runId) {
+		runIds = append(runIds, runId)
+
+		sourceRun, err := wd.workflowRunRepo.GetById(ctx, runId)
+		if err != nil {
+			// 更早的运行可能已被清理
+			break
+		}
+
+		runId = sourceRun.ResumeFromRunId
+	}
+
+	return runIds
+}
+
+// 获取在指定运行中实际执行过（产生过日志）的节点。
+func (wd *workflowDispatcher) getRanNodeIdsOfRuns(ctx context.Context, runIds []string) []string {
+	nodeIds := make([]string, 0)
+
+	for _, runId := range runIds {
+		logs, err := wd.workflowLogRepo.ListByWorkflowRunId(ctx, runId)
+		if err != nil {
+			wd.syslog.Warn(fmt.Sprintf("failed to get logs of workflow run #%s", runId), slog.Any("error", err))
+			continue
+		}
+
+		for _, log := range logs {
+			if !slices.Contains(nodeIds, log.NodeId) {
+				nodeIds = append(nodeIds, log.NodeId)
+			}
+		}
+	}
+
+	return nodeIds
+}
+
 func (wd *workflowDispatcher) tryNextAsync() {
 	wd.taskMtx.RLock()
 
@@ -2,6 +2,8 @@ package engine
 
 import (
 	"context"
+	"reflect"
+	"slices"
 )
 
 type WorkflowContext struct {
@@ -14,6 +16,14 @@ type WorkflowContext struct {
 	inputs    InOutManager
 
 	ctx context.Context
+
+	dryRun   bool           // 是否为试运行模式，试运行时不会实际申请、部署证书或推送通知
+	resuming *resumingState // 从失败节点处恢复运行时的状态，为空时表示正常运行
+
+	callStack []string // 正在执行的子工作流的调用链（不含当前工作流），用于检测循环调用
+
+	forkedVariables []VariableState // 创建分支时的变量快照，用于合并时计算差异
+	forkedInputs    []InOutState    // 创建分支时的输入输出快照，用于合并时计算差异
 }
 
 func (c *WorkflowContext) SetExecutingWorkflow(workflowId string, runId string, runGraph *Graph) *WorkflowContext {
@@ -38,6 +48,15 @@ func (c *WorkflowContext) SetInputsManager(manager InOutManager) *WorkflowContex
 	return c
 }
 
+func (c *WorkflowContext) SetDryRun(dryRun bool) *WorkflowContext {
+	c.dryRun = dryRun
+	return c
+}
+
+func (c *WorkflowContext) IsDryRun() bool {
+	return c.dryRun
+}
+
 func (c *WorkflowContext) SetContext(ctx context.Context) *WorkflowContext {
 	c.ctx = ctx
 	return c
@@ -58,5 +77,47 @@ func (c *WorkflowContext) Clone() *WorkflowContext {
 		inputs:    c.inputs,
 
 		ctx: c.ctx,
+
+		dryRun:   c.dryRun,
+		resuming: c.resuming,
+
+		callStack: c.callStack,
+	}
+}
+
+// 创建当前上下文的分支副本。
+// 分支拥有独立的变量和输入输出管理器，其修改不会影响原上下文，直到调用 [WorkflowContext.Merge] 合并。
+func (c *WorkflowContext) Fork() *WorkflowContext {
+	forked := c.Clone()
+
+	forked.forkedVariables = c.variables.All()
+	forked.variables = newVariableManager()
+	for _, state := range forked.forkedVariables {
+		forked.variables.Add(state)
+	}
+
+	forked.forkedInputs = c.inputs.All()
+	forked.inputs = newInOutManager()
+	for _, state := range forked.forkedInputs {
+		forked.inputs.Add(state)
+	}
+
+	return forked
+}
+
+// 将分支副本中新增或变更的变量和输入输出合并回当前上下文。
+func (c *WorkflowContext) Merge(forked *WorkflowContext) {
+	for _, state := range forked.variables.All() {
+		if slices.ContainsFunc(forked.forkedVariables, func(s VariableState) bool { return reflect.DeepEqual(s, state) }) {
+			continue
+		}
+		c.variables.Add(state)
+	}
+
+	for _, state := range forked.inputs.All() {
+		if slices.ContainsFunc(forked.forkedInputs, func(s InOutState) bool { return reflect.DeepEqual(s, state) }) {
+			continue
+		}
+		c.inputs.Add(state)
 	}
 }
@@ -16,6 +16,10 @@ type certificateRepository interface {
 	Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error)
 }
 
+type workflowRepository interface {
+	GetById(ctx context.Context, id string) (*domain.Workflow, error)
+}
+
 type workflowOutputRepository interface {
 	GetByWorkflowIdAndNodeId(ctx context.Context, workflowId string, workflowNodeId string) (*domain.WorkflowOutput, error)
 	Save(ctx context.Context, workflowOutput *domain.WorkflowOutput) (*domain.WorkflowOutput, error)
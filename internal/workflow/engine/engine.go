@@ -7,14 +7,17 @@ import (
 	"log/slog"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/samber/lo"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/pkg/logging"
+	xwait "github.com/certimate-go/certimate/pkg/utils/wait"
 )
 
 type WorkflowExecution struct {
@@ -23,8 +26,13 @@ type WorkflowExecution struct {
 	WorkflowDescription string
 	RunId               string
 	RunTrigger          domain.WorkflowTriggerType
+	RunTriggerData      map[string]any // 事件触发时携带的数据，将展开为 "trigger.*" 变量
 	RunAt               time.Time
+	RunTimeout          time.Duration // 零值时不限制
 	Graph               *Graph
+	ResumeFromNodeId    string   // 非空时表示从该节点处恢复运行
+	ResumeFromRunIds    []string // 被恢复的运行，由近及远，用于还原失败节点之前的节点
+	ResumeRanNodeIds    []string // 被恢复的运行中实际执行过（产生过日志）的节点，仅这些节点会被还原
 }
 
 type WorkflowEngine interface {
@@ -40,7 +48,7 @@ type WorkflowEngine interface {
 }
 
 type workflowEngine struct {
-	executors map[NodeType]NodeExecutor
+	executors map[NodeType]func() NodeExecutor
 
 	hooksMtx           sync.RWMutex
 	onStartHooks       [](func(ctx context.Context) error)
@@ -67,8 +75,26 @@ func (we *workflowEngine) Invoke(ctx context.Context, execution WorkflowExecutio
 		}
 	}()
 
+	var resuming *resumingState
+	if execution.ResumeFromNodeId != "" {
+		state, err := newResumingState(execution.Graph, execution.ResumeFromNodeId, execution.ResumeFromRunIds, execution.ResumeRanNodeIds)
+		if err != nil {
+			we.fireOnErrorHooks(ctx, err)
+			return err
+		}
+
+		resuming = state
+	}
+
 	we.fireOnStartHooks(ctx)
 
+	runCtx := ctx
+	if execution.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeoutCause(ctx, execution.RunTimeout, ErrRunTimedOut)
+		defer cancel()
+	}
+
 	wfIOs := newInOutManager()
 
 	wfVars := newVariableManager()
@@ -80,15 +106,24 @@ func (we *workflowEngine) Invoke(ctx context.Context, execution WorkflowExecutio
 	wfVars.Set(stateVarKeyErrorNodeId, "", stateValTypeString)
 	wfVars.Set(stateVarKeyErrorNodeName, "", stateValTypeString)
 	wfVars.Set(stateVarKeyErrorMessage, "", stateValTypeString)
+	for _, state := range flattenVariables(stateVarKeyTriggerPrefix, execution.RunTriggerData) {
+		wfVars.Add(state)
+	}
 
 	wfCtx := (&WorkflowContext{}).
 		SetExecutingWorkflow(execution.WorkflowId, execution.RunId, execution.Graph).
 		SetEngine(we).
 		SetInputsManager(wfIOs).
 		SetVariablesManager(wfVars).
-		SetContext(ctx)
+		SetDryRun(execution.RunTrigger == domain.WorkflowTriggerTypeDryRun).
+		SetContext(runCtx)
+	wfCtx.resuming = resuming
 	if err := we.executeBlocks(wfCtx, execution.Graph.Nodes); err != nil {
 		if !errors.Is(err, ErrTerminated) {
+			if errors.Is(context.Cause(runCtx), ErrRunTimedOut) {
+				err = fmt.Errorf("%w after %s: %w", ErrRunTimedOut, execution.RunTimeout, err)
+			}
+
 			we.fireOnErrorHooks(ctx, err)
 			return err
 		}
@@ -142,21 +177,28 @@ func (we *workflowEngine) OnNodeLogging(callback func(ctx context.Context, node
 }
 
 func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error {
-	executor, ok := we.executors[node.Type]
+	newExecutor, ok := we.executors[node.Type]
 	if !ok {
 		err := fmt.Errorf("workflow engine: no executor registered for node type: '%s'", node.Type)
 		return err
-	} else {
-		logger := slog.New(logging.NewHookHandler(nil, &logging.HookHandlerOptions{
-			Level: slog.LevelDebug,
-			WriteFunc: func(ctx context.Context, record logging.Record) error {
-				we.fireOnNodeLoggingHooks(ctx, node, record)
-				return nil
-			},
-		}))
-		executor.SetLogger(logger)
 	}
 
+	// 恢复运行时，失败节点之前的节点无需重新执行，直接还原其执行结果；
+	// 在被恢复的运行中未曾执行的节点（如未进入的分支）则保持未执行
+	if wfCtx.resuming.ShouldSkip(node.Id) {
+		return nil
+	} else if wfCtx.resuming.ShouldRestore(node.Id) {
+		return we.restoreNode(wfCtx, node)
+	}
+
+	logger := slog.New(logging.NewHookHandler(nil, &logging.HookHandlerOptions{
+		Level: slog.LevelDebug,
+		WriteFunc: func(ctx context.Context, record logging.Record) error {
+			we.fireOnNodeLoggingHooks(ctx, node, record)
+			return nil
+		},
+	}))
+
 	wfCtx.variables.SetScoped(node.Id, stateVarKeyNodeId, node.Id, stateValTypeString)
 	wfCtx.variables.SetScoped(node.Id, stateVarKeyNodeName, node.Data.Name, stateValTypeString)
 
@@ -168,12 +210,22 @@ func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error
 	we.fireOnNodeStartHooks(wfCtx.ctx, node)
 
 	execCtx := newNodeExecutionContext(wfCtx, node)
-	execRes, err := executor.Execute(execCtx)
+	execRes, err := we.executeNodeWithRetry(execCtx, newExecutor, logger)
 	if err != nil && !errors.Is(err, ErrTerminated) {
-		if !errors.Is(err, ErrBlocksException) {
+		// 子工作流节点对于当前工作流而言是一个整体，其内部的异常视为该节点自身的异常
+		if !errors.Is(err, ErrBlocksException) || node.Type == NodeTypeSubWorkflow {
 			wfCtx.variables.Set(stateVarKeyErrorNodeId, node.Id, stateValTypeString)
 			wfCtx.variables.Set(stateVarKeyErrorNodeName, node.Data.Name, stateValTypeString)
 			wfCtx.variables.Set(stateVarKeyErrorMessage, err.Error(), stateValTypeString)
+
+			err = &NodeError{NodeId: node.Id, NodeName: node.Data.Name, Err: err}
+		}
+
+		// 失败节点的变量同样保留，以便后续的 Catch 分支引用
+		if execRes != nil {
+			for _, variable := range execRes.Variables {
+				wfCtx.variables.Add(variable)
+			}
 		}
 
 		we.fireOnNodeErrorHooks(wfCtx.ctx, node, err)
@@ -195,8 +247,9 @@ func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error
 			}
 		}
 
+		// 试运行模式下不持久化节点输出，以免影响后续正式运行时的跳过判断
 		execOutputs := lo.Filter(execRes.Outputs, func(state InOutState, _ int) bool { return state.Persistent })
-		if execRes.outputForced || len(execOutputs) > 0 {
+		if !wfCtx.dryRun && (execRes.outputForced || len(execOutputs) > 0) {
 			output := &domain.WorkflowOutput{
 				WorkflowId: execCtx.WorkflowId,
 				RunId:      execCtx.RunId,
@@ -231,6 +284,93 @@ func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error
 	return nil
 }
 
+// 执行节点，并在发生可重试的错误时按照节点的重试策略重新执行。
+// 每次尝试都会创建新的执行器实例，以避免并行执行时共享状态。
+func (we *workflowEngine) executeNodeWithRetry(execCtx *NodeExecutionContext, newExecutor func() NodeExecutor, logger *slog.Logger) (*NodeExecutionResult, error) {
+	policy := newRetryPolicy(execCtx.Node)
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			logger.Info(fmt.Sprintf("retry %d time(s) ...", attempt-1))
+		}
+
+		executor := newExecutor()
+		executor.SetLogger(logger)
+
+		execRes, err := we.executeNodeWithTimeout(execCtx, executor, time.Duration(execCtx.Node.Data.Timeout)*time.Second)
+		if err == nil {
+			return execRes, nil
+		} else if attempt >= policy.MaxAttempts || !policy.IsRetryable(execCtx.Context(), err) {
+			if attempt > 1 {
+				logger.Warn(fmt.Sprintf("attempt %d/%d failed, no more retries", attempt, policy.MaxAttempts), slog.String("error", err.Error()))
+			}
+			return execRes, err
+		}
+
+		delay := policy.Delay(attempt)
+		logger.Warn(fmt.Sprintf("attempt %d/%d failed, will retry in %s", attempt, policy.MaxAttempts, delay), slog.String("error", err.Error()))
+		if err := xwait.DelayWithContext(execCtx.Context(), delay); err != nil {
+			return execRes, err
+		}
+	}
+}
+
+// 节点超时后，等待执行器响应取消信号并返回的宽限期。
+var nodeTimeoutGracePeriod = 30 * time.Second
+
+// 执行节点，如果设置了超时时间，则在超时后取消执行器的上下文。
+// 执行器运行在分支上下文中，仅在按时返回时才将其变量和输入输出合并回原上下文；
+// 超时后将在宽限期内等待执行器返回，其执行结果及分支上下文均被丢弃，以免与后续节点并发读写。
+func (we *workflowEngine) executeNodeWithTimeout(execCtx *NodeExecutionContext, executor NodeExecutor, timeout time.Duration) (*NodeExecutionResult, error) {
+	if timeout <= 0 {
+		return executor.Execute(execCtx)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(execCtx.Context(), timeout, ErrNodeTimedOut)
+	defer cancel()
+
+	type result struct {
+		res *NodeExecutionResult
+		err error
+	}
+
+	forked := execCtx.WorkflowContext.Fork()
+	resultChan := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				resultChan <- result{err: fmt.Errorf("workflow engine: node executor panic: %v", r)}
+				slog.Error(fmt.Sprintf("workflow engine: node executor panic: %v, stack trace: %s", r, string(debug.Stack())), slog.String("workflowId", execCtx.WorkflowId), slog.String("runId", execCtx.RunId), slog.String("nodeId", execCtx.Node.Id))
+			}
+		}()
+
+		res, err := executor.Execute(newNodeExecutionContext(forked, execCtx.Node).SetContext(ctx))
+		resultChan <- result{res: res, err: err}
+	}()
+
+	select {
+	case r := <-resultChan:
+		if r.err != nil && errors.Is(context.Cause(ctx), ErrNodeTimedOut) {
+			return r.res, fmt.Errorf("%w after %s: %w", ErrNodeTimedOut, timeout, r.err)
+		}
+
+		execCtx.WorkflowContext.Merge(forked)
+		return r.res, r.err
+
+	case <-ctx.Done():
+		select {
+		case <-resultChan:
+		case <-time.After(nodeTimeoutGracePeriod):
+			slog.Warn(fmt.Sprintf("workflow engine: node executor did not return within %s after cancelled", nodeTimeoutGracePeriod), slog.String("workflowId", execCtx.WorkflowId), slog.String("runId", execCtx.RunId), slog.String("nodeId", execCtx.Node.Id))
+		}
+
+		if errors.Is(context.Cause(ctx), ErrNodeTimedOut) {
+			return nil, fmt.Errorf("%w after %s", ErrNodeTimedOut, timeout)
+		}
+		return nil, ctx.Err()
+	}
+}
+
 func (we *workflowEngine) executeBlocks(wfCtx *WorkflowContext, blocks []*Node) error {
 	errs := make([]error, 0)
 
@@ -265,6 +405,65 @@ func (we *workflowEngine) executeBlocks(wfCtx *WorkflowContext, blocks []*Node)
 	return nil
 }
 
+// 并行执行多个节点。
+// 每个节点都在独立的上下文分支中运行，全部执行完毕后再按节点顺序将变量和输出合并回原上下文，
+// 以保证合并结果与执行完成的先后顺序无关。
+// 返回值中的错误列表按节点顺序排列；如有节点触发终止，则不再调度后续节点，但会等待已开始的节点执行完毕。
+func (we *workflowEngine) executeNodesInParallel(wfCtx *WorkflowContext, nodes []*Node, maxConcurrency int) (_errs []error, _terminated bool) {
+	forks := make([]*WorkflowContext, len(nodes))
+	errs := make([]error, len(nodes))
+	terminated := atomic.Bool{}
+
+	eg := errgroup.Group{}
+	if maxConcurrency > 0 {
+		eg.SetLimit(maxConcurrency)
+	}
+
+	for i, node := range nodes {
+		forks[i] = wfCtx.Fork()
+
+		eg.Go(func() error {
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = fmt.Errorf("workflow engine: node executor panic: %v", r)
+					slog.Error(fmt.Sprintf("workflow engine: node executor panic: %v, stack trace: %s", r, string(debug.Stack())), slog.String("workflowId", wfCtx.WorkflowId), slog.String("runId", wfCtx.RunId), slog.String("nodeId", node.Id))
+				}
+			}()
+
+			if terminated.Load() {
+				return nil
+			}
+
+			ctx := forks[i].Context()
+			select {
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return nil
+			default:
+			}
+
+			err := we.executeNode(forks[i], node)
+			if err != nil {
+				if errors.Is(err, ErrTerminated) {
+					terminated.Store(true)
+				} else {
+					errs[i] = err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	eg.Wait()
+
+	for _, fork := range forks {
+		wfCtx.Merge(fork)
+	}
+
+	return lo.Filter(errs, func(err error, _ int) bool { return err != nil }), terminated.Load()
+}
+
 func (we *workflowEngine) fireOnStartHooks(ctx context.Context) {
 	we.hooksMtx.RLock()
 	defer we.hooksMtx.RUnlock()
@@ -337,22 +536,25 @@ func (we *workflowEngine) fireOnNodeLoggingHooks(ctx context.Context, node *Node
 
 func NewWorkflowEngine() WorkflowEngine {
 	engine := &workflowEngine{
-		executors:    make(map[NodeType]NodeExecutor),
+		executors:    make(map[NodeType]func() NodeExecutor),
 		wfoutputRepo: repository.NewWorkflowOutputRepository(),
 		syslog:       app.GetLogger(),
 	}
-	engine.executors[NodeTypeStart] = newStartNodeExecutor()
-	engine.executors[NodeTypeEnd] = newEndNodeExecutor()
-	engine.executors[NodeTypeDelay] = newDelayNodeExecutor()
-	engine.executors[NodeTypeCondition] = newConditionNodeExecutor()
-	engine.executors[NodeTypeBranchBlock] = newBranchBlockNodeExecutor()
-	engine.executors[NodeTypeTryCatch] = newTryCatchNodeExecutor()
-	engine.executors[NodeTypeTryBlock] = newTryBlockNodeExecutor()
-	engine.executors[NodeTypeCatchBlock] = newCatchBlockNodeExecutor()
-	engine.executors[NodeTypeBizApply] = newBizApplyNodeExecutor()
-	engine.executors[NodeTypeBizUpload] = newBizUploadNodeExecutor()
-	engine.executors[NodeTypeBizMonitor] = newBizMonitorNodeExecutor()
-	engine.executors[NodeTypeBizDeploy] = newBizDeployNodeExecutor()
-	engine.executors[NodeTypeBizNotify] = newBizNotifyNodeExecutor()
+	engine.executors[NodeTypeStart] = newStartNodeExecutor
+	engine.executors[NodeTypeEnd] = newEndNodeExecutor
+	engine.executors[NodeTypeDelay] = newDelayNodeExecutor
+	engine.executors[NodeTypeCondition] = newConditionNodeExecutor
+	engine.executors[NodeTypeBranchBlock] = newBranchBlockNodeExecutor
+	engine.executors[NodeTypeTryCatch] = newTryCatchNodeExecutor
+	engine.executors[NodeTypeTryBlock] = newTryBlockNodeExecutor
+	engine.executors[NodeTypeCatchBlock] = newCatchBlockNodeExecutor
+	engine.executors[NodeTypeParallel] = newParallelNodeExecutor
+	engine.executors[NodeTypeParallelBlock] = newParallelBlockNodeExecutor
+	engine.executors[NodeTypeBizApply] = newBizApplyNodeExecutor
+	engine.executors[NodeTypeBizUpload] = newBizUploadNodeExecutor
+	engine.executors[NodeTypeBizMonitor] = newBizMonitorNodeExecutor
+	engine.executors[NodeTypeBizDeploy] = newBizDeployNodeExecutor
+	engine.executors[NodeTypeBizNotify] = newBizNotifyNodeExecutor
+	engine.executors[NodeTypeSubWorkflow] = newSubWorkflowNodeExecutor
 	return engine
 }
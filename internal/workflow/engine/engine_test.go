@@ -0,0 +1,434 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+const nodeTypeTest NodeType = "test"
+
+type testNodeExecutor struct {
+	nodeExecutor
+
+	execute func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error)
+}
+
+func (ne *testNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	return ne.execute(execCtx)
+}
+
+func newTestWorkflowEngine(execute func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error)) *workflowEngine {
+	return &workflowEngine{
+		executors: map[NodeType]func() NodeExecutor{
+			nodeTypeTest: func() NodeExecutor {
+				return &testNodeExecutor{nodeExecutor: nodeExecutor{logger: slog.Default()}, execute: execute}
+			},
+		},
+		syslog: slog.Default(),
+	}
+}
+
+func newTestWorkflowContext(variables map[string]string) *WorkflowContext {
+	wfCtx := (&WorkflowContext{}).
+		SetVariablesManager(newVariableManager()).
+		SetInputsManager(newInOutManager()).
+		SetContext(context.Background())
+	for key, value := range variables {
+		wfCtx.variables.Set(key, value, stateValTypeString)
+	}
+	return wfCtx
+}
+
+func TestExecuteNodesInParallel(t *testing.T) {
+	type testBranch struct {
+		delay     time.Duration
+		variables map[string]string
+		err       error
+		terminate bool
+	}
+	type testInput struct {
+		variables      map[string]string
+		branches       []testBranch
+		maxConcurrency int
+	}
+	type testExpected struct {
+		variables  map[string]string
+		errNodeIds []string
+		executed   []string
+		terminated bool
+	}
+	testCases := []struct {
+		name     string
+		input    testInput
+		expected testExpected
+	}{
+		{
+			name: "distinct variables merged",
+			input: testInput{
+				branches: []testBranch{
+					{variables: map[string]string{"a": "1"}},
+					{variables: map[string]string{"b": "2"}},
+				},
+			},
+			expected: testExpected{
+				variables: map[string]string{"a": "1", "b": "2"},
+				executed:  []string{"n0", "n1"},
+			},
+		},
+		{
+			name: "conflict resolved by node order when first finishes last",
+			input: testInput{
+				branches: []testBranch{
+					{delay: 50 * time.Millisecond, variables: map[string]string{"x": "first"}},
+					{variables: map[string]string{"x": "second"}},
+				},
+			},
+			expected: testExpected{
+				variables: map[string]string{"x": "second"},
+				executed:  []string{"n0", "n1"},
+			},
+		},
+		{
+			name: "conflict resolved by node order when last finishes last",
+			input: testInput{
+				branches: []testBranch{
+					{variables: map[string]string{"x": "first"}},
+					{delay: 50 * time.Millisecond, variables: map[string]string{"x": "second"}},
+				},
+			},
+			expected: testExpected{
+				variables: map[string]string{"x": "second"},
+				executed:  []string{"n0", "n1"},
+			},
+		},
+		{
+			name: "unchanged variables do not overwrite changes of other branches",
+			input: testInput{
+				variables: map[string]string{"x": "0"},
+				branches: []testBranch{
+					{variables: map[string]string{"x": "1"}},
+					{variables: map[string]string{"y": "2"}},
+				},
+			},
+			expected: testExpected{
+				variables: map[string]string{"x": "1", "y": "2"},
+				executed:  []string{"n0", "n1"},
+			},
+		},
+		{
+			name: "errors collected in node order",
+			input: testInput{
+				branches: []testBranch{
+					{delay: 50 * time.Millisecond, err: errors.New("error 0")},
+					{variables: map[string]string{"a": "1"}},
+					{err: errors.New("error 2")},
+				},
+			},
+			expected: testExpected{
+				variables:  map[string]string{"a": "1"},
+				errNodeIds: []string{"n0", "n2"},
+				executed:   []string{"n0", "n1", "n2"},
+			},
+		},
+		{
+			name: "terminated stops scheduling",
+			input: testInput{
+				branches: []testBranch{
+					{variables: map[string]string{"a": "1"}, terminate: true},
+					{variables: map[string]string{"b": "2"}},
+				},
+				maxConcurrency: 1,
+			},
+			expected: testExpected{
+				variables:  map[string]string{"a": "1"},
+				executed:   []string{"n0"},
+				terminated: true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			executedMtx := sync.Mutex{}
+			executed := make([]string, 0)
+
+			nodes := make([]*Node, len(tc.input.branches))
+			branches := make(map[string]testBranch)
+			for i, branch := range tc.input.branches {
+				nodes[i] = &Node{Id: fmt.Sprintf("n%d", i), Type: nodeTypeTest}
+				branches[nodes[i].Id] = branch
+			}
+
+			engine := newTestWorkflowEngine(func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+				branch := branches[execCtx.Node.Id]
+				time.Sleep(branch.delay)
+
+				executedMtx.Lock()
+				executed = append(executed, execCtx.Node.Id)
+				executedMtx.Unlock()
+
+				execRes := newNodeExecutionResult(execCtx.Node)
+				execRes.Terminated = branch.terminate
+				for key, value := range branch.variables {
+					execRes.AddVariable(key, value, stateValTypeString)
+				}
+				return execRes, branch.err
+			})
+
+			wfCtx := newTestWorkflowContext(tc.input.variables)
+			errs, terminated := engine.executeNodesInParallel(wfCtx, nodes, tc.input.maxConcurrency)
+
+			var errNodeIds []string
+			for _, err := range errs {
+				var nodeErr *NodeError
+				if assert.ErrorAs(t, err, &nodeErr, "Case: %-20s", tc.name) {
+					errNodeIds = append(errNodeIds, nodeErr.NodeId)
+				}
+			}
+			assert.Equal(t, tc.expected.errNodeIds, errNodeIds, "Case: %-20s", tc.name)
+			assert.Equal(t, tc.expected.terminated, terminated, "Case: %-20s", tc.name)
+
+			slices.Sort(executed)
+			assert.Equal(t, tc.expected.executed, executed, "Case: %-20s", tc.name)
+
+			for key, value := range tc.expected.variables {
+				state, ok := wfCtx.variables.Get(key)
+				if assert.True(t, ok, "Case: %-20s, Key: %s", tc.name, key) {
+					assert.Equal(t, value, state.Value, "Case: %-20s, Key: %s", tc.name, key)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteNodeWithTimeout(t *testing.T) {
+	gracePeriod := nodeTimeoutGracePeriod
+	nodeTimeoutGracePeriod = 100 * time.Millisecond
+	defer func() { nodeTimeoutGracePeriod = gracePeriod }()
+
+	errFailed := errors.New("failed")
+
+	type testInput struct {
+		timeout time.Duration
+		cancel  bool // 是否在执行期间取消整个运行
+		execute func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error)
+	}
+	type testExpected struct {
+		err        error
+		merged     bool // 执行器设置的变量是否合并回原上下文
+		maxElapsed time.Duration
+	}
+	testCases := []struct {
+		name     string
+		input    testInput
+		expected testExpected
+	}{
+		{
+			name: "no timeout",
+			input: testInput{
+				execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+					execCtx.variables.Set("x", "1", stateValTypeString)
+					return newNodeExecutionResult(execCtx.Node), nil
+				},
+			},
+			expected: testExpected{merged: true, maxElapsed: time.Second},
+		},
+		{
+			name: "completed in time",
+			input: testInput{
+				timeout: time.Second,
+				execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+					execCtx.variables.Set("x", "1", stateValTypeString)
+					return newNodeExecutionResult(execCtx.Node), nil
+				},
+			},
+			expected: testExpected{merged: true, maxElapsed: time.Second},
+		},
+		{
+			name: "failed in time",
+			input: testInput{
+				timeout: time.Second,
+				execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+					return nil, errFailed
+				},
+			},
+			expected: testExpected{err: errFailed, maxElapsed: time.Second},
+		},
+		{
+			name: "timed out and executor returns after cancelled",
+			input: testInput{
+				timeout: 50 * time.Millisecond,
+				execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+					execCtx.variables.Set("x", "1", stateValTypeString)
+					<-execCtx.Context().Done()
+					return nil, execCtx.Context().Err()
+				},
+			},
+			expected: testExpected{err: ErrNodeTimedOut, maxElapsed: time.Second},
+		},
+		{
+			name: "timed out and executor ignores cancellation",
+			input: testInput{
+				timeout: 50 * time.Millisecond,
+				execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+					time.Sleep(time.Second)
+					execCtx.variables.Set("x", "1", stateValTypeString)
+					return newNodeExecutionResult(execCtx.Node), nil
+				},
+			},
+			expected: testExpected{err: ErrNodeTimedOut, maxElapsed: 500 * time.Millisecond},
+		},
+		{
+			name: "run cancelled",
+			input: testInput{
+				timeout: time.Second,
+				cancel:  true,
+				execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+					<-execCtx.Context().Done()
+					return nil, execCtx.Context().Err()
+				},
+			},
+			expected: testExpected{err: context.Canceled, maxElapsed: time.Second},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.input.cancel {
+				time.AfterFunc(50*time.Millisecond, cancel)
+			}
+
+			engine := newTestWorkflowEngine(tc.input.execute)
+			wfCtx := newTestWorkflowContext(nil).SetContext(ctx)
+			execCtx := newNodeExecutionContext(wfCtx, &Node{Id: "n0", Type: nodeTypeTest})
+			executor := engine.executors[nodeTypeTest]()
+
+			startedAt := time.Now()
+			_, err := engine.executeNodeWithTimeout(execCtx, executor, tc.input.timeout)
+			elapsed := time.Since(startedAt)
+
+			if tc.expected.err == nil {
+				assert.NoError(t, err, "Case: %-20s", tc.name)
+			} else {
+				assert.ErrorIs(t, err, tc.expected.err, "Case: %-20s", tc.name)
+			}
+			assert.Less(t, elapsed, tc.expected.maxElapsed, "Case: %-20s", tc.name)
+
+			_, merged := wfCtx.variables.Get("x")
+			assert.Equal(t, tc.expected.merged, merged, "Case: %-20s", tc.name)
+		})
+	}
+}
+
+type testWorkflowOutputRepository struct {
+	savedMtx sync.Mutex
+	saved    []*domain.WorkflowOutput
+}
+
+func (r *testWorkflowOutputRepository) GetByWorkflowIdAndNodeId(ctx context.Context, workflowId string, workflowNodeId string) (*domain.WorkflowOutput, error) {
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *testWorkflowOutputRepository) Save(ctx context.Context, workflowOutput *domain.WorkflowOutput) (*domain.WorkflowOutput, error) {
+	r.savedMtx.Lock()
+	defer r.savedMtx.Unlock()
+
+	r.saved = append(r.saved, workflowOutput)
+	return workflowOutput, nil
+}
+
+func TestExecuteNodesInDryRun(t *testing.T) {
+	type testExpected struct {
+		plans        map[string]domain.WorkflowRunPlanActionType
+		savedOutputs int
+	}
+	testCases := []struct {
+		name     string
+		input    bool
+		expected testExpected
+	}{
+		{
+			name:  "dry run",
+			input: true,
+			expected: testExpected{
+				plans: map[string]domain.WorkflowRunPlanActionType{
+					"delay": domain.WorkflowRunPlanActionTypeWait,
+					"n0":    domain.WorkflowRunPlanActionTypeSkip,
+				},
+				savedOutputs: 0,
+			},
+		},
+		{
+			name:  "normal run",
+			input: false,
+			expected: testExpected{
+				plans:        map[string]domain.WorkflowRunPlanActionType{},
+				savedOutputs: 1,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			engine := newTestWorkflowEngine(func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+				execRes := newNodeExecutionResult(execCtx.Node)
+				if execCtx.IsDryRun() {
+					execRes.SetPlan(domain.WorkflowRunPlanActionTypeSkip, "dry run")
+				}
+				execRes.AddOutputWithPersistent("certificate", "certificate", "cert1", "string")
+				return execRes, nil
+			})
+			engine.executors[NodeTypeDelay] = newDelayNodeExecutor
+			engine.executors[NodeTypeParallel] = newParallelNodeExecutor
+			engine.executors[NodeTypeParallelBlock] = newParallelBlockNodeExecutor
+
+			wfoutputRepo := &testWorkflowOutputRepository{}
+			engine.wfoutputRepo = wfoutputRepo
+
+			plansMtx := sync.Mutex{}
+			plans := make(map[string]domain.WorkflowRunPlanActionType)
+			engine.OnNodeEnd(func(ctx context.Context, node *Node, res *NodeExecutionResult) error {
+				if res != nil && res.Plan != nil {
+					plansMtx.Lock()
+					plans[node.Id] = res.Plan.Action
+					plansMtx.Unlock()
+				}
+				return nil
+			})
+
+			nodes := make([]*Node, 0)
+			if tc.input {
+				nodes = append(nodes, &Node{Id: "delay", Type: NodeTypeDelay, Data: domain.WorkflowNodeData{Config: map[string]any{"wait": 60}}})
+			}
+			nodes = append(nodes, &Node{Id: "parallel", Type: NodeTypeParallel, Blocks: []*Node{
+				{Id: "block", Type: NodeTypeParallelBlock, Blocks: []*Node{
+					{Id: "n0", Type: nodeTypeTest, Data: domain.WorkflowNodeData{Timeout: 60}},
+				}},
+			}})
+
+			wfCtx := newTestWorkflowContext(nil).SetEngine(engine).SetDryRun(tc.input)
+			startedAt := time.Now()
+			err := engine.executeBlocks(wfCtx, nodes)
+			assert.NoError(t, err, "Case: %-20s", tc.name)
+			assert.Less(t, time.Since(startedAt), time.Second, "Case: %-20s", tc.name)
+
+			assert.Equal(t, tc.expected.plans, plans, "Case: %-20s", tc.name)
+			assert.Len(t, wfoutputRepo.saved, tc.expected.savedOutputs, "Case: %-20s", tc.name)
+
+			_, ok := wfCtx.inputs.Get("n0", "certificate")
+			assert.True(t, ok, "Case: %-20s", tc.name)
+		})
+	}
+}
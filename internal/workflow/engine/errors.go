@@ -9,4 +9,37 @@ var (
 	ErrTerminated = fmt.Errorf("workflow engine: execution was terminated")
 	// 表示工作流引擎在执行子节点时发生异常
 	ErrBlocksException = fmt.Errorf("workflow engine: error occurred when executing blocks")
+	// 表示节点执行超时
+	ErrNodeTimedOut = fmt.Errorf("workflow engine: node execution timed out")
+	// 表示工作流运行超时
+	ErrRunTimedOut = fmt.Errorf("workflow engine: workflow run timed out")
 )
+
+// 表示某个节点自身执行失败（而非其子节点），携带失败节点的信息。
+// 该错误会随着异常向上传递；若被 TryCatch 等节点处理，则不会出现在工作流的最终错误中。
+type NodeError struct {
+	NodeId   string
+	NodeName string
+	Err      error
+}
+
+func (e *NodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NodeError) Unwrap() error {
+	return e.Err
+}
+
+// 表示不应按照节点的重试策略重试的错误，除非其匹配了重试策略中显式指定的错误模式。
+type NonRetryableError struct {
+	Err error
+}
+
+func (e *NonRetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NonRetryableError) Unwrap() error {
+	return e.Err
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"log/slog"
 	"sync"
+
+	"github.com/certimate-go/certimate/internal/domain"
 )
 
 type NodeExecutor interface {
@@ -51,19 +53,28 @@ func (c *NodeExecutionContext) SetInputsManager(inputs InOutManager) *NodeExecut
 	return c
 }
 
+func (c *NodeExecutionContext) SetDryRun(dryRun bool) *NodeExecutionContext {
+	c.WorkflowContext.SetDryRun(dryRun)
+	return c
+}
+
 func (c *NodeExecutionContext) SetContext(ctx context.Context) *NodeExecutionContext {
 	c.WorkflowContext.SetContext(ctx)
 	return c
 }
 
 func newNodeExecutionContext(wfCtx *WorkflowContext, node *Node) *NodeExecutionContext {
-	return (&NodeExecutionContext{}).
+	execCtx := (&NodeExecutionContext{}).
 		SetExecutingWorkflow(wfCtx.WorkflowId, wfCtx.RunId, wfCtx.RunGraph).
 		SetExecutingNode(node).
 		SetEngine(wfCtx.engine).
 		SetVariablesManager(wfCtx.variables).
 		SetInputsManager(wfCtx.inputs).
+		SetDryRun(wfCtx.dryRun).
 		SetContext(wfCtx.ctx)
+	execCtx.resuming = wfCtx.resuming
+	execCtx.callStack = wfCtx.callStack
+	return execCtx
 }
 
 type NodeExecutionResult struct {
@@ -77,6 +88,20 @@ type NodeExecutionResult struct {
 	outputForced bool // 即使 Outputs 为空，也强制持久化输出
 	outputsMtx   sync.Mutex
 	Outputs      []InOutState
+
+	Plan *NodeExecutionPlan // 节点的执行计划（通常在试运行模式下产生）
+}
+
+type NodeExecutionPlan struct {
+	Action  domain.WorkflowRunPlanActionType
+	Message string
+}
+
+func (r *NodeExecutionResult) SetPlan(action domain.WorkflowRunPlanActionType, message string) {
+	r.Plan = &NodeExecutionPlan{
+		Action:  action,
+		Message: message,
+	}
 }
 
 func (r *NodeExecutionResult) AddVariable(key string, value any, valueType string) {
@@ -1,21 +1,29 @@
 package engine
 
 import (
+	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"log/slog"
 	"maps"
 	"math"
+	"net"
+	"os"
 	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-acme/lego/v5/acme/api"
+	"github.com/go-acme/lego/v5/certcrypto"
 	"github.com/go-acme/lego/v5/lego"
+	"github.com/go-resty/resty/v2"
 	"github.com/samber/lo"
 	"github.com/xhit/go-str2duration/v2"
 
 	"github.com/certimate-go/certimate/internal/certacme"
+	"github.com/certimate-go/certimate/internal/certlocalca"
+	"github.com/certimate-go/certimate/internal/dnsresponder"
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/internal/settings"
@@ -23,6 +31,7 @@ import (
 	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
 	xcertkey "github.com/certimate-go/certimate/pkg/utils/cert/key"
 	xenv "github.com/certimate-go/certimate/pkg/utils/env"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
 )
 
 var envMultiProc = true
@@ -35,6 +44,21 @@ const (
 	BizApplyKeySourceAuto   = "auto"
 	BizApplyKeySourceReuse  = "reuse"
 	BizApplyKeySourceCustom = "custom"
+	BizApplyKeySourceCSR    = "csr"
+)
+
+const (
+	BizApplyCSRSourceForm  = "form"
+	BizApplyCSRSourceLocal = "local"
+	BizApplyCSRSourceURL   = "url"
+)
+
+// ARI 续期信息的查询间隔。
+const (
+	ariRetryAfterDefault = 6 * time.Hour
+	ariRetryAfterMin     = time.Minute
+	ariRetryAfterMax     = 24 * time.Hour
+	ariRetryAfterOnError = time.Hour
 )
 
 /**
@@ -86,6 +110,7 @@ func (ne *bizApplyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 		ne.logger.Info(fmt.Sprintf("skip this application, because %s", reason))
 
 		execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, true, stateValTypeBoolean)
+		execRes.SetPlan(domain.WorkflowRunPlanActionTypeSkip, reason)
 		return execRes, nil
 	} else {
 		if reason != "" {
@@ -97,6 +122,43 @@ func (ne *bizApplyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 		execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, false, stateValTypeBoolean)
 	}
 
+	// 试运行模式下仅校验配置，不实际向 CA 申请证书
+	if execCtx.IsDryRun() {
+		acmeCfg, obtainReq, err := ne.prepareObtainCertificate(execCtx, &nodeCfg, lastCertificate)
+		if err != nil {
+			return execRes, err
+		}
+
+		if acmeCfg.CAProvider == domain.CAProviderTypeLocalCA {
+			if err := certlocalca.ValidateIssueCertificateRequest(ne.buildLocalCAIssueRequest(&nodeCfg, acmeCfg, obtainReq)); err != nil {
+				ne.logger.Warn("could not validate certificate request")
+				return execRes, err
+			}
+
+			ne.logger.Info("dry run: skip issuing certificate")
+
+			execRes.SetPlan(domain.WorkflowRunPlanActionTypeApply, fmt.Sprintf("issue certificate for %s from local CA", strings.Join(obtainReq.DomainOrIPs, ", ")))
+			return execRes, nil
+		}
+
+		if err := certacme.ValidateObtainCertificateRequest(obtainReq); err != nil {
+			ne.logger.Warn("could not validate certificate request")
+			return execRes, err
+		}
+
+		if nodeCfg.EnablePreflight {
+			if err := ne.execPreflight(execCtx, acmeCfg, obtainReq, ""); err != nil {
+				return execRes, err
+			}
+		}
+
+		ne.logDnsResponderRecords(obtainReq)
+		ne.logger.Info("dry run: skip requesting certificate")
+
+		execRes.SetPlan(domain.WorkflowRunPlanActionTypeApply, fmt.Sprintf("request certificate for %s from '%s' via %s '%s'", strings.Join(obtainReq.DomainOrIPs, ", "), acmeCfg.CAProvider, obtainReq.ChallengeType, obtainReq.Provider))
+		return execRes, nil
+	}
+
 	// 申请证书
 	obtainResp, err := ne.execObtainCertificate(execCtx, &nodeCfg, lastCertificate)
 	if err != nil {
@@ -117,6 +179,9 @@ func (ne *bizApplyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 		WorkflowNodeId:     execCtx.Node.Id,
 	}
 	certificate.PopulateFromPEM(obtainResp.FullChainCertificate, obtainResp.PrivateKey)
+	if certificate.PrivateKey == "" {
+		ne.logger.Info("the certificate is issued from an external csr, its private key will not be stored")
+	}
 	if certificate, err := ne.certificateRepo.Save(execCtx.Context(), certificate); err != nil {
 		ne.logger.Warn("could not save certificate")
 		return execRes, err
@@ -138,6 +203,23 @@ func (ne *bizApplyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 	return execRes, nil
 }
 
+func (ne *bizApplyNodeExecutor) Restore(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	// 节点在被恢复的运行中可能因跳过等原因未产生证书，此时没有可还原的证书
+	lastCertificate, err := getCertificateOfSourceRuns(execCtx, ne.certificateRepo)
+	if err != nil {
+		return execRes, err
+	} else if lastCertificate == nil {
+		return execRes, nil
+	}
+
+	ne.setOuputsOfResult(execCtx, execRes, lastCertificate, false)
+	ne.setVariablesOfResult(execCtx, execRes, lastCertificate)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, true, stateValTypeBoolean)
+	return execRes, nil
+}
+
 func (ne *bizApplyNodeExecutor) getLastOutputArtifacts(execCtx *NodeExecutionContext) (*domain.WorkflowOutput, *domain.Certificate, error) {
 	lastOutput, err := ne.wfoutputRepo.GetByWorkflowIdAndNodeId(execCtx.Context(), execCtx.WorkflowId, execCtx.Node.Id)
 	if err != nil && !domain.IsRecordNotFoundError(err) {
@@ -181,6 +263,15 @@ func (ne *bizApplyNodeExecutor) checkCanSkip(execCtx *NodeExecutionContext, last
 		if !maps.Equal(thisNodeCfg.ProviderConfig, lastNodeCfg.ProviderConfig) {
 			return false, "the configuration item 'ProviderConfig' changed"
 		}
+		if !slices.EqualFunc(thisNodeCfg.DomainChallenges, lastNodeCfg.DomainChallenges, func(a, b domain.WorkflowNodeConfigForBizApplyDomainChallenge) bool {
+			return a.Domain == b.Domain &&
+				a.ChallengeType == b.ChallengeType &&
+				a.Provider == b.Provider &&
+				a.ProviderAccessId == b.ProviderAccessId &&
+				maps.Equal(a.ProviderConfig, b.ProviderConfig)
+		}) {
+			return false, "the configuration item 'DomainChallenges' changed"
+		}
 		if thisNodeCfg.CAProvider != lastNodeCfg.CAProvider {
 			return false, "the configuration item 'CAProvider' changed"
 		}
@@ -196,6 +287,17 @@ func (ne *bizApplyNodeExecutor) checkCanSkip(execCtx *NodeExecutionContext, last
 		if thisNodeCfg.KeySource == BizApplyKeySourceCustom && thisNodeCfg.KeyContent != lastNodeCfg.KeyContent {
 			return false, "the configuration item 'KeyContent' changed"
 		}
+		if (thisNodeCfg.KeySource == BizApplyKeySourceCSR) != (lastNodeCfg.KeySource == BizApplyKeySourceCSR) {
+			return false, "the configuration item 'KeySource' changed"
+		}
+		if thisNodeCfg.KeySource == BizApplyKeySourceCSR {
+			if thisNodeCfg.CSRSource != lastNodeCfg.CSRSource {
+				return false, "the configuration item 'CSRSource' changed"
+			}
+			if strings.TrimSpace(thisNodeCfg.CSRContent) != strings.TrimSpace(lastNodeCfg.CSRContent) {
+				return false, "the configuration item 'CSRContent' changed"
+			}
+		}
 		if thisNodeCfg.ValidityLifetime != lastNodeCfg.ValidityLifetime {
 			return false, "the configuration item 'ValidityLifetime' changed"
 		}
@@ -215,6 +317,18 @@ func (ne *bizApplyNodeExecutor) checkCanSkip(execCtx *NodeExecutionContext, last
 			return false, "the last requested certificate has been revoked"
 		}
 
+		// 优先依据 ARI 建议的续期窗口决定是否续期，不可用时再按剩余天数判断
+		if !thisNodeCfg.DisableARI {
+			if windowStart, windowEnd, ok := ne.getRenewalWindow(execCtx, &thisNodeCfg, lastCertificate); ok {
+				if time.Now().Before(windowStart) {
+					daysUntilRenewal := int(math.Ceil(time.Until(windowStart).Hours() / 24))
+					return true, fmt.Sprintf("the ARI suggested renewal window of the last requested certificate is from %s to %s, and the next renewal will be in %d day(s)", windowStart.UTC().Format(time.RFC3339), windowEnd.UTC().Format(time.RFC3339), daysUntilRenewal)
+				}
+
+				return false, fmt.Sprintf("the ARI suggested renewal window (from %s to %s) has been reached", windowStart.UTC().Format(time.RFC3339), windowEnd.UTC().Format(time.RFC3339))
+			}
+		}
+
 		renewalInterval := time.Duration(thisNodeCfg.SkipBeforeExpiryDays) * time.Hour * 24
 		expirationTime := time.Until(lastCertificate.ValidityNotAfter)
 		daysLeft := int(math.Floor(expirationTime.Hours() / 24))
@@ -229,10 +343,90 @@ func (ne *bizApplyNodeExecutor) checkCanSkip(execCtx *NodeExecutionContext, last
 	return false, ""
 }
 
-func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizApply, lastCertificate *domain.Certificate) (*certacme.ObtainCertificateResponse, error) {
+func (ne *bizApplyNodeExecutor) getRenewalWindow(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizApply, lastCertificate *domain.Certificate) (_start time.Time, _end time.Time, _ok bool) {
+	if lastCertificate.Source != domain.CertificateSourceTypeRequest || lastCertificate.CA == domain.CAProviderTypeLocalCA.String() {
+		return time.Time{}, time.Time{}, false
+	}
+
+	savedStart, savedEnd := lastCertificate.ARIWindowStart, lastCertificate.ARIWindowEnd
+	hasSavedWindow := !savedStart.IsZero() && !savedEnd.Before(savedStart)
+
+	// 优先使用已保存的续期窗口，直至到达 CA 指定的下次查询时间后才重新查询；
+	// 试运行模式下不向 CA 发起请求
+	if execCtx.IsDryRun() || time.Now().Before(lastCertificate.ARIRetryAfter) {
+		return savedStart, savedEnd, hasSavedWindow
+	}
+
+	renewalInfo, err := ne.fetchRenewalInfo(execCtx, nodeCfg, lastCertificate)
+	if err != nil {
+		ne.logger.Warn("could not get renewal info", slog.Any("error", err))
+		ne.saveRenewalWindow(execCtx, lastCertificate, savedStart, savedEnd, ariRetryAfterOnError)
+		if !hasSavedWindow {
+			ne.logger.Info("fallback to the days-based renewal")
+		}
+		return savedStart, savedEnd, hasSavedWindow
+	} else if renewalInfo.SuggestedWindowStart.IsZero() || renewalInfo.SuggestedWindowEnd.Before(renewalInfo.SuggestedWindowStart) {
+		ne.logger.Warn("the renewal info is invalid, fallback to the days-based renewal")
+		ne.saveRenewalWindow(execCtx, lastCertificate, time.Time{}, time.Time{}, ariRetryAfterOnError)
+		return time.Time{}, time.Time{}, false
+	} else if renewalInfo.ExplanationUrl != "" {
+		ne.logger.Info("the renewal info has an explanation", slog.String("explanationUrl", renewalInfo.ExplanationUrl))
+	}
+
+	// 未指定 Retry-After 时按照 RFC 9773 的建议默认 6 小时后再查询，并限制在合理范围内
+	retryAfter := renewalInfo.RetryAfter
+	if retryAfter <= 0 {
+		retryAfter = ariRetryAfterDefault
+	}
+	retryAfter = min(max(retryAfter, ariRetryAfterMin), ariRetryAfterMax)
+	ne.saveRenewalWindow(execCtx, lastCertificate, renewalInfo.SuggestedWindowStart, renewalInfo.SuggestedWindowEnd, retryAfter)
+
+	return renewalInfo.SuggestedWindowStart, renewalInfo.SuggestedWindowEnd, true
+}
+
+func (ne *bizApplyNodeExecutor) saveRenewalWindow(execCtx *NodeExecutionContext, lastCertificate *domain.Certificate, windowStart, windowEnd time.Time, retryAfter time.Duration) {
+	lastCertificate.ARIWindowStart = windowStart
+	lastCertificate.ARIWindowEnd = windowEnd
+	lastCertificate.ARIRetryAfter = time.Now().Add(retryAfter)
+	if _, err := ne.certificateRepo.Save(execCtx.Context(), lastCertificate); err != nil {
+		ne.logger.Warn("could not save renewal window", slog.Any("error", err))
+	}
+}
+
+func (ne *bizApplyNodeExecutor) fetchRenewalInfo(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizApply, lastCertificate *domain.Certificate) (*certacme.GetRenewalInfoResponse, error) {
+	// 读取证书颁发机构授权
+	caAccessConfig := make(map[string]any)
+	if nodeCfg.CAProviderAccessId != "" {
+		if access, err := ne.accessRepo.GetById(execCtx.Context(), nodeCfg.CAProviderAccessId); err != nil {
+			return nil, fmt.Errorf("failed to get access #%s record: %w", nodeCfg.CAProviderAccessId, err)
+		} else {
+			caAccessConfig = access.Config
+		}
+	}
+
+	acmeCfg, err := certacme.CreateACMEConfig(execCtx.Context(), &certacme.ACMEConfigOptions{
+		CAProvider:               domain.CAProviderType(nodeCfg.CAProvider),
+		CAProviderAccessConfig:   caAccessConfig,
+		CAProviderExtendedConfig: nodeCfg.CAProviderConfig,
+		CertifierKeyAlgorithm:    lastCertificate.KeyAlgorithm,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize acme config: %w", err)
+	} else if acmeCfg.CAProvider == domain.CAProviderTypeLocalCA {
+		return nil, fmt.Errorf("the local ca does not support ari")
+	}
+
+	// ARI 端点无需鉴权，不必为了判断是否续期而注册 ACME 账户
+	return certacme.GetRenewalInfo(execCtx.Context(), acmeCfg, &certacme.GetRenewalInfoRequest{
+		Certificate: lastCertificate.Certificate,
+	})
+}
+
+func (ne *bizApplyNodeExecutor) prepareObtainCertificate(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizApply, lastCertificate *domain.Certificate) (*certacme.ACMEConfig, *certacme.ObtainCertificateRequest, error) {
 	// 读取私钥算法
 	// 如果复用私钥，则保持算法一致
 	keyAlgorithm := domain.CertificateKeyAlgorithmType(nodeCfg.KeyAlgorithm)
+	csrPEM := ""
 	switch nodeCfg.KeySource {
 	case BizApplyKeySourceAuto:
 		break
@@ -243,39 +437,79 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 	case BizApplyKeySourceCustom:
 		privkey, err := xcert.ParsePrivateKeyFromPEM(nodeCfg.KeyContent)
 		if err != nil {
-			return nil, fmt.Errorf("could not parse custom private key: %w", err)
+			return nil, nil, fmt.Errorf("could not parse custom private key: %w", err)
 		} else {
 			privkeyAlg, privkeySize, _ := xcertkey.GetPrivateKeyAlgorithm(privkey)
 			switch privkeyAlg {
 			case x509.RSA:
 				if nodeCfg.KeyAlgorithm != fmt.Sprintf("RSA%d", privkeySize) {
-					return nil, fmt.Errorf("could not parse custom private key: unsupported algorithm or key size")
+					return nil, nil, fmt.Errorf("could not parse custom private key: unsupported algorithm or key size")
 				}
 			case x509.ECDSA:
 				if nodeCfg.KeyAlgorithm != fmt.Sprintf("EC%d", privkeySize) {
-					return nil, fmt.Errorf("could not parse custom private key: unsupported algorithm or key size")
+					return nil, nil, fmt.Errorf("could not parse custom private key: unsupported algorithm or key size")
 				}
 			default:
-				return nil, fmt.Errorf("could not parse custom private key: unsupported algorithm")
+				return nil, nil, fmt.Errorf("could not parse custom private key: unsupported algorithm")
 			}
 		}
+	case BizApplyKeySourceCSR:
+		// 私钥由外部（如 HSM、硬件设备等）持有，仅提供证书签名请求
+		csr, err := ne.loadCSR(nodeCfg)
+		if err != nil {
+			return nil, nil, err
+		} else if err := checkCSRIdentifiers(csr, nodeCfg.Domains, nodeCfg.IPAddrs); err != nil {
+			return nil, nil, err
+		} else {
+			csrPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr.Raw}))
+		}
+
+		// 保持算法与 CSR 中的公钥一致
+		pubkeyAlg, pubkeySize, _ := xcertkey.GetPublicKeyAlgorithm(csr.PublicKey)
+		switch pubkeyAlg {
+		case x509.RSA:
+			keyAlgorithm = domain.CertificateKeyAlgorithmType(fmt.Sprintf("RSA%d", pubkeySize))
+		case x509.ECDSA:
+			keyAlgorithm = domain.CertificateKeyAlgorithmType(fmt.Sprintf("EC%d", pubkeySize))
+		}
 	}
 
 	// 读取质询提供商授权
 	providerAccessConfig := make(map[string]any)
 	if nodeCfg.ProviderAccessId != "" {
 		if access, err := ne.accessRepo.GetById(execCtx.Context(), nodeCfg.ProviderAccessId); err != nil {
-			return nil, fmt.Errorf("failed to get access #%s record: %w", nodeCfg.ProviderAccessId, err)
+			return nil, nil, fmt.Errorf("failed to get access #%s record: %w", nodeCfg.ProviderAccessId, err)
 		} else {
 			providerAccessConfig = access.Config
 		}
 	}
 
+	// 读取按域名分派的质询提供商授权
+	domainChallenges := make([]certacme.ObtainCertificateDomainChallenge, 0, len(nodeCfg.DomainChallenges))
+	for _, dc := range nodeCfg.DomainChallenges {
+		dcAccessConfig := make(map[string]any)
+		if dc.ProviderAccessId != "" {
+			if access, err := ne.accessRepo.GetById(execCtx.Context(), dc.ProviderAccessId); err != nil {
+				return nil, nil, fmt.Errorf("failed to get access #%s record: %w", dc.ProviderAccessId, err)
+			} else {
+				dcAccessConfig = access.Config
+			}
+		}
+
+		domainChallenges = append(domainChallenges, certacme.ObtainCertificateDomainChallenge{
+			Domain:                 dc.Domain,
+			ChallengeType:          dc.ChallengeType,
+			Provider:               domain.ACMEChallengeProviderType(dc.Provider),
+			ProviderAccessConfig:   dcAccessConfig,
+			ProviderExtendedConfig: dc.ProviderConfig,
+		})
+	}
+
 	// 读取证书颁发机构授权
 	caAccessConfig := make(map[string]any)
 	if nodeCfg.CAProviderAccessId != "" {
 		if access, err := ne.accessRepo.GetById(execCtx.Context(), nodeCfg.CAProviderAccessId); err != nil {
-			return nil, fmt.Errorf("failed to get access #%s record: %w", nodeCfg.CAProviderAccessId, err)
+			return nil, nil, fmt.Errorf("failed to get access #%s record: %w", nodeCfg.CAProviderAccessId, err)
 		} else {
 			caAccessConfig = access.Config
 		}
@@ -291,21 +525,13 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 	acmeCfg, err := certacme.CreateACMEConfig(execCtx.Context(), acmeOpts)
 	if err != nil {
 		ne.logger.Warn("could not initialize acme config")
-		return nil, err
+		return nil, nil, err
+	} else if acmeCfg.CAProvider == domain.CAProviderTypeLocalCA {
+		ne.logger.Info("local ca is used, acme config is not required")
 	} else {
 		ne.logger.Info("acme config initialized", slog.String("acmeDirUrl", acmeCfg.CADirUrl))
 	}
 
-	// 初始化 ACME 账户
-	// 注意此步骤仍需在主进程中进行，以保证并发安全
-	acmeAcct, err := certacme.CreateACMEAccountWithSingleFlight(execCtx.Context(), acmeCfg, nodeCfg.ContactEmail)
-	if err != nil {
-		ne.logger.Warn("could not initialize acme account")
-		return nil, err
-	} else {
-		ne.logger.Info("acme account initialized", slog.String("acmeAcctUrl", acmeAcct.ACMEAccountUrl))
-	}
-
 	// 构造证书申请请求
 	obtainReq := &certacme.ObtainCertificateRequest{
 		DomainOrIPs:    lo.Concat(nodeCfg.Domains, nodeCfg.IPAddrs),
@@ -333,16 +559,19 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 				return time.Now().Add(duration)
 			}),
 		NoCommonName:           nodeCfg.DisableCommonName,
+		CSRPEM:                 csrPEM,
 		ChallengeType:          nodeCfg.ChallengeType,
 		Provider:               domain.ACMEChallengeProviderType(nodeCfg.Provider),
 		ProviderAccessConfig:   providerAccessConfig,
 		ProviderExtendedConfig: nodeCfg.ProviderConfig,
+		DomainChallenges:       domainChallenges,
 		DisableFollowCNAME:     nodeCfg.DisableFollowCNAME,
 		Nameservers:            nodeCfg.Nameservers,
 		DnsPropagationWait:     nodeCfg.DnsPropagationWait,
 		DnsPropagationTimeout:  nodeCfg.DnsPropagationTimeout,
 		DnsTTL:                 nodeCfg.DnsTTL,
 		HttpDelayWait:          nodeCfg.HttpDelayWait,
+		TlsAlpnDelayWait:       nodeCfg.TlsAlpnDelayWait,
 		PreferredChain:         nodeCfg.PreferredChain,
 		ACMEProfile:            nodeCfg.ACMEProfile,
 		ARIReplacesAccountUrl: lo.
@@ -378,6 +607,110 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 			}),
 	}
 
+	return acmeCfg, obtainReq, nil
+}
+
+func (ne *bizApplyNodeExecutor) execPreflight(execCtx *NodeExecutionContext, acmeCfg *certacme.ACMEConfig, obtainReq *certacme.ObtainCertificateRequest, acmeAccountUrl string) error {
+	preflightResp, err := certacme.Preflight(execCtx.Context(), &certacme.PreflightRequest{
+		CADirUrl:       acmeCfg.CADirUrl,
+		ACMEAccountUrl: acmeAccountUrl,
+		ObtainRequest:  obtainReq,
+	})
+	if err != nil {
+		ne.logger.Warn("could not run preflight checks")
+		return err
+	}
+
+	for _, diag := range preflightResp.Diagnostics {
+		attrs := []any{slog.String("identifier", diag.Identifier), slog.String("check", diag.Check), slog.String("message", diag.Message)}
+		switch diag.Level {
+		case certacme.PreflightLevelError:
+			ne.logger.Error("preflight check failed", attrs...)
+		case certacme.PreflightLevelWarn:
+			ne.logger.Warn("preflight check warning", attrs...)
+		default:
+			ne.logger.Info("preflight check passed", attrs...)
+		}
+	}
+
+	if err := preflightResp.Err(); err != nil {
+		ne.logger.Warn("preflight checks failed, abort requesting certificate", slog.Any("report", preflightResp))
+		return fmt.Errorf("preflight checks failed: %w", err)
+	}
+
+	ne.logger.Info("preflight checks completed", slog.Any("report", preflightResp))
+	return nil
+}
+
+// 输出使用内置权威 DNS 服务器完成 DNS-01 质询的域名所需创建的 CNAME 记录。
+func (ne *bizApplyNodeExecutor) logDnsResponderRecords(obtainReq *certacme.ObtainCertificateRequest) {
+	isLocalDns01 := func(challengeType string, provider domain.ACMEChallengeProviderType) bool {
+		return strings.EqualFold(challengeType, "dns-01") && domain.ACMEDns01ProviderType(provider) == domain.ACMEDns01ProviderTypeLocal
+	}
+
+	domains := make([]string, 0, len(obtainReq.DomainOrIPs))
+	for _, domainOrIP := range obtainReq.DomainOrIPs {
+		if dc := certacme.MatchDomainChallenge(obtainReq.DomainChallenges, domainOrIP); dc != nil {
+			if isLocalDns01(dc.ChallengeType, dc.Provider) {
+				domains = append(domains, domainOrIP)
+			}
+		} else if isLocalDns01(obtainReq.ChallengeType, obtainReq.Provider) {
+			domains = append(domains, domainOrIP)
+		}
+	}
+	if len(domains) == 0 {
+		return
+	}
+
+	if !dnsresponder.IsEnabled() {
+		ne.logger.Warn("the built-in dns responder is not enabled, please start the server with the flag '--dnsResponderZone'")
+		return
+	}
+
+	ne.logger.Info("please make sure the following CNAME records exist to delegate dns-01 challenges to the built-in dns responder", slog.Any("records", dnsresponder.GetCNAMERecords(domains)))
+}
+
+func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizApply, lastCertificate *domain.Certificate) (*certacme.ObtainCertificateResponse, error) {
+	acmeCfg, obtainReq, err := ne.prepareObtainCertificate(execCtx, nodeCfg, lastCertificate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 由本地 CA 直接签发，无需 ACME 账户及质询
+	if acmeCfg.CAProvider == domain.CAProviderTypeLocalCA {
+		issueResp, err := certlocalca.IssueCertificate(execCtx.Context(), ne.buildLocalCAIssueRequest(nodeCfg, acmeCfg, obtainReq))
+		if err != nil {
+			ne.logger.Warn("could not issue certificate")
+			return nil, err
+		}
+
+		return &certacme.ObtainCertificateResponse{
+			CAProvider:           acmeCfg.CAProvider,
+			FullChainCertificate: issueResp.FullChainCertificate,
+			IssuerCertificate:    issueResp.IssuerCertificate,
+			PrivateKey:           issueResp.PrivateKey,
+		}, nil
+	}
+
+	// 初始化 ACME 账户
+	// 注意此步骤仍需在主进程中进行，以保证并发安全
+	acmeAcct, err := certacme.CreateACMEAccountWithSingleFlight(execCtx.Context(), acmeCfg, nodeCfg.ContactEmail)
+	if err != nil {
+		ne.logger.Warn("could not initialize acme account")
+		return nil, err
+	} else {
+		ne.logger.Info("acme account initialized", slog.String("acmeAcctUrl", acmeAcct.ACMEAccountUrl))
+	}
+
+	ne.logDnsResponderRecords(obtainReq)
+
+	// 预检，尽早发现会导致订单失败的配置问题，避免消耗 CA 的速率限制
+	if nodeCfg.EnablePreflight {
+		if err := ne.execPreflight(execCtx, acmeCfg, obtainReq, acmeAcct.ACMEAccountUrl); err != nil {
+			return nil, err
+		}
+	}
+
 	// 构造证书申请时所需的 lego 配置项
 	legoCertifierCfg := &lego.NewConfig(nil).Certificate
 	globalSettingsForPersistence := settings.GetGlobalSettingsForSSLProvider()
@@ -392,6 +725,8 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 
 			LegoAccount         *certacme.ACMEAccount   `json:"legoAccount,omitempty"`
 			LegoCertifierConfig *lego.CertificateConfig `json:"legoCertifierConfig,omitempty"`
+
+			DnsResponderZone string `json:"dnsResponderZone,omitempty"`
 		}
 
 		type OutData struct {
@@ -404,6 +739,8 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 
 			LegoAccount:         acmeAcct,
 			LegoCertifierConfig: legoCertifierCfg,
+
+			DnsResponderZone: dnsresponder.GetZone(),
 		})
 		if err != nil {
 			ne.logger.Warn("could not obtain certificate")
@@ -437,6 +774,69 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 	return obtainResp, nil
 }
 
+func (ne *bizApplyNodeExecutor) buildLocalCAIssueRequest(nodeCfg *domain.WorkflowNodeConfigForBizApply, acmeCfg *certacme.ACMEConfig, obtainReq *certacme.ObtainCertificateRequest) *certlocalca.IssueCertificateRequest {
+	return &certlocalca.IssueCertificateRequest{
+		DomainOrIPs:       obtainReq.DomainOrIPs,
+		PrivateKeyType:    obtainReq.PrivateKeyType,
+		PrivateKeyPEM:     obtainReq.PrivateKeyPEM,
+		ValidityNotBefore: obtainReq.ValidityNotBefore,
+		ValidityNotAfter:  obtainReq.ValidityNotAfter,
+		NoCommonName:      obtainReq.NoCommonName,
+		CSRPEM:            obtainReq.CSRPEM,
+		ExtKeyUsages:      xmaps.GetStringsBySplit(nodeCfg.CAProviderConfig, "extKeyUsages", ";"),
+		CAAccessConfig:    acmeCfg.CAProviderAccessConfig,
+	}
+}
+
+func (ne *bizApplyNodeExecutor) loadCSR(nodeCfg *domain.WorkflowNodeConfigForBizApply) (*x509.CertificateRequest, error) {
+	var csrPEM string
+	switch nodeCfg.CSRSource {
+	case BizApplyCSRSourceForm:
+		{
+			csrPEM = nodeCfg.CSRContent
+		}
+
+	case BizApplyCSRSourceLocal:
+		{
+			csrData, err := os.ReadFile(nodeCfg.CSRContent)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read csr file from local path: %w", err)
+			} else {
+				csrPEM = string(csrData)
+			}
+		}
+
+	case BizApplyCSRSourceURL:
+		{
+			client := resty.New()
+			if nodeCfg.CSRAllowInsecureConnections {
+				client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+			}
+
+			csrResp, err := client.NewRequest().Get(nodeCfg.CSRContent)
+			if err != nil {
+				return nil, fmt.Errorf("failed to download csr from URL: %w", err)
+			} else if csrResp.IsError() {
+				return nil, fmt.Errorf("failed to download csr from URL: unexpected status code %d", csrResp.StatusCode())
+			} else {
+				csrPEM = string(csrResp.Body())
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported csr source: '%s'", nodeCfg.CSRSource)
+	}
+
+	csr, err := certcrypto.PemDecodeTox509CSR([]byte(csrPEM))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse csr: %w", err)
+	} else if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("could not verify csr signature: %w", err)
+	}
+
+	return csr, nil
+}
+
 func (ne *bizApplyNodeExecutor) setOuputsOfResult(execCtx *NodeExecutionContext, execRes *NodeExecutionResult, certificate *domain.Certificate, persistent bool) {
 	if certificate != nil {
 		key := "certificate"
@@ -496,3 +896,48 @@ func newBizApplyNodeExecutor() NodeExecutor {
 		wfoutputRepo:    repository.NewWorkflowOutputRepository(),
 	}
 }
+
+// 校验 CSR 中的标识符与节点配置中的域名、IP 地址是否完全一致。
+func checkCSRIdentifiers(csr *x509.CertificateRequest, domains []string, ipaddrs []string) error {
+	expected := make(map[string]bool)
+	for _, name := range domains {
+		expected[strings.ToLower(name)] = true
+	}
+	for _, ipaddr := range ipaddrs {
+		if ip := net.ParseIP(ipaddr); ip != nil {
+			expected[ip.String()] = true
+		} else {
+			expected[ipaddr] = true
+		}
+	}
+
+	actual := make(map[string]bool)
+	for _, name := range csr.DNSNames {
+		actual[strings.ToLower(name)] = true
+	}
+	for _, ip := range csr.IPAddresses {
+		actual[ip.String()] = true
+	}
+	if cn := strings.ToLower(csr.Subject.CommonName); cn != "" {
+		if ip := net.ParseIP(cn); ip != nil {
+			cn = ip.String()
+		}
+		if !expected[cn] {
+			return fmt.Errorf("the common name '%s' in csr is not in the configured domains or ip addresses", csr.Subject.CommonName)
+		}
+		actual[cn] = true
+	}
+
+	for identifier := range actual {
+		if !expected[identifier] {
+			return fmt.Errorf("the identifier '%s' in csr is not in the configured domains or ip addresses", identifier)
+		}
+	}
+	for identifier := range expected {
+		if !actual[identifier] {
+			return fmt.Errorf("the configured identifier '%s' is missing in csr", identifier)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCSRIdentifiers(t *testing.T) {
+	type testInput struct {
+		commonName  string
+		dnsNames    []string
+		ipAddresses []string
+		domains     []string
+		ipaddrs     []string
+	}
+	testCases := []struct {
+		name        string
+		input       testInput
+		expectedErr bool
+	}{
+		{
+			name: "domains matched",
+			input: testInput{
+				dnsNames: []string{"example.com", "*.example.com"},
+				domains:  []string{"*.example.com", "example.com"},
+			},
+		},
+		{
+			name: "domains matched case insensitively",
+			input: testInput{
+				commonName: "Example.COM",
+				dnsNames:   []string{"EXAMPLE.com"},
+				domains:    []string{"example.com"},
+			},
+		},
+		{
+			name: "ip addresses matched",
+			input: testInput{
+				ipAddresses: []string{"127.0.0.1", "::1"},
+				ipaddrs:     []string{"0:0:0:0:0:0:0:1", "127.0.0.1"},
+			},
+		},
+		{
+			name: "ip address in common name",
+			input: testInput{
+				commonName:  "127.0.0.1",
+				ipAddresses: []string{"127.0.0.1"},
+				ipaddrs:     []string{"127.0.0.1"},
+			},
+		},
+		{
+			name: "common name only",
+			input: testInput{
+				commonName: "example.com",
+				domains:    []string{"example.com"},
+			},
+		},
+		{
+			name: "common name not configured",
+			input: testInput{
+				commonName: "other.com",
+				dnsNames:   []string{"example.com"},
+				domains:    []string{"example.com"},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "extra domain in csr",
+			input: testInput{
+				dnsNames: []string{"example.com", "other.com"},
+				domains:  []string{"example.com"},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "extra ip address in csr",
+			input: testInput{
+				dnsNames:    []string{"example.com"},
+				ipAddresses: []string{"127.0.0.1"},
+				domains:     []string{"example.com"},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "configured domain missing in csr",
+			input: testInput{
+				dnsNames: []string{"example.com"},
+				domains:  []string{"example.com", "www.example.com"},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "configured ip address missing in csr",
+			input: testInput{
+				dnsNames: []string{"example.com"},
+				domains:  []string{"example.com"},
+				ipaddrs:  []string{"127.0.0.1"},
+			},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			csr := &x509.CertificateRequest{
+				Subject:  pkix.Name{CommonName: tc.input.commonName},
+				DNSNames: tc.input.dnsNames,
+			}
+			for _, ipaddr := range tc.input.ipAddresses {
+				csr.IPAddresses = append(csr.IPAddresses, net.ParseIP(ipaddr))
+			}
+
+			err := checkCSRIdentifiers(csr, tc.input.domains, tc.input.ipaddrs)
+			if tc.expectedErr {
+				assert.Error(t, err, "Case: %-20s", tc.name)
+			} else {
+				assert.NoError(t, err, "Case: %-20s", tc.name)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"maps"
 	"strings"
 
+	"github.com/samber/lo"
+
 	"github.com/certimate-go/certimate/internal/certmgmt"
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/internal/repository"
@@ -59,7 +61,12 @@ func (ne *bizDeployNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 		}
 	}
 	if inputCertificate == nil {
-		return execRes, fmt.Errorf("invalid input certificate")
+		// 试运行模式下前序节点不会实际申请证书，此时允许输入证书为空
+		if !execCtx.IsDryRun() {
+			return execRes, fmt.Errorf("invalid input certificate")
+		}
+
+		inputCertificate = &domain.Certificate{}
 	}
 
 	// 检测是否可以跳过本次执行
@@ -68,6 +75,7 @@ func (ne *bizDeployNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 			ne.logger.Info(fmt.Sprintf("skip this deployment, because %s", reason))
 
 			execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, true, stateValTypeBoolean)
+			execRes.SetPlan(domain.WorkflowRunPlanActionTypeSkip, reason)
 			return execRes, nil
 		} else if reason != "" {
 			ne.logger.Info(fmt.Sprintf("re-deploy, because %s", reason))
@@ -97,6 +105,20 @@ func (ne *bizDeployNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 		CertificatePEM:         inputCertificate.Certificate,
 		PrivateKeyPEM:          inputCertificate.PrivateKey,
 	}
+	if execCtx.IsDryRun() {
+		if err := deployer.ValidateDeployCertificateRequest(execCtx.Context(), deployReq); err != nil {
+			ne.logger.Warn("could not validate deployment")
+			return execRes, err
+		}
+
+		ne.logger.Info("dry run: skip deploying certificate")
+
+		execRes.SetPlan(domain.WorkflowRunPlanActionTypeDeploy, lo.
+			If(inputCertificate.Id == "", fmt.Sprintf("deploy the certificate from node #%s via '%s'", nodeCfg.CertificateOutputNodeId, nodeCfg.Provider)).
+			Else(fmt.Sprintf("deploy certificate #%s via '%s'", inputCertificate.Id, nodeCfg.Provider)))
+		return execRes, nil
+	}
+
 	if _, err := deployer.DeployCertificate(execCtx.Context(), deployReq); err != nil {
 		ne.logger.Warn("could not deploy certificate")
 		return execRes, err
@@ -1,16 +1,21 @@
 package engine
 
 import (
+	"context"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
 	"net"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/samber/lo"
+
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/repository"
 	xcertx509 "github.com/certimate-go/certimate/pkg/utils/cert/x509"
@@ -18,6 +23,13 @@ import (
 	xtls "github.com/certimate-go/certimate/pkg/utils/tls"
 )
 
+const (
+	BizMonitorProtocolHTTPS = "https"
+)
+
+// 表示主机地址解析到的多个 IP 地址所提供的证书不一致，通常意味着证书部署尚未完成
+var ErrBizMonitorCertificateMismatch = errors.New("certificates mismatch across resolved ip addresses")
+
 /**
  * Variables:
  *   - "certificate.commanName": string
@@ -27,6 +39,15 @@ import (
  *   - "certificate.hoursLeft": number
  *   - "certificate.daysLeft": number
  *   - "certificate.validity": boolean
+ *   - "certificate.chainTrusted": boolean
+ *   - "certificate.chainMissingIntermediates": boolean
+ *   - "certificate.chainExtraIntermediates": boolean
+ *   - "certificate.ocspStapled": boolean
+ *   - "certificate.revocationStatus": string
+ *   - "certificate.mismatch": boolean
+ *   - "certificate.serials": string
+ *   - "tls.versions": string
+ *   - "tls.weakCipherSuites": string
  */
 type bizMonitorNodeExecutor struct {
 	nodeExecutor
@@ -40,9 +61,15 @@ func (ne *bizMonitorNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeE
 	nodeCfg := execCtx.Node.Data.Config.AsBizMonitor()
 	ne.logger.Info("ready to monitor certificate ...", slog.Any("config", nodeCfg))
 
-	targetAddr := net.JoinHostPort(nodeCfg.Host, strconv.Itoa(int(nodeCfg.Port)))
+	targetPort := strconv.Itoa(int(nodeCfg.Port))
 	if nodeCfg.Port == 0 {
-		targetAddr = net.JoinHostPort(nodeCfg.Host, "443")
+		targetPort = "443"
+	}
+	targetAddr := net.JoinHostPort(nodeCfg.Host, targetPort)
+
+	targetProtocol := nodeCfg.Protocol
+	if targetProtocol == "" {
+		targetProtocol = BizMonitorProtocolHTTPS
 	}
 
 	targetDomain := nodeCfg.Domain
@@ -50,36 +77,30 @@ func (ne *bizMonitorNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeE
 		targetDomain = nodeCfg.Host
 	}
 
-	ne.logger.Info(fmt.Sprintf("retrieving certificate at %s (domain: %s)", targetAddr, targetDomain))
+	ne.logger.Info(fmt.Sprintf("retrieving certificate at %s (domain: %s, protocol: %s)", targetAddr, targetDomain, targetProtocol))
 
-	const MAX_ATTEMPTS = 3
-	const RETRY_INTERVAL = 2 * time.Second
-	var err error
+	// 失败重试由工作流引擎根据节点重试策略统一处理
 	var certs []*x509.Certificate
-	for attempt := 0; attempt < MAX_ATTEMPTS; attempt++ {
-		if attempt > 0 {
-			ne.logger.Info(fmt.Sprintf("retry %d time(s) ...", attempt))
-
-			ctx := execCtx.Context()
-			select {
-			case <-ctx.Done():
-				return execRes, ctx.Err()
-			case <-time.After(RETRY_INTERVAL):
-			}
-		}
-
-		certs, err = ne.execRetrieveCertificates(execCtx, targetAddr, targetDomain, nodeCfg.RequestPath)
+	var err error
+	auditAddrs := []string{targetAddr}
+	if nodeCfg.ProbeAllIPs {
+		var probes []bizMonitorProbe
+		certs, probes, err = ne.execRetrieveCertificatesFromAllIPs(execCtx, nodeCfg.Host, targetPort, targetDomain, targetProtocol, nodeCfg.RequestPath, nodeCfg.Nameservers)
 		if err == nil {
-			break
+			auditAddrs = lo.Map(probes, func(probe bizMonitorProbe, _ int) string { return probe.Addr })
+			err = ne.checkProbesMismatch(execCtx, execRes, probes)
 		}
+	} else if targetProtocol == BizMonitorProtocolHTTPS {
+		certs, err = ne.execRetrieveCertificates(execCtx, targetAddr, targetDomain, nodeCfg.RequestPath)
+	} else {
+		certs, err = ne.execRetrieveCertificatesByProtocol(execCtx, targetAddr, targetDomain, targetProtocol)
 	}
-
 	if err != nil {
 		ne.logger.Warn("could not retrieve certificate")
 		return execRes, err
 	} else {
 		if len(certs) == 0 {
-			ne.logger.Warn("no ssl certificates retrieved in http response")
+			ne.logger.Warn("no ssl certificates retrieved")
 
 			ne.setVariablesOfResult(execCtx, execRes, nil)
 		} else {
@@ -97,6 +118,28 @@ func (ne *bizMonitorNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeE
 			daysLeft := int32(math.Floor(time.Until(cert.NotAfter).Hours() / 24))
 			validated := isCertPeriodValid && isCertHostMatched
 
+			// 深度审计结果仅作为附加的变量输出，审计失败不影响节点执行结果
+			var auditRes *xtls.AuditResult
+			if nodeCfg.EnableAudit {
+				// 探测所有 IP 地址时逐一审计，任一地址存在问题即视为存在问题
+				auditResults := make([]*xtls.AuditResult, 0, len(auditAddrs))
+				for _, addr := range auditAddrs {
+					res, err := ne.execAudit(execCtx, addr, targetDomain, targetProtocol, nodeCfg.CABundle)
+					if err != nil {
+						ne.logger.Warn(fmt.Sprintf("could not audit tls endpoint %s", addr), slog.Any("error", err))
+						continue
+					}
+
+					auditResults = append(auditResults, res)
+				}
+
+				if len(auditResults) > 0 {
+					auditRes = mergeAuditResults(auditResults)
+					ne.setVariablesOfAudit(execCtx, execRes, auditRes)
+					validated = validated && auditRes.ChainTrusted && auditRes.RevocationStatus != xtls.RevocationStatusRevoked
+				}
+			}
+
 			if validated {
 				ne.logger.Info(fmt.Sprintf("the certificate is valid, and will expire in %d day(s)", daysLeft))
 			} else {
@@ -104,6 +147,10 @@ func (ne *bizMonitorNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeE
 					ne.logger.Warn("the certificate is invalid, because it is not matched the host")
 				} else if !isCertPeriodValid {
 					ne.logger.Warn("the certificate is invalid, because it is either expired or not yet valid")
+				} else if auditRes != nil && auditRes.RevocationStatus == xtls.RevocationStatusRevoked {
+					ne.logger.Warn("the certificate is invalid, because it has been revoked")
+				} else if auditRes != nil && !auditRes.ChainTrusted {
+					ne.logger.Warn("the certificate is invalid, because its chain is not trusted")
 				} else {
 					ne.logger.Warn("the certificate is invalid")
 				}
@@ -157,6 +204,251 @@ func (ne *bizMonitorNodeExecutor) execRetrieveCertificates(execCtx *NodeExecutio
 	return resp.TLS.PeerCertificates, nil
 }
 
+func (ne *bizMonitorNodeExecutor) execRetrieveCertificatesByProtocol(execCtx *NodeExecutionContext, addr, domain, protocol string) ([]*x509.Certificate, error) {
+	tlsConfig := xtls.NewInsecureConfig()
+	tlsConfig.ServerName = domain
+
+	ctx, cancel := context.WithTimeout(execCtx.Context(), 30*time.Second)
+	defer cancel()
+
+	conn, err := xtls.DialWithProtocol(ctx, addr, protocol, tlsConfig)
+	if err != nil {
+		err = fmt.Errorf("failed to establish tls connection: %w", err)
+		ne.logger.Warn(err.Error())
+		return nil, err
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return make([]*x509.Certificate, 0), nil
+	}
+	return state.PeerCertificates, nil
+}
+
+type bizMonitorProbe struct {
+	IP     string
+	Addr   string
+	Serial string // 未获取到证书时为空
+	Cert   *x509.Certificate
+}
+
+func (ne *bizMonitorNodeExecutor) execRetrieveCertificatesFromAllIPs(execCtx *NodeExecutionContext, host, port, domain, protocol, requestPath string, nameservers []string) ([]*x509.Certificate, []bizMonitorProbe, error) {
+	ips, err := ne.execResolveIPs(execCtx, host, nameservers)
+	if err != nil {
+		err = fmt.Errorf("failed to resolve host '%s': %w", host, err)
+		ne.logger.Warn(err.Error())
+		return nil, nil, err
+	}
+
+	ne.logger.Info(fmt.Sprintf("host '%s' resolved to %d address(es): %s", host, len(ips), strings.Join(ips, ", ")))
+
+	// 逐一探测各 IP 地址，任一地址无法获取证书即视为失败
+	var primaryCerts []*x509.Certificate
+	probes := make([]bizMonitorProbe, 0, len(ips))
+	for _, ip := range ips {
+		addr := net.JoinHostPort(ip, port)
+
+		var certs []*x509.Certificate
+		var err error
+		if protocol == BizMonitorProtocolHTTPS {
+			certs, err = ne.execRetrieveCertificates(execCtx, addr, domain, requestPath)
+		} else {
+			certs, err = ne.execRetrieveCertificatesByProtocol(execCtx, addr, domain, protocol)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to retrieve certificate at %s: %w", addr, err)
+		}
+
+		if len(certs) == 0 {
+			ne.logger.Warn(fmt.Sprintf("no ssl certificates retrieved at %s", addr))
+			probes = append(probes, bizMonitorProbe{IP: ip, Addr: addr})
+			continue
+		}
+
+		ne.logger.Info(fmt.Sprintf("ssl certificate retrieved at %s (serial='%s', not_after='%s')", addr, certs[0].SerialNumber, certs[0].NotAfter.Format(time.RFC3339)))
+		probes = append(probes, bizMonitorProbe{IP: ip, Addr: addr, Serial: certs[0].SerialNumber.String(), Cert: certs[0]})
+		if primaryCerts == nil {
+			primaryCerts = certs
+		}
+	}
+
+	if primaryCerts == nil {
+		return make([]*x509.Certificate, 0), probes, nil
+	}
+	return primaryCerts, probes, nil
+}
+
+// 检查各 IP 地址提供的证书是否一致，并输出相应的变量。
+// 证书不一致通常意味着证书部署尚未完成，立即重试没有意义，因此返回不可重试的错误。
+func (ne *bizMonitorNodeExecutor) checkProbesMismatch(execCtx *NodeExecutionContext, execRes *NodeExecutionResult, probes []bizMonitorProbe) error {
+	mismatched := false
+	serials := make([]string, 0, len(probes))
+	for _, probe := range probes {
+		serial := probe.Serial
+		if serial == "" {
+			serial = "<none>"
+		}
+		serials = append(serials, fmt.Sprintf("%s=%s", probe.IP, serial))
+
+		if probe.Cert == nil || probes[0].Cert == nil {
+			mismatched = mismatched || probe.Cert != probes[0].Cert
+		} else if !probe.Cert.Equal(probes[0].Cert) {
+			mismatched = true
+		}
+	}
+
+	vMismatch := mismatched
+	vSerials := strings.Join(serials, ";")
+	execRes.AddVariable(stateVarKeyCertificateMismatch, vMismatch, stateValTypeBoolean)
+	execRes.AddVariable(stateVarKeyCertificateSerials, vSerials, stateValTypeString)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyCertificateMismatch, vMismatch, stateValTypeBoolean)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyCertificateSerials, vSerials, stateValTypeString)
+
+	if mismatched {
+		err := fmt.Errorf("%w: %s", ErrBizMonitorCertificateMismatch, strings.Join(serials, ", "))
+		ne.logger.Warn(err.Error())
+		return &NonRetryableError{Err: err}
+	}
+
+	return nil
+}
+
+func (ne *bizMonitorNodeExecutor) execResolveIPs(execCtx *NodeExecutionContext, host string, nameservers []string) ([]string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{ip.String()}, nil
+	}
+
+	resolver := net.DefaultResolver
+	if len(nameservers) > 0 {
+		servers := make([]string, 0, len(nameservers))
+		for _, ns := range nameservers {
+			if ns = strings.TrimSpace(ns); ns == "" {
+				continue
+			}
+
+			if _, _, err := net.SplitHostPort(ns); err != nil {
+				ns = net.JoinHostPort(ns, "53")
+			}
+			servers = append(servers, ns)
+		}
+
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var errs []error
+				for _, server := range servers {
+					conn, err := (&net.Dialer{}).DialContext(ctx, network, server)
+					if err == nil {
+						return conn, nil
+					}
+					errs = append(errs, err)
+				}
+				return nil, errors.Join(errs...)
+			},
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(execCtx.Context(), 30*time.Second)
+	defer cancel()
+
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		ip := addr.Unmap().String()
+		if !slices.Contains(ips, ip) {
+			ips = append(ips, ip)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, errors.New("no ip addresses found")
+	}
+
+	slices.Sort(ips)
+	return ips, nil
+}
+
+func (ne *bizMonitorNodeExecutor) execAudit(execCtx *NodeExecutionContext, addr, domain, protocol, caBundle string) (*xtls.AuditResult, error) {
+	ne.logger.Info(fmt.Sprintf("auditing tls endpoint %s ...", addr))
+
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if caBundle != "" {
+		if !roots.AppendCertsFromPEM([]byte(caBundle)) {
+			return nil, fmt.Errorf("failed to parse ca bundle")
+		}
+	}
+
+	if protocol == BizMonitorProtocolHTTPS {
+		protocol = xtls.ProtocolTLS
+	}
+
+	auditRes, err := xtls.Audit(execCtx.Context(), addr, &xtls.AuditOptions{
+		Protocol:   protocol,
+		ServerName: domain,
+		RootCAs:    roots,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if auditRes.ChainTrusted {
+		ne.logger.Info("the certificate chain is trusted")
+	} else if auditRes.MissingIntermediates {
+		ne.logger.Warn("the certificate chain is not trusted, because the server does not send the required intermediate certificates")
+	} else {
+		ne.logger.Warn("the certificate chain is not trusted", slog.Any("error", auditRes.ChainError))
+	}
+	for _, cert := range auditRes.ExtraIntermediates {
+		ne.logger.Warn(fmt.Sprintf("the server sends an unnecessary certificate (subject='%s', issuer='%s')", cert.Subject.String(), cert.Issuer.String()))
+	}
+	ne.logger.Info(fmt.Sprintf("revocation status: %s (source: %s, ocsp stapled: %t)", auditRes.RevocationStatus, auditRes.RevocationSource, auditRes.OCSPStapled))
+	ne.logger.Info(fmt.Sprintf("supported tls versions: %s", strings.Join(auditRes.TLSVersions, ", ")))
+	if len(auditRes.WeakCipherSuites) > 0 {
+		ne.logger.Warn(fmt.Sprintf("weak cipher suites accepted: %s", strings.Join(auditRes.WeakCipherSuites, ", ")))
+	}
+
+	return auditRes, nil
+}
+
+// 合并多个地址的审计结果，任一地址存在问题即视为存在问题。
+func mergeAuditResults(results []*xtls.AuditResult) *xtls.AuditResult {
+	merged := &xtls.AuditResult{
+		ChainTrusted:     true,
+		OCSPStapled:      true,
+		RevocationStatus: xtls.RevocationStatusGood,
+	}
+	for _, res := range results {
+		merged.ChainTrusted = merged.ChainTrusted && res.ChainTrusted
+		if merged.ChainError == nil {
+			merged.ChainError = res.ChainError
+		}
+		merged.MissingIntermediates = merged.MissingIntermediates || res.MissingIntermediates
+		merged.ExtraIntermediates = append(merged.ExtraIntermediates, res.ExtraIntermediates...)
+		merged.OCSPStapled = merged.OCSPStapled && res.OCSPStapled
+		switch {
+		case res.RevocationStatus == xtls.RevocationStatusRevoked:
+			merged.RevocationStatus = res.RevocationStatus
+			merged.RevocationSource = res.RevocationSource
+		case res.RevocationStatus == xtls.RevocationStatusUnknown && merged.RevocationStatus == xtls.RevocationStatusGood:
+			merged.RevocationStatus = res.RevocationStatus
+			merged.RevocationSource = res.RevocationSource
+		case merged.RevocationSource == "":
+			merged.RevocationSource = res.RevocationSource
+		}
+		merged.TLSVersions = lo.Union(merged.TLSVersions, res.TLSVersions)
+		merged.WeakCipherSuites = lo.Union(merged.WeakCipherSuites, res.WeakCipherSuites)
+	}
+
+	return merged
+}
+
 func (ne *bizMonitorNodeExecutor) setVariablesOfResult(execCtx *NodeExecutionContext, execRes *NodeExecutionResult, certX509 *x509.Certificate) {
 	var vCommonName string
 	var vSubjectAltNames string
@@ -196,6 +488,31 @@ func (ne *bizMonitorNodeExecutor) setVariablesOfResult(execCtx *NodeExecutionCon
 	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyCertificateValidity, vValidity, stateValTypeBoolean)
 }
 
+func (ne *bizMonitorNodeExecutor) setVariablesOfAudit(execCtx *NodeExecutionContext, execRes *NodeExecutionResult, auditRes *xtls.AuditResult) {
+	vChainTrusted := auditRes.ChainTrusted
+	vChainMissingIntermediates := auditRes.MissingIntermediates
+	vChainExtraIntermediates := len(auditRes.ExtraIntermediates) > 0
+	vOCSPStapled := auditRes.OCSPStapled
+	vRevocationStatus := auditRes.RevocationStatus
+	vTLSVersions := strings.Join(auditRes.TLSVersions, ";")
+	vTLSWeakCipherSuites := strings.Join(auditRes.WeakCipherSuites, ";")
+
+	execRes.AddVariable(stateVarKeyCertificateChainTrusted, vChainTrusted, stateValTypeBoolean)
+	execRes.AddVariable(stateVarKeyCertificateChainMissingIntermediates, vChainMissingIntermediates, stateValTypeBoolean)
+	execRes.AddVariable(stateVarKeyCertificateChainExtraIntermediates, vChainExtraIntermediates, stateValTypeBoolean)
+	execRes.AddVariable(stateVarKeyCertificateOCSPStapled, vOCSPStapled, stateValTypeBoolean)
+	execRes.AddVariable(stateVarKeyCertificateRevocationStatus, vRevocationStatus, stateValTypeString)
+	execRes.AddVariable(stateVarKeyTLSVersions, vTLSVersions, stateValTypeString)
+	execRes.AddVariable(stateVarKeyTLSWeakCipherSuites, vTLSWeakCipherSuites, stateValTypeString)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyCertificateChainTrusted, vChainTrusted, stateValTypeBoolean)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyCertificateChainMissingIntermediates, vChainMissingIntermediates, stateValTypeBoolean)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyCertificateChainExtraIntermediates, vChainExtraIntermediates, stateValTypeBoolean)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyCertificateOCSPStapled, vOCSPStapled, stateValTypeBoolean)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyCertificateRevocationStatus, vRevocationStatus, stateValTypeString)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyTLSVersions, vTLSVersions, stateValTypeString)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyTLSWeakCipherSuites, vTLSWeakCipherSuites, stateValTypeString)
+}
+
 func newBizMonitorNodeExecutor() NodeExecutor {
 	return &bizMonitorNodeExecutor{
 		nodeExecutor:    nodeExecutor{logger: slog.Default()},
@@ -27,6 +27,8 @@ func (ne *bizNotifyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 	// 检测是否可以跳过本次执行
 	if skippable, reason := ne.checkCanSkip(execCtx); skippable {
 		ne.logger.Info(fmt.Sprintf("skip this application, because %s", reason))
+
+		execRes.SetPlan(domain.WorkflowRunPlanActionTypeSkip, reason)
 		return execRes, nil
 	}
 
@@ -74,6 +76,18 @@ func (ne *bizNotifyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 		Subject:                subject,
 		Message:                message,
 	}
+	if execCtx.IsDryRun() {
+		if err := notifier.ValidateSendNotificationRequest(execCtx.Context(), notifyReq); err != nil {
+			ne.logger.Warn("could not validate notification")
+			return execRes, err
+		}
+
+		ne.logger.Info("dry run: skip sending notification")
+
+		execRes.SetPlan(domain.WorkflowRunPlanActionTypeNotify, fmt.Sprintf("send notification via '%s' with subject '%s'", nodeCfg.Provider, subject))
+		return execRes, nil
+	}
+
 	if _, err := notifier.SendNotification(execCtx.Context(), notifyReq); err != nil {
 		ne.logger.Warn("could not send notification")
 		return execRes, err
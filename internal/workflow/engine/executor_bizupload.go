@@ -73,6 +73,7 @@ func (ne *bizUploadNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 		ne.logger.Info(fmt.Sprintf("skip this uploading, because %s", reason))
 
 		execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, true, stateValTypeBoolean)
+		execRes.SetPlan(domain.WorkflowRunPlanActionTypeSkip, reason)
 		return execRes, nil
 	} else if reason != "" {
 		ne.logger.Info(fmt.Sprintf("re-upload, because %s", reason))
@@ -136,6 +137,8 @@ func (ne *bizUploadNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 	if lastCertificate != nil {
 		if xcert.EqualCertificatesFromPEM(certPEM, lastCertificate.Certificate) {
 			ne.logger.Info("skip this uploading, because the last uploaded certificate already exists")
+
+			execRes.SetPlan(domain.WorkflowRunPlanActionTypeSkip, "the last uploaded certificate already exists")
 			return execRes, nil
 		}
 	}
@@ -181,6 +184,14 @@ func (ne *bizUploadNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 		WorkflowNodeId: execCtx.Node.Id,
 	}
 	certificate.PopulateFromPEM(certPEM, privkeyPEM)
+	if execCtx.IsDryRun() {
+		ne.logger.Info("dry run: skip saving certificate")
+
+		ne.setVariablesOfResult(execCtx, execRes, certificate)
+		execRes.SetPlan(domain.WorkflowRunPlanActionTypeUpload, fmt.Sprintf("upload certificate for %s", strings.ReplaceAll(certificate.SubjectAltNames, ";", ", ")))
+		return execRes, nil
+	}
+
 	if certificate, err := ne.certificateRepo.Save(execCtx.Context(), certificate); err != nil {
 		ne.logger.Warn("could not save certificate")
 		return execRes, err
@@ -196,6 +207,23 @@ func (ne *bizUploadNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 	return execRes, nil
 }
 
+func (ne *bizUploadNodeExecutor) Restore(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	// 节点在被恢复的运行中可能因跳过等原因未产生证书，此时没有可还原的证书
+	lastCertificate, err := getCertificateOfSourceRuns(execCtx, ne.certificateRepo)
+	if err != nil {
+		return execRes, err
+	} else if lastCertificate == nil {
+		return execRes, nil
+	}
+
+	ne.setOuputsOfResult(execCtx, execRes, lastCertificate, false)
+	ne.setVariablesOfResult(execCtx, execRes, lastCertificate)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, true, stateValTypeBoolean)
+	return execRes, nil
+}
+
 func (ne *bizUploadNodeExecutor) getLastOutputArtifacts(execCtx *NodeExecutionContext) (*domain.WorkflowOutput, *domain.Certificate, error) {
 	lastOutput, err := ne.wfoutputRepo.GetByWorkflowIdAndNodeId(execCtx.Context(), execCtx.WorkflowId, execCtx.Node.Id)
 	if err != nil && !domain.IsRecordNotFoundError(err) {
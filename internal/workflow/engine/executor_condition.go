@@ -22,8 +22,25 @@ func (ne *conditionNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 
 	execRes := newNodeExecutionResult(execCtx.Node)
 
+	nodeCfg := execCtx.Node.Data.Config.AsCondition()
+
 	errs := make([]error, 0)
 	blocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeBranchBlock })
+	if nodeCfg.Parallel {
+		ne.logger.Info(fmt.Sprintf("executing %d branch(es) in parallel ...", len(blocks)), slog.Int("maxConcurrency", nodeCfg.MaxConcurrency))
+
+		branchErrs, terminated := engine.executeNodesInParallel(&execCtx.WorkflowContext, blocks, nodeCfg.MaxConcurrency)
+		if terminated {
+			return execRes, ErrTerminated
+		}
+
+		if len(branchErrs) > 0 {
+			return execRes, fmt.Errorf("%w: %w", ErrBlocksException, errors.Join(branchErrs...))
+		}
+
+		return execRes, nil
+	}
+
 	for _, node := range blocks {
 		ctx := execCtx.Context()
 		select {
@@ -61,8 +78,15 @@ type branchBlockNodeExecutor struct {
 func (ne *branchBlockNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
 	execRes := newNodeExecutionResult(execCtx.Node)
 
-	nodeCfg := execCtx.Node.Data.Config.AsBranchBlock()
-	if nodeCfg.Expression == nil {
+	nodeCfg, err := execCtx.Node.Data.Config.AsBranchBlock()
+	if err != nil {
+		ne.logger.Warn(fmt.Sprintf("failed to parse expr: %+v", err))
+		return execRes, fmt.Errorf("invalid branch expression: %w", err)
+	}
+
+	if execCtx.resuming.IsAncestor(execCtx.Node.Id) {
+		ne.logger.Info("enter this branch, because the resuming node is inside it")
+	} else if nodeCfg.Expression == nil {
 		ne.logger.Info("enter this branch without any conditions")
 	} else {
 		variables := lo.Reduce(execCtx.variables.All(), func(acc map[string]map[string]any, state VariableState, _ int) map[string]map[string]any {
@@ -70,8 +94,13 @@ func (ne *branchBlockNodeExecutor) Execute(execCtx *NodeExecutionContext) (*Node
 				acc[state.Scope] = make(map[string]any)
 			}
 
-			// 这里需要把所有值都转换为字符串形式，因为 Expression.Eval 仅支持字符串类型的值
-			acc[state.Scope][state.Key] = state.ValueString()
+			// 字符串以外的值保留原始类型，以便表达式按数字、布尔、日期时间进行比较和运算
+			switch state.ValueType {
+			case stateValTypeNumber, stateValTypeBoolean, stateValTypeDateTime:
+				acc[state.Scope][state.Key] = state.Value
+			default:
+				acc[state.Scope][state.Key] = state.ValueString()
+			}
 			return acc
 		}, make(map[string]map[string]any))
 
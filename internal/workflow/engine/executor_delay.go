@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/certimate-go/certimate/internal/domain"
 	xwait "github.com/certimate-go/certimate/pkg/utils/wait"
 )
 
@@ -16,6 +17,13 @@ func (ne *delayNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecut
 	execRes := newNodeExecutionResult(execCtx.Node)
 
 	nodeCfg := execCtx.Node.Data.Config.AsDelay()
+	if execCtx.IsDryRun() {
+		ne.logger.Info(fmt.Sprintf("dry run: skip delaying for %d second(s)", nodeCfg.Wait))
+
+		execRes.SetPlan(domain.WorkflowRunPlanActionTypeWait, fmt.Sprintf("delay for %d second(s)", nodeCfg.Wait))
+		return execRes, nil
+	}
+
 	ne.logger.Info(fmt.Sprintf("delay for %d second(s) before continuing ...", nodeCfg.Wait))
 
 	xwait.DelayWithContext(execCtx.Context(), time.Duration(nodeCfg.Wait)*time.Second)
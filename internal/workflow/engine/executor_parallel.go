@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/samber/lo"
+)
+
+type parallelNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *parallelNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("unreachable")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	nodeCfg := execCtx.Node.Data.Config.AsParallel()
+
+	blocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeParallelBlock })
+	ne.logger.Info(fmt.Sprintf("fan out %d block(s) ...", len(blocks)), slog.Int("maxConcurrency", nodeCfg.MaxConcurrency))
+
+	errs, terminated := engine.executeNodesInParallel(&execCtx.WorkflowContext, blocks, nodeCfg.MaxConcurrency)
+	if terminated {
+		return execRes, ErrTerminated
+	}
+
+	if len(errs) > 0 {
+		ne.logger.Warn(fmt.Sprintf("fan in %d block(s), %d of them failed", len(blocks), len(errs)))
+		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, errors.Join(errs...))
+	}
+
+	ne.logger.Info(fmt.Sprintf("fan in %d block(s)", len(blocks)))
+	return execRes, nil
+}
+
+func newParallelNodeExecutor() NodeExecutor {
+	return &parallelNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
+
+type parallelBlockNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *parallelBlockNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("unreachable")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	if err := engine.executeBlocks(execCtx.Clone(), execCtx.Node.Blocks); err != nil {
+		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, err)
+	}
+
+	return execRes, nil
+}
+
+func newParallelBlockNodeExecutor() NodeExecutor {
+	return &parallelBlockNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/certimate-go/certimate/internal/repository"
+)
+
+/**
+ * Inputs:
+ *   - ref: "certificate": string
+ *
+ * Outputs:
+ *   - ref: "certificate": string
+ *
+ * Variables:
+ *   - 子工作流中各节点产生的变量
+ */
+type subWorkflowNodeExecutor struct {
+	nodeExecutor
+
+	workflowRepo workflowRepository
+}
+
+func (ne *subWorkflowNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("unreachable")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	nodeCfg := execCtx.Node.Data.Config.AsSubWorkflow()
+	ne.logger.Info("ready to run sub-workflow ...", slog.Any("config", nodeCfg))
+
+	// 检测运行时的循环调用（工作流图在保存后可能被修改，因此不能完全依赖保存时的校验）
+	callStack := append(slices.Clone(execCtx.callStack), execCtx.WorkflowId)
+	if slices.Contains(callStack, nodeCfg.WorkflowId) {
+		return execRes, fmt.Errorf("circular sub-workflow call detected: %s -> %s", strings.Join(callStack, " -> "), nodeCfg.WorkflowId)
+	}
+
+	workflow, err := ne.workflowRepo.GetById(execCtx.Context(), nodeCfg.WorkflowId)
+	if err != nil {
+		return execRes, fmt.Errorf("failed to get workflow #%s record: %w", nodeCfg.WorkflowId, err)
+	} else if workflow.GraphContent == nil {
+		return execRes, fmt.Errorf("the sub-workflow #%s has no published content", nodeCfg.WorkflowId)
+	}
+
+	subGraph := workflow.GraphContent
+	if err := subGraph.Verify(); err != nil {
+		return execRes, fmt.Errorf("the sub-workflow #%s is invalid: %w", nodeCfg.WorkflowId, err)
+	}
+
+	// 子工作流在当前上下文的分支中运行，共享当前工作流的变量和输入输出
+	subCtx := execCtx.Fork()
+	subCtx.resuming = nil
+	subCtx.callStack = callStack
+
+	params := flattenVariables(stateVarKeyParamsPrefix, nodeCfg.Parameters)
+	for _, state := range params {
+		subCtx.variables.Add(state)
+	}
+
+	// 将前序节点输出的证书作为子工作流开始节点的输出传入，子工作流中的节点可以据此引用
+	subStartNode := subGraph.Nodes[0]
+	inputCertificate, hasInputCertificate := execCtx.inputs.Get(nodeCfg.CertificateOutputNodeId, "certificate")
+	if nodeCfg.CertificateOutputNodeId != "" {
+		if !hasInputCertificate && !execCtx.IsDryRun() {
+			return execRes, fmt.Errorf("invalid input certificate")
+		}
+
+		if hasInputCertificate {
+			subCtx.inputs.Add(InOutState{
+				NodeId:    subStartNode.Id,
+				Type:      inputCertificate.Type,
+				Name:      inputCertificate.Name,
+				Value:     inputCertificate.Value,
+				ValueType: inputCertificate.ValueType,
+			})
+		}
+	}
+
+	ne.logger.Info(fmt.Sprintf("enter sub-workflow #%s (%s) ...", workflow.Id, workflow.Name))
+
+	// 子工作流中的 End 节点只结束子工作流本身
+	blocksErr := engine.executeBlocks(subCtx, subGraph.Nodes)
+	if blocksErr != nil && errors.Is(blocksErr, ErrTerminated) {
+		blocksErr = nil
+	}
+
+	// 传入的参数和证书仅在子工作流内部可见，合并前移除
+	for _, state := range params {
+		subCtx.variables.Remove(state.Key)
+	}
+	subCtx.inputs.Remove(subStartNode.Id, "certificate")
+	execCtx.Merge(subCtx)
+
+	if blocksErr != nil {
+		ne.logger.Warn(fmt.Sprintf("sub-workflow #%s failed", workflow.Id))
+		return execRes, fmt.Errorf("sub-workflow #%s failed: %w", workflow.Id, blocksErr)
+	}
+
+	// 节点输出：子工作流中最后一个输出证书的节点；如果没有，则透传输入证书
+	var outputCertificate *InOutState
+	walkNodes(subGraph.Nodes, func(node *Node) {
+		if node.Id == subStartNode.Id {
+			return
+		}
+
+		if state, ok := subCtx.inputs.Get(node.Id, "certificate"); ok {
+			outputCertificate = state
+		}
+	})
+	if outputCertificate == nil && hasInputCertificate {
+		outputCertificate = inputCertificate
+	}
+	if outputCertificate != nil {
+		execRes.AddOutput(outputCertificate.Type, outputCertificate.Name, outputCertificate.Value, outputCertificate.ValueType)
+	}
+
+	ne.logger.Info(fmt.Sprintf("leave sub-workflow #%s", workflow.Id))
+	return execRes, nil
+}
+
+func newSubWorkflowNodeExecutor() NodeExecutor {
+	return &subWorkflowNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+		workflowRepo: repository.NewWorkflowRepository(),
+	}
+}
+
+func walkNodes(nodes []*Node, fn func(node *Node)) {
+	for _, node := range nodes {
+		fn(node)
+		walkNodes(node.Blocks, fn)
+	}
+}
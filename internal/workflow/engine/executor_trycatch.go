@@ -22,28 +22,42 @@ func (ne *tryCatchNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 
 	execRes := newNodeExecutionResult(execCtx.Node)
 
+	nodeCfg := execCtx.Node.Data.Config.AsTryCatch()
+
 	tryErrs := make([]error, 0)
 	tryBlocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeTryBlock })
-	for _, node := range tryBlocks {
-		ctx := execCtx.Context()
-		select {
-		case <-ctx.Done():
-			return execRes, ctx.Err()
-		default:
+	if nodeCfg.Parallel {
+		ne.logger.Info(fmt.Sprintf("executing %d try block(s) in parallel ...", len(tryBlocks)), slog.Int("maxConcurrency", nodeCfg.MaxConcurrency))
+
+		blockErrs, terminated := engine.executeNodesInParallel(&execCtx.WorkflowContext, tryBlocks, nodeCfg.MaxConcurrency)
+		if terminated {
+			return execRes, ErrTerminated
 		}
 
-		err := engine.executeNode(execCtx.Clone(), node)
-		if err != nil {
-			if errors.Is(err, ErrTerminated) {
-				return execRes, err
+		tryErrs = append(tryErrs, blockErrs...)
+	} else {
+		for _, node := range tryBlocks {
+			ctx := execCtx.Context()
+			select {
+			case <-ctx.Done():
+				return execRes, ctx.Err()
+			default:
+			}
+
+			err := engine.executeNode(execCtx.Clone(), node)
+			if err != nil {
+				if errors.Is(err, ErrTerminated) {
+					return execRes, err
+				}
+				tryErrs = append(tryErrs, err)
 			}
-			tryErrs = append(tryErrs, err)
 		}
 	}
 
-	if len(tryErrs) > 0 {
+	catchBlocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeCatchBlock })
+	catchResuming := lo.ContainsBy(catchBlocks, func(n *Node) bool { return execCtx.resuming.IsAncestor(n.Id) })
+	if len(tryErrs) > 0 || catchResuming {
 		catchErrs := make([]error, 0)
-		catchBlocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeCatchBlock })
 		for _, node := range catchBlocks {
 			select {
 			case <-execCtx.Context().Done():
@@ -63,6 +77,10 @@ func (ne *tryCatchNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 		errs := make([]error, 0)
 		errs = append(errs, tryErrs...)
 		errs = append(errs, catchErrs...)
+		if len(errs) == 0 {
+			// 从 CatchBlock 分支中恢复运行、且执行成功时，视为已处理异常
+			return execRes, nil
+		}
 		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, errors.Join(errs...))
 	}
 
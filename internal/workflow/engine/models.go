@@ -9,19 +9,22 @@ type Node = domain.WorkflowNode
 type NodeType = domain.WorkflowNodeType
 
 const (
-	NodeTypeStart       = domain.WorkflowNodeTypeStart
-	NodeTypeEnd         = domain.WorkflowNodeTypeEnd
-	NodeTypeCondition   = domain.WorkflowNodeTypeCondition
-	NodeTypeBranchBlock = domain.WorkflowNodeTypeBranchBlock
-	NodeTypeTryCatch    = domain.WorkflowNodeTypeTryCatch
-	NodeTypeTryBlock    = domain.WorkflowNodeTypeTryBlock
-	NodeTypeCatchBlock  = domain.WorkflowNodeTypeCatchBlock
-	NodeTypeDelay       = domain.WorkflowNodeTypeDelay
-	NodeTypeBizApply    = domain.WorkflowNodeTypeBizApply
-	NodeTypeBizUpload   = domain.WorkflowNodeTypeBizUpload
-	NodeTypeBizMonitor  = domain.WorkflowNodeTypeBizMonitor
-	NodeTypeBizDeploy   = domain.WorkflowNodeTypeBizDeploy
-	NodeTypeBizNotify   = domain.WorkflowNodeTypeBizNotify
+	NodeTypeStart         = domain.WorkflowNodeTypeStart
+	NodeTypeEnd           = domain.WorkflowNodeTypeEnd
+	NodeTypeCondition     = domain.WorkflowNodeTypeCondition
+	NodeTypeBranchBlock   = domain.WorkflowNodeTypeBranchBlock
+	NodeTypeTryCatch      = domain.WorkflowNodeTypeTryCatch
+	NodeTypeTryBlock      = domain.WorkflowNodeTypeTryBlock
+	NodeTypeCatchBlock    = domain.WorkflowNodeTypeCatchBlock
+	NodeTypeParallel      = domain.WorkflowNodeTypeParallel
+	NodeTypeParallelBlock = domain.WorkflowNodeTypeParallelBlock
+	NodeTypeDelay         = domain.WorkflowNodeTypeDelay
+	NodeTypeBizApply      = domain.WorkflowNodeTypeBizApply
+	NodeTypeBizUpload     = domain.WorkflowNodeTypeBizUpload
+	NodeTypeBizMonitor    = domain.WorkflowNodeTypeBizMonitor
+	NodeTypeBizDeploy     = domain.WorkflowNodeTypeBizDeploy
+	NodeTypeBizNotify     = domain.WorkflowNodeTypeBizNotify
+	NodeTypeSubWorkflow   = domain.WorkflowNodeTypeSubWorkflow
 )
 
 type Graph = domain.WorkflowGraph
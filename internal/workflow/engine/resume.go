@@ -0,0 +1,194 @@
+package engine
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+// 可恢复的节点执行器。
+// 从失败节点处恢复运行时，位于失败节点之前的节点不会被重新执行，
+// 而是由执行器根据上次持久化的节点输出还原其输出与变量。
+type NodeRestorer interface {
+	Restore(execCtx *NodeExecutionContext) (*NodeExecutionResult, error)
+}
+
+type resumingState struct {
+	targetNodeId    string
+	sourceRunIds    []string            // 被恢复的运行，由近及远
+	restoreNodeIds  map[string]struct{} // 位于目标节点之前、且在被恢复的运行中执行过的节点，需还原而非执行
+	skipNodeIds     map[string]struct{} // 位于目标节点之前、但在被恢复的运行中未曾执行的节点，保持未执行
+	ancestorNodeIds map[string]struct{} // 目标节点的祖先节点
+}
+
+func (s *resumingState) ShouldRestore(nodeId string) bool {
+	if s == nil {
+		return false
+	}
+
+	_, ok := s.restoreNodeIds[nodeId]
+	return ok
+}
+
+func (s *resumingState) ShouldSkip(nodeId string) bool {
+	if s == nil {
+		return false
+	}
+
+	_, ok := s.skipNodeIds[nodeId]
+	return ok
+}
+
+// 返回被恢复的运行。若被恢复的运行本身也是一次恢复运行，则还包括更早的运行，由近及远。
+func (s *resumingState) SourceRunIds() []string {
+	if s == nil {
+		return nil
+	}
+
+	return s.sourceRunIds
+}
+
+func (s *resumingState) IsAncestor(nodeId string) bool {
+	if s == nil {
+		return false
+	}
+
+	_, ok := s.ancestorNodeIds[nodeId]
+	return ok
+}
+
+// 按前序遍历计算从目标节点恢复运行时需要还原的节点。
+// 前序遍历中位于目标节点之前、且不是其祖先的节点，仅当其自身或其子节点在被恢复的运行中执行过时才会被还原；
+// 其余节点（如未进入的分支、未开始的并行分支）保持未执行，以免还原出更早运行中的过期结果。
+func newResumingState(graph *Graph, targetNodeId string, sourceRunIds []string, ranNodeIds []string) (*resumingState, error) {
+	state := &resumingState{
+		targetNodeId:    targetNodeId,
+		sourceRunIds:    sourceRunIds,
+		restoreNodeIds:  make(map[string]struct{}),
+		skipNodeIds:     make(map[string]struct{}),
+		ancestorNodeIds: make(map[string]struct{}),
+	}
+
+	ran := make(map[string]struct{}, len(ranNodeIds))
+	for _, id := range ranNodeIds {
+		ran[id] = struct{}{}
+	}
+
+	var hasRan func(node *Node) bool
+	hasRan = func(node *Node) bool {
+		if _, ok := ran[node.Id]; ok {
+			return true
+		}
+		return lo.ContainsBy(node.Blocks, hasRan)
+	}
+
+	var found bool
+	var walk func(nodes []*Node, ancestors []string)
+	walk = func(nodes []*Node, ancestors []string) {
+		for _, node := range nodes {
+			if found {
+				return
+			}
+
+			if node.Id == targetNodeId {
+				found = true
+				for _, id := range ancestors {
+					state.ancestorNodeIds[id] = struct{}{}
+				}
+				return
+			}
+
+			if hasRan(node) {
+				state.restoreNodeIds[node.Id] = struct{}{}
+			} else {
+				state.skipNodeIds[node.Id] = struct{}{}
+			}
+			walk(node.Blocks, append(ancestors, node.Id))
+		}
+	}
+	walk(graph.Nodes, make([]string, 0))
+
+	if !found {
+		return nil, fmt.Errorf("workflow engine: could not find node #%s to resume from", targetNodeId)
+	}
+
+	for id := range state.ancestorNodeIds {
+		delete(state.restoreNodeIds, id)
+		delete(state.skipNodeIds, id)
+	}
+
+	return state, nil
+}
+
+func (we *workflowEngine) restoreNode(wfCtx *WorkflowContext, node *Node) error {
+	wfCtx.variables.SetScoped(node.Id, stateVarKeyNodeId, node.Id, stateValTypeString)
+	wfCtx.variables.SetScoped(node.Id, stateVarKeyNodeName, node.Data.Name, stateValTypeString)
+
+	if node.Data.Disabled {
+		return nil
+	}
+
+	if len(node.Blocks) > 0 {
+		for _, block := range node.Blocks {
+			if wfCtx.resuming.ShouldSkip(block.Id) {
+				continue
+			}
+
+			if err := we.restoreNode(wfCtx, block); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	newExecutor, ok := we.executors[node.Type]
+	if !ok {
+		return fmt.Errorf("workflow engine: no executor registered for node type: '%s'", node.Type)
+	}
+
+	restorer, ok := newExecutor().(NodeRestorer)
+	if !ok {
+		return nil
+	}
+
+	execCtx := newNodeExecutionContext(wfCtx, node)
+	execRes, err := restorer.Restore(execCtx)
+	if err != nil {
+		we.syslog.Warn(fmt.Sprintf("failed to restore node #%s", node.Id), slog.Any("error", err))
+		return err
+	}
+
+	if execRes != nil {
+		for _, variable := range execRes.Variables {
+			wfCtx.variables.Add(variable)
+		}
+
+		for _, output := range execRes.Outputs {
+			output.Persistent = false
+			wfCtx.inputs.Add(output)
+		}
+	}
+
+	return nil
+}
+
+// 从被恢复的运行中查找节点签发或上传的证书。
+// 还原的节点不会重新持久化输出，因此需沿恢复链向更早的运行查找。
+func getCertificateOfSourceRuns(execCtx *NodeExecutionContext, certificateRepo certificateRepository) (*domain.Certificate, error) {
+	for _, runId := range execCtx.resuming.SourceRunIds() {
+		certificate, err := certificateRepo.GetByWorkflowRunIdAndNodeId(execCtx.Context(), runId, execCtx.Node.Id)
+		if err != nil {
+			if domain.IsRecordNotFoundError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get certificate record of node #%s in run #%s: %w", execCtx.Node.Id, runId, err)
+		}
+
+		return certificate, nil
+	}
+
+	return nil, nil
+}
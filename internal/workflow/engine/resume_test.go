@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResumingState(t *testing.T) {
+	// start
+	// condition
+	// ├── branch1: apply1 → deploy1
+	// └── branch2: apply2
+	// notify
+	graph := &Graph{
+		Nodes: []*Node{
+			{Id: "start", Type: NodeTypeStart},
+			{Id: "condition", Type: NodeTypeCondition, Blocks: []*Node{
+				{Id: "branch1", Type: NodeTypeBranchBlock, Blocks: []*Node{
+					{Id: "apply1", Type: NodeTypeBizApply},
+					{Id: "deploy1", Type: NodeTypeBizDeploy},
+				}},
+				{Id: "branch2", Type: NodeTypeBranchBlock, Blocks: []*Node{
+					{Id: "apply2", Type: NodeTypeBizApply},
+				}},
+			}},
+			{Id: "notify", Type: NodeTypeBizNotify},
+		},
+	}
+
+	type testInput struct {
+		targetNodeId string
+		ranNodeIds   []string
+	}
+	type testExpected struct {
+		restore   []string
+		skip      []string
+		ancestors []string
+		err       bool
+	}
+	testCases := []struct {
+		name     string
+		input    testInput
+		expected testExpected
+	}{
+		{
+			name: "branch not taken",
+			input: testInput{
+				targetNodeId: "notify",
+				ranNodeIds:   []string{"start", "apply1", "deploy1"},
+			},
+			expected: testExpected{
+				restore: []string{"apply1", "branch1", "condition", "deploy1", "start"},
+				skip:    []string{"apply2", "branch2"},
+			},
+		},
+		{
+			name: "other branch taken",
+			input: testInput{
+				targetNodeId: "notify",
+				ranNodeIds:   []string{"start", "apply2"},
+			},
+			expected: testExpected{
+				restore: []string{"apply2", "branch2", "condition", "start"},
+				skip:    []string{"apply1", "branch1", "deploy1"},
+			},
+		},
+		{
+			name: "target inside branch",
+			input: testInput{
+				targetNodeId: "deploy1",
+				ranNodeIds:   []string{"start", "apply1", "deploy1"},
+			},
+			expected: testExpected{
+				restore:   []string{"apply1", "start"},
+				ancestors: []string{"branch1", "condition"},
+			},
+		},
+		{
+			name: "node did not run before failure",
+			input: testInput{
+				targetNodeId: "deploy1",
+				ranNodeIds:   []string{"deploy1"},
+			},
+			expected: testExpected{
+				skip:      []string{"apply1", "start"},
+				ancestors: []string{"branch1", "condition"},
+			},
+		},
+		{
+			name: "target not found",
+			input: testInput{
+				targetNodeId: "missing",
+			},
+			expected: testExpected{
+				err: true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state, err := newResumingState(graph, tc.input.targetNodeId, []string{"run1"}, tc.input.ranNodeIds)
+			if tc.expected.err {
+				assert.Error(t, err, "Case: %-20s", tc.name)
+				return
+			} else if !assert.NoError(t, err, "Case: %-20s", tc.name) {
+				return
+			}
+
+			assert.Equal(t, tc.expected.restore, slices.Sorted(maps.Keys(state.restoreNodeIds)), "Case: %-20s", tc.name)
+			assert.Equal(t, tc.expected.skip, slices.Sorted(maps.Keys(state.skipNodeIds)), "Case: %-20s", tc.name)
+			assert.Equal(t, tc.expected.ancestors, slices.Sorted(maps.Keys(state.ancestorNodeIds)), "Case: %-20s", tc.name)
+			for _, id := range tc.expected.restore {
+				assert.True(t, state.ShouldRestore(id), "Case: %-20s, NodeId: %s", tc.name, id)
+				assert.False(t, state.ShouldSkip(id), "Case: %-20s, NodeId: %s", tc.name, id)
+			}
+			assert.False(t, state.ShouldRestore(tc.input.targetNodeId), "Case: %-20s", tc.name)
+			assert.False(t, state.ShouldSkip(tc.input.targetNodeId), "Case: %-20s", tc.name)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type retryPolicy struct {
+	MaxAttempts   int
+	InitialDelay  time.Duration
+	MaxDelay      time.Duration
+	BackoffFactor float64
+	RetryOn       string
+	ErrorPatterns []*regexp.Regexp
+}
+
+// 计算第 attempt 次执行失败后、下一次重试前需等待的时间。
+func (p *retryPolicy) Delay(attempt int) time.Duration {
+	if attempt < 1 || p.InitialDelay <= 0 {
+		return 0
+	}
+
+	delay := float64(p.InitialDelay) * math.Pow(p.BackoffFactor, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// 判断错误是否可以重试。
+func (p *retryPolicy) IsRetryable(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// 工作流已终止、子节点异常（已由子节点自行处理重试）、或整个运行已被取消时，均不再重试
+	if errors.Is(err, ErrTerminated) || errors.Is(err, ErrBlocksException) {
+		return false
+	}
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	for _, re := range p.ErrorPatterns {
+		if re.MatchString(err.Error()) {
+			return true
+		}
+	}
+
+	var nonRetryableErr *NonRetryableError
+	if errors.As(err, &nonRetryableErr) {
+		return false
+	}
+
+	switch p.RetryOn {
+	case domain.WorkflowNodeRetryOnTransient:
+		return isTransientError(err)
+	default:
+		return true
+	}
+}
+
+func newRetryPolicy(node *Node) *retryPolicy {
+	policyData := node.Data.Retry
+	if policyData == nil {
+		switch node.Type {
+		case NodeTypeBizMonitor:
+			// 监控节点默认失败后重试，以兼容旧版行为
+			policyData = &domain.WorkflowNodeRetryPolicy{MaxAttempts: 3, InitialDelay: 2, BackoffFactor: 1}
+		default:
+			policyData = &domain.WorkflowNodeRetryPolicy{MaxAttempts: 1}
+		}
+	}
+
+	policy := &retryPolicy{
+		MaxAttempts:   max(1, policyData.MaxAttempts),
+		InitialDelay:  time.Duration(max(0, policyData.InitialDelay)) * time.Second,
+		MaxDelay:      time.Duration(max(0, policyData.MaxDelay)) * time.Second,
+		BackoffFactor: policyData.BackoffFactor,
+		RetryOn:       policyData.RetryOn,
+		ErrorPatterns: make([]*regexp.Regexp, 0, len(policyData.ErrorPatterns)),
+	}
+	if policy.BackoffFactor <= 0 {
+		policy.BackoffFactor = 2
+	}
+	if policy.RetryOn == "" {
+		policy.RetryOn = domain.WorkflowNodeRetryOnAny
+	}
+	for _, pattern := range policyData.ErrorPatterns {
+		if re, err := regexp.Compile(pattern); err == nil {
+			policy.ErrorPatterns = append(policy.ErrorPatterns, re)
+		}
+	}
+
+	return policy
+}
+
+var transientErrorKeywords = []string{
+	"timeout",
+	"timed out",
+	"temporarily unavailable",
+	"temporary failure",
+	"connection reset",
+	"connection refused",
+	"broken pipe",
+	"too many requests",
+	"rate limit",
+	"throttl",
+	"service unavailable",
+	"bad gateway",
+	"gateway timeout",
+	"internal server error",
+}
+
+// 判断错误是否为瞬时性错误，如网络异常、超时、限流或服务端 5xx 错误等。
+func isTransientError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	errmsg := strings.ToLower(err.Error())
+	for _, keyword := range transientErrorKeywords {
+		if strings.Contains(errmsg, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
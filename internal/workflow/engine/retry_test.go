@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+func TestNewRetryPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    *Node
+		expected *retryPolicy
+	}{
+		{
+			name:  "no retry by default",
+			input: &Node{Type: NodeTypeBizDeploy},
+			expected: &retryPolicy{
+				MaxAttempts:   1,
+				BackoffFactor: 2,
+				RetryOn:       domain.WorkflowNodeRetryOnAny,
+			},
+		},
+		{
+			name:  "monitor node retries by default",
+			input: &Node{Type: NodeTypeBizMonitor},
+			expected: &retryPolicy{
+				MaxAttempts:   3,
+				InitialDelay:  2 * time.Second,
+				BackoffFactor: 1,
+				RetryOn:       domain.WorkflowNodeRetryOnAny,
+			},
+		},
+		{
+			name: "custom policy",
+			input: &Node{Type: NodeTypeBizDeploy, Data: domain.WorkflowNodeData{Retry: &domain.WorkflowNodeRetryPolicy{
+				MaxAttempts:   5,
+				InitialDelay:  1,
+				MaxDelay:      10,
+				BackoffFactor: 3,
+				RetryOn:       domain.WorkflowNodeRetryOnTransient,
+				ErrorPatterns: []string{"^quota"},
+			}}},
+			expected: &retryPolicy{
+				MaxAttempts:   5,
+				InitialDelay:  time.Second,
+				MaxDelay:      10 * time.Second,
+				BackoffFactor: 3,
+				RetryOn:       domain.WorkflowNodeRetryOnTransient,
+				ErrorPatterns: []*regexp.Regexp{regexp.MustCompile("^quota")},
+			},
+		},
+		{
+			name: "invalid values fallback",
+			input: &Node{Type: NodeTypeBizDeploy, Data: domain.WorkflowNodeData{Retry: &domain.WorkflowNodeRetryPolicy{
+				MaxAttempts:   -1,
+				InitialDelay:  -1,
+				MaxDelay:      -1,
+				BackoffFactor: -1,
+				ErrorPatterns: []string{"("},
+			}}},
+			expected: &retryPolicy{
+				MaxAttempts:   1,
+				BackoffFactor: 2,
+				RetryOn:       domain.WorkflowNodeRetryOnAny,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := newRetryPolicy(tc.input)
+			if tc.expected.ErrorPatterns == nil {
+				tc.expected.ErrorPatterns = make([]*regexp.Regexp, 0)
+			}
+			assert.Equal(t, tc.expected, actual, "Case: %-20s", tc.name)
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := &retryPolicy{InitialDelay: time.Second, MaxDelay: 5 * time.Second, BackoffFactor: 2}
+
+	testCases := []struct {
+		input    int
+		expected time.Duration
+	}{
+		{0, 0},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, policy.Delay(tc.input), "Attempt: %d", tc.input)
+	}
+}
+
+func TestRetryPolicyIsRetryable(t *testing.T) {
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	type testInput struct {
+		policy *retryPolicy
+		ctx    context.Context
+		err    error
+	}
+	testCases := []struct {
+		name     string
+		input    testInput
+		expected bool
+	}{
+		{
+			name:     "nil error",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnAny}, err: nil},
+			expected: false,
+		},
+		{
+			name:     "any error",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnAny}, err: errors.New("invalid credentials")},
+			expected: true,
+		},
+		{
+			name:     "terminated",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnAny}, err: ErrTerminated},
+			expected: false,
+		},
+		{
+			name:     "blocks exception",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnAny}, err: fmt.Errorf("%w: foo", ErrBlocksException)},
+			expected: false,
+		},
+		{
+			name:     "run canceled",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnAny}, ctx: canceledCtx, err: errors.New("timeout")},
+			expected: false,
+		},
+		{
+			name:     "non-retryable error",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnAny}, err: &NonRetryableError{Err: errors.New("quota exceeded")}},
+			expected: false,
+		},
+		{
+			name:     "non-retryable error matched pattern",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnAny, ErrorPatterns: []*regexp.Regexp{regexp.MustCompile("^quota")}}, err: &NonRetryableError{Err: errors.New("quota exceeded")}},
+			expected: true,
+		},
+		{
+			name:     "transient error",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnTransient}, err: errors.New("503 Service Unavailable")},
+			expected: true,
+		},
+		{
+			name:     "transient wrapped eof",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnTransient}, err: fmt.Errorf("read body: %w", io.ErrUnexpectedEOF)},
+			expected: true,
+		},
+		{
+			name:     "transient node timeout",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnTransient}, err: fmt.Errorf("%w after 1s", ErrNodeTimedOut)},
+			expected: true,
+		},
+		{
+			name:     "not transient error",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnTransient}, err: errors.New("invalid credentials")},
+			expected: false,
+		},
+		{
+			name:     "not transient error matched pattern",
+			input:    testInput{policy: &retryPolicy{RetryOn: domain.WorkflowNodeRetryOnTransient, ErrorPatterns: []*regexp.Regexp{regexp.MustCompile("credentials")}}, err: errors.New("invalid credentials")},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := tc.input.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			actual := tc.input.policy.IsRetryable(ctx, tc.input.err)
+			assert.Equal(t, tc.expected, actual, "Case: %-20s", tc.name)
+		})
+	}
+}
@@ -1,11 +1,16 @@
 package engine
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/certimate-go/certimate/internal/domain"
 )
 
 type VariableState struct {
@@ -290,24 +295,128 @@ const (
 )
 
 const (
-	stateVarKeyWorkflowId                 = "workflow.id"                 // ValueType: "string"
-	stateVarKeyWorkflowName               = "workflow.name"               // ValueType: "string"
-	stateVarKeyWorkflowDescription        = "workflow.description"        // ValueType: "string"
-	stateVarKeyRunId                      = "run.id"                      // ValueType: "string"
-	stateVarKeyRunTrigger                 = "run.trigger"                 // ValueType: "string"
-	stateVarKeyNodeId                     = "node.id"                     // ValueType: "string"
-	stateVarKeyNodeName                   = "node.name"                   // ValueType: "string"
-	stateVarKeyNodeSkipped                = "node.skipped"                // ValueType: "boolean"
-	stateVarKeyErrorNodeId                = "error.nodeId"                // ValueType: "string"
-	stateVarKeyErrorNodeName              = "error.nodeName"              // ValueType: "string"
-	stateVarKeyErrorMessage               = "error.message"               // ValueType: "string"
-	stateVarKeyCertificateDomain          = "certificate.domain"          // 已废弃，仅为兼容旧版而保留，请使用 [stateVarKeyCertificateCommonName]
-	stateVarKeyCertificateDomains         = "certificate.domains"         // 已废弃，仅为兼容旧版而保留，请使用 [stateVarKeyCertificateSubjectAltNames]
-	stateVarKeyCertificateCommonName      = "certificate.commonName"      // ValueType: "string"
-	stateVarKeyCertificateSubjectAltNames = "certificate.subjectAltNames" // ValueType: "string"
-	stateVarKeyCertificateNotBefore       = "certificate.notBefore"       // ValueType: "datetime"
-	stateVarKeyCertificateNotAfter        = "certificate.notAfter"        // ValueType: "datetime"
-	stateVarKeyCertificateHoursLeft       = "certificate.hoursLeft"       // ValueType: "number"
-	stateVarKeyCertificateDaysLeft        = "certificate.daysLeft"        // ValueType: "number"
-	stateVarKeyCertificateValidity        = "certificate.validity"        // ValueType: "boolean"
+	stateVarKeyWorkflowId                           = "workflow.id"                           // ValueType: "string"
+	stateVarKeyWorkflowName                         = "workflow.name"                         // ValueType: "string"
+	stateVarKeyWorkflowDescription                  = "workflow.description"                  // ValueType: "string"
+	stateVarKeyRunId                                = "run.id"                                // ValueType: "string"
+	stateVarKeyRunTrigger                           = "run.trigger"                           // ValueType: "string"
+	stateVarKeyNodeId                               = "node.id"                               // ValueType: "string"
+	stateVarKeyNodeName                             = "node.name"                             // ValueType: "string"
+	stateVarKeyNodeSkipped                          = "node.skipped"                          // ValueType: "boolean"
+	stateVarKeyErrorNodeId                          = "error.nodeId"                          // ValueType: "string"
+	stateVarKeyErrorNodeName                        = "error.nodeName"                        // ValueType: "string"
+	stateVarKeyErrorMessage                         = "error.message"                         // ValueType: "string"
+	stateVarKeyCertificateDomain                    = "certificate.domain"                    // 已废弃，仅为兼容旧版而保留，请使用 [stateVarKeyCertificateCommonName]
+	stateVarKeyCertificateDomains                   = "certificate.domains"                   // 已废弃，仅为兼容旧版而保留，请使用 [stateVarKeyCertificateSubjectAltNames]
+	stateVarKeyCertificateCommonName                = "certificate.commonName"                // ValueType: "string"
+	stateVarKeyCertificateSubjectAltNames           = "certificate.subjectAltNames"           // ValueType: "string"
+	stateVarKeyCertificateNotBefore                 = "certificate.notBefore"                 // ValueType: "datetime"
+	stateVarKeyCertificateNotAfter                  = "certificate.notAfter"                  // ValueType: "datetime"
+	stateVarKeyCertificateHoursLeft                 = "certificate.hoursLeft"                 // ValueType: "number"
+	stateVarKeyCertificateDaysLeft                  = "certificate.daysLeft"                  // ValueType: "number"
+	stateVarKeyCertificateValidity                  = "certificate.validity"                  // ValueType: "boolean"
+	stateVarKeyCertificateChainTrusted              = "certificate.chainTrusted"              // ValueType: "boolean"
+	stateVarKeyCertificateChainMissingIntermediates = "certificate.chainMissingIntermediates" // ValueType: "boolean"
+	stateVarKeyCertificateChainExtraIntermediates   = "certificate.chainExtraIntermediates"   // ValueType: "boolean"
+	stateVarKeyCertificateOCSPStapled               = "certificate.ocspStapled"               // ValueType: "boolean"
+	stateVarKeyCertificateRevocationStatus          = "certificate.revocationStatus"          // ValueType: "string"
+	stateVarKeyCertificateMismatch                  = "certificate.mismatch"                  // ValueType: "boolean"
+	stateVarKeyCertificateSerials                   = "certificate.serials"                   // ValueType: "string"
+	stateVarKeyTLSVersions                          = "tls.versions"                          // ValueType: "string"
+	stateVarKeyTLSWeakCipherSuites                  = "tls.weakCipherSuites"                  // ValueType: "string"
+	stateVarKeyTriggerPrefix                        = "trigger."                              // 事件触发时携带的数据，如 "trigger.payload.foo"
+	stateVarKeyParamsPrefix                         = "params."                               // 调用子工作流时传入的参数，如 "params.foo"
 )
+
+func init() {
+	// 向工作流校验器注册各类型节点可能产生的节点作用域变量名
+	// 子工作流节点的变量取决于被调用的工作流，无法静态确定，因此不注册
+	nodeKeys := []string{
+		stateVarKeyNodeId,
+		stateVarKeyNodeName,
+	}
+	certificateKeys := []string{
+		stateVarKeyCertificateDomain,
+		stateVarKeyCertificateDomains,
+		stateVarKeyCertificateCommonName,
+		stateVarKeyCertificateSubjectAltNames,
+		stateVarKeyCertificateNotBefore,
+		stateVarKeyCertificateNotAfter,
+		stateVarKeyCertificateHoursLeft,
+		stateVarKeyCertificateDaysLeft,
+		stateVarKeyCertificateValidity,
+	}
+	monitorKeys := []string{
+		stateVarKeyCertificateChainTrusted,
+		stateVarKeyCertificateChainMissingIntermediates,
+		stateVarKeyCertificateChainExtraIntermediates,
+		stateVarKeyCertificateOCSPStapled,
+		stateVarKeyCertificateRevocationStatus,
+		stateVarKeyCertificateMismatch,
+		stateVarKeyCertificateSerials,
+		stateVarKeyTLSVersions,
+		stateVarKeyTLSWeakCipherSuites,
+	}
+
+	for _, nodeType := range []NodeType{
+		NodeTypeStart,
+		NodeTypeEnd,
+		NodeTypeCondition,
+		NodeTypeBranchBlock,
+		NodeTypeTryCatch,
+		NodeTypeTryBlock,
+		NodeTypeCatchBlock,
+		NodeTypeParallel,
+		NodeTypeParallelBlock,
+		NodeTypeDelay,
+		NodeTypeBizNotify,
+	} {
+		domain.RegisterWorkflowNodeVariableNames(nodeType, nodeKeys...)
+	}
+	domain.RegisterWorkflowNodeVariableNames(NodeTypeBizApply, slices.Concat(nodeKeys, []string{stateVarKeyNodeSkipped}, certificateKeys)...)
+	domain.RegisterWorkflowNodeVariableNames(NodeTypeBizUpload, slices.Concat(nodeKeys, []string{stateVarKeyNodeSkipped}, certificateKeys)...)
+	domain.RegisterWorkflowNodeVariableNames(NodeTypeBizMonitor, slices.Concat(nodeKeys, certificateKeys, monitorKeys)...)
+	domain.RegisterWorkflowNodeVariableNames(NodeTypeBizDeploy, slices.Concat(nodeKeys, []string{stateVarKeyNodeSkipped})...)
+}
+
+// 将事件触发时携带的数据或子工作流参数等展开为以 prefix 为前缀的全局变量。
+// 嵌套对象的键以 "." 连接；数组等无法直接表示的值将序列化为 JSON 字符串。
+func flattenVariables(prefix string, data map[string]any) []VariableState {
+	states := make([]VariableState, 0)
+
+	var walk func(prefix string, value any)
+	walk = func(prefix string, value any) {
+		switch v := value.(type) {
+		case nil:
+			return
+		case map[string]any:
+			for k, item := range v {
+				walk(prefix+"."+k, item)
+			}
+		case string:
+			states = append(states, VariableState{Key: prefix, Value: v, ValueType: stateValTypeString})
+		case bool:
+			states = append(states, VariableState{Key: prefix, Value: v, ValueType: stateValTypeBoolean})
+		case float64:
+			if v == math.Trunc(v) {
+				states = append(states, VariableState{Key: prefix, Value: int64(v), ValueType: stateValTypeNumber})
+			} else {
+				states = append(states, VariableState{Key: prefix, Value: strconv.FormatFloat(v, 'f', -1, 64), ValueType: stateValTypeString})
+			}
+		case int, int32, int64:
+			states = append(states, VariableState{Key: prefix, Value: v, ValueType: stateValTypeNumber})
+		default:
+			bytes, err := json.Marshal(v)
+			if err != nil {
+				return
+			}
+			states = append(states, VariableState{Key: prefix, Value: string(bytes), ValueType: stateValTypeString})
+		}
+	}
+	for k, v := range data {
+		walk(prefix+k, v)
+	}
+
+	slices.SortFunc(states, func(a, b VariableState) int { return strings.Compare(a.Key, b.Key) })
+	return states
+}
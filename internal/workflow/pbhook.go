@@ -2,8 +2,11 @@ package workflow
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
 
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/domain"
@@ -12,6 +15,10 @@ import (
 func registerWorkflowRecordEvents() {
 	pb := app.GetApp()
 	pb.OnRecordCreateRequest(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordRequestEvent) error {
+		if err := validateWorkflowRecordGraph(e.Request.Context(), e.Record); err != nil {
+			return err
+		}
+
 		if err := e.Next(); err != nil {
 			return err
 		}
@@ -24,6 +31,10 @@ func registerWorkflowRecordEvents() {
 		return nil
 	})
 	pb.OnRecordUpdateRequest(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordRequestEvent) error {
+		if err := validateWorkflowRecordGraph(e.Request.Context(), e.Record); err != nil {
+			return err
+		}
+
 		if err := e.Next(); err != nil {
 			return err
 		}
@@ -47,9 +58,83 @@ func registerWorkflowRecordEvents() {
 
 		return nil
 	})
+	pb.OnRecordUpdate(domain.CollectionNameWorkflowRun).BindFunc(func(e *core.RecordEvent) error {
+		lastStatus := e.Record.Original().GetString("status")
+
+		if err := e.Next(); err != nil {
+			return err
+		}
+
+		onWorkflowRunRecordUpdate(e.Context, e.App, e.Record, lastStatus)
+		return nil
+	})
 }
 
-func onWorkflowRecordCreateOrUpdate(_ context.Context, _ core.App, record *core.Record) error {
+func validateWorkflowRecordGraph(ctx context.Context, record *core.Record) error {
+	// 仅在发布内容变更时校验，草稿允许暂存不完整的工作流图
+	graphContent := record.GetString("graphContent")
+	if graphContent == "" || graphContent == "null" {
+		return nil
+	} else if !record.IsNew() && graphContent == record.Original().GetString("graphContent") {
+		return nil
+	}
+
+	graph := &domain.WorkflowGraph{}
+	if err := record.UnmarshalJSONField("graphContent", graph); err != nil {
+		return router.NewBadRequestError("Failed to parse workflow graph content.", err)
+	}
+
+	issues := graph.Validate()
+	if warnings := issues.Warnings(); len(warnings) > 0 {
+		app.GetLogger().Warn(fmt.Sprintf("workflow #%s graph content has %d warning(s):\n%s", record.Id, len(warnings), warnings.String()))
+	}
+
+	if errs := issues.Errors(); len(errs) > 0 {
+		data := make(map[string]router.SafeErrorItem, len(errs))
+		for i, issue := range errs {
+			data[strconv.Itoa(i)] = workflowGraphIssueError{issue}
+		}
+		return router.NewBadRequestError("Invalid workflow graph content.", map[string]any{"graphContent": data})
+	}
+
+	// 检查子工作流之间是否存在循环引用，与运行、回滚时的校验保持一致
+	if err := graph.VerifyWithResolver(record.Id, thisSvcInst().resolveWorkflowGraph(ctx)); err != nil {
+		return router.NewBadRequestError(fmt.Sprintf("Invalid workflow graph content: %s.", err.Error()), err)
+	}
+
+	return nil
+}
+
+// 将工作流图的校验问题包装为 PocketBase 可安全输出的错误项。
+type workflowGraphIssueError struct {
+	*domain.WorkflowGraphIssue
+}
+
+func (e workflowGraphIssueError) Code() string {
+	return "validation_invalid_workflow_graph"
+}
+
+func (e workflowGraphIssueError) Params() map[string]any {
+	return map[string]any{
+		"nodeId":   e.NodeId,
+		"nodeName": e.NodeName,
+		"level":    e.Level,
+	}
+}
+
+func onWorkflowRecordCreateOrUpdate(ctx context.Context, _ core.App, record *core.Record) error {
+	// 发布内容变更时，记录为新的版本
+	if graphContent := record.GetString("graphContent"); graphContent != "" && graphContent != "null" {
+		graph := &domain.WorkflowGraph{}
+		if err := record.UnmarshalJSONField("graphContent", graph); err != nil {
+			return err
+		}
+
+		if _, err := thisSvcInst().publishVersion(ctx, record.Id, graph, ""); err != nil {
+			return fmt.Errorf("failed to save workflow version: %w", err)
+		}
+	}
+
 	scheduler := app.GetScheduler()
 
 	// 向数据库插入/更新时，同时更新定时任务
@@ -79,3 +164,32 @@ func onWorkflowRecordDelete(_ context.Context, _ core.App, record *core.Record)
 
 	return nil
 }
+
+func onWorkflowRunRecordUpdate(_ context.Context, _ core.App, record *core.Record, lastStatus string) {
+	// 运行结束时，触发以其为上游的工作流
+	status := domain.WorkflowRunStatusType(record.GetString("status"))
+	if status.String() == lastStatus {
+		return
+	}
+
+	switch status {
+	case domain.WorkflowRunStatusTypeSucceeded, domain.WorkflowRunStatusTypeFailed, domain.WorkflowRunStatusTypeCanceled:
+	default:
+		return
+	}
+
+	triggerData := make(map[string]any)
+	record.UnmarshalJSONField("triggerData", &triggerData)
+
+	workflowRun := &domain.WorkflowRun{
+		Meta:        domain.Meta{Id: record.Id},
+		WorkflowId:  record.GetString("workflowRef"),
+		Status:      status,
+		Trigger:     domain.WorkflowTriggerType(record.GetString("trigger")),
+		TriggerData: triggerData,
+		Error:       record.GetString("error"),
+	}
+
+	// 此时可能仍处于事务中，需异步执行
+	go thisSvcInst().triggerOnWorkflowCompleted(context.Background(), workflowRun)
+}
@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -19,16 +20,22 @@ import (
 type WorkflowService struct {
 	dispatcher dispatcher.WorkflowDispatcher
 
-	workflowRepo    workflowRepository
-	workflowRunRepo workflowRunRepository
+	workflowRepo              workflowRepository
+	workflowRunRepo           workflowRunRepository
+	workflowVersionRepo       workflowVersionRepository
+	workflowTriggerDedupeRepo workflowTriggerDedupeRepository
+	certificateRepo           certificateRepository
 }
 
-func NewWorkflowService(workflowRepo workflowRepository, workflowRunRepo workflowRunRepository) *WorkflowService {
+func NewWorkflowService(workflowRepo workflowRepository, workflowRunRepo workflowRunRepository, workflowVersionRepo workflowVersionRepository, workflowTriggerDedupeRepo workflowTriggerDedupeRepository, certificateRepo certificateRepository) *WorkflowService {
 	srv := &WorkflowService{
 		dispatcher: dispatcher.GetSingletonDispatcher(),
 
-		workflowRepo:    workflowRepo,
-		workflowRunRepo: workflowRunRepo,
+		workflowRepo:              workflowRepo,
+		workflowRunRepo:           workflowRunRepo,
+		workflowVersionRepo:       workflowVersionRepo,
+		workflowTriggerDedupeRepo: workflowTriggerDedupeRepo,
+		certificateRepo:           certificateRepo,
 	}
 	return srv
 }
@@ -37,6 +44,12 @@ func (s *WorkflowService) InitSchedule(ctx context.Context) error {
 	// 每日清理工作流运行历史
 	app.GetScheduler().MustAdd("cleanupWorkflowHistoryRuns", "0 0 * * *", func() {
 		s.cleanupHistoryRuns(context.Background())
+		s.cleanupExpiredTriggerDedupes(context.Background())
+	})
+
+	// 每小时检查即将到期的证书，并触发相应的工作流
+	app.GetScheduler().MustAdd("triggerWorkflowsOnCertificateExpiring", "0 * * * *", func() {
+		s.triggerOnCertificateExpiring(context.Background())
 	})
 
 	// 初始化工作流调度器
@@ -80,20 +93,39 @@ func (s *WorkflowService) StartRun(ctx context.Context, req *dtos.WorkflowStartR
 		return nil, err
 	}
 
-	if req.RunTrigger == domain.WorkflowTriggerTypeManual && (workflow.LastRunStatus == domain.WorkflowRunStatusTypePending || workflow.LastRunStatus == domain.WorkflowRunStatusTypeProcessing) {
+	// 试运行时优先使用尚未发布的草稿，以便在发布前预览其执行计划
+	graph := workflow.GraphContent
+	usingDraft := false
+	if req.RunTrigger == domain.WorkflowTriggerTypeDryRun && workflow.HasDraft && workflow.GraphDraft != nil {
+		graph = workflow.GraphDraft
+		usingDraft = true
+	}
+
+	if (req.RunTrigger == domain.WorkflowTriggerTypeManual || req.RunTrigger == domain.WorkflowTriggerTypeDryRun) && (workflow.LastRunStatus == domain.WorkflowRunStatusTypePending || workflow.LastRunStatus == domain.WorkflowRunStatusTypeProcessing) {
 		return nil, fmt.Errorf("workflow is already pending or processing")
-	} else if workflow.GraphContent == nil {
+	} else if graph == nil {
 		return nil, fmt.Errorf("workflow graph content is empty")
-	} else if err := workflow.GraphContent.Verify(); err != nil {
+	} else if err := graph.VerifyWithResolver(workflow.Id, s.resolveWorkflowGraph(ctx)); err != nil {
 		return nil, fmt.Errorf("workflow graph content is invalid: %w", err)
 	}
 
 	workflowRun := &domain.WorkflowRun{
-		WorkflowId: workflow.Id,
-		Status:     domain.WorkflowRunStatusTypePending,
-		Trigger:    req.RunTrigger,
-		StartedAt:  time.Now(),
-		Graph:      workflow.GraphContent.Clone(),
+		WorkflowId:  workflow.Id,
+		Status:      domain.WorkflowRunStatusTypePending,
+		Trigger:     req.RunTrigger,
+		TriggerData: req.RunTriggerData,
+		StartedAt:   time.Now(),
+		Graph:       graph.Clone(),
+	}
+	if !usingDraft {
+		// 记录本次运行所执行的版本，草稿尚未发布，因此不关联任何版本
+		if version, err := s.workflowVersionRepo.GetLatestByWorkflowId(ctx, workflow.Id); err != nil {
+			if !domain.IsRecordNotFoundError(err) {
+				return nil, err
+			}
+		} else {
+			workflowRun.VersionId = version.Id
+		}
 	}
 	if resp, err := s.workflowRunRepo.Save(ctx, workflowRun); err != nil {
 		return nil, err
@@ -130,6 +162,84 @@ func (s *WorkflowService) CancelRun(ctx context.Context, req *dtos.WorkflowCance
 	return &dtos.WorkflowCancelRunResp{}, nil
 }
 
+func (s *WorkflowService) ResumeRun(ctx context.Context, req *dtos.WorkflowResumeRunReq) (*dtos.WorkflowResumeRunResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	if workflow.LastRunStatus == domain.WorkflowRunStatusTypePending || workflow.LastRunStatus == domain.WorkflowRunStatusTypeProcessing {
+		return nil, fmt.Errorf("workflow is already pending or processing")
+	}
+
+	failedRun, err := s.workflowRunRepo.GetById(ctx, req.RunId)
+	if err != nil {
+		return nil, err
+	} else if failedRun.WorkflowId != workflow.Id {
+		return nil, fmt.Errorf("workflow run not found")
+	} else if failedRun.Status != domain.WorkflowRunStatusTypeFailed {
+		return nil, fmt.Errorf("workflow run is not failed")
+	} else if failedRun.ErrorNodeId == "" {
+		return nil, fmt.Errorf("workflow run has no failed node to resume from")
+	} else if failedRun.Graph == nil {
+		return nil, fmt.Errorf("workflow run graph is empty")
+	}
+
+	// 使用失败运行时的工作流图，而非当前最新发布的版本，以保证节点一致；
+	// 同时沿用其触发方式及触发数据，以保证 "trigger.*" 变量一致
+	workflowRun := &domain.WorkflowRun{
+		WorkflowId:       workflow.Id,
+		VersionId:        failedRun.VersionId,
+		Status:           domain.WorkflowRunStatusTypePending,
+		Trigger:          failedRun.Trigger,
+		TriggerData:      failedRun.TriggerData,
+		StartedAt:        time.Now(),
+		Graph:            failedRun.Graph.Clone(),
+		ResumeFromRunId:  failedRun.Id,
+		ResumeFromNodeId: failedRun.ErrorNodeId,
+	}
+	if resp, err := s.workflowRunRepo.Save(ctx, workflowRun); err != nil {
+		return nil, err
+	} else {
+		workflowRun = resp
+	}
+
+	if err := s.dispatcher.Start(ctx, workflowRun.Id); err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowResumeRunResp{RunId: workflowRun.Id}, nil
+}
+
+func (s *WorkflowService) TriggerWebhook(ctx context.Context, req *dtos.WorkflowTriggerWebhookReq) (*dtos.WorkflowTriggerWebhookResp, error) {
+	// 无论工作流是否存在或密钥是否匹配，均返回相同的错误，以免泄露工作流信息
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, domain.ErrUnauthorized
+		}
+		return nil, err
+	} else if !workflow.Enabled || workflow.Trigger != domain.WorkflowTriggerTypeWebhook {
+		return nil, domain.ErrUnauthorized
+	}
+
+	secret := workflow.TriggerConfig.AsWebhook().Secret
+	if secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(req.Secret)) != 1 {
+		return nil, domain.ErrUnauthorized
+	}
+
+	resp, err := s.StartRun(ctx, &dtos.WorkflowStartRunReq{
+		WorkflowId:     workflow.Id,
+		RunTrigger:     domain.WorkflowTriggerTypeWebhook,
+		RunTriggerData: map[string]any{"payload": req.Payload},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowTriggerWebhookResp{RunId: resp.RunId}, nil
+}
+
 func (s *WorkflowService) Shutdown(ctx context.Context) {
 	s.dispatcher.Shutdown(ctx)
 }
@@ -154,3 +264,28 @@ func (s *WorkflowService) cleanupHistoryRuns(ctx context.Context) error {
 
 	return nil
 }
+
+func (s *WorkflowService) cleanupExpiredTriggerDedupes(ctx context.Context) error {
+	ret, err := s.workflowTriggerDedupeRepo.DeleteWithExprs(ctx, dbx.NewExp("expireAt<DATETIME('now')"))
+	if err != nil {
+		app.GetLogger().Error("failed to delete expired workflow trigger dedupes", slog.Any("error", err))
+		return err
+	}
+
+	if ret > 0 {
+		app.GetLogger().Info(fmt.Sprintf("cleanup %d expired workflow trigger dedupes", ret))
+	}
+
+	return nil
+}
+
+func (s *WorkflowService) resolveWorkflowGraph(ctx context.Context) domain.WorkflowGraphResolver {
+	return func(workflowId string) (*domain.WorkflowGraph, error) {
+		workflow, err := s.workflowRepo.GetById(ctx, workflowId)
+		if err != nil {
+			return nil, err
+		}
+
+		return workflow.GraphContent, nil
+	}
+}
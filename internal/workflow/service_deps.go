@@ -10,6 +10,7 @@ import (
 
 type workflowRepository interface {
 	ListEnabledScheduled(ctx context.Context) ([]*domain.Workflow, error)
+	ListEnabledByTrigger(ctx context.Context, trigger domain.WorkflowTriggerType) ([]*domain.Workflow, error)
 	GetById(ctx context.Context, id string) (*domain.Workflow, error)
 	Save(ctx context.Context, workflow *domain.Workflow) (*domain.Workflow, error)
 }
@@ -20,3 +21,20 @@ type workflowRunRepository interface {
 	SaveWithCascading(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error)
 	DeleteWithExprs(ctx context.Context, exprs ...dbx.Expression) (int, error)
 }
+
+type workflowVersionRepository interface {
+	ListByWorkflowId(ctx context.Context, workflowId string) ([]*domain.WorkflowVersion, error)
+	GetById(ctx context.Context, id string) (*domain.WorkflowVersion, error)
+	GetLatestByWorkflowId(ctx context.Context, workflowId string) (*domain.WorkflowVersion, error)
+	Save(ctx context.Context, workflowVersion *domain.WorkflowVersion) (*domain.WorkflowVersion, error)
+}
+
+type workflowTriggerDedupeRepository interface {
+	ExistsByWorkflowIdAndKey(ctx context.Context, workflowId string, key string) (bool, error)
+	Save(ctx context.Context, dedupe *domain.WorkflowTriggerDedupe) (*domain.WorkflowTriggerDedupe, error)
+	DeleteWithExprs(ctx context.Context, exprs ...dbx.Expression) (int, error)
+}
+
+type certificateRepository interface {
+	ListExpiringWithinDays(ctx context.Context, days int) ([]*domain.Certificate, error)
+}
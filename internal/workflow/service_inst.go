@@ -16,6 +16,9 @@ func thisSvcInst() *WorkflowService {
 		thisSvc = NewWorkflowService(
 			repository.NewWorkflowRepository(),
 			repository.NewWorkflowRunRepository(),
+			repository.NewWorkflowVersionRepository(),
+			repository.NewWorkflowTriggerDedupeRepository(),
+			repository.NewCertificateRepository(),
 		)
 	})
 	return thisSvc
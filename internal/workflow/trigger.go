@@ -0,0 +1,178 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/settings"
+	xcerthostname "github.com/certimate-go/certimate/pkg/utils/cert/hostname"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+// 工作流之间链式触发的最大深度，用于避免互相触发导致的无限循环。
+const maxWorkflowCompletedTriggerDepth = 8
+
+func (s *WorkflowService) triggerOnCertificateExpiring(ctx context.Context) error {
+	workflows, err := s.workflowRepo.ListEnabledByTrigger(ctx, domain.WorkflowTriggerTypeCertificateExpiring)
+	if err != nil {
+		app.GetLogger().Error("failed to list workflows triggered by certificate expiring", slog.Any("error", err))
+		return err
+	} else if len(workflows) == 0 {
+		return nil
+	}
+
+	globalSettingsForPersistence := settings.GetGlobalSettingsForPersistence()
+
+	var errs []error
+	certificatesByDays := make(map[int][]*domain.Certificate)
+	for _, workflow := range workflows {
+		triggerCfg := workflow.TriggerConfig.AsCertificateExpiring()
+
+		days := triggerCfg.DaysBeforeExpire
+		if days <= 0 {
+			days = globalSettingsForPersistence.CertificatesWarningDaysBeforeExpire
+		}
+
+		certificates, ok := certificatesByDays[days]
+		if !ok {
+			certificates, err = s.certificateRepo.ListExpiringWithinDays(ctx, days)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			certificatesByDays[days] = certificates
+		}
+
+		for _, certificate := range certificates {
+			if !matchCertificateDomains(certificate, triggerCfg.Domains) {
+				continue
+			}
+
+			// 每张证书只触发一次，避免在到期前重复触发
+			dedupeKey := fmt.Sprintf("certificateExpiring:%s", certificate.Id)
+			if triggered, err := s.workflowTriggerDedupeRepo.ExistsByWorkflowIdAndKey(ctx, workflow.Id, dedupeKey); err != nil {
+				errs = append(errs, err)
+				continue
+			} else if triggered {
+				continue
+			}
+
+			app.GetLogger().Info(fmt.Sprintf("workflow #%s is triggered by certificate #%s expiring ...", workflow.Id, certificate.Id))
+
+			_, err := s.StartRun(ctx, &dtos.WorkflowStartRunReq{
+				WorkflowId: workflow.Id,
+				RunTrigger: domain.WorkflowTriggerTypeCertificateExpiring,
+				RunTriggerData: map[string]any{
+					"certificateId":              certificate.Id,
+					"certificateSubjectAltNames": certificate.SubjectAltNames,
+					"certificateNotAfter":        certificate.ValidityNotAfter.Format(time.RFC3339),
+					"certificateDaysLeft":        int(time.Until(certificate.ValidityNotAfter).Hours() / 24),
+				},
+			})
+			if err != nil {
+				app.GetLogger().Warn(fmt.Sprintf("failed to start run for workflow #%s", workflow.Id), slog.Any("error", err))
+				errs = append(errs, err)
+				continue
+			}
+
+			// 去重记录保留至证书到期，此后该证书不会再满足触发条件
+			if _, err := s.workflowTriggerDedupeRepo.Save(ctx, &domain.WorkflowTriggerDedupe{
+				WorkflowId: workflow.Id,
+				Key:        dedupeKey,
+				ExpireAt:   certificate.ValidityNotAfter,
+			}); err != nil {
+				app.GetLogger().Warn(fmt.Sprintf("failed to save trigger dedupe for workflow #%s", workflow.Id), slog.Any("error", err))
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+func (s *WorkflowService) triggerOnWorkflowCompleted(ctx context.Context, workflowRun *domain.WorkflowRun) error {
+	// 试运行不会产生实际的变更，因此不触发下游工作流
+	if workflowRun.Trigger == domain.WorkflowTriggerTypeDryRun {
+		return nil
+	}
+
+	depth := 0
+	if workflowRun.Trigger == domain.WorkflowTriggerTypeWorkflowCompleted {
+		depth = xmaps.GetInt(workflowRun.TriggerData, "depth")
+	}
+	if depth >= maxWorkflowCompletedTriggerDepth {
+		app.GetLogger().Warn(fmt.Sprintf("workflow run #%s reached the max chain depth, downstream workflows will not be triggered", workflowRun.Id))
+		return nil
+	}
+
+	workflows, err := s.workflowRepo.ListEnabledByTrigger(ctx, domain.WorkflowTriggerTypeWorkflowCompleted)
+	if err != nil {
+		app.GetLogger().Error("failed to list workflows triggered by workflow completed", slog.Any("error", err))
+		return err
+	}
+
+	var errs []error
+	for _, workflow := range workflows {
+		if workflow.Id == workflowRun.WorkflowId {
+			continue
+		}
+
+		triggerCfg := workflow.TriggerConfig.AsWorkflowCompleted()
+		if triggerCfg.WorkflowId != workflowRun.WorkflowId || !slices.Contains(triggerCfg.Statuses, workflowRun.Status) {
+			continue
+		}
+
+		app.GetLogger().Info(fmt.Sprintf("workflow #%s is triggered by workflow #%s completed ...", workflow.Id, workflowRun.WorkflowId))
+
+		_, err := s.StartRun(ctx, &dtos.WorkflowStartRunReq{
+			WorkflowId: workflow.Id,
+			RunTrigger: domain.WorkflowTriggerTypeWorkflowCompleted,
+			RunTriggerData: map[string]any{
+				"workflowId": workflowRun.WorkflowId,
+				"runId":      workflowRun.Id,
+				"status":     workflowRun.Status.String(),
+				"error":      workflowRun.Error,
+				"depth":      depth + 1,
+			},
+		})
+		if err != nil {
+			app.GetLogger().Warn(fmt.Sprintf("failed to start run for workflow #%s", workflow.Id), slog.Any("error", err))
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+func matchCertificateDomains(certificate *domain.Certificate, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, san := range strings.Split(certificate.SubjectAltNames, ";") {
+		for _, pattern := range patterns {
+			if xcerthostname.IsMatch(pattern, san) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
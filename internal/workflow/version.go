@@ -0,0 +1,147 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+func (s *WorkflowService) ListVersions(ctx context.Context, req *dtos.WorkflowListVersionsReq) (*dtos.WorkflowListVersionsResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.workflowVersionRepo.ListByWorkflowId(ctx, workflow.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowListVersionsResp{Versions: versions}, nil
+}
+
+func (s *WorkflowService) DiffVersions(ctx context.Context, req *dtos.WorkflowDiffVersionsReq) (*dtos.WorkflowDiffVersionsResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	fromVersion, err := s.getVersionOfWorkflow(ctx, workflow.Id, req.FromVersionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 未指定目标版本时，与当前已发布的内容进行比较
+	resp := &dtos.WorkflowDiffVersionsResp{FromVersion: fromVersion.Version}
+	toGraph := workflow.GraphContent
+	if req.ToVersionId != "" {
+		toVersion, err := s.getVersionOfWorkflow(ctx, workflow.Id, req.ToVersionId)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.ToVersion = toVersion.Version
+		toGraph = toVersion.GraphContent
+	}
+
+	resp.Diff = domain.DiffWorkflowGraphs(fromVersion.GraphContent, toGraph)
+	return resp, nil
+}
+
+func (s *WorkflowService) RollbackVersion(ctx context.Context, req *dtos.WorkflowRollbackVersionReq) (*dtos.WorkflowRollbackVersionResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	targetVersion, err := s.getVersionOfWorkflow(ctx, workflow.Id, req.VersionId)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := targetVersion.GraphContent
+	if graph == nil || len(graph.Nodes) == 0 {
+		return nil, fmt.Errorf("workflow version graph content is empty")
+	} else if err := graph.VerifyWithResolver(workflow.Id, s.resolveWorkflowGraph(ctx)); err != nil {
+		return nil, fmt.Errorf("workflow version graph content is invalid: %w", err)
+	}
+
+	// 回滚后草稿一并重置，避免未发布的修改覆盖回滚结果
+	workflow.GraphContent = graph.Clone()
+	workflow.GraphDraft = graph.Clone()
+	workflow.HasContent = true
+	workflow.HasDraft = false
+	if _, err := s.workflowRepo.Save(ctx, workflow); err != nil {
+		return nil, err
+	}
+
+	version, err := s.publishVersion(ctx, workflow.Id, workflow.GraphContent, fmt.Sprintf("rollback to v%d", targetVersion.Version))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowRollbackVersionResp{
+		VersionId: version.Id,
+		Version:   version.Version,
+	}, nil
+}
+
+// 将工作流图记录为一个新版本。若与最新版本的内容一致，则直接返回最新版本。
+func (s *WorkflowService) publishVersion(ctx context.Context, workflowId string, graph *domain.WorkflowGraph, description string) (*domain.WorkflowVersion, error) {
+	latestVersion, err := s.workflowVersionRepo.GetLatestByWorkflowId(ctx, workflowId)
+	if err != nil && !domain.IsRecordNotFoundError(err) {
+		return nil, err
+	}
+
+	nextVersion := 1
+	if latestVersion != nil {
+		if equal, err := isWorkflowGraphEqual(latestVersion.GraphContent, graph); err != nil {
+			return nil, err
+		} else if equal {
+			return latestVersion, nil
+		}
+
+		nextVersion = latestVersion.Version + 1
+	}
+
+	return s.workflowVersionRepo.Save(ctx, &domain.WorkflowVersion{
+		WorkflowId:   workflowId,
+		Version:      nextVersion,
+		GraphContent: graph,
+		Description:  description,
+	})
+}
+
+func (s *WorkflowService) getVersionOfWorkflow(ctx context.Context, workflowId string, versionId string) (*domain.WorkflowVersion, error) {
+	if versionId == "" {
+		return nil, fmt.Errorf("workflow version id is required")
+	}
+
+	version, err := s.workflowVersionRepo.GetById(ctx, versionId)
+	if err != nil {
+		return nil, err
+	} else if version.WorkflowId != workflowId {
+		return nil, fmt.Errorf("workflow version not found")
+	}
+
+	return version, nil
+}
+
+func isWorkflowGraphEqual(a, b *domain.WorkflowGraph) (bool, error) {
+	// 节点配置为任意结构，以序列化后的结果比较可避免数字类型等差异带来的误判
+	aBytes, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+
+	bBytes, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(aBytes, bBytes), nil
+}
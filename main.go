@@ -15,6 +15,7 @@ import (
 
 	"github.com/certimate-go/certimate/cmd"
 	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/dnsresponder"
 	"github.com/certimate-go/certimate/internal/rest/routes"
 	"github.com/certimate-go/certimate/internal/scheduler"
 	"github.com/certimate-go/certimate/internal/settings"
@@ -42,6 +43,12 @@ func main() {
 
 	isServeCmd := slices.Contains(os.Args[1:], "serve")
 
+	dnsresponderConfig := dnsresponder.Config{}
+	pb.RootCmd.PersistentFlags().StringVar(&dnsresponderConfig.Zone, "dnsResponderZone", "", "the zone served by the built-in authoritative DNS responder for DNS-01 challenges (disabled if empty)")
+	pb.RootCmd.PersistentFlags().StringVar(&dnsresponderConfig.ListenAddress, "dnsResponderListen", ":53", "the listen address of the built-in authoritative DNS responder")
+	pb.RootCmd.PersistentFlags().StringVar(&dnsresponderConfig.Nameserver, "dnsResponderNS", "", "the nameserver hostname of the built-in authoritative DNS responder (default \"ns.<zone>\")")
+	pb.RootCmd.PersistentFlags().IntVar(&dnsresponderConfig.TTL, "dnsResponderTTL", 60, "the TTL of the answers of the built-in authoritative DNS responder")
+
 	if isServeCmd {
 		pb.OnBootstrap().BindFunc(func(e *core.BootstrapEvent) error {
 			if err := e.Next(); err != nil {
@@ -65,6 +72,10 @@ func main() {
 		pb.OnServe().BindFunc(func(e *core.ServeEvent) error {
 			scheduler.Setup()
 			workflow.Setup()
+			if err := dnsresponder.Setup(&dnsresponderConfig); err != nil {
+				app.GetLogger().Error("failed to setup the built-in dns responder, dns-01 challenges delegated to it will not be served", slog.Any("error", err))
+				slog.Error("[CERTIMATE] Failed to setup the built-in DNS responder.", slog.Any("error", err))
+			}
 			routes.BindRouter(e.Router)
 
 			if err := e.Next(); err != nil {
@@ -78,6 +89,7 @@ func main() {
 		pb.OnTerminate().BindFunc(func(e *core.TerminateEvent) error {
 			if pb.IsBootstrapped() {
 				workflow.Teardown()
+				dnsresponder.Teardown()
 			}
 
 			return e.Next()
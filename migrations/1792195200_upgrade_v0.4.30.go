@@ -0,0 +1,1407 @@
+package migrations
+
+import (
+	"errors"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tracer := NewTracer("v0.4.30")
+		tracer.Printf("go ...")
+
+		// update collection `workflow`
+		//   - modify field `trigger`
+		//   - add field `triggerConfig`
+		//   - add field `runTimeout`
+		{
+			collection, err := app.FindCollectionByNameOrId("tovyif5ax6j62ur")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(5, []byte(`{
+				"hidden": false,
+				"id": "number1710137423",
+				"max": null,
+				"min": 0,
+				"name": "runTimeout",
+				"onlyInt": true,
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "number"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(3, []byte(`{
+				"hidden": false,
+				"id": "vqoajwjq",
+				"maxSelect": 1,
+				"name": "trigger",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "select",
+				"values": [
+					"manual",
+					"scheduled",
+					"webhook",
+					"certificateExpiring",
+					"workflowCompleted"
+				]
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(5, []byte(`{
+				"hidden": false,
+				"id": "json3326734405",
+				"maxSize": 0,
+				"name": "triggerConfig",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "json"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// update collection `workflow_run`
+		//   - modify field `trigger`
+		//   - add field `errorNodeId`
+		//   - add field `resumeFromRunRef`
+		//   - add field `resumeFromNodeId`
+		//   - add field `plan`
+		//   - add field `triggerData`
+		{
+			collection, err := app.FindCollectionByNameOrId("qjp8lygssgwyqyz")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(3, []byte(`{
+				"hidden": false,
+				"id": "jlroa3fk",
+				"maxSelect": 1,
+				"name": "trigger",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "select",
+				"values": [
+					"manual",
+					"scheduled",
+					"dryrun",
+					"webhook",
+					"certificateExpiring",
+					"workflowCompleted"
+				]
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(8, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": false,
+				"id": "text2493127618",
+				"max": 0,
+				"min": 0,
+				"name": "errorNodeId",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(9, []byte(`{
+				"cascadeDelete": false,
+				"collectionId": "qjp8lygssgwyqyz",
+				"hidden": false,
+				"id": "relation3817402541",
+				"maxSelect": 1,
+				"minSelect": 0,
+				"name": "resumeFromRunRef",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "relation"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(10, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": false,
+				"id": "text1153084966",
+				"max": 0,
+				"min": 0,
+				"name": "resumeFromNodeId",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(11, []byte(`{
+				"hidden": false,
+				"id": "json2970041692",
+				"maxSize": 0,
+				"name": "plan",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "json"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(12, []byte(`{
+				"hidden": false,
+				"id": "json1285573096",
+				"maxSize": 0,
+				"name": "triggerData",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "json"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// create collection `workflow_version`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"cascadeDelete": true,
+							"collectionId": "tovyif5ax6j62ur",
+							"hidden": false,
+							"id": "relation3494172116",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "workflowRef",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"hidden": false,
+							"id": "number2155046657",
+							"max": null,
+							"min": 1,
+							"name": "version",
+							"onlyInt": true,
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "number"
+						},
+						{
+							"hidden": false,
+							"id": "json1804150713",
+							"maxSize": 5000000,
+							"name": "graphContent",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "json"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text1843675174",
+							"max": 0,
+							"min": 0,
+							"name": "description",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "pbc_2917456013",
+					"indexes": [
+						"CREATE UNIQUE INDEX ` + "`" + `idx_Wv6kPz3qLm` + "`" + ` ON ` + "`" + `workflow_version` + "`" + ` (` + "`" + `workflowRef` + "`" + `, ` + "`" + `version` + "`" + `)"
+					],
+					"name": "workflow_version",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'workflow_version' created")
+		}
+
+		// update collection `workflow_run`
+		//   - add field `versionRef`
+		{
+			collection, err := app.FindCollectionByNameOrId("qjp8lygssgwyqyz")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(2, []byte(`{
+				"cascadeDelete": false,
+				"collectionId": "pbc_2917456013",
+				"hidden": false,
+				"id": "relation2352471920",
+				"maxSelect": 1,
+				"minSelect": 0,
+				"name": "versionRef",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "relation"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// migrate data
+		//   - record published graph content of existing workflows as their first version
+		{
+			collection, err := app.FindCollectionByNameOrId("pbc_2917456013")
+			if err != nil {
+				return err
+			}
+
+			records, err := app.FindRecordsByFilter("tovyif5ax6j62ur", "hasContent=true", "", 0, 0)
+			if err != nil {
+				return err
+			}
+
+			for _, record := range records {
+				graphContent := record.GetString("graphContent")
+				if graphContent == "" || graphContent == "null" {
+					continue
+				}
+
+				versionRecord := core.NewRecord(collection)
+				versionRecord.Set("workflowRef", record.Id)
+				versionRecord.Set("version", 1)
+				versionRecord.Set("graphContent", record.Get("graphContent"))
+				if err := app.Save(versionRecord); err != nil {
+					return err
+				}
+
+				tracer.Printf("record #%s in collection '%s' created", versionRecord.Id, collection.Name)
+			}
+		}
+
+		// update collection `certificate`
+		//   - modify field `source`
+		//   - modify field `privateKey`
+		{
+			collection, err := app.FindCollectionByNameOrId("4szxr9x43tpj6np")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(1, []byte(`{
+				"hidden": false,
+				"id": "by9hetqi",
+				"maxSelect": 1,
+				"name": "source",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "select",
+				"values": [
+					"request",
+					"upload",
+					"acmeserver",
+					"discovered"
+				]
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(3, []byte(`{
+				"autogeneratePattern": "",
+				"help": "",
+				"hidden": false,
+				"id": "49qvwxcg",
+				"max": 100000,
+				"min": 0,
+				"name": "privateKey",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// create collection `acme_server_client`
+		// create collection `acme_server_account`
+		// create collection `acme_server_order`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text1579384326",
+							"max": 0,
+							"min": 0,
+							"name": "name",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "[a-zA-Z0-9]{24}",
+							"hidden": false,
+							"id": "text2473602411",
+							"max": 0,
+							"min": 0,
+							"name": "eabKid",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "[a-zA-Z0-9_-]{43}",
+							"hidden": false,
+							"id": "text1986245517",
+							"max": 0,
+							"min": 0,
+							"name": "eabHmacKey",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "json3894302213",
+							"maxSize": 0,
+							"name": "allowedIdentifiers",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "json"
+						},
+						{
+							"hidden": false,
+							"id": "bool2564233066",
+							"name": "disabled",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "bool"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "pbc_3741903618",
+					"indexes": [
+						"CREATE UNIQUE INDEX ` + "`" + `idx_Rk2pXh7sQa` + "`" + ` ON ` + "`" + `acme_server_client` + "`" + ` (` + "`" + `eabKid` + "`" + `)"
+					],
+					"name": "acme_server_client",
+					"system": false,
+					"type": "base"
+				},
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"cascadeDelete": true,
+							"collectionId": "pbc_3741903618",
+							"hidden": false,
+							"id": "relation1128370590",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "clientRef",
+							"presentable": false,
+							"required": true,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2324736937",
+							"max": 0,
+							"min": 0,
+							"name": "key",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text3617044806",
+							"max": 0,
+							"min": 0,
+							"name": "keyThumbprint",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "json1251775236",
+							"maxSize": 0,
+							"name": "contact",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "json"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2063623452",
+							"max": 0,
+							"min": 0,
+							"name": "status",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "pbc_1466872045",
+					"indexes": [
+						"CREATE UNIQUE INDEX ` + "`" + `idx_Yb5nTq1wLe` + "`" + ` ON ` + "`" + `acme_server_account` + "`" + ` (` + "`" + `keyThumbprint` + "`" + `)"
+					],
+					"name": "acme_server_account",
+					"system": false,
+					"type": "base"
+				},
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text4123574031",
+							"max": 0,
+							"min": 0,
+							"name": "orderId",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"cascadeDelete": true,
+							"collectionId": "pbc_1466872045",
+							"hidden": false,
+							"id": "relation3253625724",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "accountRef",
+							"presentable": false,
+							"required": true,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2063623452",
+							"max": 0,
+							"min": 0,
+							"name": "status",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "date261981154",
+							"max": "",
+							"min": "",
+							"name": "expires",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"cascadeDelete": false,
+							"collectionId": "4szxr9x43tpj6np",
+							"hidden": false,
+							"id": "relation2134807182",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "certificateRef",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"hidden": false,
+							"id": "json4274335913",
+							"maxSize": 0,
+							"name": "content",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "json"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "pbc_2205374218",
+					"indexes": [
+						"CREATE UNIQUE INDEX ` + "`" + `idx_Qm4vLc8nTe` + "`" + ` ON ` + "`" + `acme_server_order` + "`" + ` (` + "`" + `orderId` + "`" + `)",
+						"CREATE INDEX ` + "`" + `idx_Wd6sHf3kPz` + "`" + ` ON ` + "`" + `acme_server_order` + "`" + ` (` + "`" + `certificateRef` + "`" + `)"
+					],
+					"name": "acme_server_order",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'acme_server_client' created")
+			tracer.Printf("collection 'acme_server_account' created")
+			tracer.Printf("collection 'acme_server_order' created")
+		}
+
+		// update collection `certificate`
+		//   - add field `ariWindowStart`
+		//   - add field `ariWindowEnd`
+		//   - add field `ariRetryAfter`
+		{
+			collection, err := app.FindCollectionByNameOrId("4szxr9x43tpj6np")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(18, []byte(`{
+				"hidden": false,
+				"id": "date1683504135",
+				"max": "",
+				"min": "",
+				"name": "ariWindowStart",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "date"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(19, []byte(`{
+				"hidden": false,
+				"id": "date2760327598",
+				"max": "",
+				"min": "",
+				"name": "ariWindowEnd",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "date"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(20, []byte(`{
+				"hidden": false,
+				"id": "date3094511287",
+				"max": "",
+				"min": "",
+				"name": "ariRetryAfter",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "date"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// create collection `monitored_endpoint`
+		// create collection `monitored_endpoint_scan`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text1579384326",
+							"max": 0,
+							"min": 0,
+							"name": "name",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text1587448267",
+							"max": 0,
+							"min": 0,
+							"name": "host",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "number2715227580",
+							"max": 65535,
+							"min": 1,
+							"name": "port",
+							"onlyInt": true,
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "number"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text3802185573",
+							"max": 0,
+							"min": 0,
+							"name": "domain",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "select2146356401",
+							"maxSelect": 1,
+							"name": "protocol",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "select",
+							"values": [
+								"https",
+								"tls",
+								"smtp",
+								"imap",
+								"pop3",
+								"ftp",
+								"ldap",
+								"postgres",
+								"mysql"
+							]
+						},
+						{
+							"hidden": false,
+							"id": "number1466534506",
+							"max": null,
+							"min": 0,
+							"name": "scanInterval",
+							"onlyInt": true,
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "number"
+						},
+						{
+							"hidden": false,
+							"id": "number3318297742",
+							"max": null,
+							"min": 0,
+							"name": "warningDays",
+							"onlyInt": true,
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "number"
+						},
+						{
+							"hidden": false,
+							"id": "bool2564233066",
+							"name": "disabled",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "bool"
+						},
+						{
+							"hidden": false,
+							"id": "date1926419562",
+							"max": "",
+							"min": "",
+							"name": "lastScannedAt",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"hidden": false,
+							"id": "select2744374011",
+							"maxSelect": 1,
+							"name": "lastStatus",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "select",
+							"values": [
+								"ok",
+								"expiring",
+								"invalid",
+								"error"
+							]
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text3167040946",
+							"max": 0,
+							"min": 0,
+							"name": "lastSerialNumber",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "date2061637314",
+							"max": "",
+							"min": "",
+							"name": "lastValidityNotAfter",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "pbc_2851046237",
+					"indexes": [
+						"CREATE UNIQUE INDEX ` + "`" + `idx_Mq4vNe8rTd` + "`" + ` ON ` + "`" + `monitored_endpoint` + "`" + ` (` + "`" + `host` + "`" + `, ` + "`" + `port` + "`" + `, ` + "`" + `domain` + "`" + `)"
+					],
+					"name": "monitored_endpoint",
+					"system": false,
+					"type": "base"
+				},
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"cascadeDelete": true,
+							"collectionId": "pbc_2851046237",
+							"hidden": false,
+							"id": "relation3470512045",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "endpointRef",
+							"presentable": false,
+							"required": true,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"hidden": false,
+							"id": "select2063623452",
+							"maxSelect": 1,
+							"name": "status",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "select",
+							"values": [
+								"ok",
+								"expiring",
+								"invalid",
+								"error"
+							]
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2097104386",
+							"max": 0,
+							"min": 0,
+							"name": "serialNumber",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text3915466409",
+							"max": 0,
+							"min": 0,
+							"name": "subjectAltNames",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2714983610",
+							"max": 0,
+							"min": 0,
+							"name": "issuerName",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "date1301281358",
+							"max": "",
+							"min": "",
+							"name": "validityNotBefore",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"hidden": false,
+							"id": "date2398219946",
+							"max": "",
+							"min": "",
+							"name": "validityNotAfter",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text1574812785",
+							"max": 0,
+							"min": 0,
+							"name": "error",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "pbc_1720583914",
+					"indexes": [
+						"CREATE INDEX ` + "`" + `idx_Hs7wKc2pZy` + "`" + ` ON ` + "`" + `monitored_endpoint_scan` + "`" + ` (` + "`" + `endpointRef` + "`" + `, ` + "`" + `created` + "`" + `)"
+					],
+					"name": "monitored_endpoint_scan",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'monitored_endpoint' created")
+			tracer.Printf("collection 'monitored_endpoint_scan' created")
+		}
+
+		// update collection `certificate`
+		//   - add field `discoveredEndpoints`
+		{
+			collection, err := app.FindCollectionByNameOrId("4szxr9x43tpj6np")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(20, []byte(`{
+				"hidden": false,
+				"id": "json3472096180",
+				"maxSize": 2000000,
+				"name": "discoveredEndpoints",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "json"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// update collection `certificate`
+		//   - add field `revokedAt`
+		{
+			collection, err := app.FindCollectionByNameOrId("4szxr9x43tpj6np")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(24, []byte(`{
+				"hidden": false,
+				"id": "date1866298917",
+				"max": "",
+				"min": "",
+				"name": "revokedAt",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "date"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// update collection `access`
+		//   - add field `crlNumber`
+		//   - add field `crl`
+		{
+			collection, err := app.FindCollectionByNameOrId("4yzbv8urny5ja1e")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(5, []byte(`{
+				"hidden": true,
+				"id": "number2815046793",
+				"max": null,
+				"min": null,
+				"name": "crlNumber",
+				"onlyInt": true,
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "number"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(6, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": true,
+				"id": "text4230436133",
+				"max": 0,
+				"min": 0,
+				"name": "crl",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// create collection `workflow_trigger_dedupe`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"cascadeDelete": true,
+							"collectionId": "tovyif5ax6j62ur",
+							"hidden": false,
+							"id": "relation3371272342",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "workflowRef",
+							"presentable": false,
+							"required": true,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2324736937",
+							"max": 0,
+							"min": 0,
+							"name": "key",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "date2755040542",
+							"max": "",
+							"min": "",
+							"name": "expireAt",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "pbc_3862619070",
+					"indexes": [
+						"CREATE UNIQUE INDEX ` + "`" + `idx_Qm4tVb8xRn` + "`" + ` ON ` + "`" + `workflow_trigger_dedupe` + "`" + ` (` + "`" + `workflowRef` + "`" + `, ` + "`" + `key` + "`" + `)",
+						"CREATE INDEX ` + "`" + `idx_Jd2nXw6pLc` + "`" + ` ON ` + "`" + `workflow_trigger_dedupe` + "`" + ` (` + "`" + `expireAt` + "`" + `)"
+					],
+					"name": "workflow_trigger_dedupe",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'workflow_trigger_dedupe' created")
+		}
+
+		tracer.Printf("done")
+		return nil
+	}, func(app core.App) error {
+		return errors.ErrUnsupported
+	})
+}
@@ -0,0 +1,156 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-acme/lego/v5/challenge/dns01"
+	"github.com/go-acme/lego/v5/log"
+
+	"github.com/certimate-go/certimate/internal/tools/ssh"
+	"github.com/certimate-go/certimate/pkg/core"
+	"github.com/certimate-go/certimate/pkg/core/certifier/challengers/internal/execcmd"
+)
+
+type ServerConfig struct {
+	// SSH 主机。
+	SshHost string `json:"sshHost"`
+	// SSH 端口。
+	// 零值时默认值 22。
+	SshPort int32 `json:"sshPort,omitempty"`
+	// SSH 认证方式。
+	// 可取值 "none"、"password"、"key"。
+	// 零值时根据有无密码或私钥字段决定。
+	SshAuthMethod string `json:"sshAuthMethod,omitempty"`
+	// SSH 登录用户名。
+	// 零值时默认值 "root"。
+	SshUsername string `json:"sshUsername,omitempty"`
+	// SSH 登录密码。
+	SshPassword string `json:"sshPassword,omitempty"`
+	// SSH 登录私钥。
+	SshKey string `json:"sshKey,omitempty"`
+	// SSH 登录私钥口令。
+	SshKeyPassphrase string `json:"sshKeyPassphrase,omitempty"`
+}
+
+type ChallengerConfig struct {
+	ServerConfig
+
+	// 跳板机配置数组。
+	JumpServers []ServerConfig `json:"jumpServers,omitempty"`
+	// Shell 执行环境，仅在本地执行时有效。
+	// 可取值 "sh"、"cmd"、"powershell"。
+	// 零值时根据操作系统决定。
+	ShellEnv string `json:"shellEnv,omitempty"`
+	// 待执行的命令。
+	// 执行时将追加参数 "present|cleanup <fqdn> <value>"，
+	// 并设置环境变量 CERTIMATE_ACME_ACTION、CERTIMATE_ACME_DOMAIN、CERTIMATE_ACME_TOKEN、CERTIMATE_ACME_KEYAUTH、CERTIMATE_ACME_FQDN、CERTIMATE_ACME_VALUE。
+	Command string `json:"command"`
+	// 命令执行超时时间（单位：秒）。
+	// 零值时默认值 60。
+	CommandTimeout        int `json:"commandTimeout,omitempty"`
+	DnsPropagationTimeout int `json:"dnsPropagationTimeout,omitempty"`
+}
+
+type challenger struct {
+	config    *ChallengerConfig
+	cmdConfig *execcmd.Config
+}
+
+var _ core.ACMEChallenger = (*challenger)(nil)
+
+// 创建通过外部命令完成 DNS-01 质询的提供商。
+// 若 [ServerConfig.SshHost] 为空，则在本地执行命令；否则通过 SSH 在远程服务器上执行命令。
+func NewChallenger(config *ChallengerConfig) (core.ACMEChallenger, error) {
+	if config == nil {
+		return nil, fmt.Errorf("the configuration of the acme challenge provider is nil")
+	}
+	if config.Command == "" {
+		return nil, fmt.Errorf("exec: command must be set")
+	}
+
+	cmdConfig := &execcmd.Config{
+		ShellEnv: config.ShellEnv,
+		Command:  config.Command,
+		Timeout:  time.Duration(config.CommandTimeout) * time.Second,
+	}
+	if config.SshHost != "" {
+		cmdConfig.SSH = newSshConfig(config)
+	}
+
+	return &challenger{
+		config:    config,
+		cmdConfig: cmdConfig,
+	}, nil
+}
+
+func (c *challenger) Present(ctx context.Context, domain, token, keyAuth string) error {
+	return c.run(ctx, "present", domain, token, keyAuth)
+}
+
+func (c *challenger) CleanUp(ctx context.Context, domain, token, keyAuth string) error {
+	return c.run(ctx, "cleanup", domain, token, keyAuth)
+}
+
+func (c *challenger) Timeout() (timeout, interval time.Duration) {
+	if c.config.DnsPropagationTimeout > 0 {
+		return time.Duration(c.config.DnsPropagationTimeout) * time.Second, dns01.DefaultPollingInterval
+	}
+
+	return dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+}
+
+func (c *challenger) run(ctx context.Context, action, domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(ctx, domain, keyAuth)
+
+	args := []string{action, info.EffectiveFQDN, info.Value}
+	envs := map[string]string{
+		"CERTIMATE_ACME_ACTION":  action,
+		"CERTIMATE_ACME_DOMAIN":  domain,
+		"CERTIMATE_ACME_TOKEN":   token,
+		"CERTIMATE_ACME_KEYAUTH": keyAuth,
+		"CERTIMATE_ACME_FQDN":    info.EffectiveFQDN,
+		"CERTIMATE_ACME_VALUE":   info.Value,
+	}
+
+	stdout, stderr, err := execcmd.Run(ctx, c.cmdConfig, args, envs)
+	if err != nil {
+		log.Warn(fmt.Sprintf("exec: %s command failed", action), slog.String("fqdn", info.EffectiveFQDN), slog.String("stdout", execcmd.TruncateOutput(stdout)), slog.String("stderr", execcmd.TruncateOutput(stderr)))
+		return fmt.Errorf("exec: failed to execute %s command: %w", action, err)
+	}
+
+	log.Debug(fmt.Sprintf("exec: %s command executed", action), slog.String("fqdn", info.EffectiveFQDN), slog.String("stdout", execcmd.TruncateOutput(stdout)), slog.String("stderr", execcmd.TruncateOutput(stderr)))
+	return nil
+}
+
+func newSshConfig(config *ChallengerConfig) *ssh.Config {
+	sshConfig := ssh.NewDefaultConfig()
+	sshConfig.Host = config.SshHost
+	if config.SshPort != 0 {
+		sshConfig.Port = int(config.SshPort)
+	}
+	if config.SshAuthMethod != "" {
+		sshConfig.AuthMethod = ssh.AuthMethodType(config.SshAuthMethod)
+	}
+	if config.SshUsername != "" {
+		sshConfig.Username = config.SshUsername
+	}
+	sshConfig.Password = config.SshPassword
+	sshConfig.Key = config.SshKey
+	sshConfig.KeyPassphrase = config.SshKeyPassphrase
+	for _, jumpServer := range config.JumpServers {
+		sshConfig.JumpServers = append(sshConfig.JumpServers, ssh.ServerConfig{
+			Host:          jumpServer.SshHost,
+			Port:          int(jumpServer.SshPort),
+			AuthMethod:    ssh.AuthMethodType(jumpServer.SshAuthMethod),
+			Username:      jumpServer.SshUsername,
+			Password:      jumpServer.SshPassword,
+			Key:           jumpServer.SshKey,
+			KeyPassphrase: jumpServer.SshKeyPassphrase,
+		})
+	}
+
+	return sshConfig
+}
@@ -0,0 +1,171 @@
+package lego
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/challenge"
+)
+
+type providerFactory func(credentials map[string]string) (challenge.Provider, error)
+
+func newProviderFactory[C any, P challenge.Provider](newDefaultConfig func() *C, newProviderConfig func(*C) (P, error)) providerFactory {
+	return newProviderFactoryWithError(func() (*C, error) { return newDefaultConfig(), nil }, newProviderConfig)
+}
+
+func newProviderFactoryWithError[C any, P challenge.Provider](newDefaultConfig func() (*C, error), newProviderConfig func(*C) (P, error)) providerFactory {
+	return func(credentials map[string]string) (challenge.Provider, error) {
+		config, err := newDefaultConfig()
+		if err != nil {
+			return nil, err
+		} else if config == nil {
+			return nil, fmt.Errorf("the default configuration of the DNS provider is nil")
+		}
+
+		if err := populateConfig(config, credentials); err != nil {
+			return nil, err
+		}
+
+		return newProviderConfig(config)
+	}
+}
+
+var (
+	typeDuration = reflect.TypeFor[time.Duration]()
+	typeURLPtr   = reflect.TypeFor[*url.URL]()
+)
+
+// 将凭据键值对写入 lego 提供商的 Config 结构体。
+// 键名为 Config 的字段名，忽略大小写及下划线，如 "AccessToken"、"access_token" 均对应字段 `AccessToken`。
+func populateConfig(config any, credentials map[string]string) error {
+	rv := reflect.ValueOf(config).Elem()
+	rt := rv.Type()
+
+	fields := make(map[string]int, rt.NumField())
+	for i := range rt.NumField() {
+		if rt.Field(i).IsExported() {
+			fields[normalizeFieldName(rt.Field(i).Name)] = i
+		}
+	}
+
+	for key, value := range credentials {
+		idx, ok := fields[normalizeFieldName(key)]
+		if !ok {
+			return fmt.Errorf("unknown credential key '%s'", key)
+		}
+
+		if err := setFieldValue(rv.Field(idx), value); err != nil {
+			return fmt.Errorf("invalid value of credential key '%s': %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+func setFieldValue(field reflect.Value, value string) error {
+	if field.CanAddr() {
+		if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(value))
+		}
+	}
+
+	switch field.Type() {
+	case typeDuration:
+		// 与 lego 环境变量的约定一致，纯数字时单位为秒
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+			field.SetInt(int64(time.Duration(seconds) * time.Second))
+			return nil
+		}
+
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+
+	case typeURLPtr:
+		u, err := url.Parse(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(u))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type '%s'", field.Type())
+		}
+
+		items := make([]string, 0)
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			slice.Index(i).SetString(item)
+		}
+		field.Set(slice)
+
+	case reflect.Map:
+		if field.Type().Key().Kind() != reflect.String || field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type '%s'", field.Type())
+		}
+
+		m := reflect.New(field.Type())
+		if err := json.Unmarshal([]byte(value), m.Interface()); err != nil {
+			return err
+		}
+		field.Set(m.Elem())
+
+	default:
+		return fmt.Errorf("unsupported field type '%s'", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package lego
+
+//go:generate go run providers_generate.go
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/challenge"
+	"github.com/go-acme/lego/v5/challenge/dns01"
+
+	"github.com/certimate-go/certimate/pkg/core"
+)
+
+type ChallengerConfig struct {
+	// lego DNS 提供商名称，如 "infoblox"、"dnsimple"。
+	// 可选值为当前依赖的 lego 版本所支持的提供商（见 providers.go），参考 https://go-acme.github.io/lego/dns/
+	ProviderName string `json:"providerName"`
+	// lego DNS 提供商 Config 结构体的字段键值对，如 {"AccessToken": "..."}。
+	// 键名忽略大小写及下划线；TTL 等其他可选配置项亦可通过此处传入，如 {"TTL": "120"}。
+	Credentials           map[string]string `json:"credentials,omitempty"`
+	DnsPropagationTimeout int               `json:"dnsPropagationTimeout,omitempty"`
+}
+
+func NewChallenger(config *ChallengerConfig) (core.ACMEChallenger, error) {
+	if config == nil {
+		return nil, fmt.Errorf("the configuration of the acme challenge provider is nil")
+	}
+
+	providerName := strings.ToLower(strings.TrimSpace(config.ProviderName))
+	if providerName == "" {
+		return nil, fmt.Errorf("lego: the provider name is not specified")
+	}
+
+	factory, ok := providerFactories[providerName]
+	if !ok {
+		return nil, fmt.Errorf("lego: unsupported provider '%s'", providerName)
+	}
+
+	// 直接构造提供商的 Config 结构体，而非经由环境变量传递凭据，
+	// 避免凭据在进程内（及其子进程间）泄露，也避免服务端自身的环境变量混入提供商的配置。
+	provider, err := factory(config.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("lego: %s: %w", providerName, err)
+	}
+
+	if config.DnsPropagationTimeout != 0 {
+		return &challengerWithTimeout{
+			Provider: provider,
+			timeout:  time.Duration(config.DnsPropagationTimeout) * time.Second,
+		}, nil
+	}
+
+	return provider, nil
+}
+
+// 由于不同 lego DNS 提供商的环境变量前缀各不相同，
+// 此处通过包装的方式统一覆盖其 DNS 传播检查的超时时间。
+type challengerWithTimeout struct {
+	challenge.Provider
+	timeout time.Duration
+}
+
+var _ challenge.ProviderTimeout = (*challengerWithTimeout)(nil)
+
+func (c *challengerWithTimeout) Timeout() (timeout, interval time.Duration) {
+	interval = dns01.DefaultPollingInterval
+	if t, ok := c.Provider.(challenge.ProviderTimeout); ok {
+		_, interval = t.Timeout()
+	}
+
+	return c.timeout, interval
+}
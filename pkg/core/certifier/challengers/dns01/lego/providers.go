@@ -0,0 +1,449 @@
+// Code generated by providers_generate.go; DO NOT EDIT.
+
+package lego
+
+import (
+	"github.com/go-acme/lego/v5/providers/dns/abion"
+	"github.com/go-acme/lego/v5/providers/dns/acmedns"
+	"github.com/go-acme/lego/v5/providers/dns/active24"
+	"github.com/go-acme/lego/v5/providers/dns/alidns"
+	"github.com/go-acme/lego/v5/providers/dns/aliesa"
+	"github.com/go-acme/lego/v5/providers/dns/allinkl"
+	"github.com/go-acme/lego/v5/providers/dns/alwaysdata"
+	"github.com/go-acme/lego/v5/providers/dns/anexia"
+	"github.com/go-acme/lego/v5/providers/dns/artfiles"
+	"github.com/go-acme/lego/v5/providers/dns/arvancloud"
+	"github.com/go-acme/lego/v5/providers/dns/auroradns"
+	"github.com/go-acme/lego/v5/providers/dns/autodns"
+	"github.com/go-acme/lego/v5/providers/dns/axelname"
+	"github.com/go-acme/lego/v5/providers/dns/azion"
+	"github.com/go-acme/lego/v5/providers/dns/azuredns"
+	"github.com/go-acme/lego/v5/providers/dns/baiducloud"
+	"github.com/go-acme/lego/v5/providers/dns/beget"
+	"github.com/go-acme/lego/v5/providers/dns/binarylane"
+	"github.com/go-acme/lego/v5/providers/dns/bindman"
+	"github.com/go-acme/lego/v5/providers/dns/bluecat"
+	"github.com/go-acme/lego/v5/providers/dns/bluecatv2"
+	"github.com/go-acme/lego/v5/providers/dns/bookmyname"
+	"github.com/go-acme/lego/v5/providers/dns/bunny"
+	"github.com/go-acme/lego/v5/providers/dns/checkdomain"
+	"github.com/go-acme/lego/v5/providers/dns/civo"
+	"github.com/go-acme/lego/v5/providers/dns/clouddns"
+	"github.com/go-acme/lego/v5/providers/dns/cloudflare"
+	"github.com/go-acme/lego/v5/providers/dns/cloudns"
+	"github.com/go-acme/lego/v5/providers/dns/cloudru"
+	"github.com/go-acme/lego/v5/providers/dns/com35"
+	"github.com/go-acme/lego/v5/providers/dns/connbyte"
+	"github.com/go-acme/lego/v5/providers/dns/conoha"
+	"github.com/go-acme/lego/v5/providers/dns/conohav3"
+	"github.com/go-acme/lego/v5/providers/dns/constellix"
+	"github.com/go-acme/lego/v5/providers/dns/corenetworks"
+	"github.com/go-acme/lego/v5/providers/dns/cpanel"
+	"github.com/go-acme/lego/v5/providers/dns/curanet"
+	"github.com/go-acme/lego/v5/providers/dns/czechia"
+	"github.com/go-acme/lego/v5/providers/dns/dandomain"
+	"github.com/go-acme/lego/v5/providers/dns/ddnss"
+	"github.com/go-acme/lego/v5/providers/dns/derak"
+	"github.com/go-acme/lego/v5/providers/dns/desec"
+	"github.com/go-acme/lego/v5/providers/dns/designate"
+	"github.com/go-acme/lego/v5/providers/dns/digitalocean"
+	"github.com/go-acme/lego/v5/providers/dns/dinahosting"
+	"github.com/go-acme/lego/v5/providers/dns/directadmin"
+	"github.com/go-acme/lego/v5/providers/dns/dns51"
+	"github.com/go-acme/lego/v5/providers/dns/dnscale"
+	"github.com/go-acme/lego/v5/providers/dns/dnsexit"
+	"github.com/go-acme/lego/v5/providers/dns/dnshomede"
+	"github.com/go-acme/lego/v5/providers/dns/dnsimple"
+	"github.com/go-acme/lego/v5/providers/dns/dnsla"
+	"github.com/go-acme/lego/v5/providers/dns/dnsmadeeasy"
+	"github.com/go-acme/lego/v5/providers/dns/dnsservices"
+	"github.com/go-acme/lego/v5/providers/dns/dnsupdate"
+	"github.com/go-acme/lego/v5/providers/dns/dode"
+	"github.com/go-acme/lego/v5/providers/dns/domeneshop"
+	"github.com/go-acme/lego/v5/providers/dns/dreamhost"
+	"github.com/go-acme/lego/v5/providers/dns/duckdns"
+	"github.com/go-acme/lego/v5/providers/dns/dyn"
+	"github.com/go-acme/lego/v5/providers/dns/dynadot"
+	"github.com/go-acme/lego/v5/providers/dns/dyndnsfree"
+	"github.com/go-acme/lego/v5/providers/dns/dynu"
+	"github.com/go-acme/lego/v5/providers/dns/easydns"
+	"github.com/go-acme/lego/v5/providers/dns/edgecenter"
+	"github.com/go-acme/lego/v5/providers/dns/edgedns"
+	"github.com/go-acme/lego/v5/providers/dns/edgeone"
+	"github.com/go-acme/lego/v5/providers/dns/efficientip"
+	"github.com/go-acme/lego/v5/providers/dns/epik"
+	"github.com/go-acme/lego/v5/providers/dns/eurodns"
+	"github.com/go-acme/lego/v5/providers/dns/euserv"
+	"github.com/go-acme/lego/v5/providers/dns/excedo"
+	"github.com/go-acme/lego/v5/providers/dns/exoscale"
+	"github.com/go-acme/lego/v5/providers/dns/f5xc"
+	"github.com/go-acme/lego/v5/providers/dns/fornex"
+	"github.com/go-acme/lego/v5/providers/dns/freemyip"
+	"github.com/go-acme/lego/v5/providers/dns/gandi"
+	"github.com/go-acme/lego/v5/providers/dns/gandiv5"
+	"github.com/go-acme/lego/v5/providers/dns/gcloud"
+	"github.com/go-acme/lego/v5/providers/dns/gcore"
+	"github.com/go-acme/lego/v5/providers/dns/gehirn"
+	"github.com/go-acme/lego/v5/providers/dns/gigahostno"
+	"github.com/go-acme/lego/v5/providers/dns/glesys"
+	"github.com/go-acme/lego/v5/providers/dns/gname"
+	"github.com/go-acme/lego/v5/providers/dns/godaddy"
+	"github.com/go-acme/lego/v5/providers/dns/gravity"
+	"github.com/go-acme/lego/v5/providers/dns/hetzner"
+	"github.com/go-acme/lego/v5/providers/dns/hostingde"
+	"github.com/go-acme/lego/v5/providers/dns/hostinger"
+	"github.com/go-acme/lego/v5/providers/dns/hostingnl"
+	"github.com/go-acme/lego/v5/providers/dns/hosttech"
+	"github.com/go-acme/lego/v5/providers/dns/hostup"
+	"github.com/go-acme/lego/v5/providers/dns/httpnet"
+	"github.com/go-acme/lego/v5/providers/dns/httpreq"
+	"github.com/go-acme/lego/v5/providers/dns/huaweicloud"
+	"github.com/go-acme/lego/v5/providers/dns/hurricane"
+	"github.com/go-acme/lego/v5/providers/dns/hyperone"
+	"github.com/go-acme/lego/v5/providers/dns/ibmcloud"
+	"github.com/go-acme/lego/v5/providers/dns/iijdpf"
+	"github.com/go-acme/lego/v5/providers/dns/infoblox"
+	"github.com/go-acme/lego/v5/providers/dns/infomaniak"
+	"github.com/go-acme/lego/v5/providers/dns/internetbs"
+	"github.com/go-acme/lego/v5/providers/dns/inwx"
+	"github.com/go-acme/lego/v5/providers/dns/ionos"
+	"github.com/go-acme/lego/v5/providers/dns/ionoscloud"
+	"github.com/go-acme/lego/v5/providers/dns/ipv64"
+	"github.com/go-acme/lego/v5/providers/dns/ispconfig"
+	"github.com/go-acme/lego/v5/providers/dns/ispconfigddns"
+	"github.com/go-acme/lego/v5/providers/dns/jdcloud"
+	"github.com/go-acme/lego/v5/providers/dns/joker"
+	"github.com/go-acme/lego/v5/providers/dns/katapult"
+	"github.com/go-acme/lego/v5/providers/dns/keyhelp"
+	"github.com/go-acme/lego/v5/providers/dns/leaseweb"
+	"github.com/go-acme/lego/v5/providers/dns/liara"
+	"github.com/go-acme/lego/v5/providers/dns/lightsail"
+	"github.com/go-acme/lego/v5/providers/dns/limacity"
+	"github.com/go-acme/lego/v5/providers/dns/linode"
+	"github.com/go-acme/lego/v5/providers/dns/liquidweb"
+	"github.com/go-acme/lego/v5/providers/dns/loopia"
+	"github.com/go-acme/lego/v5/providers/dns/luadns"
+	"github.com/go-acme/lego/v5/providers/dns/mailinabox"
+	"github.com/go-acme/lego/v5/providers/dns/manageengine"
+	"github.com/go-acme/lego/v5/providers/dns/metaname"
+	"github.com/go-acme/lego/v5/providers/dns/metaregistrar"
+	"github.com/go-acme/lego/v5/providers/dns/mijnhost"
+	"github.com/go-acme/lego/v5/providers/dns/mittwald"
+	"github.com/go-acme/lego/v5/providers/dns/myaddr"
+	"github.com/go-acme/lego/v5/providers/dns/mydnsjp"
+	"github.com/go-acme/lego/v5/providers/dns/mythicbeasts"
+	"github.com/go-acme/lego/v5/providers/dns/namecheap"
+	"github.com/go-acme/lego/v5/providers/dns/namedotcom"
+	"github.com/go-acme/lego/v5/providers/dns/namesilo"
+	"github.com/go-acme/lego/v5/providers/dns/namesurfer"
+	"github.com/go-acme/lego/v5/providers/dns/nearlyfreespeech"
+	"github.com/go-acme/lego/v5/providers/dns/nederhost"
+	"github.com/go-acme/lego/v5/providers/dns/neodigit"
+	"github.com/go-acme/lego/v5/providers/dns/netcup"
+	"github.com/go-acme/lego/v5/providers/dns/netlify"
+	"github.com/go-acme/lego/v5/providers/dns/netnod"
+	"github.com/go-acme/lego/v5/providers/dns/ngenix"
+	"github.com/go-acme/lego/v5/providers/dns/nicmanager"
+	"github.com/go-acme/lego/v5/providers/dns/nicru"
+	"github.com/go-acme/lego/v5/providers/dns/nifcloud"
+	"github.com/go-acme/lego/v5/providers/dns/njalla"
+	"github.com/go-acme/lego/v5/providers/dns/nodion"
+	"github.com/go-acme/lego/v5/providers/dns/ns1"
+	"github.com/go-acme/lego/v5/providers/dns/octenium"
+	"github.com/go-acme/lego/v5/providers/dns/omglol"
+	"github.com/go-acme/lego/v5/providers/dns/onecloudru"
+	"github.com/go-acme/lego/v5/providers/dns/onlinenet"
+	"github.com/go-acme/lego/v5/providers/dns/openprovider"
+	"github.com/go-acme/lego/v5/providers/dns/opusdns"
+	"github.com/go-acme/lego/v5/providers/dns/oraclecloud"
+	"github.com/go-acme/lego/v5/providers/dns/otc"
+	"github.com/go-acme/lego/v5/providers/dns/ovh"
+	"github.com/go-acme/lego/v5/providers/dns/pdns"
+	"github.com/go-acme/lego/v5/providers/dns/plesk"
+	"github.com/go-acme/lego/v5/providers/dns/pointdns"
+	"github.com/go-acme/lego/v5/providers/dns/porkbun"
+	"github.com/go-acme/lego/v5/providers/dns/poweradmin"
+	"github.com/go-acme/lego/v5/providers/dns/rackspace"
+	"github.com/go-acme/lego/v5/providers/dns/rage4"
+	"github.com/go-acme/lego/v5/providers/dns/rainyun"
+	"github.com/go-acme/lego/v5/providers/dns/rcodezero"
+	"github.com/go-acme/lego/v5/providers/dns/regfish"
+	"github.com/go-acme/lego/v5/providers/dns/regru"
+	"github.com/go-acme/lego/v5/providers/dns/rimuhosting"
+	"github.com/go-acme/lego/v5/providers/dns/route53"
+	"github.com/go-acme/lego/v5/providers/dns/safedns"
+	"github.com/go-acme/lego/v5/providers/dns/sakuracloud"
+	"github.com/go-acme/lego/v5/providers/dns/scaleway"
+	"github.com/go-acme/lego/v5/providers/dns/scannet"
+	"github.com/go-acme/lego/v5/providers/dns/selectel"
+	"github.com/go-acme/lego/v5/providers/dns/selectelv2"
+	"github.com/go-acme/lego/v5/providers/dns/selfhostde"
+	"github.com/go-acme/lego/v5/providers/dns/servercow"
+	"github.com/go-acme/lego/v5/providers/dns/shellrent"
+	"github.com/go-acme/lego/v5/providers/dns/simply"
+	"github.com/go-acme/lego/v5/providers/dns/sonic"
+	"github.com/go-acme/lego/v5/providers/dns/spaceship"
+	"github.com/go-acme/lego/v5/providers/dns/stackpath"
+	"github.com/go-acme/lego/v5/providers/dns/syse"
+	"github.com/go-acme/lego/v5/providers/dns/technitium"
+	"github.com/go-acme/lego/v5/providers/dns/tele3"
+	"github.com/go-acme/lego/v5/providers/dns/tencentcloud"
+	"github.com/go-acme/lego/v5/providers/dns/timewebcloud"
+	"github.com/go-acme/lego/v5/providers/dns/todaynic"
+	"github.com/go-acme/lego/v5/providers/dns/transip"
+	"github.com/go-acme/lego/v5/providers/dns/ucloud"
+	"github.com/go-acme/lego/v5/providers/dns/ultradns"
+	"github.com/go-acme/lego/v5/providers/dns/uniteddomains"
+	"github.com/go-acme/lego/v5/providers/dns/variomedia"
+	"github.com/go-acme/lego/v5/providers/dns/veesp"
+	"github.com/go-acme/lego/v5/providers/dns/vegadns"
+	"github.com/go-acme/lego/v5/providers/dns/vercel"
+	"github.com/go-acme/lego/v5/providers/dns/versio"
+	"github.com/go-acme/lego/v5/providers/dns/vinyldns"
+	"github.com/go-acme/lego/v5/providers/dns/virtualname"
+	"github.com/go-acme/lego/v5/providers/dns/vkcloud"
+	"github.com/go-acme/lego/v5/providers/dns/volcengine"
+	"github.com/go-acme/lego/v5/providers/dns/vscale"
+	"github.com/go-acme/lego/v5/providers/dns/vultr"
+	"github.com/go-acme/lego/v5/providers/dns/wannafind"
+	"github.com/go-acme/lego/v5/providers/dns/webnamesca"
+	"github.com/go-acme/lego/v5/providers/dns/webnamesru"
+	"github.com/go-acme/lego/v5/providers/dns/websupport"
+	"github.com/go-acme/lego/v5/providers/dns/wedos"
+	"github.com/go-acme/lego/v5/providers/dns/westcn"
+	"github.com/go-acme/lego/v5/providers/dns/xinnet"
+	"github.com/go-acme/lego/v5/providers/dns/yandex"
+	"github.com/go-acme/lego/v5/providers/dns/yandex360"
+	"github.com/go-acme/lego/v5/providers/dns/yandexcloud"
+	"github.com/go-acme/lego/v5/providers/dns/zilore"
+	"github.com/go-acme/lego/v5/providers/dns/zoneedit"
+	"github.com/go-acme/lego/v5/providers/dns/zoneee"
+	"github.com/go-acme/lego/v5/providers/dns/zonomi"
+)
+
+// 支持的 lego DNS 提供商，由 lego 的 `dns.NewDNSChallengeProviderByName` 生成。
+// 其中 "exec"、"manual" 需在服务端执行命令或人工介入，故不予支持。
+var providerFactories = map[string]providerFactory{
+	"abion":            newProviderFactory(abion.NewDefaultConfig, abion.NewDNSProviderConfig),
+	"acme-dns":         newProviderFactory(acmedns.NewDefaultConfig, acmedns.NewDNSProviderConfig),
+	"acmedns":          newProviderFactory(acmedns.NewDefaultConfig, acmedns.NewDNSProviderConfig),
+	"active24":         newProviderFactory(active24.NewDefaultConfig, active24.NewDNSProviderConfig),
+	"alidns":           newProviderFactory(alidns.NewDefaultConfig, alidns.NewDNSProviderConfig),
+	"aliesa":           newProviderFactory(aliesa.NewDefaultConfig, aliesa.NewDNSProviderConfig),
+	"allinkl":          newProviderFactory(allinkl.NewDefaultConfig, allinkl.NewDNSProviderConfig),
+	"alwaysdata":       newProviderFactory(alwaysdata.NewDefaultConfig, alwaysdata.NewDNSProviderConfig),
+	"anexia":           newProviderFactory(anexia.NewDefaultConfig, anexia.NewDNSProviderConfig),
+	"artfiles":         newProviderFactory(artfiles.NewDefaultConfig, artfiles.NewDNSProviderConfig),
+	"arvancloud":       newProviderFactory(arvancloud.NewDefaultConfig, arvancloud.NewDNSProviderConfig),
+	"auroradns":        newProviderFactory(auroradns.NewDefaultConfig, auroradns.NewDNSProviderConfig),
+	"autodns":          newProviderFactory(autodns.NewDefaultConfig, autodns.NewDNSProviderConfig),
+	"axelname":         newProviderFactory(axelname.NewDefaultConfig, axelname.NewDNSProviderConfig),
+	"azion":            newProviderFactory(azion.NewDefaultConfig, azion.NewDNSProviderConfig),
+	"azuredns":         newProviderFactory(azuredns.NewDefaultConfig, azuredns.NewDNSProviderConfig),
+	"baiducloud":       newProviderFactory(baiducloud.NewDefaultConfig, baiducloud.NewDNSProviderConfig),
+	"beget":            newProviderFactory(beget.NewDefaultConfig, beget.NewDNSProviderConfig),
+	"binarylane":       newProviderFactory(binarylane.NewDefaultConfig, binarylane.NewDNSProviderConfig),
+	"bindman":          newProviderFactory(bindman.NewDefaultConfig, bindman.NewDNSProviderConfig),
+	"bluecat":          newProviderFactory(bluecat.NewDefaultConfig, bluecat.NewDNSProviderConfig),
+	"bluecatv2":        newProviderFactory(bluecatv2.NewDefaultConfig, bluecatv2.NewDNSProviderConfig),
+	"bookmyname":       newProviderFactory(bookmyname.NewDefaultConfig, bookmyname.NewDNSProviderConfig),
+	"bunny":            newProviderFactory(bunny.NewDefaultConfig, bunny.NewDNSProviderConfig),
+	"checkdomain":      newProviderFactory(checkdomain.NewDefaultConfig, checkdomain.NewDNSProviderConfig),
+	"civo":             newProviderFactory(civo.NewDefaultConfig, civo.NewDNSProviderConfig),
+	"clouddns":         newProviderFactory(clouddns.NewDefaultConfig, clouddns.NewDNSProviderConfig),
+	"cloudflare":       newProviderFactory(cloudflare.NewDefaultConfig, cloudflare.NewDNSProviderConfig),
+	"cloudns":          newProviderFactory(cloudns.NewDefaultConfig, cloudns.NewDNSProviderConfig),
+	"cloudru":          newProviderFactory(cloudru.NewDefaultConfig, cloudru.NewDNSProviderConfig),
+	"com35":            newProviderFactory(com35.NewDefaultConfig, com35.NewDNSProviderConfig),
+	"connbyte":         newProviderFactory(connbyte.NewDefaultConfig, connbyte.NewDNSProviderConfig),
+	"conoha":           newProviderFactory(conoha.NewDefaultConfig, conoha.NewDNSProviderConfig),
+	"conohav3":         newProviderFactory(conohav3.NewDefaultConfig, conohav3.NewDNSProviderConfig),
+	"constellix":       newProviderFactory(constellix.NewDefaultConfig, constellix.NewDNSProviderConfig),
+	"corenetworks":     newProviderFactory(corenetworks.NewDefaultConfig, corenetworks.NewDNSProviderConfig),
+	"cpanel":           newProviderFactory(cpanel.NewDefaultConfig, cpanel.NewDNSProviderConfig),
+	"curanet":          newProviderFactory(curanet.NewDefaultConfig, curanet.NewDNSProviderConfig),
+	"czechia":          newProviderFactory(czechia.NewDefaultConfig, czechia.NewDNSProviderConfig),
+	"dandomain":        newProviderFactory(dandomain.NewDefaultConfig, dandomain.NewDNSProviderConfig),
+	"ddnss":            newProviderFactory(ddnss.NewDefaultConfig, ddnss.NewDNSProviderConfig),
+	"derak":            newProviderFactory(derak.NewDefaultConfig, derak.NewDNSProviderConfig),
+	"desec":            newProviderFactory(desec.NewDefaultConfig, desec.NewDNSProviderConfig),
+	"designate":        newProviderFactory(designate.NewDefaultConfig, designate.NewDNSProviderConfig),
+	"digitalocean":     newProviderFactory(digitalocean.NewDefaultConfig, digitalocean.NewDNSProviderConfig),
+	"dinahosting":      newProviderFactory(dinahosting.NewDefaultConfig, dinahosting.NewDNSProviderConfig),
+	"directadmin":      newProviderFactory(directadmin.NewDefaultConfig, directadmin.NewDNSProviderConfig),
+	"dns51":            newProviderFactory(dns51.NewDefaultConfig, dns51.NewDNSProviderConfig),
+	"dnscale":          newProviderFactory(dnscale.NewDefaultConfig, dnscale.NewDNSProviderConfig),
+	"dnsexit":          newProviderFactory(dnsexit.NewDefaultConfig, dnsexit.NewDNSProviderConfig),
+	"dnshomede":        newProviderFactory(dnshomede.NewDefaultConfig, dnshomede.NewDNSProviderConfig),
+	"dnsimple":         newProviderFactory(dnsimple.NewDefaultConfig, dnsimple.NewDNSProviderConfig),
+	"dnsla":            newProviderFactory(dnsla.NewDefaultConfig, dnsla.NewDNSProviderConfig),
+	"dnsmadeeasy":      newProviderFactory(dnsmadeeasy.NewDefaultConfig, dnsmadeeasy.NewDNSProviderConfig),
+	"dnsservices":      newProviderFactory(dnsservices.NewDefaultConfig, dnsservices.NewDNSProviderConfig),
+	"dnsupdate":        newProviderFactory(dnsupdate.NewDefaultConfig, dnsupdate.NewDNSProviderConfig),
+	"dode":             newProviderFactory(dode.NewDefaultConfig, dode.NewDNSProviderConfig),
+	"domainnameshop":   newProviderFactory(domeneshop.NewDefaultConfig, domeneshop.NewDNSProviderConfig),
+	"domeneshop":       newProviderFactory(domeneshop.NewDefaultConfig, domeneshop.NewDNSProviderConfig),
+	"dreamhost":        newProviderFactory(dreamhost.NewDefaultConfig, dreamhost.NewDNSProviderConfig),
+	"duckdns":          newProviderFactory(duckdns.NewDefaultConfig, duckdns.NewDNSProviderConfig),
+	"dyn":              newProviderFactory(dyn.NewDefaultConfig, dyn.NewDNSProviderConfig),
+	"dynadot":          newProviderFactory(dynadot.NewDefaultConfig, dynadot.NewDNSProviderConfig),
+	"dyndnsfree":       newProviderFactory(dyndnsfree.NewDefaultConfig, dyndnsfree.NewDNSProviderConfig),
+	"dynu":             newProviderFactory(dynu.NewDefaultConfig, dynu.NewDNSProviderConfig),
+	"easydns":          newProviderFactory(easydns.NewDefaultConfig, easydns.NewDNSProviderConfig),
+	"edgecenter":       newProviderFactory(edgecenter.NewDefaultConfig, edgecenter.NewDNSProviderConfig),
+	"edgedns":          newProviderFactory(edgedns.NewDefaultConfig, edgedns.NewDNSProviderConfig),
+	"edgeone":          newProviderFactory(edgeone.NewDefaultConfig, edgeone.NewDNSProviderConfig),
+	"efficientip":      newProviderFactory(efficientip.NewDefaultConfig, efficientip.NewDNSProviderConfig),
+	"epik":             newProviderFactory(epik.NewDefaultConfig, epik.NewDNSProviderConfig),
+	"eurodns":          newProviderFactory(eurodns.NewDefaultConfig, eurodns.NewDNSProviderConfig),
+	"euserv":           newProviderFactory(euserv.NewDefaultConfig, euserv.NewDNSProviderConfig),
+	"excedo":           newProviderFactory(excedo.NewDefaultConfig, excedo.NewDNSProviderConfig),
+	"exoscale":         newProviderFactory(exoscale.NewDefaultConfig, exoscale.NewDNSProviderConfig),
+	"f5xc":             newProviderFactory(f5xc.NewDefaultConfig, f5xc.NewDNSProviderConfig),
+	"fastdns":          newProviderFactory(edgedns.NewDefaultConfig, edgedns.NewDNSProviderConfig),
+	"fornex":           newProviderFactory(fornex.NewDefaultConfig, fornex.NewDNSProviderConfig),
+	"freemyip":         newProviderFactory(freemyip.NewDefaultConfig, freemyip.NewDNSProviderConfig),
+	"gandi":            newProviderFactory(gandi.NewDefaultConfig, gandi.NewDNSProviderConfig),
+	"gandiv5":          newProviderFactory(gandiv5.NewDefaultConfig, gandiv5.NewDNSProviderConfig),
+	"gcloud":           newProviderFactory(gcloud.NewDefaultConfig, gcloud.NewDNSProviderConfig),
+	"gcore":            newProviderFactory(gcore.NewDefaultConfig, gcore.NewDNSProviderConfig),
+	"gehirn":           newProviderFactory(gehirn.NewDefaultConfig, gehirn.NewDNSProviderConfig),
+	"gigahostno":       newProviderFactory(gigahostno.NewDefaultConfig, gigahostno.NewDNSProviderConfig),
+	"glesys":           newProviderFactory(glesys.NewDefaultConfig, glesys.NewDNSProviderConfig),
+	"gname":            newProviderFactory(gname.NewDefaultConfig, gname.NewDNSProviderConfig),
+	"godaddy":          newProviderFactory(godaddy.NewDefaultConfig, godaddy.NewDNSProviderConfig),
+	"gravity":          newProviderFactory(gravity.NewDefaultConfig, gravity.NewDNSProviderConfig),
+	"hetzner":          newProviderFactory(hetzner.NewDefaultConfig, hetzner.NewDNSProviderConfig),
+	"hostingde":        newProviderFactory(hostingde.NewDefaultConfig, hostingde.NewDNSProviderConfig),
+	"hostinger":        newProviderFactory(hostinger.NewDefaultConfig, hostinger.NewDNSProviderConfig),
+	"hostingnl":        newProviderFactory(hostingnl.NewDefaultConfig, hostingnl.NewDNSProviderConfig),
+	"hosttech":         newProviderFactory(hosttech.NewDefaultConfig, hosttech.NewDNSProviderConfig),
+	"hostup":           newProviderFactory(hostup.NewDefaultConfig, hostup.NewDNSProviderConfig),
+	"httpnet":          newProviderFactory(httpnet.NewDefaultConfig, httpnet.NewDNSProviderConfig),
+	"httpreq":          newProviderFactory(httpreq.NewDefaultConfig, httpreq.NewDNSProviderConfig),
+	"huaweicloud":      newProviderFactory(huaweicloud.NewDefaultConfig, huaweicloud.NewDNSProviderConfig),
+	"hurricane":        newProviderFactory(hurricane.NewDefaultConfig, hurricane.NewDNSProviderConfig),
+	"hyperone":         newProviderFactory(hyperone.NewDefaultConfig, hyperone.NewDNSProviderConfig),
+	"ibmcloud":         newProviderFactory(ibmcloud.NewDefaultConfig, ibmcloud.NewDNSProviderConfig),
+	"iijdpf":           newProviderFactory(iijdpf.NewDefaultConfig, iijdpf.NewDNSProviderConfig),
+	"infoblox":         newProviderFactory(infoblox.NewDefaultConfig, infoblox.NewDNSProviderConfig),
+	"infomaniak":       newProviderFactory(infomaniak.NewDefaultConfig, infomaniak.NewDNSProviderConfig),
+	"internetbs":       newProviderFactory(internetbs.NewDefaultConfig, internetbs.NewDNSProviderConfig),
+	"inwx":             newProviderFactory(inwx.NewDefaultConfig, inwx.NewDNSProviderConfig),
+	"ionos":            newProviderFactory(ionos.NewDefaultConfig, ionos.NewDNSProviderConfig),
+	"ionoscloud":       newProviderFactory(ionoscloud.NewDefaultConfig, ionoscloud.NewDNSProviderConfig),
+	"ipv64":            newProviderFactory(ipv64.NewDefaultConfig, ipv64.NewDNSProviderConfig),
+	"ispconfig":        newProviderFactory(ispconfig.NewDefaultConfig, ispconfig.NewDNSProviderConfig),
+	"ispconfigddns":    newProviderFactory(ispconfigddns.NewDefaultConfig, ispconfigddns.NewDNSProviderConfig),
+	"jdcloud":          newProviderFactory(jdcloud.NewDefaultConfig, jdcloud.NewDNSProviderConfig),
+	"joker":            newProviderFactory(joker.NewDefaultConfig, joker.NewDNSProviderConfig),
+	"katapult":         newProviderFactory(katapult.NewDefaultConfig, katapult.NewDNSProviderConfig),
+	"keyhelp":          newProviderFactory(keyhelp.NewDefaultConfig, keyhelp.NewDNSProviderConfig),
+	"leaseweb":         newProviderFactory(leaseweb.NewDefaultConfig, leaseweb.NewDNSProviderConfig),
+	"liara":            newProviderFactory(liara.NewDefaultConfig, liara.NewDNSProviderConfig),
+	"lightsail":        newProviderFactory(lightsail.NewDefaultConfig, lightsail.NewDNSProviderConfig),
+	"limacity":         newProviderFactory(limacity.NewDefaultConfig, limacity.NewDNSProviderConfig),
+	"linode":           newProviderFactory(linode.NewDefaultConfig, linode.NewDNSProviderConfig),
+	"linodev4":         newProviderFactory(linode.NewDefaultConfig, linode.NewDNSProviderConfig),
+	"liquidweb":        newProviderFactory(liquidweb.NewDefaultConfig, liquidweb.NewDNSProviderConfig),
+	"loopia":           newProviderFactory(loopia.NewDefaultConfig, loopia.NewDNSProviderConfig),
+	"luadns":           newProviderFactory(luadns.NewDefaultConfig, luadns.NewDNSProviderConfig),
+	"mailinabox":       newProviderFactory(mailinabox.NewDefaultConfig, mailinabox.NewDNSProviderConfig),
+	"manageengine":     newProviderFactory(manageengine.NewDefaultConfig, manageengine.NewDNSProviderConfig),
+	"metaname":         newProviderFactory(metaname.NewDefaultConfig, metaname.NewDNSProviderConfig),
+	"metaregistrar":    newProviderFactory(metaregistrar.NewDefaultConfig, metaregistrar.NewDNSProviderConfig),
+	"mijnhost":         newProviderFactory(mijnhost.NewDefaultConfig, mijnhost.NewDNSProviderConfig),
+	"mittwald":         newProviderFactory(mittwald.NewDefaultConfig, mittwald.NewDNSProviderConfig),
+	"myaddr":           newProviderFactory(myaddr.NewDefaultConfig, myaddr.NewDNSProviderConfig),
+	"mydnsjp":          newProviderFactory(mydnsjp.NewDefaultConfig, mydnsjp.NewDNSProviderConfig),
+	"mythicbeasts":     newProviderFactoryWithError(mythicbeasts.NewDefaultConfig, mythicbeasts.NewDNSProviderConfig),
+	"namecheap":        newProviderFactory(namecheap.NewDefaultConfig, namecheap.NewDNSProviderConfig),
+	"namedotcom":       newProviderFactory(namedotcom.NewDefaultConfig, namedotcom.NewDNSProviderConfig),
+	"namesilo":         newProviderFactory(namesilo.NewDefaultConfig, namesilo.NewDNSProviderConfig),
+	"namesurfer":       newProviderFactory(namesurfer.NewDefaultConfig, namesurfer.NewDNSProviderConfig),
+	"nearlyfreespeech": newProviderFactory(nearlyfreespeech.NewDefaultConfig, nearlyfreespeech.NewDNSProviderConfig),
+	"nederhost":        newProviderFactory(nederhost.NewDefaultConfig, nederhost.NewDNSProviderConfig),
+	"neodigit":         newProviderFactory(neodigit.NewDefaultConfig, neodigit.NewDNSProviderConfig),
+	"netcup":           newProviderFactory(netcup.NewDefaultConfig, netcup.NewDNSProviderConfig),
+	"netlify":          newProviderFactory(netlify.NewDefaultConfig, netlify.NewDNSProviderConfig),
+	"netnod":           newProviderFactory(netnod.NewDefaultConfig, netnod.NewDNSProviderConfig),
+	"ngenix":           newProviderFactory(ngenix.NewDefaultConfig, ngenix.NewDNSProviderConfig),
+	"nicmanager":       newProviderFactory(nicmanager.NewDefaultConfig, nicmanager.NewDNSProviderConfig),
+	"nicru":            newProviderFactory(nicru.NewDefaultConfig, nicru.NewDNSProviderConfig),
+	"nifcloud":         newProviderFactory(nifcloud.NewDefaultConfig, nifcloud.NewDNSProviderConfig),
+	"njalla":           newProviderFactory(njalla.NewDefaultConfig, njalla.NewDNSProviderConfig),
+	"nodion":           newProviderFactory(nodion.NewDefaultConfig, nodion.NewDNSProviderConfig),
+	"ns1":              newProviderFactory(ns1.NewDefaultConfig, ns1.NewDNSProviderConfig),
+	"octenium":         newProviderFactory(octenium.NewDefaultConfig, octenium.NewDNSProviderConfig),
+	"omglol":           newProviderFactory(omglol.NewDefaultConfig, omglol.NewDNSProviderConfig),
+	"onecloudru":       newProviderFactory(onecloudru.NewDefaultConfig, onecloudru.NewDNSProviderConfig),
+	"onlinenet":        newProviderFactory(onlinenet.NewDefaultConfig, onlinenet.NewDNSProviderConfig),
+	"openprovider":     newProviderFactory(openprovider.NewDefaultConfig, openprovider.NewDNSProviderConfig),
+	"opusdns":          newProviderFactory(opusdns.NewDefaultConfig, opusdns.NewDNSProviderConfig),
+	"oraclecloud":      newProviderFactory(oraclecloud.NewDefaultConfig, oraclecloud.NewDNSProviderConfig),
+	"otc":              newProviderFactory(otc.NewDefaultConfig, otc.NewDNSProviderConfig),
+	"ovh":              newProviderFactory(ovh.NewDefaultConfig, ovh.NewDNSProviderConfig),
+	"pdns":             newProviderFactory(pdns.NewDefaultConfig, pdns.NewDNSProviderConfig),
+	"plesk":            newProviderFactory(plesk.NewDefaultConfig, plesk.NewDNSProviderConfig),
+	"pointdns":         newProviderFactory(pointdns.NewDefaultConfig, pointdns.NewDNSProviderConfig),
+	"porkbun":          newProviderFactory(porkbun.NewDefaultConfig, porkbun.NewDNSProviderConfig),
+	"poweradmin":       newProviderFactory(poweradmin.NewDefaultConfig, poweradmin.NewDNSProviderConfig),
+	"rackspace":        newProviderFactory(rackspace.NewDefaultConfig, rackspace.NewDNSProviderConfig),
+	"rage4":            newProviderFactory(rage4.NewDefaultConfig, rage4.NewDNSProviderConfig),
+	"rainyun":          newProviderFactory(rainyun.NewDefaultConfig, rainyun.NewDNSProviderConfig),
+	"rcodezero":        newProviderFactory(rcodezero.NewDefaultConfig, rcodezero.NewDNSProviderConfig),
+	"regfish":          newProviderFactory(regfish.NewDefaultConfig, regfish.NewDNSProviderConfig),
+	"regru":            newProviderFactory(regru.NewDefaultConfig, regru.NewDNSProviderConfig),
+	"rfc2136":          newProviderFactory(dnsupdate.NewDefaultConfig, dnsupdate.NewDNSProviderConfig),
+	"rimuhosting":      newProviderFactory(rimuhosting.NewDefaultConfig, rimuhosting.NewDNSProviderConfig),
+	"route53":          newProviderFactory(route53.NewDefaultConfig, route53.NewDNSProviderConfig),
+	"safedns":          newProviderFactory(safedns.NewDefaultConfig, safedns.NewDNSProviderConfig),
+	"sakuracloud":      newProviderFactory(sakuracloud.NewDefaultConfig, sakuracloud.NewDNSProviderConfig),
+	"scaleway":         newProviderFactory(scaleway.NewDefaultConfig, scaleway.NewDNSProviderConfig),
+	"scannet":          newProviderFactory(scannet.NewDefaultConfig, scannet.NewDNSProviderConfig),
+	"selectel":         newProviderFactory(selectel.NewDefaultConfig, selectel.NewDNSProviderConfig),
+	"selectelv2":       newProviderFactory(selectelv2.NewDefaultConfig, selectelv2.NewDNSProviderConfig),
+	"selfhostde":       newProviderFactory(selfhostde.NewDefaultConfig, selfhostde.NewDNSProviderConfig),
+	"servercow":        newProviderFactory(servercow.NewDefaultConfig, servercow.NewDNSProviderConfig),
+	"shellrent":        newProviderFactory(shellrent.NewDefaultConfig, shellrent.NewDNSProviderConfig),
+	"simply":           newProviderFactory(simply.NewDefaultConfig, simply.NewDNSProviderConfig),
+	"sonic":            newProviderFactory(sonic.NewDefaultConfig, sonic.NewDNSProviderConfig),
+	"spaceship":        newProviderFactory(spaceship.NewDefaultConfig, spaceship.NewDNSProviderConfig),
+	"stackpath":        newProviderFactory(stackpath.NewDefaultConfig, stackpath.NewDNSProviderConfig),
+	"syse":             newProviderFactory(syse.NewDefaultConfig, syse.NewDNSProviderConfig),
+	"technitium":       newProviderFactory(technitium.NewDefaultConfig, technitium.NewDNSProviderConfig),
+	"tele3":            newProviderFactory(tele3.NewDefaultConfig, tele3.NewDNSProviderConfig),
+	"tencentcloud":     newProviderFactory(tencentcloud.NewDefaultConfig, tencentcloud.NewDNSProviderConfig),
+	"timewebcloud":     newProviderFactory(timewebcloud.NewDefaultConfig, timewebcloud.NewDNSProviderConfig),
+	"todaynic":         newProviderFactory(todaynic.NewDefaultConfig, todaynic.NewDNSProviderConfig),
+	"transip":          newProviderFactory(transip.NewDefaultConfig, transip.NewDNSProviderConfig),
+	"ucloud":           newProviderFactory(ucloud.NewDefaultConfig, ucloud.NewDNSProviderConfig),
+	"ultradns":         newProviderFactory(ultradns.NewDefaultConfig, ultradns.NewDNSProviderConfig),
+	"uniteddomains":    newProviderFactory(uniteddomains.NewDefaultConfig, uniteddomains.NewDNSProviderConfig),
+	"variomedia":       newProviderFactory(variomedia.NewDefaultConfig, variomedia.NewDNSProviderConfig),
+	"veesp":            newProviderFactory(veesp.NewDefaultConfig, veesp.NewDNSProviderConfig),
+	"vegadns":          newProviderFactory(vegadns.NewDefaultConfig, vegadns.NewDNSProviderConfig),
+	"vercel":           newProviderFactory(vercel.NewDefaultConfig, vercel.NewDNSProviderConfig),
+	"versio":           newProviderFactory(versio.NewDefaultConfig, versio.NewDNSProviderConfig),
+	"vinyldns":         newProviderFactory(vinyldns.NewDefaultConfig, vinyldns.NewDNSProviderConfig),
+	"virtualname":      newProviderFactory(virtualname.NewDefaultConfig, virtualname.NewDNSProviderConfig),
+	"vkcloud":          newProviderFactory(vkcloud.NewDefaultConfig, vkcloud.NewDNSProviderConfig),
+	"volcengine":       newProviderFactory(volcengine.NewDefaultConfig, volcengine.NewDNSProviderConfig),
+	"vscale":           newProviderFactory(vscale.NewDefaultConfig, vscale.NewDNSProviderConfig),
+	"vultr":            newProviderFactory(vultr.NewDefaultConfig, vultr.NewDNSProviderConfig),
+	"wannafind":        newProviderFactory(wannafind.NewDefaultConfig, wannafind.NewDNSProviderConfig),
+	"webnames":         newProviderFactory(webnamesru.NewDefaultConfig, webnamesru.NewDNSProviderConfig),
+	"webnamesca":       newProviderFactory(webnamesca.NewDefaultConfig, webnamesca.NewDNSProviderConfig),
+	"webnamesru":       newProviderFactory(webnamesru.NewDefaultConfig, webnamesru.NewDNSProviderConfig),
+	"websupport":       newProviderFactory(websupport.NewDefaultConfig, websupport.NewDNSProviderConfig),
+	"wedos":            newProviderFactory(wedos.NewDefaultConfig, wedos.NewDNSProviderConfig),
+	"westcn":           newProviderFactory(westcn.NewDefaultConfig, westcn.NewDNSProviderConfig),
+	"xinnet":           newProviderFactory(xinnet.NewDefaultConfig, xinnet.NewDNSProviderConfig),
+	"yandex":           newProviderFactory(yandex.NewDefaultConfig, yandex.NewDNSProviderConfig),
+	"yandex360":        newProviderFactory(yandex360.NewDefaultConfig, yandex360.NewDNSProviderConfig),
+	"yandexcloud":      newProviderFactory(yandexcloud.NewDefaultConfig, yandexcloud.NewDNSProviderConfig),
+	"zilore":           newProviderFactory(zilore.NewDefaultConfig, zilore.NewDNSProviderConfig),
+	"zoneedit":         newProviderFactory(zoneedit.NewDefaultConfig, zoneedit.NewDNSProviderConfig),
+	"zoneee":           newProviderFactory(zoneee.NewDefaultConfig, zoneee.NewDNSProviderConfig),
+	"zonomi":           newProviderFactory(zonomi.NewDefaultConfig, zonomi.NewDNSProviderConfig),
+}
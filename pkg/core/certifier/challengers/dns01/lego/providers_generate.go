@@ -0,0 +1,197 @@
+//go:build ignore
+
+// 根据 lego 的 DNS 提供商注册表（`dns.NewDNSChallengeProviderByName`）生成 providers.go。
+// 升级 lego 版本后执行 `go generate ./pkg/core/certifier/challengers/dns01/lego/` 即可同步其新增的提供商。
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const legoModule = "github.com/go-acme/lego/v5"
+
+// 需在服务端执行命令或人工介入的提供商，故不予支持。
+var excludedProviders = []string{"exec", "manual"}
+
+type provider struct {
+	name       string
+	pkgName    string
+	importPath string
+	withError  bool
+}
+
+func main() {
+	if err := generate("providers.go"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func generate(output string) error {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", legoModule).Output()
+	if err != nil {
+		return fmt.Errorf("failed to locate module %s: %w", legoModule, err)
+	}
+
+	moduleDir := strings.TrimSpace(string(out))
+	registry := filepath.Join(moduleDir, "providers", "dns", "zz_gen_dns_providers.go")
+	providers, err := parseRegistry(registry)
+	if err != nil {
+		return err
+	}
+
+	for i, p := range providers {
+		pkgDir := filepath.Join(moduleDir, strings.TrimPrefix(p.importPath, legoModule+"/"))
+		withError, err := isDefaultConfigWithError(pkgDir)
+		if err != nil {
+			return err
+		}
+
+		providers[i].withError = withError
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by providers_generate.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package lego\n\n")
+	buf.WriteString("import (\n")
+	imports := make([]string, 0)
+	for _, p := range providers {
+		if !slices.Contains(imports, p.importPath) {
+			imports = append(imports, p.importPath)
+		}
+	}
+	slices.Sort(imports)
+	for _, importPath := range imports {
+		fmt.Fprintf(&buf, "\t%q\n", importPath)
+	}
+	buf.WriteString(")\n\n")
+	buf.WriteString("// 支持的 lego DNS 提供商，由 lego 的 `dns.NewDNSChallengeProviderByName` 生成。\n")
+	fmt.Fprintf(&buf, "// 其中 %s 需在服务端执行命令或人工介入，故不予支持。\n", strings.Join(quote(excludedProviders), "、"))
+	buf.WriteString("var providerFactories = map[string]providerFactory{\n")
+	for _, p := range providers {
+		factory := "newProviderFactory"
+		if p.withError {
+			factory = "newProviderFactoryWithError"
+		}
+		fmt.Fprintf(&buf, "\t%q: %s(%s.NewDefaultConfig, %s.NewDNSProviderConfig),\n", p.name, factory, p.pkgName, p.pkgName)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	return os.WriteFile(output, src, 0o644)
+}
+
+// 解析 lego 的 DNS 提供商注册表中的 switch 语句，形如：
+//
+//	case "webnamesru", "webnames":
+//		return webnamesru.NewDNSProvider()
+func parseRegistry(filename string) ([]provider, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	importPaths := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		importPaths[filepath.Base(importPath)] = importPath
+	}
+
+	providers := make([]provider, 0)
+	ast.Inspect(file, func(n ast.Node) bool {
+		clause, ok := n.(*ast.CaseClause)
+		if !ok || len(clause.Body) != 1 {
+			return true
+		}
+
+		ret, ok := clause.Body[0].(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			return true
+		}
+		call, ok := ret.Results[0].(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "NewDNSProvider" {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok || importPaths[pkg.Name] == "" {
+			return true
+		}
+
+		for _, expr := range clause.List {
+			lit, ok := expr.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				continue
+			}
+
+			name, _ := strconv.Unquote(lit.Value)
+			if slices.Contains(excludedProviders, pkg.Name) {
+				continue
+			}
+
+			providers = append(providers, provider{name: name, pkgName: pkg.Name, importPath: importPaths[pkg.Name]})
+		}
+
+		return true
+	})
+
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no providers found in %s", filename)
+	}
+
+	slices.SortFunc(providers, func(a, b provider) int { return strings.Compare(a.name, b.name) })
+	return providers, nil
+}
+
+// 判断提供商包中的 `NewDefaultConfig` 是否会返回 error。
+func isDefaultConfigWithError(pkgDir string) (bool, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, pkgDir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", pkgDir, err)
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || fn.Name.Name != "NewDefaultConfig" {
+					continue
+				}
+
+				return fn.Type.Results != nil && fn.Type.Results.NumFields() == 2, nil
+			}
+		}
+	}
+
+	return false, fmt.Errorf("no NewDefaultConfig found in %s", pkgDir)
+}
+
+func quote(names []string) []string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, strconv.Quote(name))
+	}
+	return quoted
+}
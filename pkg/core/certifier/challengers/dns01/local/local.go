@@ -0,0 +1,78 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/challenge/dns01"
+	"github.com/miekg/dns"
+
+	"github.com/certimate-go/certimate/pkg/core"
+)
+
+type ChallengerConfig struct {
+	// 内置权威 DNS 服务器的区域，须与 [ServerConfig.Zone] 一致。
+	Zone string `json:"zone"`
+	// 记录存储，须与内置权威 DNS 服务器所查询的存储一致。
+	Store                 RecordStore `json:"-"`
+	DnsPropagationTimeout int         `json:"dnsPropagationTimeout,omitempty"`
+}
+
+type challenger struct {
+	config *ChallengerConfig
+	store  RecordStore
+	zone   string
+}
+
+var _ core.ACMEChallenger = (*challenger)(nil)
+
+func NewChallenger(config *ChallengerConfig) (core.ACMEChallenger, error) {
+	if config == nil {
+		return nil, fmt.Errorf("the configuration of the acme challenge provider is nil")
+	}
+	if config.Zone == "" {
+		return nil, fmt.Errorf("local: the zone of the built-in dns responder is not specified")
+	}
+	if config.Store == nil {
+		return nil, fmt.Errorf("local: the record store of the built-in dns responder is not specified")
+	}
+
+	return &challenger{
+		config: config,
+		store:  config.Store,
+		zone:   dns.CanonicalName(config.Zone),
+	}, nil
+}
+
+func (c *challenger) Present(ctx context.Context, domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(ctx, domain, keyAuth)
+
+	// lego 会跟随 CNAME 得到最终的记录名称，其须位于内置权威 DNS 服务器的区域内
+	if !dns.IsSubDomain(c.zone, dns.CanonicalName(info.EffectiveFQDN)) {
+		return fmt.Errorf("local: '%s' is not delegated to the built-in dns responder, please create the CNAME record: %s", info.FQDN, CNAMERecord(domain, c.config.Zone))
+	}
+
+	return c.store.Put(info.EffectiveFQDN, info.Value)
+}
+
+func (c *challenger) CleanUp(ctx context.Context, domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(ctx, domain, keyAuth)
+	return c.store.Delete(info.EffectiveFQDN, info.Value)
+}
+
+func (c *challenger) Timeout() (timeout, interval time.Duration) {
+	if c.config.DnsPropagationTimeout > 0 {
+		return time.Duration(c.config.DnsPropagationTimeout) * time.Second, dns01.DefaultPollingInterval
+	}
+
+	return dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+}
+
+// 返回将域名的质询记录委派至内置权威 DNS 服务器所需的 CNAME 记录，
+// 形如 "_acme-challenge.example.com. CNAME example.com.acme.example.net."。
+func CNAMERecord(domain, zone string) string {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "*."))
+	return fmt.Sprintf("%s CNAME %s", dns.Fqdn("_acme-challenge."+domain), dns.Fqdn(domain+"."+strings.ToLower(strings.TrimSuffix(zone, "."))))
+}
@@ -0,0 +1,211 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+type ServerConfig struct {
+	// 监听地址，形如 "0.0.0.0:53"、":53"。
+	// 零值时默认值 ":53"。
+	ListenAddress string `json:"listenAddress,omitempty"`
+	// 权威区域，形如 "acme.example.com"。
+	Zone string `json:"zone"`
+	// 权威区域的域名服务器主机名，用于 NS 及 SOA 记录。
+	// 零值时默认值 "ns.<Zone>"。
+	Nameserver string `json:"nameserver,omitempty"`
+	// 应答的 TTL。
+	// 零值时默认值 60。
+	TTL int `json:"ttl,omitempty"`
+	// 记录存储。应答时合并查询各存储中的记录。
+	Stores []RecordStore `json:"-"`
+}
+
+// 内置的权威 DNS 服务器，仅应答权威区域内的 TXT、NS、SOA 查询。
+type Server struct {
+	config *ServerConfig
+	logger *slog.Logger
+
+	zone    string
+	servers []*dns.Server
+}
+
+func NewServer(config *ServerConfig, logger *slog.Logger) (*Server, error) {
+	if config == nil {
+		return nil, fmt.Errorf("the configuration of the dns responder is nil")
+	}
+	if config.Zone == "" {
+		return nil, fmt.Errorf("the zone of the dns responder is not specified")
+	}
+	if _, ok := dns.IsDomainName(config.Zone); !ok {
+		return nil, fmt.Errorf("the zone '%s' of the dns responder is invalid", config.Zone)
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Server{
+		config: config,
+		logger: logger,
+		zone:   dns.CanonicalName(config.Zone),
+	}, nil
+}
+
+// 启动 UDP 及 TCP 服务，直至两者均已开始监听或任一启动失败。
+func (s *Server) Start() error {
+	addr := s.config.ListenAddress
+	if addr == "" {
+		addr = ":53"
+	}
+
+	errs := make(chan error, 2)
+	for _, network := range []string{"udp", "tcp"} {
+		started := make(chan struct{})
+		server := &dns.Server{
+			Addr:              addr,
+			Net:               network,
+			Handler:           dns.HandlerFunc(s.serveDNS),
+			NotifyStartedFunc: func() { close(started) },
+		}
+		s.servers = append(s.servers, server)
+
+		go func() {
+			if err := server.ListenAndServe(); err != nil {
+				errs <- fmt.Errorf("failed to serve dns over %s: %w", network, err)
+			}
+		}()
+
+		select {
+		case <-started:
+		case err := <-errs:
+			s.Shutdown(context.Background())
+			return err
+		}
+	}
+
+	s.logger.Info(fmt.Sprintf("dns responder is serving zone '%s' on %s", s.zone, addr))
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, server := range s.servers {
+		if err := server.ShutdownContext(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	s.servers = nil
+
+	return errors.Join(errs...)
+}
+
+func (s *Server) serveDNS(w dns.ResponseWriter, req *dns.Msg) {
+	resp := new(dns.Msg)
+	resp.SetReply(req)
+	resp.Authoritative = true
+
+	if len(req.Question) != 1 {
+		resp.SetRcode(req, dns.RcodeFormatError)
+		w.WriteMsg(resp)
+		return
+	}
+
+	question := req.Question[0]
+	name := dns.CanonicalName(question.Name)
+	if !dns.IsSubDomain(s.zone, name) {
+		resp.Authoritative = false
+		resp.SetRcode(req, dns.RcodeRefused)
+		w.WriteMsg(resp)
+		return
+	}
+
+	switch question.Qtype {
+	case dns.TypeTXT, dns.TypeANY:
+		values, err := s.lookup(name)
+		if err != nil {
+			s.logger.Warn("dns responder: failed to lookup records", slog.String("name", name), slog.Any("error", err))
+			resp.SetRcode(req, dns.RcodeServerFailure)
+			w.WriteMsg(resp)
+			return
+		}
+
+		for _, value := range values {
+			resp.Answer = append(resp.Answer, &dns.TXT{
+				Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: s.ttl()},
+				Txt: []string{value},
+			})
+		}
+
+	case dns.TypeNS:
+		if name == s.zone {
+			resp.Answer = append(resp.Answer, s.nsRecord())
+		}
+
+	case dns.TypeSOA:
+		if name == s.zone {
+			resp.Answer = append(resp.Answer, s.soaRecord())
+		}
+	}
+
+	if len(resp.Answer) == 0 {
+		resp.Ns = append(resp.Ns, s.soaRecord())
+	}
+
+	w.WriteMsg(resp)
+}
+
+func (s *Server) lookup(name string) ([]string, error) {
+	values := make([]string, 0)
+	for _, store := range s.config.Stores {
+		res, err := store.Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, res...)
+	}
+
+	return values, nil
+}
+
+func (s *Server) ttl() uint32 {
+	if s.config.TTL > 0 {
+		return uint32(s.config.TTL)
+	}
+
+	return 60
+}
+
+func (s *Server) nameserver() string {
+	if s.config.Nameserver != "" {
+		return dns.Fqdn(strings.ToLower(s.config.Nameserver))
+	}
+
+	return "ns." + s.zone
+}
+
+func (s *Server) nsRecord() dns.RR {
+	return &dns.NS{
+		Hdr: dns.RR_Header{Name: s.zone, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: s.ttl()},
+		Ns:  s.nameserver(),
+	}
+}
+
+func (s *Server) soaRecord() dns.RR {
+	return &dns.SOA{
+		Hdr:     dns.RR_Header{Name: s.zone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: s.ttl()},
+		Ns:      s.nameserver(),
+		Mbox:    "hostmaster." + s.zone,
+		Serial:  uint32(time.Now().Unix()),
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		Minttl:  s.ttl(),
+	}
+}
@@ -0,0 +1,192 @@
+package local
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// 记录的最长保留时间，避免质询异常中断后残留过期记录。
+const recordLifetime = time.Hour
+
+// TXT 记录存储。
+type RecordStore interface {
+	Put(fqdn, value string) error
+	Delete(fqdn, value string) error
+	// 查询指定域名的所有未过期的 TXT 记录值。
+	Lookup(fqdn string) ([]string, error)
+}
+
+type record struct {
+	FQDN     string    `json:"fqdn"`
+	Value    string    `json:"value"`
+	ExpireAt time.Time `json:"expireAt"`
+}
+
+// 进程内的 TXT 记录存储。
+type memoryRecordStore struct {
+	records map[string]*record
+	mtx     sync.Mutex
+}
+
+var _ RecordStore = (*memoryRecordStore)(nil)
+
+func NewMemoryRecordStore() RecordStore {
+	return &memoryRecordStore{records: make(map[string]*record)}
+}
+
+func (s *memoryRecordStore) Put(fqdn, value string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.records[recordKey(fqdn, value)] = &record{
+		FQDN:     dns.CanonicalName(fqdn),
+		Value:    value,
+		ExpireAt: time.Now().Add(recordLifetime),
+	}
+	return nil
+}
+
+func (s *memoryRecordStore) Delete(fqdn, value string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.records, recordKey(fqdn, value))
+	return nil
+}
+
+func (s *memoryRecordStore) Lookup(fqdn string) ([]string, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	fqdn = dns.CanonicalName(fqdn)
+	values := make([]string, 0)
+	for key, rec := range s.records {
+		if time.Now().After(rec.ExpireAt) {
+			delete(s.records, key)
+			continue
+		}
+
+		if rec.FQDN == fqdn {
+			values = append(values, rec.Value)
+		}
+	}
+
+	return values, nil
+}
+
+// 基于文件的 TXT 记录存储，用于与其他进程（如证书申请子进程）共享记录。
+type fileRecordStore struct {
+	path string
+}
+
+var _ RecordStore = (*fileRecordStore)(nil)
+
+// 创建基于文件的 TXT 记录存储。
+// 存储目录不存在时将以 0700 权限创建；已存在时须为当前用户所有、且不允许其他用户访问，
+// 以免他人预先创建同名目录后注入伪造的质询记录。
+func NewFileRecordStore(path string) (RecordStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the path of the record store is not specified")
+	}
+
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create record store directory: %w", err)
+	}
+
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat record store directory: %w", err)
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("the record store path '%s' is not a directory", path)
+	}
+	if err := checkStoreDirSecure(fi); err != nil {
+		return nil, fmt.Errorf("the record store directory '%s' is insecure: %w", path, err)
+	}
+
+	return &fileRecordStore{path: path}, nil
+}
+
+func (s *fileRecordStore) Put(fqdn, value string) error {
+	data, err := json.Marshal(&record{
+		FQDN:     dns.CanonicalName(fqdn),
+		Value:    value,
+		ExpireAt: time.Now().Add(recordLifetime),
+	})
+	if err != nil {
+		return err
+	}
+
+	// 先写入临时文件再重命名，避免读取到不完整的内容
+	filename := s.filename(fqdn, value)
+	if err := os.WriteFile(filename+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+
+	return os.Rename(filename+".tmp", filename)
+}
+
+func (s *fileRecordStore) Delete(fqdn, value string) error {
+	if err := os.Remove(s.filename(fqdn, value)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+
+	return nil
+}
+
+func (s *fileRecordStore) Lookup(fqdn string) ([]string, error) {
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	fqdn = dns.CanonicalName(fqdn)
+	values := make([]string, 0)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.path, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		rec := &record{}
+		if err := json.Unmarshal(data, rec); err != nil {
+			continue
+		}
+
+		if time.Now().After(rec.ExpireAt) {
+			os.Remove(filepath.Join(s.path, entry.Name()))
+			continue
+		}
+
+		if rec.FQDN == fqdn {
+			values = append(values, rec.Value)
+		}
+	}
+
+	return values, nil
+}
+
+func (s *fileRecordStore) filename(fqdn, value string) string {
+	return filepath.Join(s.path, recordKey(fqdn, value)+".json")
+}
+
+func recordKey(fqdn, value string) string {
+	digest := sha256.Sum256([]byte(dns.CanonicalName(fqdn) + " " + value))
+	return hex.EncodeToString(digest[:16])
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+// +build !windows
+
+package local
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+func checkStoreDirSecure(fi os.FileInfo) error {
+	if perm := fi.Mode().Perm(); perm&0o077 != 0 {
+		return fmt.Errorf("permission %#o is too open, expected 0700", perm)
+	}
+
+	if stat, ok := fi.Sys().(*syscall.Stat_t); ok {
+		if int(stat.Uid) != os.Geteuid() {
+			return fmt.Errorf("owned by uid %d, expected %d", stat.Uid, os.Geteuid())
+		}
+	}
+
+	return nil
+}
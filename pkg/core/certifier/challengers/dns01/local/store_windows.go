@@ -0,0 +1,13 @@
+//go:build windows
+// +build windows
+
+package local
+
+import (
+	"os"
+)
+
+func checkStoreDirSecure(fi os.FileInfo) error {
+	// Windows 下的访问控制由 ACL 决定，数据目录默认仅对当前用户及管理员可写
+	return nil
+}
@@ -0,0 +1,256 @@
+package execcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/ssh"
+
+	sshtool "github.com/certimate-go/certimate/internal/tools/ssh"
+)
+
+const (
+	ShellEnvSh         = "sh"
+	ShellEnvCmd        = "cmd"
+	ShellEnvPowerShell = "powershell"
+)
+
+// 执行命令的默认超时时间。
+const DefaultTimeout = 60 * time.Second
+
+type Config struct {
+	// 远程 SSH 连接配置。
+	// 零值时在本地执行命令。
+	SSH *sshtool.Config
+	// Shell 执行环境，仅在本地执行时有效。
+	// 零值时根据操作系统决定。
+	ShellEnv string
+	// 待执行的命令。
+	Command string
+	// 执行超时时间。
+	// 零值时默认值 [DefaultTimeout]。
+	Timeout time.Duration
+}
+
+// 执行命令，并返回执行后标准输出和标准错误。
+// 参数将依次追加至命令末尾，环境变量将传递给命令的执行环境。
+func Run(ctx context.Context, config *Config, args []string, envs map[string]string) (string, string, error) {
+	if config == nil {
+		return "", "", fmt.Errorf("the configuration of the command is nil")
+	}
+	if config.Command == "" {
+		return "", "", fmt.Errorf("the command is not specified")
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if config.SSH != nil {
+		return runRemote(ctx, config.SSH, config.Command, args, envs)
+	}
+
+	return runLocal(ctx, config.ShellEnv, config.Command, args, envs)
+}
+
+// 记录日志时命令输出的最大长度（字节数）。
+const MaxLoggedOutputLength = 1024
+
+// 截断命令输出以便记录日志，避免将冗长或敏感的输出完整写入日志。
+func TruncateOutput(s string) string {
+	if len(s) <= MaxLoggedOutputLength {
+		return s
+	}
+
+	cut := MaxLoggedOutputLength
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s...(%d bytes truncated)", s[:cut], len(s)-cut)
+}
+
+func runLocal(ctx context.Context, shellEnv string, command string, args []string, envs map[string]string) (string, string, error) {
+	if shellEnv == "" {
+		if runtime.GOOS == "windows" {
+			shellEnv = ShellEnvCmd
+		} else {
+			shellEnv = ShellEnvSh
+		}
+	}
+
+	var cmd *exec.Cmd
+	switch shellEnv {
+	case ShellEnvSh:
+		cmd = exec.CommandContext(ctx, "sh", "-c", joinCommand(command, args, quotePosix))
+	case ShellEnvCmd:
+		cmd = newCmdCommand(ctx, joinCommand(command, args, quoteCmd))
+	case ShellEnvPowerShell:
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-Command", joinCommand(command, args, quotePowerShell))
+	default:
+		return "", "", fmt.Errorf("unsupported shell env '%s'", shellEnv)
+	}
+
+	cmd.WaitDelay = time.Second
+	cmd.Env = baseEnviron()
+	for _, key := range slices.Sorted(maps.Keys(envs)) {
+		cmd.Env = append(cmd.Env, key+"="+envs[key])
+	}
+
+	stdoutBuf := bytes.NewBuffer(nil)
+	cmd.Stdout = stdoutBuf
+	stderrBuf := bytes.NewBuffer(nil)
+	cmd.Stderr = stderrBuf
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return stdoutBuf.String(), stderrBuf.String(), fmt.Errorf("failed to execute command: %w", ctx.Err())
+		}
+
+		return stdoutBuf.String(), stderrBuf.String(), fmt.Errorf("failed to execute command: %w", err)
+	}
+
+	return stdoutBuf.String(), stderrBuf.String(), nil
+}
+
+func runRemote(ctx context.Context, config *sshtool.Config, command string, args []string, envs map[string]string) (string, string, error) {
+	client, err := sshtool.NewClient(config)
+	if err != nil {
+		return "", "", err
+	}
+	defer client.Close()
+
+	session, err := client.RawClient().NewSession()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create ssh session: %w", err)
+	}
+	defer session.Close()
+
+	// 多数 SSH 服务端默认不接受客户端设置的环境变量，因此以命令前缀的形式传递
+	var cmdline strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(envs)) {
+		cmdline.WriteString(key + "=" + quotePosix(envs[key]) + " ")
+	}
+	cmdline.WriteString(joinCommand(command, args, quotePosix))
+
+	stdoutBuf := bytes.NewBuffer(nil)
+	session.Stdout = stdoutBuf
+	stderrBuf := bytes.NewBuffer(nil)
+	session.Stderr = stderrBuf
+	if err := session.Start(cmdline.String()); err != nil {
+		return "", "", fmt.Errorf("failed to execute ssh command: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return stdoutBuf.String(), stderrBuf.String(), fmt.Errorf("failed to execute ssh command: %w", err)
+		}
+
+	case <-ctx.Done():
+		session.Signal(ssh.SIGKILL)
+		session.Close()
+		<-done
+		return stdoutBuf.String(), stderrBuf.String(), fmt.Errorf("failed to execute ssh command: %w", ctx.Err())
+	}
+
+	return stdoutBuf.String(), stderrBuf.String(), nil
+}
+
+func joinCommand(command string, args []string, quote func(string) string) string {
+	var sb strings.Builder
+	sb.WriteString(command)
+	for _, arg := range args {
+		sb.WriteString(" ")
+		sb.WriteString(quote(arg))
+	}
+	return sb.String()
+}
+
+// 执行本地命令时从当前进程继承的环境变量。
+// 仅传递执行命令所必需的系统环境变量，以免服务端自身的敏感配置泄露给外部命令。
+var inheritedEnvKeys = []string{
+	"PATH", "HOME", "LANG", "TZ", "TMPDIR",
+	"SYSTEMROOT", "COMSPEC", "PATHEXT", "WINDIR", "TEMP", "TMP", "USERPROFILE",
+}
+
+func baseEnviron() []string {
+	env := make([]string, 0, len(inheritedEnvKeys))
+	for _, key := range inheritedEnvKeys {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+	return env
+}
+
+// 转义为 POSIX Shell 中的单个参数。
+// 单引号内的所有字符均无特殊含义，仅需处理单引号本身。
+func quotePosix(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// 转义为 PowerShell 中的单个参数。
+// 单引号字符串内不会展开变量及子表达式，仅需将单引号（包括 PowerShell 视同单引号的弯引号）重复一次。
+func quotePowerShell(s string) string {
+	var sb strings.Builder
+	sb.WriteString("'")
+	for _, r := range s {
+		switch r {
+		case '\'', '\u2018', '\u2019', '\u201a', '\u201b':
+			sb.WriteRune(r)
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteString("'")
+	return sb.String()
+}
+
+// 转义为 cmd 中的单个参数。
+// 先按照 CommandLineToArgvW 的规则加引号，以便被调用的程序正确解析；
+// 再以 "^" 转义所有 cmd 元字符，使 cmd 不会将参数中的任何字符视为操作符或变量引用。
+func quoteCmd(s string) string {
+	var sb strings.Builder
+	sb.WriteString(`"`)
+	backslashes := 0
+	for _, r := range s {
+		switch r {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			sb.WriteString(strings.Repeat(`\`, backslashes*2+1))
+		default:
+			sb.WriteString(strings.Repeat(`\`, backslashes))
+		}
+		backslashes = 0
+		sb.WriteRune(r)
+	}
+	sb.WriteString(strings.Repeat(`\`, backslashes*2))
+	sb.WriteString(`"`)
+
+	var escaped strings.Builder
+	for _, r := range sb.String() {
+		if strings.ContainsRune(`()%!^"<>&|`, r) {
+			escaped.WriteRune('^')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
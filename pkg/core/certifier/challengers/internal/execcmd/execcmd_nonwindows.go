@@ -0,0 +1,13 @@
+//go:build !windows
+// +build !windows
+
+package execcmd
+
+import (
+	"context"
+	"os/exec"
+)
+
+func newCmdCommand(ctx context.Context, cmdline string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/D", "/S", "/C", cmdline)
+}
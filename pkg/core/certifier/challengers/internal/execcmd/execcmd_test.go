@@ -0,0 +1,116 @@
+package execcmd
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	t.Run("Posix", func(t *testing.T) {
+		testCases := []struct {
+			input    string
+			expected string
+		}{
+			{"", `''`},
+			{"abc", `'abc'`},
+			{"a b", `'a b'`},
+			{"it's", `'it'\''s'`},
+			{"$(id) `id` $HOME", "'$(id) `id` $HOME'"},
+			{"a;b|c&d", `'a;b|c&d'`},
+		}
+
+		for _, tc := range testCases {
+			assert.Equal(t, tc.expected, quotePosix(tc.input), "Input: %s", tc.input)
+		}
+	})
+
+	t.Run("PowerShell", func(t *testing.T) {
+		testCases := []struct {
+			input    string
+			expected string
+		}{
+			{"", `''`},
+			{"abc", `'abc'`},
+			{"it's", `'it''s'`},
+			{"it’s", "'it’’s'"},
+			{"$env:PATH $(whoami) `n", "'$env:PATH $(whoami) `n'"},
+			{"a;b|c&d", `'a;b|c&d'`},
+			{`a"b`, `'a"b'`},
+		}
+
+		for _, tc := range testCases {
+			assert.Equal(t, tc.expected, quotePowerShell(tc.input), "Input: %s", tc.input)
+		}
+	})
+
+	t.Run("Cmd", func(t *testing.T) {
+		testCases := []struct {
+			input    string
+			expected string
+		}{
+			{"", `^"^"`},
+			{"abc", `^"abc^"`},
+			{"a b", `^"a b^"`},
+			{"%PATH%", `^"^%PATH^%^"`},
+			{"a&b|c", `^"a^&b^|c^"`},
+			{"a^b!c", `^"a^^b^!c^"`},
+			{"<a>(b)", `^"^<a^>^(b^)^"`},
+			{`a"b`, `^"a\^"b^"`},
+			{`a\"b`, `^"a\\\^"b^"`},
+			{`a\b`, `^"a\b^"`},
+			{`a\`, `^"a\\^"`},
+		}
+
+		for _, tc := range testCases {
+			assert.Equal(t, tc.expected, quoteCmd(tc.input), "Input: %s", tc.input)
+		}
+	})
+}
+
+func TestTruncateOutput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"short", "hello", "hello"},
+		{"exact limit", strings.Repeat("a", MaxLoggedOutputLength), strings.Repeat("a", MaxLoggedOutputLength)},
+		{"over limit", strings.Repeat("a", MaxLoggedOutputLength+10), strings.Repeat("a", MaxLoggedOutputLength) + "...(10 bytes truncated)"},
+		{"multibyte boundary", strings.Repeat("a", MaxLoggedOutputLength-1) + "中文", strings.Repeat("a", MaxLoggedOutputLength-1) + "...(6 bytes truncated)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := TruncateOutput(tc.input)
+			assert.Equal(t, tc.expected, actual, "Case: %-20s", tc.name)
+		})
+	}
+}
+
+func TestRunLocal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a posix shell")
+	}
+
+	t.Run("Arguments", func(t *testing.T) {
+		args := []string{"a b", "it's", "$(echo injected)", "`echo injected`", "a;echo injected", "$HOME"}
+		stdout, _, err := Run(context.Background(), &Config{ShellEnv: ShellEnvSh, Command: `printf '%s\n'`}, args, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, args, strings.Split(strings.TrimSuffix(stdout, "\n"), "\n"))
+	})
+
+	t.Run("Environment", func(t *testing.T) {
+		t.Setenv("CERTIMATE_TEST_SECRET", "secret")
+
+		stdout, _, err := Run(context.Background(), &Config{ShellEnv: ShellEnvSh, Command: "env"}, nil, map[string]string{"CERTIMATE_ACME_ACTION": "present"})
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "CERTIMATE_ACME_ACTION=present")
+		assert.Contains(t, stdout, "PATH=")
+		assert.NotContains(t, stdout, "CERTIMATE_TEST_SECRET")
+	})
+}
@@ -0,0 +1,18 @@
+//go:build windows
+// +build windows
+
+package execcmd
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+)
+
+func newCmdCommand(ctx context.Context, cmdline string) *exec.Cmd {
+	// cmd 不遵循 CommandLineToArgvW 的规则解析命令行，
+	// 因此需要原样传递命令行，而不是由 Go 再次转义；"/S" 表示仅去除首尾的引号。
+	cmd := exec.CommandContext(ctx, "cmd")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd /D /S /C "` + cmdline + `"`}
+	return cmd
+}
@@ -0,0 +1,35 @@
+package local
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/go-acme/lego/v5/challenge/tlsalpn01"
+
+	"github.com/certimate-go/certimate/pkg/core"
+)
+
+type ChallengerConfig struct {
+	// 监听地址，形如 "0.0.0.0:443"、":443"。
+	// 零值时默认值 ":443"。
+	ListenAddress string `json:"listenAddress,omitempty"`
+}
+
+func NewChallenger(config *ChallengerConfig) (core.ACMEChallenger, error) {
+	if config == nil {
+		return nil, fmt.Errorf("the configuration of the acme challenge provider is nil")
+	}
+
+	host, port := "", ""
+	if config.ListenAddress != "" {
+		h, p, err := net.SplitHostPort(config.ListenAddress)
+		if err != nil {
+			return nil, fmt.Errorf("local: invalid listen address '%s': %w", config.ListenAddress, err)
+		}
+
+		host, port = h, p
+	}
+
+	provider := tlsalpn01.NewProviderServer(host, port)
+	return provider, nil
+}
@@ -0,0 +1,201 @@
+package internal
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v5/challenge"
+	"github.com/go-acme/lego/v5/challenge/tlsalpn01"
+	"github.com/go-acme/lego/v5/log"
+
+	"github.com/certimate-go/certimate/internal/tools/ssh"
+)
+
+var _ challenge.Provider = (*TLSALPNProvider)(nil)
+
+type Config struct {
+	ssh.Config
+
+	RemoteListenAddress string
+}
+
+func NewDefaultConfig() *Config {
+	defaultCfg := ssh.NewDefaultConfig()
+
+	return &Config{
+		Config:              *defaultCfg,
+		RemoteListenAddress: "0.0.0.0:443",
+	}
+}
+
+// 借助 SSH 远程端口转发，在远程主机上监听端口，并由本地完成 TLS 握手以响应质询。
+type TLSALPNProvider struct {
+	config *Config
+
+	mtx      sync.Mutex
+	client   *ssh.Client
+	listener net.Listener
+	certs    map[string]*tls.Certificate
+}
+
+func NewTLSALPNProviderConfig(config *Config) (*TLSALPNProvider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("the configuration of the acme challenge provider is nil")
+	}
+
+	if _, _, err := net.SplitHostPort(config.RemoteListenAddress); err != nil {
+		return nil, fmt.Errorf("ssh: invalid remote listen address '%s': %w", config.RemoteListenAddress, err)
+	}
+
+	return &TLSALPNProvider{
+		config: config,
+		certs:  make(map[string]*tls.Certificate),
+	}, nil
+}
+
+func (p *TLSALPNProvider) Present(ctx context.Context, domain, token, keyAuth string) error {
+	cert, err := tlsalpn01.ChallengeCert(domain, keyAuth)
+	if err != nil {
+		return fmt.Errorf("ssh: failed to generate certificate for TLS-ALPN challenge: %w", err)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.certs[domain] = cert
+	if p.listener != nil {
+		return nil
+	}
+
+	client, err := p.createSshClient()
+	if err != nil {
+		delete(p.certs, domain)
+		return fmt.Errorf("ssh: failed to create SSH client: %w", err)
+	}
+
+	log.Info("ssh: ssh connected")
+
+	listener, err := client.RawClient().Listen("tcp", p.config.RemoteListenAddress)
+	if err != nil {
+		client.Close()
+		delete(p.certs, domain)
+		return fmt.Errorf("ssh: failed to listen on remote address '%s': %w", p.config.RemoteListenAddress, err)
+	}
+
+	log.Info("ssh: remote listener started", slog.String("address", p.config.RemoteListenAddress))
+
+	p.client = client
+	p.listener = listener
+	go p.serve(listener)
+
+	return nil
+}
+
+func (p *TLSALPNProvider) CleanUp(ctx context.Context, domain, token, keyAuth string) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	delete(p.certs, domain)
+	if len(p.certs) > 0 || p.listener == nil {
+		return nil
+	}
+
+	// 所有质询均已结束，关闭远程监听及 SSH 连接
+	var errs []error
+	if err := p.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		errs = append(errs, err)
+	}
+	if err := p.client.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	p.listener = nil
+	p.client = nil
+
+	log.Info("ssh: ssh closed")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("ssh: failed to close remote listener: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func (p *TLSALPNProvider) serve(listener net.Listener) {
+	tlsConfig := &tls.Config{
+		NextProtos:     []string{tlsalpn01.ACMETLS1Protocol},
+		GetCertificate: p.getCertificate,
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) && !errors.Is(err, context.Canceled) {
+				log.Debug("ssh: remote listener stopped", log.ErrorAttr(err))
+			}
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+
+			tlsConn := tls.Server(conn, tlsConfig)
+			tlsConn.SetDeadline(time.Now().Add(30 * time.Second))
+			if err := tlsConn.Handshake(); err != nil {
+				log.Debug("ssh: TLS handshake failed", log.ErrorAttr(err))
+			}
+		}()
+	}
+}
+
+func (p *TLSALPNProvider) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if cert, ok := p.certs[hello.ServerName]; ok {
+		return cert, nil
+	}
+
+	// 对于 IP 地址标识符，验证方发送的 SNI 为其反向解析名称，此时若仅有一个待验证证书则直接使用
+	if len(p.certs) == 1 {
+		for _, cert := range p.certs {
+			return cert, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no challenge certificate for '%s'", hello.ServerName)
+}
+
+func (p *TLSALPNProvider) createSshClient() (*ssh.Client, error) {
+	clientCfg := ssh.NewDefaultConfig()
+	clientCfg.Host = p.config.Host
+	clientCfg.Port = p.config.Port
+	clientCfg.AuthMethod = ssh.AuthMethodType(p.config.AuthMethod)
+	clientCfg.Username = p.config.Username
+	clientCfg.Password = p.config.Password
+	clientCfg.Key = p.config.Key
+	clientCfg.KeyPassphrase = p.config.KeyPassphrase
+	for _, jumpServer := range p.config.JumpServers {
+		jumpServerCfg := ssh.NewServerConfig()
+		jumpServerCfg.Host = jumpServer.Host
+		jumpServerCfg.Port = jumpServer.Port
+		jumpServerCfg.AuthMethod = ssh.AuthMethodType(jumpServer.AuthMethod)
+		jumpServerCfg.Username = jumpServer.Username
+		jumpServerCfg.Password = jumpServer.Password
+		jumpServerCfg.Key = jumpServer.Key
+		jumpServerCfg.KeyPassphrase = jumpServer.KeyPassphrase
+		clientCfg.JumpServers = append(clientCfg.JumpServers, *jumpServerCfg)
+	}
+
+	client, err := ssh.NewClient(clientCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
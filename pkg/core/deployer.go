@@ -25,3 +25,10 @@ type Deployer interface {
 type DeployerDeployResult struct {
 	ExtendedData map[string]any `json:"extendedData,omitempty"`
 }
+
+// 表示部署时必须提供私钥的 SSL 证书部署器的可选接口。
+// 未实现该接口的部署器不强制要求私钥。
+type DeployerPrivateKeyRequirer interface {
+	// 返回部署时是否必须提供私钥。
+	RequiresPrivateKey() bool
+}
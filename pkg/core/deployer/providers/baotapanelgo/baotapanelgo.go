@@ -75,6 +75,10 @@ func (d *Deployer) SetLogger(logger *slog.Logger) {
 	}
 }
 
+func (d *Deployer) RequiresPrivateKey() bool {
+	return true
+}
+
 func (d *Deployer) Deploy(ctx context.Context, certPEM, privkeyPEM string) (*DeployResult, error) {
 	if len(d.config.SiteNames) == 0 {
 		return nil, fmt.Errorf("config `siteNames` is required")
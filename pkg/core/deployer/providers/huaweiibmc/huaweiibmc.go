@@ -61,6 +61,10 @@ func (d *Deployer) SetLogger(logger *slog.Logger) {
 	}
 }
 
+func (d *Deployer) RequiresPrivateKey() bool {
+	return true
+}
+
 func (d *Deployer) Deploy(ctx context.Context, certPEM, privkeyPEM string) (*DeployResult, error) {
 	// 转换证书格式
 	certPFXPwd := make([]byte, 24)
@@ -83,6 +83,15 @@ func (d *Deployer) SetLogger(logger *slog.Logger) {
 	}
 }
 
+func (d *Deployer) RequiresPrivateKey() bool {
+	// PEM 格式下仅在需要写入私钥文件时才需要私钥
+	if d.config.FileFormat == FILE_FORMAT_PEM {
+		return d.config.FilePathForKey != ""
+	}
+
+	return true
+}
+
 func (d *Deployer) Deploy(ctx context.Context, certPEM, privkeyPEM string) (*DeployResult, error) {
 	// 提取服务器证书和中间证书
 	serverCertPEM, issuerCertPEM, err := xcert.ExtractCertificatesFromPEM(certPEM)
@@ -108,6 +108,10 @@ func (d *Deployer) SetLogger(logger *slog.Logger) {
 	d.sdkCertmgr.SetLogger(logger)
 }
 
+func (d *Deployer) RequiresPrivateKey() bool {
+	return true
+}
+
 func (d *Deployer) Deploy(ctx context.Context, certPEM, privkeyPEM string) (*DeployResult, error) {
 	if d.config.MakersProjectId == "" {
 		return nil, fmt.Errorf("config `makersProjectId` is required")
@@ -0,0 +1,390 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	RevocationStatusGood    = "good"
+	RevocationStatusRevoked = "revoked"
+	RevocationStatusUnknown = "unknown"
+)
+
+// 单次握手或单次 HTTP 请求的超时时间。
+const auditStepTimeout = 10 * time.Second
+
+// 通过 AIA 补全中间证书时的最大层数。
+const maxAIAFetchDepth = 4
+
+type AuditOptions struct {
+	// 协议，同 [DialWithProtocol]。
+	Protocol string
+	// 服务器名称，用于 SNI。
+	ServerName string
+	// 信任的根证书池。
+	// 零值时使用系统信任的根证书。
+	RootCAs *x509.CertPool
+}
+
+type AuditResult struct {
+	// 服务器返回的证书链是否可信。
+	ChainTrusted bool
+	// 证书链验证失败的原因。
+	ChainError error
+	// 服务器是否未返回必要的中间证书（即需借助 AIA 方可构建完整的证书链）。
+	MissingIntermediates bool
+	// 服务器返回的证书链中多余的证书。
+	ExtraIntermediates []*x509.Certificate
+	// 服务器是否启用了 OCSP 装订。
+	OCSPStapled bool
+	// 吊销状态，可取值 [RevocationStatusGood]、[RevocationStatusRevoked]、[RevocationStatusUnknown]。
+	RevocationStatus string
+	// 吊销状态的来源，可取值 "ocsp-stapling"、"ocsp"、"crl"。
+	RevocationSource string
+	// 服务器支持的 TLS 版本，如 "TLSv1.2"、"TLSv1.3"。
+	TLSVersions []string
+	// 服务器接受的弱密码套件。
+	WeakCipherSuites []string
+}
+
+// 对 TLS 端点进行深度审计，包括证书链、吊销状态、支持的协议版本及弱密码套件。
+//
+// 入参:
+//   - ctx: 上下文。
+//   - addr: 目标地址，形如 "host:port"。
+//   - options: 审计选项。
+//
+// 出参:
+//   - result: 审计结果。
+//   - err: 错误。
+func Audit(ctx context.Context, addr string, options *AuditOptions) (*AuditResult, error) {
+	if options == nil {
+		options = &AuditOptions{}
+	}
+
+	roots := options.RootCAs
+	if roots == nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load system cert pool: %w", err)
+		}
+		roots = pool
+	}
+
+	config := NewInsecureConfig()
+	config.ServerName = options.ServerName
+	state, err := auditHandshake(ctx, addr, options.Protocol, config)
+	if err != nil {
+		return nil, err
+	}
+	if len(state.PeerCertificates) == 0 {
+		return nil, errors.New("no peer certificates")
+	}
+
+	result := &AuditResult{RevocationStatus: RevocationStatusUnknown}
+	client := &http.Client{Timeout: auditStepTimeout}
+
+	// 验证证书链，并检查是否缺失或多余中间证书
+	leaf := state.PeerCertificates[0]
+	served := state.PeerCertificates[1:]
+	chains, err := verifyChain(leaf, served, roots)
+	if err != nil {
+		result.ChainError = err
+
+		fetched := fetchIntermediates(ctx, client, leaf, served)
+		if len(fetched) > 0 {
+			if chains, err = verifyChain(leaf, append(slices.Clone(served), fetched...), roots); err == nil {
+				result.MissingIntermediates = true
+			}
+		}
+	} else {
+		result.ChainTrusted = true
+	}
+
+	if len(chains) > 0 {
+		for _, cert := range served {
+			used := slices.ContainsFunc(chains, func(chain []*x509.Certificate) bool {
+				return slices.ContainsFunc(chain, cert.Equal)
+			})
+			if !used {
+				result.ExtraIntermediates = append(result.ExtraIntermediates, cert)
+			}
+		}
+	}
+
+	// 检查吊销状态，依次尝试 OCSP 装订、OCSP、CRL
+	var issuer *x509.Certificate
+	if len(chains) > 0 && len(chains[0]) > 1 {
+		issuer = chains[0][1]
+	} else {
+		for _, cert := range served {
+			if leaf.CheckSignatureFrom(cert) == nil {
+				issuer = cert
+				break
+			}
+		}
+	}
+
+	if len(state.OCSPResponse) > 0 {
+		result.OCSPStapled = true
+		if issuer != nil {
+			if resp, err := ocsp.ParseResponseForCert(state.OCSPResponse, leaf, issuer); err == nil {
+				result.RevocationStatus = ocspStatusString(resp.Status)
+				result.RevocationSource = "ocsp-stapling"
+			}
+		}
+	}
+	if result.RevocationStatus == RevocationStatusUnknown && issuer != nil {
+		if status := checkOCSP(ctx, client, leaf, issuer); status != RevocationStatusUnknown {
+			result.RevocationStatus = status
+			result.RevocationSource = "ocsp"
+		} else if status := checkCRL(ctx, client, leaf, issuer); status != RevocationStatusUnknown {
+			result.RevocationStatus = status
+			result.RevocationSource = "crl"
+		}
+	}
+
+	// 枚举支持的 TLS 版本及弱密码套件
+	for _, version := range []uint16{tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13} {
+		config := NewInsecureConfig()
+		config.ServerName = options.ServerName
+		config.MinVersion = version
+		config.MaxVersion = version
+		if _, err := auditHandshake(ctx, addr, options.Protocol, config); err != nil {
+			continue
+		}
+
+		result.TLSVersions = append(result.TLSVersions, tlsVersionString(version))
+		if version == tls.VersionTLS13 {
+			continue // TLS 1.3 的密码套件均为安全的
+		}
+
+		// 每次仅提供尚未被服务器接受的弱密码套件，直至握手失败
+		candidates := weakCipherSuites(version)
+		for len(candidates) > 0 {
+			config.CipherSuites = candidates
+			state, err := auditHandshake(ctx, addr, options.Protocol, config)
+			if err != nil {
+				break
+			}
+
+			name := tls.CipherSuiteName(state.CipherSuite)
+			if !slices.Contains(result.WeakCipherSuites, name) {
+				result.WeakCipherSuites = append(result.WeakCipherSuites, name)
+			}
+			candidates = slices.DeleteFunc(candidates, func(id uint16) bool { return id == state.CipherSuite })
+		}
+	}
+
+	return result, nil
+}
+
+func auditHandshake(ctx context.Context, addr string, protocol string, config *tls.Config) (*tls.ConnectionState, error) {
+	ctx, cancel := context.WithTimeout(ctx, auditStepTimeout)
+	defer cancel()
+
+	conn, err := DialWithProtocol(ctx, addr, protocol, config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	return &state, nil
+}
+
+func verifyChain(leaf *x509.Certificate, intermediates []*x509.Certificate, roots *x509.CertPool) ([][]*x509.Certificate, error) {
+	pool := x509.NewCertPool()
+	for _, cert := range intermediates {
+		pool.AddCert(cert)
+	}
+
+	return leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: pool,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+}
+
+// 沿 AIA 扩展中的颁发者地址下载服务器未返回的中间证书。
+func fetchIntermediates(ctx context.Context, client *http.Client, leaf *x509.Certificate, served []*x509.Certificate) []*x509.Certificate {
+	fetched := make([]*x509.Certificate, 0)
+
+	current := leaf
+	for range maxAIAFetchDepth {
+		// 服务器已返回其颁发者时沿已有证书继续向上查找
+		if idx := slices.IndexFunc(served, func(cert *x509.Certificate) bool { return current.CheckSignatureFrom(cert) == nil }); idx >= 0 {
+			current = served[idx]
+			continue
+		}
+
+		var issuer *x509.Certificate
+		for _, url := range current.IssuingCertificateURL {
+			data, err := httpGet(ctx, client, url)
+			if err != nil {
+				continue
+			}
+
+			// AIA 指向的证书可能为 DER 或 PKCS#7 格式，这里仅处理常见的 DER 格式
+			cert, err := x509.ParseCertificate(data)
+			if err != nil {
+				continue
+			}
+			issuer = cert
+			break
+		}
+		if issuer == nil || bytes.Equal(issuer.RawSubject, issuer.RawIssuer) {
+			break
+		}
+
+		fetched = append(fetched, issuer)
+		current = issuer
+	}
+
+	return fetched
+}
+
+func checkOCSP(ctx context.Context, client *http.Client, leaf, issuer *x509.Certificate) string {
+	if len(leaf.OCSPServer) == 0 {
+		return RevocationStatusUnknown
+	}
+
+	reqBytes, err := ocsp.CreateRequest(leaf, issuer, &ocsp.RequestOptions{Hash: crypto.SHA256})
+	if err != nil {
+		return RevocationStatusUnknown
+	}
+
+	for _, url := range leaf.OCSPServer {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBytes))
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Content-Type", "application/ocsp-request")
+
+		data, err := httpDo(client, req)
+		if err != nil {
+			continue
+		}
+
+		resp, err := ocsp.ParseResponseForCert(data, leaf, issuer)
+		if err != nil {
+			continue
+		}
+
+		if status := ocspStatusString(resp.Status); status != RevocationStatusUnknown {
+			return status
+		}
+	}
+
+	return RevocationStatusUnknown
+}
+
+func checkCRL(ctx context.Context, client *http.Client, leaf, issuer *x509.Certificate) string {
+	for _, url := range leaf.CRLDistributionPoints {
+		data, err := httpGet(ctx, client, url)
+		if err != nil {
+			continue
+		}
+
+		crl, err := x509.ParseRevocationList(data)
+		if err != nil {
+			continue
+		} else if err := crl.CheckSignatureFrom(issuer); err != nil {
+			continue
+		}
+
+		for _, entry := range crl.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+				return RevocationStatusRevoked
+			}
+		}
+		return RevocationStatusGood
+	}
+
+	return RevocationStatusUnknown
+}
+
+func httpGet(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpDo(client, req)
+}
+
+func httpDo(client *http.Client, req *http.Request) ([]byte, error) {
+	const MAX_BODY_SIZE = 16 << 20
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, MAX_BODY_SIZE))
+}
+
+func ocspStatusString(status int) string {
+	switch status {
+	case ocsp.Good:
+		return RevocationStatusGood
+	case ocsp.Revoked:
+		return RevocationStatusRevoked
+	}
+
+	return RevocationStatusUnknown
+}
+
+func tlsVersionString(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLSv1.0"
+	case tls.VersionTLS11:
+		return "TLSv1.1"
+	case tls.VersionTLS12:
+		return "TLSv1.2"
+	case tls.VersionTLS13:
+		return "TLSv1.3"
+	}
+
+	return fmt.Sprintf("0x%04x", version)
+}
+
+// 返回指定 TLS 版本下的弱密码套件，包括不安全的密码套件、不具备前向安全性的 RSA 密钥交换套件及 CBC 模式套件。
+func weakCipherSuites(version uint16) []uint16 {
+	ids := make([]uint16, 0)
+	for _, suite := range tls.InsecureCipherSuites() {
+		if slices.Contains(suite.SupportedVersions, version) {
+			ids = append(ids, suite.ID)
+		}
+	}
+	for _, suite := range tls.CipherSuites() {
+		if !slices.Contains(suite.SupportedVersions, version) {
+			continue
+		}
+
+		if strings.HasPrefix(suite.Name, "TLS_RSA_") || strings.Contains(suite.Name, "_CBC_") {
+			ids = append(ids, suite.ID)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,396 @@
+package tls
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	ProtocolTLS      = "tls"
+	ProtocolSMTP     = "smtp"
+	ProtocolIMAP     = "imap"
+	ProtocolPOP3     = "pop3"
+	ProtocolFTP      = "ftp"
+	ProtocolLDAP     = "ldap"
+	ProtocolPostgres = "postgres"
+	ProtocolMySQL    = "mysql"
+)
+
+// 返回协议的默认端口。对于未知协议，返回 HTTPS 的默认端口 443。
+//
+// 入参:
+//   - protocol: 协议。
+//
+// 出参:
+//   - port: 默认端口。
+func GetDefaultPort(protocol string) int {
+	switch strings.ToLower(protocol) {
+	case ProtocolSMTP:
+		return 587
+	case ProtocolIMAP:
+		return 143
+	case ProtocolPOP3:
+		return 110
+	case ProtocolFTP:
+		return 21
+	case ProtocolLDAP:
+		return 389
+	case ProtocolPostgres:
+		return 5432
+	case ProtocolMySQL:
+		return 3306
+	}
+
+	return 443
+}
+
+// 建立 TLS 连接的默认超时时间。
+const defaultDialTimeout = 30 * time.Second
+
+// 建立 TLS 连接。对于明文协议，将先按协议约定完成 STARTTLS 升级后再进行 TLS 握手。
+//
+// 入参:
+//   - ctx: 上下文。
+//   - addr: 目标地址，形如 "host:port"。
+//   - protocol: 协议，可取值 [ProtocolTLS]、[ProtocolSMTP]、[ProtocolIMAP]、[ProtocolPOP3]、[ProtocolFTP]、[ProtocolLDAP]、[ProtocolPostgres]、[ProtocolMySQL]。
+//   - config: [tls.Config] 对象。
+//
+// 出参:
+//   - conn: 已完成握手的 TLS 连接。
+//   - err: 错误。
+func DialWithProtocol(ctx context.Context, addr string, protocol string, config *tls.Config) (*tls.Conn, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultDialTimeout)
+		defer cancel()
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	var upgradeErr error
+	switch strings.ToLower(protocol) {
+	case "", ProtocolTLS:
+	case ProtocolSMTP:
+		upgradeErr = startTLSForSMTP(conn)
+	case ProtocolIMAP:
+		upgradeErr = startTLSForIMAP(conn)
+	case ProtocolPOP3:
+		upgradeErr = startTLSForPOP3(conn)
+	case ProtocolFTP:
+		upgradeErr = startTLSForFTP(conn)
+	case ProtocolLDAP:
+		upgradeErr = startTLSForLDAP(conn)
+	case ProtocolPostgres:
+		upgradeErr = startTLSForPostgres(conn)
+	case ProtocolMySQL:
+		upgradeErr = startTLSForMySQL(conn)
+	default:
+		upgradeErr = fmt.Errorf("unsupported protocol '%s'", protocol)
+	}
+	if upgradeErr != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to upgrade %s connection: %w", protocol, upgradeErr)
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Time{})
+	return tlsConn, nil
+}
+
+// 读取形如 "250-xxx" / "250 xxx" 的多行响应，返回最终的状态码。
+func readMultilineReply(r *bufio.Reader) (string, error) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+
+		if len(line) < 4 {
+			return "", fmt.Errorf("malformed reply '%s'", strings.TrimSpace(line))
+		}
+		if line[3] != '-' {
+			return line[:3], nil
+		}
+	}
+}
+
+func startTLSForSMTP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	if code, err := readMultilineReply(r); err != nil {
+		return err
+	} else if code != "220" {
+		return fmt.Errorf("unexpected greeting code %s", code)
+	}
+
+	if _, err := io.WriteString(conn, "EHLO certimate\r\n"); err != nil {
+		return err
+	}
+	if code, err := readMultilineReply(r); err != nil {
+		return err
+	} else if code != "250" {
+		return fmt.Errorf("unexpected EHLO reply code %s", code)
+	}
+
+	if _, err := io.WriteString(conn, "STARTTLS\r\n"); err != nil {
+		return err
+	}
+	if code, err := readMultilineReply(r); err != nil {
+		return err
+	} else if code != "220" {
+		return fmt.Errorf("the server refused STARTTLS with code %s", code)
+	}
+
+	return nil
+}
+
+func startTLSForIMAP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	greeting, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	} else if !strings.HasPrefix(greeting, "* OK") {
+		return fmt.Errorf("unexpected greeting '%s'", strings.TrimSpace(greeting))
+	}
+
+	if _, err := io.WriteString(conn, "a001 STARTTLS\r\n"); err != nil {
+		return err
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(line, "a001 ") {
+			if !strings.HasPrefix(line, "a001 OK") {
+				return fmt.Errorf("the server refused STARTTLS: '%s'", strings.TrimSpace(line))
+			}
+			return nil
+		}
+	}
+}
+
+func startTLSForPOP3(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	greeting, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	} else if !strings.HasPrefix(greeting, "+OK") {
+		return fmt.Errorf("unexpected greeting '%s'", strings.TrimSpace(greeting))
+	}
+
+	if _, err := io.WriteString(conn, "STLS\r\n"); err != nil {
+		return err
+	}
+	reply, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	} else if !strings.HasPrefix(reply, "+OK") {
+		return fmt.Errorf("the server refused STLS: '%s'", strings.TrimSpace(reply))
+	}
+
+	return nil
+}
+
+func startTLSForFTP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	if code, err := readMultilineReply(r); err != nil {
+		return err
+	} else if code != "220" {
+		return fmt.Errorf("unexpected greeting code %s", code)
+	}
+
+	if _, err := io.WriteString(conn, "AUTH TLS\r\n"); err != nil {
+		return err
+	}
+	if code, err := readMultilineReply(r); err != nil {
+		return err
+	} else if code != "234" {
+		return fmt.Errorf("the server refused AUTH TLS with code %s", code)
+	}
+
+	return nil
+}
+
+func startTLSForLDAP(conn net.Conn) error {
+	// LDAPMessage { messageID 1, extendedReq { requestName "1.3.6.1.4.1.1466.20037" } }，参考 RFC 4511
+	const oid = "1.3.6.1.4.1.1466.20037"
+	extendedReq := append([]byte{0x80, byte(len(oid))}, oid...)
+	protocolOp := append([]byte{0x77, byte(len(extendedReq))}, extendedReq...)
+	body := append([]byte{0x02, 0x01, 0x01}, protocolOp...)
+	message := append([]byte{0x30, byte(len(body))}, body...)
+	if _, err := conn.Write(message); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(conn)
+	tag, content, err := readBER(r)
+	if err != nil {
+		return err
+	} else if tag != 0x30 {
+		return fmt.Errorf("unexpected ldap message tag 0x%02x", tag)
+	}
+
+	cr := bufio.NewReader(bytes.NewReader(content))
+	if tag, _, err := readBER(cr); err != nil {
+		return err
+	} else if tag != 0x02 {
+		return fmt.Errorf("unexpected ldap message id tag 0x%02x", tag)
+	}
+
+	tag, op, err := readBER(cr)
+	if err != nil {
+		return err
+	} else if tag != 0x78 {
+		return fmt.Errorf("unexpected ldap response tag 0x%02x", tag)
+	}
+
+	tag, resultCode, err := readBER(bufio.NewReader(bytes.NewReader(op)))
+	if err != nil {
+		return err
+	} else if tag != 0x0a || len(resultCode) != 1 {
+		return fmt.Errorf("malformed ldap extended response")
+	} else if resultCode[0] != 0 {
+		return fmt.Errorf("the server refused StartTLS with result code %d", resultCode[0])
+	}
+
+	return nil
+}
+
+func readBER(r *bufio.Reader) (byte, []byte, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	first, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length := int(first)
+	if first&0x80 != 0 {
+		n := int(first & 0x7f)
+		if n == 0 || n > 4 {
+			return 0, nil, errors.New("unsupported ber length")
+		}
+
+		length = 0
+		for range n {
+			b, err := r.ReadByte()
+			if err != nil {
+				return 0, nil, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	if length > 1<<20 {
+		return 0, nil, errors.New("ber content too large")
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return 0, nil, err
+	}
+
+	return tag, content, nil
+}
+
+func startTLSForPostgres(conn net.Conn) error {
+	// SSLRequest，参考 https://www.postgresql.org/docs/current/protocol-message-formats.html
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], 80877103)
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	reply := make([]byte, 1)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	} else if reply[0] != 'S' {
+		return fmt.Errorf("the server does not support ssl")
+	}
+
+	return nil
+}
+
+func startTLSForMySQL(conn net.Conn) error {
+	const (
+		clientProtocol41     = 0x00000200
+		clientSSL            = 0x00000800
+		clientSecureConn     = 0x00008000
+		clientLongPassword   = 0x00000001
+		clientPluginAuth     = 0x00080000
+		maxInitialPacketSize = 1 << 16
+	)
+
+	// 读取初始握手包，参考 https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_connection_phase_packets_protocol_handshake_v10.html
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	if length <= 0 || length > maxInitialPacketSize {
+		return fmt.Errorf("malformed handshake packet")
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return err
+	}
+
+	if payload[0] == 0xff {
+		return fmt.Errorf("the server returned an error")
+	} else if payload[0] != 10 {
+		return fmt.Errorf("unsupported protocol version %d", payload[0])
+	}
+
+	// 跳过服务器版本、连接 ID、认证数据第一部分及填充字节
+	pos := bytes.IndexByte(payload[1:], 0)
+	if pos < 0 {
+		return fmt.Errorf("malformed handshake packet")
+	}
+	pos = 1 + pos + 1 + 4 + 8 + 1
+	if len(payload) < pos+2 {
+		return fmt.Errorf("malformed handshake packet")
+	}
+	capabilities := uint32(binary.LittleEndian.Uint16(payload[pos : pos+2]))
+	if capabilities&clientSSL == 0 {
+		return fmt.Errorf("the server does not support ssl")
+	}
+
+	// 发送 SSLRequest 包
+	request := make([]byte, 4+32)
+	request[0] = 32
+	request[3] = header[3] + 1
+	binary.LittleEndian.PutUint32(request[4:8], clientLongPassword|clientProtocol41|clientSSL|clientSecureConn|clientPluginAuth)
+	binary.LittleEndian.PutUint32(request[8:12], 1<<24)
+	request[12] = 0x21 // utf8_general_ci
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	return nil
+}
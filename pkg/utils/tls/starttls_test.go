@@ -0,0 +1,192 @@
+package tls
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 按预设内容应答的连接，并记录所有写入的内容。
+type scriptedConn struct {
+	net.Conn
+	r io.Reader
+	w bytes.Buffer
+}
+
+func (c *scriptedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+func (c *scriptedConn) Write(b []byte) (int, error) {
+	return c.w.Write(b)
+}
+
+func TestStartTLS(t *testing.T) {
+	ldapReply := func(resultCode byte, longForm bool) string {
+		op := []byte{0x78, 0x07, 0x0a, 0x01, resultCode, 0x04, 0x00, 0x04, 0x00}
+		body := append([]byte{0x02, 0x01, 0x01}, op...)
+		if longForm {
+			return string(append([]byte{0x30, 0x81, byte(len(body))}, body...))
+		}
+		return string(append([]byte{0x30, byte(len(body))}, body...))
+	}
+
+	mysqlHandshake := func(capabilities uint16) string {
+		payload := []byte{10}
+		payload = append(payload, "8.0.0\x00"...)
+		payload = append(payload, 0x01, 0x00, 0x00, 0x00)
+		payload = append(payload, "12345678"...)
+		payload = append(payload, 0x00)
+		payload = binary.LittleEndian.AppendUint16(payload, capabilities)
+		payload = append(payload, 0x21, 0x02, 0x00)
+		header := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), 0x00}
+		return string(append(header, payload...))
+	}
+
+	testCases := []struct {
+		name          string
+		upgrade       func(conn net.Conn) error
+		input         string
+		expectedWrite string
+		expectedErr   bool
+	}{
+		{
+			name:          "smtp",
+			upgrade:       startTLSForSMTP,
+			input:         "220 mail.example.com ESMTP\r\n250-mail.example.com\r\n250-SIZE 1024\r\n250 STARTTLS\r\n220 ready\r\n",
+			expectedWrite: "EHLO certimate\r\nSTARTTLS\r\n",
+		},
+		{
+			name:        "smtp refused",
+			upgrade:     startTLSForSMTP,
+			input:       "220 mail.example.com ESMTP\r\n250 mail.example.com\r\n454 not available\r\n",
+			expectedErr: true,
+		},
+		{
+			name:        "smtp bad greeting",
+			upgrade:     startTLSForSMTP,
+			input:       "554 go away\r\n",
+			expectedErr: true,
+		},
+		{
+			name:        "smtp malformed reply",
+			upgrade:     startTLSForSMTP,
+			input:       "22\r\n",
+			expectedErr: true,
+		},
+		{
+			name:          "imap",
+			upgrade:       startTLSForIMAP,
+			input:         "* OK IMAP4rev1 ready\r\n* CAPABILITY IMAP4rev1\r\na001 OK begin TLS\r\n",
+			expectedWrite: "a001 STARTTLS\r\n",
+		},
+		{
+			name:        "imap refused",
+			upgrade:     startTLSForIMAP,
+			input:       "* OK IMAP4rev1 ready\r\na001 BAD unsupported\r\n",
+			expectedErr: true,
+		},
+		{
+			name:        "imap bad greeting",
+			upgrade:     startTLSForIMAP,
+			input:       "* BYE\r\n",
+			expectedErr: true,
+		},
+		{
+			name:          "pop3",
+			upgrade:       startTLSForPOP3,
+			input:         "+OK POP3 ready\r\n+OK begin TLS\r\n",
+			expectedWrite: "STLS\r\n",
+		},
+		{
+			name:        "pop3 refused",
+			upgrade:     startTLSForPOP3,
+			input:       "+OK POP3 ready\r\n-ERR unsupported\r\n",
+			expectedErr: true,
+		},
+		{
+			name:          "ftp",
+			upgrade:       startTLSForFTP,
+			input:         "220-Welcome\r\n220 ready\r\n234 AUTH TLS OK\r\n",
+			expectedWrite: "AUTH TLS\r\n",
+		},
+		{
+			name:        "ftp refused",
+			upgrade:     startTLSForFTP,
+			input:       "220 ready\r\n502 not implemented\r\n",
+			expectedErr: true,
+		},
+		{
+			name:    "ldap",
+			upgrade: startTLSForLDAP,
+			input:   ldapReply(0x00, false),
+		},
+		{
+			name:    "ldap long form length",
+			upgrade: startTLSForLDAP,
+			input:   ldapReply(0x00, true),
+		},
+		{
+			name:        "ldap refused",
+			upgrade:     startTLSForLDAP,
+			input:       ldapReply(0x02, false),
+			expectedErr: true,
+		},
+		{
+			name:        "ldap truncated",
+			upgrade:     startTLSForLDAP,
+			input:       ldapReply(0x00, false)[:6],
+			expectedErr: true,
+		},
+		{
+			name:          "postgres",
+			upgrade:       startTLSForPostgres,
+			input:         "S",
+			expectedWrite: "\x00\x00\x00\x08\x04\xd2\x16\x2f",
+		},
+		{
+			name:        "postgres refused",
+			upgrade:     startTLSForPostgres,
+			input:       "N",
+			expectedErr: true,
+		},
+		{
+			name:    "mysql",
+			upgrade: startTLSForMySQL,
+			input:   mysqlHandshake(0xffff),
+		},
+		{
+			name:        "mysql without ssl",
+			upgrade:     startTLSForMySQL,
+			input:       mysqlHandshake(0xffff &^ 0x0800),
+			expectedErr: true,
+		},
+		{
+			name:        "mysql error packet",
+			upgrade:     startTLSForMySQL,
+			input:       "\x03\x00\x00\x00\xff\x10\x04",
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := &scriptedConn{r: strings.NewReader(tc.input)}
+
+			err := tc.upgrade(conn)
+			if tc.expectedErr {
+				assert.Error(t, err, "Case: %-20s", tc.name)
+				return
+			}
+
+			assert.NoError(t, err, "Case: %-20s", tc.name)
+			if tc.expectedWrite != "" {
+				assert.Equal(t, tc.expectedWrite, conn.w.String(), "Case: %-20s", tc.name)
+			}
+		})
+	}
+}